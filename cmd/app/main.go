@@ -3,10 +3,18 @@ package main
 import (
 	"Clinic_backend/config"
 	_ "Clinic_backend/docs"
+	"Clinic_backend/internal/lifecycle"
+	"Clinic_backend/internal/logging"
+	"Clinic_backend/internal/media"
+	"Clinic_backend/internal/repository"
 	"Clinic_backend/internal/router"
+	"Clinic_backend/internal/service"
 	"Clinic_backend/internal/storage"
+	"Clinic_backend/internal/tracing"
+	"Clinic_backend/internal/worker"
 	"context"
 	"errors"
+	"flag"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -36,75 +44,251 @@ import (
 // @description Type "Bearer" followed by a space and JWT token.
 
 func main() {
+	configPath := flag.String("config", os.Getenv("CONFIG_FILE"), "path to a YAML or TOML config file")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [-config path] [check-config | backfill-media [-base-url url]]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	switch flag.Arg(0) {
+	case "":
+	case "check-config":
+		os.Exit(checkConfig(*configPath))
+	case "backfill-media":
+		os.Exit(backfillMedia(*configPath, flag.Args()[1:]))
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
 	slog.Info("Running main...")
 
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
-	errChan := make(chan error, 1)
+	if err := StartApplication(ctx, *configPath); err != nil {
+		slog.Error("Application stopped with error", "error", err.Error())
+		stop()
+		os.Exit(lifecycle.ExitCode(err))
+	}
+
+	slog.Info("Shutdown completed")
+}
+
+// StartApplication запускает приложение и блокируется до сигнала остановки
+// или сбоя одного из компонентов, после чего выполняет упорядоченную остановку
+func StartApplication(ctx context.Context, configPath string) error {
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return err
+	}
+
+	holder := config.NewHolder(configPath, cfg)
+
+	level, err := logging.ParseLevel(cfg.Logging.Level)
+	if err != nil {
+		return err
+	}
+	logLevel := new(slog.LevelVar)
+	logLevel.Set(level)
+	slog.SetDefault(logging.New(os.Stdout, logLevel))
 
-	go func() {
-		if err := StartApplication(ctx); err != nil {
-			errChan <- err
-		} else {
-			errChan <- nil
+	// Уровень логирования меняется без рестарта
+	holder.OnReload(func(next *config.Config) {
+		if level, err := logging.ParseLevel(next.Logging.Level); err == nil {
+			logLevel.Set(level)
 		}
-	}()
+	})
 
-	select {
-	case <-ctx.Done():
-		slog.Info("Received shutdown signal, shutting down gracefully...")
+	slog.Info("Starting Clinic Backend API", "version", "1.0.0")
+
+	lc := lifecycle.New(cfg.Server.ShutdownGracePeriod)
+
+	tracerProvider, err := tracing.Setup(ctx, cfg.Tracing)
+	if err != nil {
+		return err
+	}
 
-	case err := <-errChan:
-		if err != nil {
-			slog.Error("Application error, shutting down gracefully", "error", err.Error())
-		} else {
-			slog.Info("Application completed successfully")
+	cfg.Client, err = storage.NewConnection(ctx, cfg)
+	if err != nil {
+		if tracerProvider != nil {
+			_ = tracerProvider.Shutdown(context.Background())
 		}
+		return err
 	}
 
-	slog.Info("Shutdown completed")
-}
+	blobStore, err := media.NewStore(cfg.Media)
+	if err != nil {
+		cfg.Client.Close()
+		if tracerProvider != nil {
+			_ = tracerProvider.Shutdown(context.Background())
+		}
+		return err
+	}
 
-func StartApplication(ctx context.Context) error {
-	cfg := config.GetConfig()
+	healthService := service.NewHealthService(
+		service.HealthCheck{Name: "database", Check: func(ctx context.Context) error {
+			return cfg.Client.Ping(ctx)
+		}},
+		service.HealthCheck{Name: "migrations", Check: func(ctx context.Context) error {
+			pending, err := storage.PendingMigrations(ctx, cfg.Client)
+			if err != nil {
+				return err
+			}
+			if pending {
+				return errors.New("schema migrations pending")
+			}
+			return nil
+		}},
+		// Без хранилища недоступны только изображения, остальной API работает
+		service.HealthCheck{Name: "media storage", Optional: true, Check: blobStore.Ping},
+	)
 
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
-		Level: slog.LevelInfo,
-	}))
-	slog.SetDefault(logger)
+	r, err := router.SetupRouter(holder, cfg.Client, blobStore, healthService)
+	if err != nil {
+		cfg.Client.Close()
+		if tracerProvider != nil {
+			_ = tracerProvider.Shutdown(context.Background())
+		}
+		return err
+	}
 
-	slog.Info("Starting Clinic Backend API", "version", "1.0.0")
+	// Окончательное удаление записей после истечения срока хранения
+	purgeWorker := worker.NewPurgeWorker(
+		time.Duration(cfg.Retention.SoftDeleteDays)*24*time.Hour,
+		24*time.Hour,
+		worker.PurgeTarget{Name: "services", Purger: repository.NewServiceRepository(cfg.Client)},
+		worker.PurgeTarget{Name: "doctors", Purger: repository.NewDoctorRepository(cfg.Client)},
+		worker.PurgeTarget{Name: "licenses", Purger: repository.NewLicenseRepository(cfg.Client)},
+		worker.PurgeTarget{Name: "carousel", Purger: repository.NewCarouselRepository(cfg.Client)},
+		worker.PurgeTarget{Name: "service_categories", Purger: repository.NewServiceCategoryRepository(cfg.Client)},
+		worker.PurgeTarget{Name: "specializations", Purger: repository.NewSpecializationRepository(cfg.Client)},
+	)
+	lc.Worker("purge worker", purgeWorker.Run)
 
-	cfg.Client = storage.NewConnection(ctx, cfg)
-	defer cfg.Client.Close()
+	// Уменьшенные копии загруженных изображений
+	mediaService := service.NewMediaService(holder, blobStore, repository.NewMediaRepository(cfg.Client))
+	variantWorker := worker.NewVariantWorker(mediaService, 10*time.Second, 20)
+	lc.Worker("media variant worker", variantWorker.Run)
 
-	r := router.SetupRouter(cfg, cfg.Client)
+	// Перезагрузка конфигурации по SIGHUP и при изменении файла
+	lc.Worker("config watcher", holder.Watch)
 
-	addr := fmt.Sprintf("%s:%d", cfg.Env.IpAddress, cfg.Env.ApiPort)
+	addr := cfg.Server.Addr()
 	server := &http.Server{
 		Addr:         addr,
 		Handler:      r,
-		WriteTimeout: time.Second * 15,
-		ReadTimeout:  time.Second * 15,
-		IdleTimeout:  time.Second * 60,
+		WriteTimeout: cfg.Server.WriteTimeout,
+		ReadTimeout:  cfg.Server.ReadTimeout,
+		IdleTimeout:  cfg.Server.IdleTimeout,
 	}
 
-	go func() {
-		slog.Info("Server started")
+	lc.Run("http server", func() error {
+		slog.Info("Server started", "addr", addr)
 		slog.Info("Swagger UI available at", "url", fmt.Sprintf("http://%s/swagger/index.html", addr))
 
 		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
-			slog.Error("Server %v", slog.String("error", err.Error()))
-			panic(err)
+			return err
 		}
-	}()
+		return nil
+	})
 
-	<-ctx.Done()
-	slog.Info("⚫️ Graceful shutdown initiated...")
-	if err := server.Shutdown(ctx); err != nil {
-		slog.Error("⚫️ Server forced to shutdown", slog.String("error", err.Error()))
-		panic(err)
+	// Порядок остановки: снять готовность, дождаться запросов, остановить воркеры,
+	// отправить трассы, закрыть пул соединений
+	lc.OnShutdown("readiness", func(ctx context.Context) error {
+		healthService.SetShuttingDown()
+		select {
+		case <-time.After(cfg.Server.ShutdownDrainDelay):
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+	lc.OnShutdown("http server", server.Shutdown)
+	lc.OnShutdown("workers", lc.StopWorkers)
+	if tracerProvider != nil {
+		lc.OnShutdown("tracing", tracerProvider.Shutdown)
+	}
+	lc.OnShutdown("database", func(context.Context) error {
+		cfg.Client.Close()
+		return nil
+	})
+
+	return lc.Wait(ctx)
+}
+
+// checkConfig проверяет конфигурацию и печатает её без секретов.
+// Возвращает код выхода: 0 - конфигурация корректна, 1 - есть ошибки.
+func checkConfig(configPath string) int {
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	out, err := cfg.Redacted().YAML()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	fmt.Print(string(out))
+	fmt.Println("configuration OK")
+	return 0
+}
+
+// backfillMedia переносит изображения, заданные ссылками и data URI, в хранилище
+// и ставит их в очередь на создание копий. Возвращает код выхода.
+func backfillMedia(configPath string, args []string) int {
+	flags := flag.NewFlagSet("backfill-media", flag.ExitOnError)
+	baseURL := flags.String("base-url", "", "base URL for relative photo links, e.g. https://clinic.example.com")
+	_ = flags.Parse(args)
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	cfg.Client, err = storage.NewConnection(ctx, cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer cfg.Client.Close()
+
+	blobStore, err := media.NewStore(cfg.Media)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	holder := config.NewHolder(configPath, cfg)
+	txManager := repository.NewTransactionManager(cfg.Client)
+	mediaRepo := repository.NewMediaRepository(cfg.Client)
+	backfillService := service.NewMediaBackfillService(
+		holder,
+		txManager,
+		service.NewAuditService(txManager, repository.NewAuditRepository(cfg.Client)),
+		service.NewMediaService(holder, blobStore, mediaRepo),
+		mediaRepo,
+	)
+
+	result, err := backfillService.Backfill(ctx, *baseURL)
+	if result != nil {
+		fmt.Printf("linked: %d, failed: %d, skipped: %d, requeued for variants: %d\n",
+			result.Linked, result.Failed, result.Skipped, result.Requeued)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if result.Failed > 0 {
+		return 1
 	}
-	return nil
+	return 0
 }
@@ -1,54 +1,254 @@
 package config
 
 import (
-	"log/slog"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
 
-	"github.com/caarlos0/env/v11"
 	"github.com/jackc/pgx/v5/pgxpool"
-	"github.com/joho/godotenv"
 )
 
-type Env struct {
-	DbName     string `env:"DB_NAME"`
-	DbUsername string `env:"DB_USERNAME"`
-	DbPassword string `env:"DB_PASSWORD"`
-	DbPort     int    `env:"DB_PORT"`
-	DbHost     string `env:"DB_HOST"`
-	IpAddress  string `env:"IP_ADDRESS"`
-	ApiPort    int    `env:"API_PORT"`
+// Config собирается в три слоя: значения по умолчанию (Default), файл YAML/TOML
+// и переменные окружения, которые имеют наивысший приоритет
+type Config struct {
+	// development, staging или production
+	Environment string `yaml:"environment" env:"ENVIRONMENT"`
 
-	JWTSecret             string `env:"JWT_SECRET"`
-	JWTExpireHours        int    `env:"JWT_EXPIRE_HOURS"`
-	JWTRefreshExpireHours int    `env:"JWT_REFRESH_EXPIRE_HOURS"`
+	Server    ServerConfig    `yaml:"server"`
+	Database  DatabaseConfig  `yaml:"database"`
+	Auth      AuthConfig      `yaml:"auth"`
+	CORS      CORSConfig      `yaml:"cors"`
+	Security  SecurityConfig  `yaml:"security"`
+	Logging   LoggingConfig   `yaml:"logging"`
+	Metrics   MetricsConfig   `yaml:"metrics"`
+	Tracing   TracingConfig   `yaml:"tracing"`
+	Retention RetentionConfig `yaml:"retention"`
+	Media     MediaConfig     `yaml:"media"`
+	EMR       EMRConfig       `yaml:"emr"`
+	Clinic    ClinicConfig    `yaml:"clinic"`
+	Lab       LabConfig       `yaml:"lab"`
+	Features  FeaturesConfig  `yaml:"features"`
 
-	Environment string `env:"ENVIRONMENT"`
+	Client *pgxpool.Pool `yaml:"-"`
 }
 
-type Config struct {
-	Env    Env
-	Client *pgxpool.Pool
+type ServerConfig struct {
+	Host         string        `yaml:"host" env:"IP_ADDRESS"`
+	Port         int           `yaml:"port" env:"API_PORT"`
+	ReadTimeout  time.Duration `yaml:"read_timeout" env:"SERVER_READ_TIMEOUT"`
+	WriteTimeout time.Duration `yaml:"write_timeout" env:"SERVER_WRITE_TIMEOUT"`
+	IdleTimeout  time.Duration `yaml:"idle_timeout" env:"SERVER_IDLE_TIMEOUT"`
+
+	// Общий срок на все шаги остановки и время, в течение которого сервер
+	// продолжает отвечать после снятия готовности
+	ShutdownGracePeriod time.Duration `yaml:"shutdown_grace_period" env:"SHUTDOWN_GRACE_PERIOD"`
+	ShutdownDrainDelay  time.Duration `yaml:"shutdown_drain_delay" env:"SHUTDOWN_DRAIN_DELAY"`
+}
+
+func (s ServerConfig) Addr() string {
+	return s.Host + ":" + strconv.Itoa(s.Port)
 }
 
-var config Config
+type DatabaseConfig struct {
+	// Полный DSN; если задан, отдельные параметры подключения не используются
+	URL string `yaml:"url" env:"DATABASE_URL"`
 
-func GetConfig() *Config {
-	config.Env = *getEnv()
+	Host     string `yaml:"host" env:"DB_HOST"`
+	Port     int    `yaml:"port" env:"DB_PORT"`
+	Name     string `yaml:"name" env:"DB_NAME"`
+	Username string `yaml:"username" env:"DB_USERNAME"`
+	Password string `yaml:"password" env:"DB_PASSWORD"`
+	SSLMode  string `yaml:"ssl_mode" env:"DB_SSL_MODE"`
 
-	return &config
+	MaxConns        int32         `yaml:"max_conns" env:"DB_MAX_CONNS"`
+	MinConns        int32         `yaml:"min_conns" env:"DB_MIN_CONNS"`
+	MaxConnLifetime time.Duration `yaml:"max_conn_lifetime" env:"DB_MAX_CONN_LIFETIME"`
+	MaxConnIdleTime time.Duration `yaml:"max_conn_idle_time" env:"DB_MAX_CONN_IDLE_TIME"`
 }
 
-func getEnv() *Env {
-	err := godotenv.Load()
-	if err != nil {
-		slog.Warn("No .env file found, using system environment variables")
+// DSN возвращает строку подключения: DATABASE_URL или собранную из параметров
+func (d DatabaseConfig) DSN() string {
+	if d.URL != "" {
+		return d.URL
 	}
 
-	var cfg Env
-	err = env.Parse(&cfg)
-	if err != nil {
-		slog.Error("Failed to parse env: %v", "error", err.Error())
-		panic(err)
+	u := url.URL{
+		Scheme: "postgresql",
+		User:   url.UserPassword(d.Username, d.Password),
+		Host:   fmt.Sprintf("%s:%d", d.Host, d.Port),
+		Path:   "/" + d.Name,
 	}
+	if d.SSLMode != "" {
+		u.RawQuery = url.Values{"sslmode": {d.SSLMode}}.Encode()
+	}
+	return u.String()
+}
+
+type AuthConfig struct {
+	JWTSecret             string `yaml:"jwt_secret" env:"JWT_SECRET"`
+	JWTExpireHours        int    `yaml:"jwt_expire_hours" env:"JWT_EXPIRE_HOURS"`
+	JWTRefreshExpireHours int    `yaml:"jwt_refresh_expire_hours" env:"JWT_REFRESH_EXPIRE_HOURS"`
+}
+
+type CORSConfig struct {
+	// "*" разрешает любые источники, но тогда cookies и Authorization от браузера не передаются
+	AllowedOrigins []string `yaml:"allowed_origins" env:"CORS_ALLOWED_ORIGINS" envSeparator:","`
+}
+
+type SecurityConfig struct {
+	// X-Forwarded-For учитывается только от этих адресов
+	TrustedProxies []string `yaml:"trusted_proxies" env:"TRUSTED_PROXIES" envSeparator:","`
+	HSTSMaxAge     int      `yaml:"hsts_max_age" env:"HSTS_MAX_AGE"`
+
+	// Лимиты по группам маршрутов: группа=запросов_в_минуту:всплеск. Группы, не
+	// указанные в файле или RATE_LIMITS, сохраняют значения по умолчанию
+	RateLimits map[string]string `yaml:"rate_limits" env:"RATE_LIMITS" envSeparator:"," envKeyValSeparator:"="`
+}
+
+type LoggingConfig struct {
+	Level string `yaml:"level" env:"LOG_LEVEL"`
+}
+
+type MetricsConfig struct {
+	Username   string   `yaml:"username" env:"METRICS_USERNAME"`
+	Password   string   `yaml:"password" env:"METRICS_PASSWORD"`
+	AllowedIPs []string `yaml:"allowed_ips" env:"METRICS_ALLOWED_IPS" envSeparator:","`
+}
+
+type TracingConfig struct {
+	ServiceName  string            `yaml:"service_name" env:"OTEL_SERVICE_NAME"`
+	Exporter     string            `yaml:"exporter" env:"OTEL_TRACES_EXPORTER"`
+	SampleRatio  float64           `yaml:"sample_ratio" env:"OTEL_TRACES_SAMPLER_ARG"`
+	OTLPEndpoint string            `yaml:"otlp_endpoint" env:"OTEL_EXPORTER_OTLP_ENDPOINT"`
+	OTLPHeaders  map[string]string `yaml:"otlp_headers" env:"OTEL_EXPORTER_OTLP_HEADERS" envSeparator:"," envKeyValSeparator:"="`
+}
+
+type RetentionConfig struct {
+	SoftDeleteDays int `yaml:"soft_delete_days" env:"SOFT_DELETE_RETENTION_DAYS"`
+}
+
+type MediaConfig struct {
+	// local или s3
+	Storage string `yaml:"storage" env:"MEDIA_STORAGE"`
+	// Максимальный размер загружаемого файла в байтах
+	MaxUploadSize int64    `yaml:"max_upload_size" env:"MEDIA_MAX_UPLOAD_SIZE"`
+	LocalDir      string   `yaml:"local_dir" env:"MEDIA_LOCAL_DIR"`
+	S3            S3Config `yaml:"s3"`
+	// Ширины уменьшенных копий для srcset. Копии шире оригинала не создаются
+	VariantWidths []int `yaml:"variant_widths" env:"MEDIA_VARIANT_WIDTHS" envSeparator:","`
+	// Качество JPEG уменьшенных копий, 1-100
+	VariantQuality int `yaml:"variant_quality" env:"MEDIA_VARIANT_QUALITY"`
+}
+
+// S3Config - S3-совместимое хранилище (AWS S3, MinIO и т.п.)
+type S3Config struct {
+	Endpoint  string `yaml:"endpoint" env:"MEDIA_S3_ENDPOINT"`
+	Region    string `yaml:"region" env:"MEDIA_S3_REGION"`
+	Bucket    string `yaml:"bucket" env:"MEDIA_S3_BUCKET"`
+	AccessKey string `yaml:"access_key" env:"MEDIA_S3_ACCESS_KEY"`
+	SecretKey string `yaml:"secret_key" env:"MEDIA_S3_SECRET_KEY"`
+	// Адресация endpoint/bucket/key вместо bucket.endpoint/key; нужна для MinIO
+	PathStyle bool `yaml:"path_style" env:"MEDIA_S3_PATH_STYLE"`
+}
+
+type EMRConfig struct {
+	// Файл справочника МКБ-10 (код<TAB>название); пустое значение - встроенный список частых диагнозов
+	ICD10File string `yaml:"icd10_file" env:"EMR_ICD10_FILE"`
+}
+
+// ClinicConfig - реквизиты клиники для шапки печатных документов
+type ClinicConfig struct {
+	Name    string `yaml:"name" env:"CLINIC_NAME"`
+	Address string `yaml:"address" env:"CLINIC_ADDRESS"`
+	Phone   string `yaml:"phone" env:"CLINIC_PHONE"`
+	// TrueType-шрифт с кириллицей для PDF (образ Docker задаёт DejaVu Sans);
+	// пустое значение - Helvetica с транслитерацией
+	PDFFontFile string `yaml:"pdf_font_file" env:"CLINIC_PDF_FONT_FILE"`
+}
+
+// LabConfig - приём результатов от лаборатории
+type LabConfig struct {
+	// Токены лабораторий для заголовка X-Lab-Token; пустой список отключает импорт
+	ImportTokens []string `yaml:"import_tokens" env:"LAB_IMPORT_TOKENS" envSeparator:","`
+	// Максимальный размер PDF-бланка результата в байтах
+	MaxAttachmentSize int64 `yaml:"max_attachment_size" env:"LAB_MAX_ATTACHMENT_SIZE"`
+}
+
+type FeaturesConfig struct {
+	RateLimit bool `yaml:"rate_limit" env:"RATE_LIMIT_ENABLED"`
+	Metrics   bool `yaml:"metrics" env:"METRICS_ENABLED"`
+	Swagger   bool `yaml:"swagger" env:"SWAGGER_ENABLED"`
+}
+
+// Default - значения по умолчанию, на которые накладываются файл и окружение
+func Default() *Config {
+	return &Config{
+		Environment: "development",
+		Server: ServerConfig{
+			Host:                "0.0.0.0",
+			Port:                8000,
+			ReadTimeout:         15 * time.Second,
+			WriteTimeout:        15 * time.Second,
+			IdleTimeout:         60 * time.Second,
+			ShutdownGracePeriod: 30 * time.Second,
+		},
+		Database: DatabaseConfig{
+			Port:            5432,
+			SSLMode:         "prefer",
+			MaxConns:        25,
+			MinConns:        5,
+			MaxConnLifetime: 30 * time.Minute,
+			MaxConnIdleTime: 5 * time.Minute,
+		},
+		Auth: AuthConfig{
+			JWTExpireHours:        24,
+			JWTRefreshExpireHours: 168,
+		},
+		CORS: CORSConfig{
+			AllowedOrigins: []string{"http://localhost:4200"},
+		},
+		Security: SecurityConfig{
+			HSTSMaxAge: 31536000,
+			RateLimits: map[string]string{"default": "600:100", "auth": "10:5"},
+		},
+		Logging: LoggingConfig{
+			Level: "info",
+		},
+		Tracing: TracingConfig{
+			ServiceName:  "clinic-backend",
+			Exporter:     "none",
+			SampleRatio:  1,
+			OTLPEndpoint: "http://localhost:4318",
+		},
+		Retention: RetentionConfig{
+			SoftDeleteDays: 1825,
+		},
+		Media: MediaConfig{
+			Storage:        "local",
+			MaxUploadSize:  10 << 20,
+			LocalDir:       "data/media",
+			VariantWidths:  []int{320, 640, 1280},
+			VariantQuality: 82,
+			S3: S3Config{
+				Region:    "us-east-1",
+				PathStyle: true,
+			},
+		},
+		Clinic: ClinicConfig{
+			Name: "Clinic",
+		},
+		Lab: LabConfig{
+			MaxAttachmentSize: 20 << 20,
+		},
+		Features: FeaturesConfig{
+			RateLimit: true,
+			Metrics:   true,
+			Swagger:   true,
+		},
+	}
+}
 
-	return &cfg
+func (c *Config) IsProduction() bool {
+	return c.Environment == "production"
 }
@@ -0,0 +1,148 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// Интервал проверки файла конфигурации на изменения
+const watchInterval = 5 * time.Second
+
+// Holder хранит текущий снимок конфигурации и подменяет его атомарно при перезагрузке.
+// Снимки неизменяемы: потребители получают их через Get на каждый запрос и не
+// сохраняют у себя, а производные значения пересобирают в обработчиках OnReload.
+type Holder struct {
+	path    string
+	current atomic.Pointer[Config]
+
+	mu        sync.Mutex
+	listeners []func(*Config)
+}
+
+func NewHolder(path string, cfg *Config) *Holder {
+	h := &Holder{path: path}
+	h.current.Store(cfg)
+	return h
+}
+
+// Get возвращает текущий снимок конфигурации
+func (h *Holder) Get() *Config {
+	return h.current.Load()
+}
+
+// OnReload регистрирует обработчик, вызываемый с новым снимком после каждой
+// успешной перезагрузки
+func (h *Holder) OnReload(fn func(*Config)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.listeners = append(h.listeners, fn)
+}
+
+// Reload перечитывает файл и окружение. При ошибке текущий снимок остаётся в силе.
+// Параметры, которые применяются только при старте (сервер, БД, трассировка и т.п.),
+// переносятся из текущего снимка, а их изменения попадают в лог как требующие рестарта.
+func (h *Holder) Reload() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	next, err := Load(h.path)
+	if err != nil {
+		slog.Error("Config reload failed, keeping current configuration", "error", err.Error())
+		return err
+	}
+
+	prev := h.Get()
+	if changed := next.keepStatic(prev); len(changed) > 0 {
+		slog.Warn("Config changes require restart and were not applied", "fields", changed)
+	}
+
+	h.current.Store(next)
+	for _, fn := range h.listeners {
+		fn(next)
+	}
+
+	slog.Info("Configuration reloaded")
+	return nil
+}
+
+// Watch перезагружает конфигурацию по SIGHUP и при изменении файла.
+// Блокируется до отмены ctx.
+func (h *Holder) Watch(ctx context.Context) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+
+	lastMod := h.fileVersion()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			slog.Info("SIGHUP received, reloading configuration")
+			lastMod = h.fileVersion()
+			_ = h.Reload()
+		case <-ticker.C:
+			if h.path == "" {
+				continue
+			}
+			if mod := h.fileVersion(); mod != lastMod {
+				lastMod = mod
+				slog.Info("Config file changed, reloading configuration", "path", h.path)
+				_ = h.Reload()
+			}
+		}
+	}
+}
+
+// fileVersion - время изменения и размер файла; пустая строка, если файла нет
+func (h *Holder) fileVersion() string {
+	if h.path == "" {
+		return ""
+	}
+	info, err := os.Stat(h.path)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%d/%d", info.ModTime().UnixNano(), info.Size())
+}
+
+// keepStatic переносит из prev параметры, применяемые только при старте,
+// и возвращает имена тех из них, что изменились
+func (c *Config) keepStatic(prev *Config) []string {
+	var changed []string
+	keep(&changed, "environment", &c.Environment, prev.Environment)
+	keep(&changed, "server", &c.Server, prev.Server)
+	keep(&changed, "database", &c.Database, prev.Database)
+	keep(&changed, "tracing", &c.Tracing, prev.Tracing)
+	keep(&changed, "retention", &c.Retention, prev.Retention)
+	keep(&changed, "media.storage", &c.Media.Storage, prev.Media.Storage)
+	keep(&changed, "media.local_dir", &c.Media.LocalDir, prev.Media.LocalDir)
+	keep(&changed, "media.s3", &c.Media.S3, prev.Media.S3)
+	keep(&changed, "emr", &c.EMR, prev.EMR)
+	keep(&changed, "clinic.pdf_font_file", &c.Clinic.PDFFontFile, prev.Clinic.PDFFontFile)
+	keep(&changed, "security.trusted_proxies", &c.Security.TrustedProxies, prev.Security.TrustedProxies)
+	keep(&changed, "features.metrics", &c.Features.Metrics, prev.Features.Metrics)
+	keep(&changed, "features.swagger", &c.Features.Swagger, prev.Features.Swagger)
+
+	c.Client = prev.Client
+	return changed
+}
+
+func keep[T any](changed *[]string, name string, next *T, old T) {
+	if !reflect.DeepEqual(*next, old) {
+		*changed = append(*changed, name)
+		*next = old
+	}
+}
@@ -0,0 +1,148 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"maps"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/caarlos0/env/v11"
+	"github.com/goccy/go-yaml"
+	"github.com/joho/godotenv"
+	"github.com/pelletier/go-toml/v2"
+)
+
+// Load собирает конфигурацию: значения по умолчанию, затем файл path (если задан),
+// затем переменные окружения (включая .env). Возвращает ошибку, если итоговая
+// конфигурация некорректна.
+func Load(path string) (*Config, error) {
+	if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to load .env: %w", err)
+	}
+
+	cfg := Default()
+	limits := maps.Clone(cfg.Security.RateLimits)
+
+	if path != "" {
+		if err := loadFile(path, cfg); err != nil {
+			return nil, err
+		}
+		limits = mergeRateLimits(cfg, limits)
+		slog.Info("Config file loaded", "path", path)
+	}
+
+	if err := env.Parse(cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse environment: %w", err)
+	}
+	mergeRateLimits(cfg, limits)
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// loadFile накладывает файл на cfg. TOML сначала разбирается в map и
+// перекодируется в YAML, чтобы оба формата использовали одни теги и
+// одинаково разбирали длительности вида "30s".
+func loadFile(path string, cfg *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+	case ".toml":
+		var raw map[string]any
+		if err := toml.Unmarshal(data, &raw); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		if data, err = yaml.Marshal(raw); err != nil {
+			return fmt.Errorf("failed to convert %s: %w", path, err)
+		}
+	default:
+		return fmt.Errorf("unsupported config file format %q, expected .yaml, .yml or .toml", filepath.Ext(path))
+	}
+
+	if err := yaml.UnmarshalWithOptions(data, cfg, yaml.Strict()); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return nil
+}
+
+// Redacted возвращает копию конфигурации без секретов, для вывода и отладки
+func (c *Config) Redacted() *Config {
+	const mask = "[REDACTED]"
+
+	out := *c
+	out.Client = nil
+
+	if out.Database.Password != "" {
+		out.Database.Password = mask
+	}
+	if out.Database.URL != "" {
+		out.Database.URL = redactURL(out.Database.URL)
+	}
+	if out.Auth.JWTSecret != "" {
+		out.Auth.JWTSecret = mask
+	}
+	if out.Metrics.Password != "" {
+		out.Metrics.Password = mask
+	}
+	if out.Media.S3.SecretKey != "" {
+		out.Media.S3.SecretKey = mask
+	}
+	if len(out.Tracing.OTLPHeaders) > 0 {
+		headers := make(map[string]string, len(out.Tracing.OTLPHeaders))
+		for key := range out.Tracing.OTLPHeaders {
+			headers[key] = mask
+		}
+		out.Tracing.OTLPHeaders = headers
+	}
+	if len(out.Lab.ImportTokens) > 0 {
+		tokens := make([]string, len(out.Lab.ImportTokens))
+		for i := range tokens {
+			tokens[i] = mask
+		}
+		out.Lab.ImportTokens = tokens
+	}
+
+	return &out
+}
+
+// YAML сериализует конфигурацию (обычно результат Redacted)
+func (c *Config) YAML() ([]byte, error) {
+	return yaml.Marshal(c)
+}
+
+// redactURL скрывает пароль в DSN
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "[REDACTED]"
+	}
+	if _, ok := u.User.Password(); ok {
+		u.User = url.UserPassword(u.User.Username(), "REDACTED")
+	}
+	return u.String()
+}
+
+// mergeRateLimits накладывает лимиты из очередного источника на прежние. Файл и
+// RATE_LIMITS заменяют map целиком, и без этого, например, RATE_LIMITS=default=...
+// убрал бы лимит auth.
+func mergeRateLimits(cfg *Config, prev map[string]string) map[string]string {
+	merged := maps.Clone(prev)
+	if merged == nil {
+		merged = make(map[string]string, len(cfg.Security.RateLimits))
+	}
+	maps.Copy(merged, cfg.Security.RateLimits)
+	cfg.Security.RateLimits = merged
+	return merged
+}
@@ -0,0 +1,173 @@
+package config
+
+import (
+	"Clinic_backend/internal/logging"
+	"Clinic_backend/internal/ratelimit"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+)
+
+const minProductionSecretLen = 32
+
+var (
+	environments = []string{"development", "staging", "production"}
+	sslModes     = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+	exporters    = []string{"none", "stdout", "otlp"}
+	mediaStores  = []string{"local", "s3"}
+)
+
+// Validate проверяет конфигурацию целиком и возвращает все найденные ошибки сразу
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(slices.Contains(environments, c.Environment),
+		"environment: must be one of %s, got %q", strings.Join(environments, ", "), c.Environment)
+
+	// server
+	check(c.Server.Port > 0 && c.Server.Port <= 65535, "server.port (API_PORT): must be between 1 and 65535, got %d", c.Server.Port)
+	check(c.Server.ReadTimeout > 0, "server.read_timeout (SERVER_READ_TIMEOUT): must be positive")
+	check(c.Server.WriteTimeout > 0, "server.write_timeout (SERVER_WRITE_TIMEOUT): must be positive")
+	check(c.Server.IdleTimeout > 0, "server.idle_timeout (SERVER_IDLE_TIMEOUT): must be positive")
+	check(c.Server.ShutdownGracePeriod > 0, "server.shutdown_grace_period (SHUTDOWN_GRACE_PERIOD): must be positive")
+	check(c.Server.ShutdownDrainDelay >= 0 && c.Server.ShutdownDrainDelay < c.Server.ShutdownGracePeriod,
+		"server.shutdown_drain_delay (SHUTDOWN_DRAIN_DELAY): must be non-negative and shorter than the grace period")
+
+	// database
+	db := c.Database
+	if db.URL != "" {
+		u, err := url.Parse(db.URL)
+		check(err == nil && (u.Scheme == "postgres" || u.Scheme == "postgresql") && u.Host != "",
+			"database.url (DATABASE_URL): must be a postgres:// or postgresql:// URL")
+	} else {
+		check(db.Host != "", "database.host (DB_HOST): required when DATABASE_URL is not set")
+		check(db.Name != "", "database.name (DB_NAME): required when DATABASE_URL is not set")
+		check(db.Username != "", "database.username (DB_USERNAME): required when DATABASE_URL is not set")
+		check(db.Port > 0 && db.Port <= 65535, "database.port (DB_PORT): must be between 1 and 65535, got %d", db.Port)
+		check(slices.Contains(sslModes, db.SSLMode),
+			"database.ssl_mode (DB_SSL_MODE): must be one of %s, got %q", strings.Join(sslModes, ", "), db.SSLMode)
+	}
+	check(db.MaxConns >= 1, "database.max_conns (DB_MAX_CONNS): must be at least 1")
+	check(db.MinConns >= 0 && db.MinConns <= db.MaxConns, "database.min_conns (DB_MIN_CONNS): must be between 0 and max_conns")
+	check(db.MaxConnLifetime > 0, "database.max_conn_lifetime (DB_MAX_CONN_LIFETIME): must be positive")
+	check(db.MaxConnIdleTime > 0, "database.max_conn_idle_time (DB_MAX_CONN_IDLE_TIME): must be positive")
+	if c.IsProduction() && db.URL == "" {
+		check(db.SSLMode != "disable", "database.ssl_mode (DB_SSL_MODE): must not be disable in production")
+	}
+
+	// auth
+	check(c.Auth.JWTSecret != "", "auth.jwt_secret (JWT_SECRET): required")
+	if c.IsProduction() {
+		check(len(c.Auth.JWTSecret) >= minProductionSecretLen,
+			"auth.jwt_secret (JWT_SECRET): must be at least %d characters in production", minProductionSecretLen)
+	}
+	check(c.Auth.JWTExpireHours > 0, "auth.jwt_expire_hours (JWT_EXPIRE_HOURS): must be positive")
+	check(c.Auth.JWTRefreshExpireHours > 0, "auth.jwt_refresh_expire_hours (JWT_REFRESH_EXPIRE_HOURS): must be positive")
+
+	// cors
+	for _, origin := range c.CORS.AllowedOrigins {
+		if origin == "*" {
+			continue
+		}
+		u, err := url.Parse(origin)
+		check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" && (u.Path == "" || u.Path == "/"),
+			"cors.allowed_origins (CORS_ALLOWED_ORIGINS): %q is not a valid origin, expected scheme://host[:port]", origin)
+	}
+
+	// security
+	for _, proxy := range c.Security.TrustedProxies {
+		check(validIPOrCIDR(proxy), "security.trusted_proxies (TRUSTED_PROXIES): %q is not an IP or CIDR", proxy)
+	}
+	check(c.Security.HSTSMaxAge >= 0, "security.hsts_max_age (HSTS_MAX_AGE): must not be negative")
+	if _, err := ratelimit.ParseLimits(c.Security.RateLimits); err != nil {
+		errs = append(errs, fmt.Errorf("security.rate_limits (RATE_LIMITS): %w", err))
+	}
+
+	// logging
+	if _, err := logging.ParseLevel(c.Logging.Level); err != nil {
+		errs = append(errs, fmt.Errorf("logging.level (LOG_LEVEL): %w", err))
+	}
+
+	// metrics
+	check(c.Metrics.Username == "" || c.Metrics.Password != "",
+		"metrics.password (METRICS_PASSWORD): required when metrics.username is set")
+	for _, ip := range c.Metrics.AllowedIPs {
+		check(validIPOrCIDR(ip), "metrics.allowed_ips (METRICS_ALLOWED_IPS): %q is not an IP or CIDR", ip)
+	}
+
+	// tracing
+	check(slices.Contains(exporters, strings.ToLower(c.Tracing.Exporter)),
+		"tracing.exporter (OTEL_TRACES_EXPORTER): must be one of %s, got %q", strings.Join(exporters, ", "), c.Tracing.Exporter)
+	check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1,
+		"tracing.sample_ratio (OTEL_TRACES_SAMPLER_ARG): must be between 0 and 1")
+	if strings.EqualFold(c.Tracing.Exporter, "otlp") {
+		u, err := url.Parse(c.Tracing.OTLPEndpoint)
+		check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "",
+			"tracing.otlp_endpoint (OTEL_EXPORTER_OTLP_ENDPOINT): must be an http(s) URL")
+	}
+
+	// retention
+	check(c.Retention.SoftDeleteDays >= 1, "retention.soft_delete_days (SOFT_DELETE_RETENTION_DAYS): must be at least 1")
+
+	// media
+	media := c.Media
+	check(slices.Contains(mediaStores, media.Storage),
+		"media.storage (MEDIA_STORAGE): must be one of %s, got %q", strings.Join(mediaStores, ", "), media.Storage)
+	check(media.MaxUploadSize > 0, "media.max_upload_size (MEDIA_MAX_UPLOAD_SIZE): must be positive")
+	for i, width := range media.VariantWidths {
+		check(width >= 16 && width <= 4096,
+			"media.variant_widths (MEDIA_VARIANT_WIDTHS): width must be between 16 and 4096, got %d", width)
+		check(!slices.Contains(media.VariantWidths[:i], width),
+			"media.variant_widths (MEDIA_VARIANT_WIDTHS): duplicate width %d", width)
+	}
+	check(media.VariantQuality >= 1 && media.VariantQuality <= 100,
+		"media.variant_quality (MEDIA_VARIANT_QUALITY): must be between 1 and 100")
+	switch media.Storage {
+	case "local":
+		check(media.LocalDir != "", "media.local_dir (MEDIA_LOCAL_DIR): required for local storage")
+	case "s3":
+		u, err := url.Parse(media.S3.Endpoint)
+		check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "",
+			"media.s3.endpoint (MEDIA_S3_ENDPOINT): must be an http(s) URL")
+		check(media.S3.Region != "", "media.s3.region (MEDIA_S3_REGION): required for s3 storage")
+		check(media.S3.Bucket != "", "media.s3.bucket (MEDIA_S3_BUCKET): required for s3 storage")
+		check(media.S3.AccessKey != "" && media.S3.SecretKey != "",
+			"media.s3.access_key, media.s3.secret_key (MEDIA_S3_ACCESS_KEY, MEDIA_S3_SECRET_KEY): required for s3 storage")
+	}
+
+	// clinic
+	if c.Clinic.PDFFontFile != "" {
+		info, err := os.Stat(c.Clinic.PDFFontFile)
+		check(err == nil && info.Mode().IsRegular(),
+			"clinic.pdf_font_file (CLINIC_PDF_FONT_FILE): %q is not a readable file", c.Clinic.PDFFontFile)
+	}
+
+	// lab
+	check(c.Lab.MaxAttachmentSize > 0, "lab.max_attachment_size (LAB_MAX_ATTACHMENT_SIZE): must be positive")
+	for _, token := range c.Lab.ImportTokens {
+		check(len(token) >= 16, "lab.import_tokens (LAB_IMPORT_TOKENS): tokens must be at least 16 characters")
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid configuration:\n%w", errors.Join(errs...))
+	}
+	return nil
+}
+
+func validIPOrCIDR(value string) bool {
+	value = strings.TrimSpace(value)
+	if strings.Contains(value, "/") {
+		_, _, err := net.ParseCIDR(value)
+		return err == nil
+	}
+	return net.ParseIP(value) != nil
+}
@@ -0,0 +1,90 @@
+package entity
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+const (
+	AuditEntityDoctor          = "doctor"
+	AuditEntityService         = "service"
+	AuditEntityServiceCategory = "service_category"
+	AuditEntitySpecialization  = "specialization"
+	AuditEntityLicense         = "license"
+	AuditEntityCarousel        = "carousel"
+	AuditEntitySchedule        = "schedule"
+	AuditEntityUser            = "user"
+	AuditEntityPatientProfile  = "patient_profile"
+	AuditEntityPatientGuardian = "patient_guardian"
+	AuditEntityTreatingDoctor  = "treating_doctor"
+	AuditEntityEncounter       = "encounter"
+	AuditEntityPrescription    = "prescription"
+	AuditEntityLabOrder        = "lab_order"
+	AuditEntityDataRequest     = "data_request"
+	AuditEntityConsentDocument = "consent_document"
+	AuditEntityDoctorReview    = "doctor_review"
+)
+
+const (
+	AuditActionCreate  = "create"
+	AuditActionUpdate  = "update"
+	AuditActionDelete  = "delete"
+	AuditActionRestore = "restore"
+	AuditActionSign    = "sign"
+	AuditActionAmend   = "amend"
+	AuditActionErase   = "erase"
+	AuditActionPublish = "publish"
+	// Чтение персональных данных внешней системой (FHIR)
+	AuditActionRead = "read"
+)
+
+type AuditLog struct {
+	ID         int64           `json:"id"`
+	ActorID    *int            `json:"actor_id"`
+	IP         string          `json:"ip"`
+	EntityType string          `json:"entity_type"`
+	EntityID   int             `json:"entity_id"`
+	Action     string          `json:"action"`
+	Before     json.RawMessage `json:"before,omitempty" swaggertype:"object"`
+	After      json.RawMessage `json:"after,omitempty" swaggertype:"object"`
+	RequestID  string          `json:"request_id"`
+	PrevHash   string          `json:"prev_hash"`
+	Hash       string          `json:"hash"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+type AuditLogFilter struct {
+	ActorID    *int       `form:"actor_id"`
+	EntityType string     `form:"entity_type"`
+	EntityID   *int       `form:"entity_id"`
+	Action     string     `form:"action"`
+	From       *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To         *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Page       int        `form:"page"`
+	Limit      int        `form:"limit"`
+}
+
+type AuditVerifyResult struct {
+	Valid    bool   `json:"valid"`
+	Checked  int    `json:"checked"`
+	BrokenAt *int64 `json:"broken_at,omitempty"`
+}
+
+// Actor - инициатор изменения, переносится в контексте запроса до сервисного слоя
+type Actor struct {
+	UserID    *int
+	IP        string
+	RequestID string
+}
+
+type actorKey struct{}
+
+func ContextWithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+func ActorFromContext(ctx context.Context) Actor {
+	actor, _ := ctx.Value(actorKey{}).(Actor)
+	return actor
+}
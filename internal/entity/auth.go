@@ -4,6 +4,8 @@ type AuthResponse struct {
 	Token        string        `json:"token"`
 	RefreshToken string        `json:"refresh_token"`
 	User         *UserResponse `json:"user"`
+	// Новые версии обязательных документов, с которыми пользователь ещё не согласился
+	PendingConsents []ConsentDocument `json:"pending_consents,omitempty"`
 }
 
 type RefreshTokenRequest struct {
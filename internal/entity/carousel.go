@@ -3,10 +3,14 @@ package entity
 import "time"
 
 type Carousel struct {
-	ID          int       `json:"id"`
-	Image       *string   `json:"image"`
-	Header      *string   `json:"header"`
-	Description *string   `json:"description"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          int        `json:"id"`
+	Image       *string    `json:"image"`
+	ImageID     *string    `json:"image_id"`
+	ImageSrcset Srcset     `json:"image_srcset,omitempty"`
+	Header      *string    `json:"header"`
+	Description *string    `json:"description"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	Version     int        `json:"version"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
 }
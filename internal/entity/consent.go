@@ -0,0 +1,76 @@
+package entity
+
+import "time"
+
+const (
+	ConsentPrivacyPolicy = "privacy_policy"
+	ConsentPersonalData  = "personal_data"
+	ConsentMarketing     = "marketing"
+)
+
+// ConsentTypes - типы документов в порядке показа пользователю
+var ConsentTypes = []string{ConsentPrivacyPolicy, ConsentPersonalData, ConsentMarketing}
+
+// ConsentMandatory сообщает, обязательно ли согласие: без политики конфиденциальности и
+// согласия на обработку данных сервисом пользоваться нельзя, рассылки - по желанию
+func ConsentMandatory(consentType string) bool {
+	return consentType != ConsentMarketing
+}
+
+// ConsentDocument - версия документа, с которой соглашается пользователь.
+// Черновик (без published_at) виден только администратору
+type ConsentDocument struct {
+	ID          int        `json:"id"`
+	Type        string     `json:"type" enums:"privacy_policy,personal_data,marketing"`
+	Version     int        `json:"version"`
+	Title       string     `json:"title"`
+	Content     string     `json:"content,omitempty"`
+	Mandatory   bool       `json:"mandatory"`
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+type ConsentDocumentRequest struct {
+	Type    string `json:"type" binding:"required,oneof=privacy_policy personal_data marketing"`
+	Title   string `json:"title" binding:"required,max=200"`
+	Content string `json:"content" binding:"required,max=200000"`
+}
+
+type ConsentDocumentUpdateRequest struct {
+	Title   string `json:"title" binding:"required,max=200"`
+	Content string `json:"content" binding:"required,max=200000"`
+}
+
+type ConsentDocumentFilter struct {
+	Type string `form:"type" binding:"omitempty,oneof=privacy_policy personal_data marketing"`
+}
+
+// UserConsent - согласие пользователя с версией документа и адрес, с которого оно дано
+type UserConsent struct {
+	ID              int        `json:"id"`
+	UserID          int        `json:"user_id"`
+	DocumentID      int        `json:"document_id"`
+	Type            string     `json:"type"`
+	DocumentVersion int        `json:"document_version"`
+	AcceptedAt      time.Time  `json:"accepted_at"`
+	IP              string     `json:"ip"`
+	WithdrawnAt     *time.Time `json:"withdrawn_at,omitempty"`
+	WithdrawnIP     *string    `json:"withdrawn_ip,omitempty"`
+}
+
+// ConsentStatus - состояние согласия пользователя по типу документа. Reconsent - нужно
+// согласиться с действующей версией: документ обязательный или пользователь соглашался
+// с его прежней версией и не отзывал согласие
+type ConsentStatus struct {
+	Type      string           `json:"type"`
+	Mandatory bool             `json:"mandatory"`
+	Current   *ConsentDocument `json:"current,omitempty"`
+	Accepted  *UserConsent     `json:"accepted,omitempty"`
+	UpToDate  bool             `json:"up_to_date"`
+	Reconsent bool             `json:"reconsent"`
+}
+
+type ConsentAcceptRequest struct {
+	DocumentIDs []int `json:"document_ids" binding:"required,min=1,max=10,dive,min=1"`
+}
@@ -3,22 +3,32 @@ package entity
 import "time"
 
 type Doctor struct {
-	ID              int              `json:"id"`
-	Fullname        string           `json:"fullname" binding:"required"`
-	Description     *string          `json:"description"`
-	DoctorPhoto     *string          `json:"doctor_photo"`
-	ScheduleID      *int             `json:"schedule_id"`
-	Schedule        *Schedule        `json:"schedule,omitempty"`
-	Specializations []Specialization `json:"specializations,omitempty"`
-	CreatedAt       time.Time        `json:"created_at"`
-	UpdatedAt       time.Time        `json:"updated_at"`
+	ID                int              `json:"id"`
+	Fullname          string           `json:"fullname" binding:"required"`
+	Description       *string          `json:"description"`
+	DoctorPhoto       *string          `json:"doctor_photo"`
+	DoctorPhotoID     *string          `json:"doctor_photo_id"`
+	DoctorPhotoSrcset Srcset           `json:"doctor_photo_srcset,omitempty"`
+	ScheduleID        *int             `json:"schedule_id"`
+	UserID            *int             `json:"user_id,omitempty"`
+	Schedule          *Schedule        `json:"schedule,omitempty"`
+	Specializations   []Specialization `json:"specializations,omitempty"`
+	// Средняя оценка по одобренным отзывам; null, пока отзывов нет
+	RatingAverage     *float64         `json:"rating_average"`
+	RatingCount       int              `json:"rating_count"`
+	CreatedAt         time.Time        `json:"created_at"`
+	UpdatedAt         time.Time        `json:"updated_at"`
+	Version           int              `json:"version"`
+	DeletedAt         *time.Time       `json:"deleted_at,omitempty"`
 }
 
 type DoctorCreateRequest struct {
 	Fullname           string  `json:"fullname" binding:"required"`
 	Description        *string `json:"description"`
 	DoctorPhoto        *string `json:"doctor_photo"`
+	DoctorPhotoID      *string `json:"doctor_photo_id"`
 	ScheduleID         *int    `json:"schedule_id"`
+	UserID             *int    `json:"user_id"`
 	SpecializationIDs  []int   `json:"specialization_ids"`
 }
 
@@ -26,6 +36,9 @@ type DoctorUpdateRequest struct {
 	Fullname           *string `json:"fullname"`
 	Description        *string `json:"description"`
 	DoctorPhoto        *string `json:"doctor_photo"`
+	DoctorPhotoID      *string `json:"doctor_photo_id"`
 	ScheduleID         *int    `json:"schedule_id"`
+	// Учётная запись врача; 0 отвязывает
+	UserID             *int    `json:"user_id"`
 	SpecializationIDs  []int   `json:"specialization_ids"`
 }
@@ -0,0 +1,96 @@
+package entity
+
+import "time"
+
+const (
+	EncounterStatusDraft  = "draft"
+	EncounterStatusSigned = "signed"
+)
+
+const (
+	DiagnosisPrimary      = "primary"
+	DiagnosisSecondary    = "secondary"
+	DiagnosisComplication = "complication"
+)
+
+// Diagnosis - диагноз по МКБ-10. Название берётся из справочника на момент записи
+type Diagnosis struct {
+	Code  string `json:"code" example:"J06.9"`
+	Title string `json:"title"`
+	Kind  string `json:"kind" enums:"primary,secondary,complication"`
+}
+
+// EncounterContent - клиническое содержание записи о приёме
+type EncounterContent struct {
+	EncounterDate   string      `json:"encounter_date" example:"2026-10-19"`
+	Complaints      *string     `json:"complaints,omitempty"`
+	Anamnesis       *string     `json:"anamnesis,omitempty"`
+	Examination     *string     `json:"examination,omitempty"`
+	Diagnoses       []Diagnosis `json:"diagnoses,omitempty"`
+	Recommendations *string     `json:"recommendations,omitempty"`
+}
+
+// Encounter - запись о приёме в медицинской карте. Version - версия для
+// оптимистичной блокировки, Revision - номер подписанной редакции (0 у черновика).
+type Encounter struct {
+	ID           int    `json:"id"`
+	PatientID    int    `json:"patient_id"`
+	PatientName  string `json:"patient_name"`
+	DoctorUserID int    `json:"doctor_user_id"`
+	DoctorName   string `json:"doctor_name"`
+	EncounterContent
+	Status      string     `json:"status" enums:"draft,signed"`
+	Revision    int        `json:"revision"`
+	ContentHash *string    `json:"content_hash,omitempty"`
+	SignedAt    *time.Time `json:"signed_at,omitempty"`
+	Version     int        `json:"version"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// EncounterRevision - неизменяемая подписанная редакция записи
+type EncounterRevision struct {
+	EncounterID  int     `json:"encounter_id"`
+	Revision     int     `json:"revision"`
+	AuthorUserID int     `json:"author_user_id"`
+	Reason       *string `json:"reason,omitempty"`
+	EncounterContent
+	ContentHash string    `json:"content_hash"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type DiagnosisRequest struct {
+	Code string `json:"code" binding:"required" example:"J06.9"`
+	Kind string `json:"kind" binding:"required,oneof=primary secondary complication"`
+}
+
+type EncounterContentRequest struct {
+	EncounterDate   string             `json:"encounter_date" binding:"required" example:"2026-10-19"`
+	Complaints      *string            `json:"complaints"`
+	Anamnesis       *string            `json:"anamnesis"`
+	Examination     *string            `json:"examination"`
+	Diagnoses       []DiagnosisRequest `json:"diagnoses" binding:"dive"`
+	Recommendations *string            `json:"recommendations"`
+}
+
+type EncounterCreateRequest struct {
+	PatientID int `json:"patient_id" binding:"required"`
+	EncounterContentRequest
+}
+
+// EncounterAmendmentRequest - новая редакция подписанной записи целиком и причина изменения
+type EncounterAmendmentRequest struct {
+	EncounterContentRequest
+	Reason string `json:"reason" binding:"required"`
+}
+
+// EncounterFilter - условия выборки записей. ViewerUserID заполняет сервис:
+// чужие черновики в выборку не попадают
+type EncounterFilter struct {
+	PatientID    *int   `form:"patient_id"`
+	DoctorUserID *int   `form:"doctor_user_id"`
+	Status       string `form:"status"`
+	Page         int    `form:"page"`
+	Limit        int    `form:"limit"`
+	ViewerUserID int    `form:"-"`
+}
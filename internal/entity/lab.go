@@ -0,0 +1,108 @@
+package entity
+
+import "time"
+
+const (
+	LabOrderStatusOrdered    = "ordered"
+	LabOrderStatusSampled    = "sampled"
+	LabOrderStatusInProgress = "in_progress"
+	LabOrderStatusReady      = "ready"
+)
+
+// LabTest - назначенное исследование. Код совпадает с кодом показателя в результатах лаборатории
+type LabTest struct {
+	Code string `json:"code" binding:"required,max=32" example:"HGB"`
+	Name string `json:"name" binding:"required,max=200" example:"Гемоглобин"`
+}
+
+// LabOrder - направление на лабораторные анализы
+type LabOrder struct {
+	ID          int        `json:"id"`
+	PatientID   int        `json:"patient_id"`
+	PatientName string     `json:"patient_name"`
+	DoctorID    int        `json:"doctor_id"`
+	DoctorName  string     `json:"doctor_name"`
+	EncounterID *int       `json:"encounter_id,omitempty"`
+	Tests       []LabTest  `json:"tests"`
+	Comment     *string    `json:"comment,omitempty"`
+	Status      string     `json:"status" enums:"ordered,sampled,in_progress,ready"`
+	SampledAt   *time.Time `json:"sampled_at,omitempty"`
+	ReadyAt     *time.Time `json:"ready_at,omitempty"`
+	// Результаты и бланки заполняются только при получении одного направления
+	Results     []LabResult     `json:"results,omitempty"`
+	Attachments []LabAttachment `json:"attachments,omitempty"`
+	Version     int             `json:"version"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+// LabResult - значение показателя. Flag и OutOfRange вычисляются по референсному интервалу,
+// если лаборатория не указала флаг сама
+type LabResult struct {
+	ID             int        `json:"id"`
+	OrderID        int        `json:"order_id"`
+	TestCode       string     `json:"test_code"`
+	TestName       string     `json:"test_name"`
+	Value          string     `json:"value"`
+	Unit           *string    `json:"unit,omitempty"`
+	ReferenceRange *string    `json:"reference_range,omitempty" example:"120-160"`
+	Flag           *string    `json:"flag,omitempty" enums:"normal,low,high,abnormal"`
+	OutOfRange     bool       `json:"out_of_range"`
+	Final          bool       `json:"final"`
+	ObservedAt     *time.Time `json:"observed_at,omitempty"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// LabAttachment - PDF-бланк результата
+type LabAttachment struct {
+	ID         int       `json:"id"`
+	OrderID    int       `json:"order_id"`
+	BlobKey    string    `json:"-"`
+	Filename   string    `json:"filename"`
+	Size       int64     `json:"size"`
+	UploadedBy *int      `json:"uploaded_by,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type LabOrderCreateRequest struct {
+	PatientID   int       `json:"patient_id" binding:"required"`
+	EncounterID *int      `json:"encounter_id"`
+	Tests       []LabTest `json:"tests" binding:"required,min=1,max=50,dive"`
+	Comment     *string   `json:"comment" binding:"omitempty,max=1000"`
+}
+
+type LabStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=sampled in_progress ready"`
+}
+
+type LabResultRequest struct {
+	TestCode string `json:"test_code" binding:"required,max=32"`
+	// По умолчанию - название назначенного исследования
+	TestName       string  `json:"test_name" binding:"max=200"`
+	Value          string  `json:"value" binding:"required,max=200"`
+	Unit           *string `json:"unit" binding:"omitempty,max=50"`
+	ReferenceRange *string `json:"reference_range" binding:"omitempty,max=100"`
+	// Флаг лаборатории (H, L, A, N); без него вычисляется по интервалу
+	Flag       *string    `json:"flag" binding:"omitempty,max=16"`
+	Final      *bool      `json:"final"`
+	ObservedAt *time.Time `json:"observed_at"`
+}
+
+type LabResultsRequest struct {
+	Results []LabResultRequest `json:"results" binding:"required,min=1,max=200,dive"`
+}
+
+type LabOrderFilter struct {
+	PatientID *int   `form:"patient_id"`
+	DoctorID  *int   `form:"doctor_id"`
+	Status    string `form:"status" binding:"omitempty,oneof=ordered sampled in_progress ready"`
+	Page      int    `form:"page"`
+	Limit     int    `form:"limit"`
+}
+
+// LabImportSummary - итог импорта файла лаборатории
+type LabImportSummary struct {
+	Orders      []int `json:"orders"`
+	Results     int   `json:"results"`
+	Attachments int   `json:"attachments"`
+}
@@ -3,10 +3,14 @@ package entity
 import "time"
 
 type License struct {
-	ID          int       `json:"id"`
-	Photo       *string   `json:"photo"`
-	Name        *string   `json:"name"`
-	Description *string   `json:"description"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          int        `json:"id"`
+	Photo       *string    `json:"photo"`
+	PhotoID     *string    `json:"photo_id"`
+	PhotoSrcset Srcset     `json:"photo_srcset,omitempty"`
+	Name        *string    `json:"name"`
+	Description *string    `json:"description"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	Version     int        `json:"version"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
 }
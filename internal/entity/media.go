@@ -0,0 +1,76 @@
+package entity
+
+import (
+	"strconv"
+	"time"
+)
+
+// Состояние генерации уменьшенных копий изображения
+const (
+	MediaVariantsPending     = "pending"
+	MediaVariantsDone        = "done"
+	MediaVariantsFailed      = "failed"
+	MediaVariantsUnsupported = "unsupported"
+)
+
+// Media - загруженное изображение. ID - SHA-256 содержимого
+type Media struct {
+	ID             string    `json:"id"`
+	URL            string    `json:"url"`
+	ContentType    string    `json:"content_type"`
+	Size           int64     `json:"size"`
+	Width          int       `json:"width"`
+	Height         int       `json:"height"`
+	OriginalName   string    `json:"original_name"`
+	UploadedBy     *int      `json:"uploaded_by"`
+	VariantsStatus string    `json:"variants_status"`
+	Srcset         Srcset    `json:"srcset,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// MediaVariant - уменьшенная копия изображения заданной ширины
+type MediaVariant struct {
+	MediaID     string `json:"media_id"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+}
+
+// MediaUploadRequest - загрузка изображения в JSON: data URI или base64-строка
+type MediaUploadRequest struct {
+	Data     string `json:"data" binding:"required"`
+	Filename string `json:"filename"`
+}
+
+// Srcset - адреса изображения по ширине для атрибута srcset:
+// {"320w": "/media/{id}?w=320", ..., "1920w": "/media/{id}"}. Оригинал входит в набор.
+type Srcset map[string]string
+
+// MediaPhotoRef - изображение записи каталога, заданное строкой (URL или data URI)
+type MediaPhotoRef struct {
+	EntityType string
+	EntityID   int
+	// Поле со строковой ссылкой и поле с ID изображения
+	URLField string
+	IDField  string
+	Value    string
+}
+
+// MediaBackfillResult - итог переноса старых ссылок на изображения в хранилище
+type MediaBackfillResult struct {
+	Linked   int   `json:"linked"`
+	Failed   int   `json:"failed"`
+	Skipped  int   `json:"skipped"`
+	Requeued int64 `json:"requeued"`
+}
+
+// MediaURL - адрес, по которому изображение отдаётся клиентам
+func MediaURL(id string) string {
+	return "/media/" + id
+}
+
+// MediaVariantURL - адрес уменьшенной копии. Пока копия не готова, по нему отдаётся оригинал
+func MediaVariantURL(id string, width int) string {
+	return MediaURL(id) + "?w=" + strconv.Itoa(width)
+}
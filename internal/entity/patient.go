@@ -0,0 +1,125 @@
+package entity
+
+import "time"
+
+const (
+	SexMale   = "male"
+	SexFemale = "female"
+)
+
+// PatientProfile - персональные данные пациента. Профиль привязан к учётной записи
+// пользователя; телефон хранится в формате E.164, полис ОМС и СНИЛС - только цифрами.
+type PatientProfile struct {
+	ID              int       `json:"id"`
+	UserID          *int      `json:"user_id"`
+	LastName        string    `json:"last_name"`
+	FirstName       string    `json:"first_name"`
+	MiddleName      *string   `json:"middle_name,omitempty"`
+	FullName        string    `json:"full_name"`
+	BirthDate       *string   `json:"birth_date,omitempty" example:"1990-05-17"`
+	Sex             *string   `json:"sex,omitempty" enums:"male,female"`
+	Phone           *string   `json:"phone,omitempty" example:"+79123456789"`
+	Address         *string   `json:"address,omitempty"`
+	InsurancePolicy *string   `json:"insurance_policy,omitempty" example:"1234567890123456"`
+	SNILS           *string   `json:"snils,omitempty" example:"11223344595"`
+	Version         int       `json:"version"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// PatientProfileRequest заменяет профиль целиком: не переданные необязательные поля очищаются.
+// Телефон и СНИЛС принимаются с разделителями, сохраняются нормализованными.
+type PatientProfileRequest struct {
+	LastName        string  `json:"last_name" binding:"required,max=100"`
+	FirstName       string  `json:"first_name" binding:"required,max=100"`
+	MiddleName      *string `json:"middle_name" binding:"omitempty,max=100"`
+	BirthDate       *string `json:"birth_date" example:"1990-05-17"`
+	Sex             *string `json:"sex" binding:"omitempty,oneof=male female"`
+	Phone           *string `json:"phone" example:"+7 (912) 345-67-89"`
+	Address         *string `json:"address" binding:"omitempty,max=500"`
+	InsurancePolicy *string `json:"insurance_policy" example:"1234567890123456"`
+	SNILS           *string `json:"snils" example:"112-233-445 95"`
+}
+
+// PatientFilter - условия поиска пациентов администратором. Имя ищется по вхождению
+// каждого слова в ФИО, телефон - по вхождению цифр, полис - точным совпадением.
+type PatientFilter struct {
+	Name   string `form:"name"`
+	Phone  string `form:"phone"`
+	Policy string `form:"policy"`
+	Page   int    `form:"page"`
+	Limit  int    `form:"limit"`
+}
+
+// Права на профиль пациента. Пользователь имеет все права на свой профиль,
+// опекун - на профили подопечных с учётом их возраста и согласия.
+const (
+	PatientAccessView   = "view"
+	PatientAccessBook   = "book"
+	PatientAccessManage = "manage"
+)
+
+const (
+	RelationshipParent              = "parent"
+	RelationshipLegalRepresentative = "legal_representative"
+	RelationshipChild               = "child"
+	RelationshipSpouse              = "spouse"
+	RelationshipSibling             = "sibling"
+	RelationshipOther               = "other"
+)
+
+// PatientGuardian - связь опекуна с подопечным. CanBook и CanViewRecords - согласие
+// подопечного, подтверждённое опекуном; до 15 лет не требуется ни то, ни другое,
+// с 15 лет нужно согласие на просмотр медицинских данных, с 18 - и на запись к врачу.
+type PatientGuardian struct {
+	ID                 int        `json:"id"`
+	GuardianUserID     int        `json:"guardian_user_id"`
+	PatientID          int        `json:"patient_id"`
+	Relationship       string     `json:"relationship"`
+	CanBook            bool       `json:"can_book"`
+	CanViewRecords     bool       `json:"can_view_records"`
+	ConsentConfirmedAt *time.Time `json:"consent_confirmed_at,omitempty"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+}
+
+// TreatingDoctor - врач, назначенный пациенту администратором. Записи о приёмах
+// пациента создают только назначенные врачи и те, у кого он уже был на приёме
+type TreatingDoctor struct {
+	ID           int       `json:"id"`
+	PatientID    int       `json:"patient_id"`
+	DoctorUserID int       `json:"doctor_user_id"`
+	AssignedBy   *int      `json:"assigned_by,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type TreatingDoctorRequest struct {
+	DoctorUserID int `json:"doctor_user_id" binding:"required,min=1"`
+}
+
+// Dependent - подопечный с действующими сейчас правами опекуна
+type Dependent struct {
+	Profile      PatientProfile  `json:"profile"`
+	Guardianship PatientGuardian `json:"guardianship"`
+	Access       []string        `json:"access"`
+}
+
+// DependentCreateRequest создаёт профиль подопечного без учётной записи. Дата рождения
+// обязательна - от неё зависят права опекуна. Для подопечного от 15 лет флаги согласия
+// и для совершеннолетнего сам профиль требуют consent_confirmed.
+type DependentCreateRequest struct {
+	PatientProfileRequest
+	Relationship     string `json:"relationship" binding:"required,oneof=parent legal_representative child spouse sibling other"`
+	CanBook          bool   `json:"can_book"`
+	CanViewRecords   bool   `json:"can_view_records"`
+	ConsentConfirmed bool   `json:"consent_confirmed"`
+}
+
+// GuardianshipUpdateRequest меняет переданные поля связи; включение флагов согласия
+// для подопечного от 15 лет требует consent_confirmed
+type GuardianshipUpdateRequest struct {
+	Relationship     *string `json:"relationship" binding:"omitempty,oneof=parent legal_representative child spouse sibling other"`
+	CanBook          *bool   `json:"can_book"`
+	CanViewRecords   *bool   `json:"can_view_records"`
+	ConsentConfirmed bool    `json:"consent_confirmed"`
+}
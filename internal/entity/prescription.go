@@ -0,0 +1,51 @@
+package entity
+
+import "time"
+
+// Prescription - рецепт: назначение препарата пациенту врачом клиники
+type Prescription struct {
+	ID                    int              `json:"id"`
+	PatientID             int              `json:"patient_id"`
+	PatientName           string           `json:"patient_name"`
+	DoctorID              int              `json:"doctor_id"`
+	DoctorName            string           `json:"doctor_name"`
+	DoctorSpecializations []Specialization `json:"doctor_specializations,omitempty"`
+	EncounterID           *int             `json:"encounter_id,omitempty"`
+	PrescriptionContent
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// PrescriptionContent - назначение: препарат и схема приёма
+type PrescriptionContent struct {
+	DrugName     string  `json:"drug_name" example:"Амоксициллин"`
+	Dosage       string  `json:"dosage" example:"500 мг"`
+	Frequency    string  `json:"frequency" example:"3 раза в день"`
+	Duration     string  `json:"duration" example:"7 дней"`
+	Instructions *string `json:"instructions,omitempty" example:"После еды"`
+	IssuedOn     string  `json:"issued_on" example:"2026-10-19"`
+}
+
+type PrescriptionRequest struct {
+	EncounterID  *int    `json:"encounter_id"`
+	DrugName     string  `json:"drug_name" binding:"required,max=200"`
+	Dosage       string  `json:"dosage" binding:"required,max=100"`
+	Frequency    string  `json:"frequency" binding:"required,max=100"`
+	Duration     string  `json:"duration" binding:"required,max=100"`
+	Instructions *string `json:"instructions" binding:"omitempty,max=1000"`
+	// Дата выписки; по умолчанию - сегодня
+	IssuedOn *string `json:"issued_on" example:"2026-10-19"`
+}
+
+type PrescriptionCreateRequest struct {
+	PatientID int `json:"patient_id" binding:"required"`
+	PrescriptionRequest
+}
+
+type PrescriptionFilter struct {
+	PatientID *int `form:"patient_id"`
+	DoctorID  *int `form:"doctor_id"`
+	Page      int  `form:"page"`
+	Limit     int  `form:"limit"`
+}
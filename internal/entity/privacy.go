@@ -0,0 +1,60 @@
+package entity
+
+import "time"
+
+const (
+	DataRequestExport  = "export"
+	DataRequestErasure = "erasure"
+)
+
+const (
+	DataRequestStatusPending   = "pending"
+	DataRequestStatusCompleted = "completed"
+	DataRequestStatusRejected  = "rejected"
+)
+
+// DataRequest - обращение пользователя по своим персональным данным: выгрузка или удаление
+type DataRequest struct {
+	ID     int    `json:"id"`
+	UserID int    `json:"user_id"`
+	Type   string `json:"type" enums:"export,erasure"`
+	Status string `json:"status" enums:"pending,completed,rejected"`
+	// Комментарий пользователя и решение администратора
+	Reason      *string    `json:"reason,omitempty"`
+	Resolution  *string    `json:"resolution,omitempty"`
+	ReviewedBy  *int       `json:"reviewed_by,omitempty"`
+	ReviewedAt  *time.Time `json:"reviewed_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	Version     int        `json:"version"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+type ErasureRequest struct {
+	Reason *string `json:"reason" binding:"omitempty,max=1000"`
+}
+
+// DataRequestDecision - решение администратора; при отказе комментарий обязателен
+type DataRequestDecision struct {
+	Resolution *string `json:"resolution" binding:"omitempty,max=1000"`
+}
+
+type DataRequestFilter struct {
+	UserID *int   `form:"user_id"`
+	Type   string `form:"type" binding:"omitempty,oneof=export erasure"`
+	Status string `form:"status" binding:"omitempty,oneof=pending completed rejected"`
+	Page   int    `form:"page"`
+	Limit  int    `form:"limit"`
+}
+
+// ErasureSummary - что сделано при обезличивании учётной записи. Профиль пациента
+// с медицинскими документами сохраняется без контактных данных, иначе удаляется
+type ErasureSummary struct {
+	UserID               int  `json:"user_id"`
+	PatientID            *int `json:"patient_id,omitempty"`
+	ProfileDeleted       bool `json:"profile_deleted"`
+	ProfileRetained      bool `json:"profile_retained"`
+	GuardianshipsRemoved int  `json:"guardianships_removed"`
+	ReviewsRemoved       int  `json:"reviews_removed"`
+	DoctorUnlinked       bool `json:"doctor_unlinked"`
+}
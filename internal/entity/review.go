@@ -0,0 +1,47 @@
+package entity
+
+import "time"
+
+const (
+	ReviewStatusPending  = "pending"
+	ReviewStatusApproved = "approved"
+	ReviewStatusRejected = "rejected"
+)
+
+// DoctorSortRating - сортировка списка врачей по рейтингу, лучшие первыми
+const DoctorSortRating = "rating"
+
+// DoctorReview - отзыв пользователя о враче. В публичном списке автор указан только именем
+type DoctorReview struct {
+	ID         int    `json:"id"`
+	DoctorID   int    `json:"doctor_id"`
+	UserID     int    `json:"user_id,omitempty"`
+	AuthorName string `json:"author_name"`
+	Rating     int    `json:"rating"`
+	Text       string `json:"text"`
+	Status     string `json:"status" enums:"pending,approved,rejected"`
+	// Решение модератора
+	ModerationComment *string    `json:"moderation_comment,omitempty"`
+	ModeratedBy       *int       `json:"moderated_by,omitempty"`
+	ModeratedAt       *time.Time `json:"moderated_at,omitempty"`
+	Version           int        `json:"version"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+type DoctorReviewRequest struct {
+	Rating int    `json:"rating" binding:"required,min=1,max=5"`
+	Text   string `json:"text" binding:"required,max=2000"`
+}
+
+type ReviewModerationRequest struct {
+	Comment *string `json:"comment" binding:"omitempty,max=1000"`
+}
+
+type DoctorReviewFilter struct {
+	DoctorID *int   `form:"doctor_id"`
+	UserID   *int   `form:"user_id"`
+	Status   string `form:"status" binding:"omitempty,oneof=pending approved rejected"`
+	Page     int    `form:"page"`
+	Limit    int    `form:"limit"`
+}
@@ -0,0 +1,42 @@
+package entity
+
+// Типы результатов поиска
+const (
+	SearchTypeDoctor          = "doctor"
+	SearchTypeService         = "service"
+	SearchTypeServiceCategory = "service_category"
+	SearchTypeSpecialization  = "specialization"
+)
+
+// SearchTypes - все типы результатов поиска
+var SearchTypes = []string{SearchTypeDoctor, SearchTypeService, SearchTypeServiceCategory, SearchTypeSpecialization}
+
+// SearchRequest - параметры GET /search
+type SearchRequest struct {
+	Q     string `form:"q" binding:"required"`
+	Type  string `form:"type"`
+	Limit int    `form:"limit"`
+}
+
+// SearchQuery - разобранный поисковый запрос
+type SearchQuery struct {
+	// Слова запроса: только буквы и цифры в нижнем регистре
+	Terms []string
+	Types []string
+	Limit int
+}
+
+// SearchResult - найденная запись. Title и Snippet - экранированный HTML,
+// совпадения выделены тегом <mark>
+type SearchResult struct {
+	Type    string  `json:"type"`
+	ID      int     `json:"id"`
+	Title   string  `json:"title"`
+	Snippet string  `json:"snippet,omitempty"`
+	Rank    float64 `json:"rank"`
+}
+
+type SearchResponse struct {
+	Query   string         `json:"query"`
+	Results []SearchResult `json:"results"`
+}
@@ -25,6 +25,8 @@ type UserRegisterRequest struct {
 	Username string `json:"username" binding:"required"`
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required,min=6"`
+	// Действующие документы, с которыми согласился пользователь; обязательные - все
+	ConsentDocumentIDs []int `json:"consent_document_ids" binding:"omitempty,max=10,dive,min=1"`
 }
 
 type UserLoginRequest struct {
@@ -0,0 +1,91 @@
+package fhir
+
+type CapabilityStatement struct {
+	ResourceType   string           `json:"resourceType"`
+	Status         string           `json:"status"`
+	Date           string           `json:"date"`
+	Kind           string           `json:"kind"`
+	Software       *Software        `json:"software,omitempty"`
+	Implementation *Implementation  `json:"implementation,omitempty"`
+	FHIRVersion    string           `json:"fhirVersion"`
+	Format         []string         `json:"format"`
+	Rest           []CapabilityRest `json:"rest"`
+}
+
+type Software struct {
+	Name string `json:"name"`
+}
+
+type Implementation struct {
+	Description string `json:"description"`
+	URL         string `json:"url"`
+}
+
+type CapabilityRest struct {
+	Mode     string               `json:"mode"`
+	Resource []CapabilityResource `json:"resource"`
+}
+
+type CapabilityResource struct {
+	Type        string        `json:"type"`
+	Interaction []Interaction `json:"interaction"`
+	SearchParam []SearchParam `json:"searchParam,omitempty"`
+}
+
+type Interaction struct {
+	Code string `json:"code"`
+}
+
+type SearchParam struct {
+	Name          string `json:"name"`
+	Type          string `json:"type"`
+	Documentation string `json:"documentation,omitempty"`
+}
+
+// Параметры поиска, общие для всех ресурсов
+var commonSearchParams = []SearchParam{
+	{Name: "_id", Type: "token", Documentation: "Один или несколько ID через запятую"},
+	{Name: "_count", Type: "number", Documentation: "Размер страницы, не больше 100"},
+	{Name: "_page", Type: "number", Documentation: "Номер страницы, начиная с 1"},
+}
+
+var nameSearchParam = SearchParam{Name: "name", Type: "string", Documentation: "Начало любого слова имени, без учёта регистра"}
+
+// Resources - типы ресурсов, которые отдаёт фасад, с их параметрами поиска сверх общих
+var Resources = map[string][]SearchParam{
+	"Practitioner":      {nameSearchParam},
+	"PractitionerRole":  {{Name: "practitioner", Type: "reference"}},
+	"HealthcareService": {nameSearchParam},
+	"Schedule":          {{Name: "actor", Type: "reference"}},
+	"Slot":              {{Name: "schedule", Type: "reference"}},
+	"Patient":           {nameSearchParam},
+}
+
+// NewCapabilityStatement описывает сервер, доступный по адресу base. date - дата
+// публикации в формате YYYY-MM-DD
+func NewCapabilityStatement(base, software, date string) *CapabilityStatement {
+	statement := &CapabilityStatement{
+		ResourceType: "CapabilityStatement",
+		Status:       "active",
+		Date:         date,
+		Kind:         "instance",
+		Software:     &Software{Name: software},
+		Implementation: &Implementation{
+			Description: "Read-only FHIR R4 facade",
+			URL:         base,
+		},
+		FHIRVersion: Version,
+		Format:      []string{"json"},
+	}
+
+	rest := CapabilityRest{Mode: "server"}
+	for _, name := range []string{"Practitioner", "PractitionerRole", "HealthcareService", "Schedule", "Slot", "Patient"} {
+		rest.Resource = append(rest.Resource, CapabilityResource{
+			Type:        name,
+			Interaction: []Interaction{{Code: "read"}, {Code: "search-type"}},
+			SearchParam: append(append([]SearchParam{}, commonSearchParams...), Resources[name]...),
+		})
+	}
+	statement.Rest = []CapabilityRest{rest}
+	return statement
+}
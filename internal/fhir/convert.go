@@ -0,0 +1,271 @@
+package fhir
+
+import (
+	"Clinic_backend/internal/entity"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OID СНИЛС в справочниках ЕГИСЗ
+const snilsSystem = "urn:oid:1.2.643.100.3"
+
+// Дни недели FHIR в нумерации расписаний: 1 - понедельник, 7 - воскресенье
+var daysOfWeek = [...]string{"", "mon", "tue", "wed", "thu", "fri", "sat", "sun"}
+
+// Converter переводит сущности в ресурсы. APIBase - адрес REST API клиники: локальные
+// справочники (специализации, категории услуг) ссылаются на его списки как на системы кодов.
+// Location - часовой пояс клиники, в котором заданы часы приёма
+type Converter struct {
+	APIBase  string
+	Location *time.Location
+}
+
+func (c Converter) Practitioner(doctor entity.Doctor) Practitioner {
+	return Practitioner{
+		ResourceType: "Practitioner",
+		ID:           strconv.Itoa(doctor.ID),
+		Meta:         meta(doctor.Version, doctor.UpdatedAt),
+		Active:       doctor.DeletedAt == nil,
+		Name:         []HumanName{humanName(doctor.Fullname)},
+	}
+}
+
+// PractitionerRole - одна роль на врача со всеми его специализациями; ID совпадает с ID врача
+func (c Converter) PractitionerRole(doctor entity.Doctor) PractitionerRole {
+	role := PractitionerRole{
+		ResourceType: "PractitionerRole",
+		ID:           strconv.Itoa(doctor.ID),
+		Meta:         meta(doctor.Version, doctor.UpdatedAt),
+		Active:       doctor.DeletedAt == nil,
+		Practitioner: Reference{Reference: "Practitioner/" + strconv.Itoa(doctor.ID), Display: doctor.Fullname},
+	}
+	for _, spec := range doctor.Specializations {
+		role.Specialty = append(role.Specialty, c.concept("specializations", spec.ID, spec.Name))
+	}
+	if doctor.Schedule != nil && doctor.Schedule.Day >= 1 && doctor.Schedule.Day <= 7 {
+		role.AvailableTime = []AvailableTime{{
+			DaysOfWeek:         []string{daysOfWeek[doctor.Schedule.Day]},
+			AvailableStartTime: clockTime(doctor.Schedule.TimeFrom),
+			AvailableEndTime:   clockTime(doctor.Schedule.TimeTo),
+		}}
+	}
+	return role
+}
+
+// HealthcareService - услуга клиники. Названия категорий и специализаций передаются по ID
+func (c Converter) HealthcareService(service entity.Service, categories, specializations map[int]string) HealthcareService {
+	resource := HealthcareService{
+		ResourceType: "HealthcareService",
+		ID:           strconv.Itoa(service.ID),
+		Meta:         meta(service.Version, service.UpdatedAt),
+		Active:       service.DeletedAt == nil,
+		Name:         service.Name,
+	}
+	if service.Description != nil {
+		resource.Comment = *service.Description
+	}
+	if service.ServiceCategoryID != nil {
+		if name, ok := categories[*service.ServiceCategoryID]; ok {
+			resource.Category = []CodeableConcept{c.concept("service-categories", *service.ServiceCategoryID, name)}
+		}
+	}
+	if service.SpecializationID != nil {
+		if name, ok := specializations[*service.SpecializationID]; ok {
+			resource.Specialty = []CodeableConcept{c.concept("specializations", *service.SpecializationID, name)}
+		}
+	}
+	if service.Price != nil {
+		resource.ExtraDetails = fmt.Sprintf("Стоимость: %d ₽", *service.Price)
+	}
+	return resource
+}
+
+// Schedule - расписание врача; ID совпадает с ID врача
+func (c Converter) Schedule(doctor entity.Doctor, horizon Period) Schedule {
+	schedule := Schedule{
+		ResourceType:    "Schedule",
+		ID:              strconv.Itoa(doctor.ID),
+		Meta:            meta(doctor.Version, doctor.UpdatedAt),
+		Active:          doctor.DeletedAt == nil && doctor.Schedule != nil,
+		Actor:           []Reference{{Reference: "Practitioner/" + strconv.Itoa(doctor.ID), Display: doctor.Fullname}},
+		PlanningHorizon: &horizon,
+	}
+	if doctor.Schedule != nil && doctor.Schedule.Day >= 1 && doctor.Schedule.Day <= 7 {
+		schedule.Comment = fmt.Sprintf("%s %s-%s", daysOfWeek[doctor.Schedule.Day],
+			clockTime(doctor.Schedule.TimeFrom), clockTime(doctor.Schedule.TimeTo))
+	}
+	return schedule
+}
+
+// Slots разворачивает еженедельные часы приёма врача в интервалы на дни [from, to).
+// Записи на приём в системе нет, поэтому все интервалы свободны
+func (c Converter) Slots(doctor entity.Doctor, from, to time.Time) []Slot {
+	if doctor.Schedule == nil || doctor.Schedule.Day < 1 || doctor.Schedule.Day > 7 {
+		return nil
+	}
+
+	var slots []Slot
+	for day := c.startOfDay(from); day.Before(to); day = day.AddDate(0, 0, 1) {
+		if isoWeekday(day) != doctor.Schedule.Day {
+			continue
+		}
+		// Сегодняшний приём, который уже закончился, не предлагается
+		if end, ok := atClock(day, doctor.Schedule.TimeTo); ok && !end.After(from) {
+			continue
+		}
+		if slot, ok := c.slot(doctor, day); ok {
+			slots = append(slots, slot)
+		}
+	}
+	return slots
+}
+
+// Slot восстанавливает интервал по ID вида {врач}-{ГГГГММДД}
+func (c Converter) Slot(doctor entity.Doctor, id string) (Slot, bool) {
+	_, date, ok := ParseSlotID(id)
+	if !ok || doctor.Schedule == nil || isoWeekday(date) != doctor.Schedule.Day {
+		return Slot{}, false
+	}
+	return c.slot(doctor, time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, c.Location))
+}
+
+func (c Converter) slot(doctor entity.Doctor, day time.Time) (Slot, bool) {
+	start, okStart := atClock(day, doctor.Schedule.TimeFrom)
+	end, okEnd := atClock(day, doctor.Schedule.TimeTo)
+	if !okStart || !okEnd || !end.After(start) {
+		return Slot{}, false
+	}
+
+	return Slot{
+		ResourceType: "Slot",
+		ID:           fmt.Sprintf("%d-%s", doctor.ID, day.Format("20060102")),
+		Schedule:     Reference{Reference: "Schedule/" + strconv.Itoa(doctor.ID)},
+		Status:       "free",
+		Start:        start.Format(time.RFC3339),
+		End:          end.Format(time.RFC3339),
+	}, true
+}
+
+// ParseSlotID разбирает ID интервала на ID врача и дату
+func ParseSlotID(id string) (int, time.Time, bool) {
+	doctorPart, datePart, found := strings.Cut(id, "-")
+	if !found {
+		return 0, time.Time{}, false
+	}
+	doctorID, err := strconv.Atoi(doctorPart)
+	if err != nil || doctorID <= 0 {
+		return 0, time.Time{}, false
+	}
+	date, err := time.Parse("20060102", datePart)
+	if err != nil {
+		return 0, time.Time{}, false
+	}
+	return doctorID, date, true
+}
+
+func (c Converter) Patient(profile entity.PatientProfile) Patient {
+	given := []string{profile.FirstName}
+	if profile.MiddleName != nil {
+		given = append(given, *profile.MiddleName)
+	}
+
+	patient := Patient{
+		ResourceType: "Patient",
+		ID:           strconv.Itoa(profile.ID),
+		Meta:         meta(profile.Version, profile.UpdatedAt),
+		Active:       true,
+		Name: []HumanName{{
+			Use:    "official",
+			Text:   profile.FullName,
+			Family: profile.LastName,
+			Given:  given,
+		}},
+	}
+	if profile.SNILS != nil {
+		patient.Identifier = append(patient.Identifier, Identifier{
+			Use:    "official",
+			Type:   &CodeableConcept{Text: "СНИЛС"},
+			System: snilsSystem,
+			Value:  *profile.SNILS,
+		})
+	}
+	if profile.InsurancePolicy != nil {
+		patient.Identifier = append(patient.Identifier, Identifier{
+			Use:   "official",
+			Type:  &CodeableConcept{Text: "Полис ОМС"},
+			Value: *profile.InsurancePolicy,
+		})
+	}
+	if profile.Phone != nil {
+		patient.Telecom = []ContactPoint{{System: "phone", Value: *profile.Phone, Use: "mobile"}}
+	}
+	if profile.Sex != nil {
+		// Значения entity.SexMale и entity.SexFemale совпадают с кодами AdministrativeGender
+		patient.Gender = *profile.Sex
+	}
+	if profile.BirthDate != nil {
+		patient.BirthDate = *profile.BirthDate
+	}
+	if profile.Address != nil {
+		patient.Address = []Address{{Text: *profile.Address}}
+	}
+	return patient
+}
+
+func (c Converter) concept(list string, id int, name string) CodeableConcept {
+	return CodeableConcept{
+		Coding: []Coding{{System: c.APIBase + "/" + list, Code: strconv.Itoa(id), Display: name}},
+		Text:   name,
+	}
+}
+
+func (c Converter) startOfDay(t time.Time) time.Time {
+	t = t.In(c.Location)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, c.Location)
+}
+
+func meta(version int, updatedAt time.Time) *Meta {
+	return &Meta{
+		VersionID:   strconv.Itoa(version),
+		LastUpdated: updatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+// humanName раскладывает ФИО врача, записанное одной строкой: первое слово - фамилия
+func humanName(fullname string) HumanName {
+	parts := strings.Fields(fullname)
+	name := HumanName{Use: "official", Text: strings.Join(parts, " ")}
+	if len(parts) > 0 {
+		name.Family = parts[0]
+		name.Given = parts[1:]
+	}
+	return name
+}
+
+// clockTime приводит время из расписания ("9:00", "09:00:00") к типу time FHIR - hh:mm:ss
+func clockTime(value string) string {
+	for _, layout := range []string{"15:04:05", "15:04"} {
+		if t, err := time.Parse(layout, strings.TrimSpace(value)); err == nil {
+			return t.Format("15:04:05")
+		}
+	}
+	return value
+}
+
+func atClock(day time.Time, value string) (time.Time, bool) {
+	t, err := time.Parse("15:04:05", clockTime(value))
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), t.Hour(), t.Minute(), t.Second(), 0, day.Location()), true
+}
+
+// isoWeekday - день недели в нумерации расписаний
+func isoWeekday(t time.Time) int {
+	if t.Weekday() == time.Sunday {
+		return 7
+	}
+	return int(t.Weekday())
+}
@@ -0,0 +1,185 @@
+// Package fhir описывает подмножество ресурсов HL7 FHIR R4, которое клиника отдаёт
+// внешним медицинским системам, и переводит в них сущности приложения.
+package fhir
+
+// Version - версия спецификации FHIR, которой соответствуют ресурсы
+const Version = "4.0.1"
+
+// ContentType - MIME-тип ответов FHIR в формате JSON
+const ContentType = "application/fhir+json"
+
+// Resource - ресурс, который можно адресовать как Тип/ID
+type Resource interface {
+	Reference() string
+}
+
+type Meta struct {
+	VersionID   string `json:"versionId,omitempty"`
+	LastUpdated string `json:"lastUpdated,omitempty"`
+}
+
+type Coding struct {
+	System  string `json:"system,omitempty"`
+	Code    string `json:"code,omitempty"`
+	Display string `json:"display,omitempty"`
+}
+
+type CodeableConcept struct {
+	Coding []Coding `json:"coding,omitempty"`
+	Text   string   `json:"text,omitempty"`
+}
+
+type Identifier struct {
+	Use    string           `json:"use,omitempty"`
+	Type   *CodeableConcept `json:"type,omitempty"`
+	System string           `json:"system,omitempty"`
+	Value  string           `json:"value"`
+}
+
+type HumanName struct {
+	Use    string   `json:"use,omitempty"`
+	Text   string   `json:"text,omitempty"`
+	Family string   `json:"family,omitempty"`
+	Given  []string `json:"given,omitempty"`
+}
+
+type ContactPoint struct {
+	System string `json:"system"`
+	Value  string `json:"value"`
+	Use    string `json:"use,omitempty"`
+}
+
+type Address struct {
+	Text string `json:"text"`
+}
+
+type Reference struct {
+	Reference string `json:"reference"`
+	Display   string `json:"display,omitempty"`
+}
+
+type Period struct {
+	Start string `json:"start,omitempty"`
+	End   string `json:"end,omitempty"`
+}
+
+type Practitioner struct {
+	ResourceType string      `json:"resourceType"`
+	ID           string      `json:"id"`
+	Meta         *Meta       `json:"meta,omitempty"`
+	Active       bool        `json:"active"`
+	Name         []HumanName `json:"name,omitempty"`
+}
+
+// AvailableTime - время приёма в PractitionerRole
+type AvailableTime struct {
+	DaysOfWeek         []string `json:"daysOfWeek"`
+	AvailableStartTime string   `json:"availableStartTime"`
+	AvailableEndTime   string   `json:"availableEndTime"`
+}
+
+type PractitionerRole struct {
+	ResourceType  string            `json:"resourceType"`
+	ID            string            `json:"id"`
+	Meta          *Meta             `json:"meta,omitempty"`
+	Active        bool              `json:"active"`
+	Practitioner  Reference         `json:"practitioner"`
+	Specialty     []CodeableConcept `json:"specialty,omitempty"`
+	AvailableTime []AvailableTime   `json:"availableTime,omitempty"`
+}
+
+type HealthcareService struct {
+	ResourceType string            `json:"resourceType"`
+	ID           string            `json:"id"`
+	Meta         *Meta             `json:"meta,omitempty"`
+	Active       bool              `json:"active"`
+	Name         string            `json:"name"`
+	Comment      string            `json:"comment,omitempty"`
+	Category     []CodeableConcept `json:"category,omitempty"`
+	Specialty    []CodeableConcept `json:"specialty,omitempty"`
+	// Цена услуги; в R4 для неё нет отдельного элемента
+	ExtraDetails string `json:"extraDetails,omitempty"`
+}
+
+type Schedule struct {
+	ResourceType    string      `json:"resourceType"`
+	ID              string      `json:"id"`
+	Meta            *Meta       `json:"meta,omitempty"`
+	Active          bool        `json:"active"`
+	Actor           []Reference `json:"actor"`
+	PlanningHorizon *Period     `json:"planningHorizon,omitempty"`
+	Comment         string      `json:"comment,omitempty"`
+}
+
+type Slot struct {
+	ResourceType string    `json:"resourceType"`
+	ID           string    `json:"id"`
+	Schedule     Reference `json:"schedule"`
+	Status       string    `json:"status"`
+	Start        string    `json:"start"`
+	End          string    `json:"end"`
+}
+
+type Patient struct {
+	ResourceType string         `json:"resourceType"`
+	ID           string         `json:"id"`
+	Meta         *Meta          `json:"meta,omitempty"`
+	Active       bool           `json:"active"`
+	Identifier   []Identifier   `json:"identifier,omitempty"`
+	Name         []HumanName    `json:"name,omitempty"`
+	Telecom      []ContactPoint `json:"telecom,omitempty"`
+	Gender       string         `json:"gender,omitempty"`
+	BirthDate    string         `json:"birthDate,omitempty"`
+	Address      []Address      `json:"address,omitempty"`
+}
+
+func (r Practitioner) Reference() string      { return r.ResourceType + "/" + r.ID }
+func (r PractitionerRole) Reference() string  { return r.ResourceType + "/" + r.ID }
+func (r HealthcareService) Reference() string { return r.ResourceType + "/" + r.ID }
+func (r Schedule) Reference() string          { return r.ResourceType + "/" + r.ID }
+func (r Slot) Reference() string              { return r.ResourceType + "/" + r.ID }
+func (r Patient) Reference() string           { return r.ResourceType + "/" + r.ID }
+
+// Bundle - результат поиска (type searchset)
+type Bundle struct {
+	ResourceType string        `json:"resourceType"`
+	Type         string        `json:"type"`
+	Total        int           `json:"total"`
+	Link         []BundleLink  `json:"link,omitempty"`
+	Entry        []BundleEntry `json:"entry,omitempty"`
+}
+
+type BundleLink struct {
+	Relation string `json:"relation"`
+	URL      string `json:"url"`
+}
+
+type BundleEntry struct {
+	FullURL  string       `json:"fullUrl"`
+	Resource Resource     `json:"resource"`
+	Search   *EntrySearch `json:"search,omitempty"`
+}
+
+type EntrySearch struct {
+	Mode string `json:"mode"`
+}
+
+// OperationOutcome - описание ошибки в ответах FHIR
+type OperationOutcome struct {
+	ResourceType string  `json:"resourceType"`
+	Issue        []Issue `json:"issue"`
+}
+
+type Issue struct {
+	Severity    string `json:"severity"`
+	Code        string `json:"code"`
+	Diagnostics string `json:"diagnostics,omitempty"`
+}
+
+// NewOperationOutcome - ошибка с кодом из IssueType (invalid, not-found, not-supported...)
+func NewOperationOutcome(code, diagnostics string) *OperationOutcome {
+	return &OperationOutcome{
+		ResourceType: "OperationOutcome",
+		Issue:        []Issue{{Severity: "error", Code: code, Diagnostics: diagnostics}},
+	}
+}
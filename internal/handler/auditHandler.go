@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"Clinic_backend/internal/entity"
+	"Clinic_backend/internal/service"
+	"Clinic_backend/internal/utils"
+	"encoding/csv"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuditHandler struct {
+	auditService service.AuditServiceInterface
+}
+
+func NewAuditHandler(auditService service.AuditServiceInterface) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+// List godoc
+// @Summary Get audit log
+// @Description Get paginated audit log entries with filters (admin only)
+// @Tags audit
+// @Security BearerAuth
+// @Produce json
+// @Param actor_id query int false "Actor user ID"
+// @Param entity_type query string false "Entity type"
+// @Param entity_id query int false "Entity ID"
+// @Param action query string false "Action (create, update, delete, restore)"
+// @Param from query string false "From time (RFC3339)"
+// @Param to query string false "To time (RFC3339)"
+// @Param page query int false "Page number"
+// @Param limit query int false "Page size (max 100)"
+// @Success 200 {object} utils.PaginatedData
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /audit-log [get]
+func (h *AuditHandler) List(c *gin.Context) {
+	var filter entity.AuditLogFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	entries, total, err := h.auditService.List(c.Request.Context(), &filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.PaginatedData{
+		Data:  entries,
+		Page:  filter.Page,
+		Limit: filter.Limit,
+		Total: total,
+	})
+}
+
+// Export godoc
+// @Summary Export audit log
+// @Description Export filtered audit log entries as CSV (admin only)
+// @Tags audit
+// @Security BearerAuth
+// @Produce text/csv
+// @Param actor_id query int false "Actor user ID"
+// @Param entity_type query string false "Entity type"
+// @Param entity_id query int false "Entity ID"
+// @Param action query string false "Action (create, update, delete, restore)"
+// @Param from query string false "From time (RFC3339)"
+// @Param to query string false "To time (RFC3339)"
+// @Success 200 {string} string "CSV file"
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /audit-log/export [get]
+func (h *AuditHandler) Export(c *gin.Context) {
+	var filter entity.AuditLogFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	entries, err := h.auditService.Export(c.Request.Context(), &filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="audit-log.csv"`)
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	_ = w.Write([]string{"id", "created_at", "actor_id", "ip", "request_id", "entity_type", "entity_id", "action", "before", "after", "prev_hash", "hash"})
+	for _, entry := range entries {
+		actorID := ""
+		if entry.ActorID != nil {
+			actorID = strconv.Itoa(*entry.ActorID)
+		}
+		_ = w.Write([]string{
+			strconv.FormatInt(entry.ID, 10),
+			entry.CreatedAt.Format(time.RFC3339Nano),
+			actorID,
+			entry.IP,
+			entry.RequestID,
+			entry.EntityType,
+			strconv.Itoa(entry.EntityID),
+			entry.Action,
+			string(entry.Before),
+			string(entry.After),
+			entry.PrevHash,
+			entry.Hash,
+		})
+	}
+	w.Flush()
+}
+
+// Verify godoc
+// @Summary Verify audit log
+// @Description Recompute the audit log hash chain and report the first broken entry (admin only)
+// @Tags audit
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} entity.AuditVerifyResult
+// @Failure 403 {object} map[string]string
+// @Router /audit-log/verify [get]
+func (h *AuditHandler) Verify(c *gin.Context) {
+	result, err := h.auditService.Verify(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
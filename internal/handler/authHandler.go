@@ -3,7 +3,6 @@ package handler
 import (
 	"Clinic_backend/internal/entity"
 	"Clinic_backend/internal/service"
-	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -21,7 +20,7 @@ func NewAuthHandler(authService service.AuthServiceInterface) *AuthHandler {
 
 // Register godoc
 // @Summary Register new user
-// @Description Register a new user account
+// @Description Register a new user account. consent_document_ids must include every current mandatory consent document (see GET /consents/current); acceptances are stored with the client IP
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -32,12 +31,17 @@ func NewAuthHandler(authService service.AuthServiceInterface) *AuthHandler {
 func (h *AuthHandler) Register(c *gin.Context) {
 	var req entity.UserRegisterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		fmt.Println("req: ", req)
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	response, err := h.authService.Register(c.Request.Context(), &req)
+	// На /auth нет AuthMiddleware: адрес, с которого даны согласия, передаём сами
+	ctx := entity.ContextWithActor(c.Request.Context(), entity.Actor{
+		IP:        c.ClientIP(),
+		RequestID: c.GetString("request_id"),
+	})
+
+	response, err := h.authService.Register(ctx, &req)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -48,7 +52,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 
 // Login godoc
 // @Summary User login
-// @Description Authenticate user and return JWT token
+// @Description Authenticate user and return JWT token. pending_consents lists newly published versions of consent documents the user still has to accept
 // @Tags auth
 // @Accept json
 // @Produce json
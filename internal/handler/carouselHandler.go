@@ -43,6 +43,7 @@ func (h *CarouselHandler) CreateSlide(c *gin.Context) {
 		return
 	}
 
+	setETag(c, created.Version)
 	c.JSON(http.StatusCreated, created)
 }
 
@@ -52,9 +53,10 @@ func (h *CarouselHandler) CreateSlide(c *gin.Context) {
 // @Tags carousel
 // @Produce json
 // @Success 200 {array} entity.Carousel
+// @Param include_deleted query bool false "Include soft-deleted records (admin only)"
 // @Router /carousel [get]
 func (h *CarouselHandler) GetAllSlides(c *gin.Context) {
-	slides, err := h.carouselService.GetAllSlides(c.Request.Context())
+	slides, err := h.carouselService.GetAllSlides(c.Request.Context(), includeDeleted(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -85,6 +87,7 @@ func (h *CarouselHandler) GetSlideByID(c *gin.Context) {
 		return
 	}
 
+	setETag(c, slide.Version)
 	c.JSON(http.StatusOK, slide)
 }
 
@@ -97,8 +100,11 @@ func (h *CarouselHandler) GetSlideByID(c *gin.Context) {
 // @Produce json
 // @Param id path int true "Slide ID"
 // @Param request body entity.Carousel true "Slide update data"
+// @Param If-Match header string true "ETag of the current version"
 // @Success 200 {object} entity.Carousel
 // @Failure 400 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 428 {object} map[string]string
 // @Router /carousel/{id} [put]
 func (h *CarouselHandler) UpdateSlide(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
@@ -107,18 +113,24 @@ func (h *CarouselHandler) UpdateSlide(c *gin.Context) {
 		return
 	}
 
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
 	var carousel entity.Carousel
 	if err := c.ShouldBindJSON(&carousel); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	updated, err := h.carouselService.UpdateSlide(c.Request.Context(), id, &carousel)
+	updated, err := h.carouselService.UpdateSlide(c.Request.Context(), id, version, &carousel)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeMutationError(c, err)
 		return
 	}
 
+	setETag(c, updated.Version)
 	c.JSON(http.StatusOK, updated)
 }
 
@@ -128,8 +140,11 @@ func (h *CarouselHandler) UpdateSlide(c *gin.Context) {
 // @Tags carousel
 // @Security BearerAuth
 // @Param id path int true "Slide ID"
+// @Param If-Match header string true "ETag of the current version"
 // @Success 204
 // @Failure 400 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 428 {object} map[string]string
 // @Router /carousel/{id} [delete]
 func (h *CarouselHandler) DeleteSlide(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
@@ -138,8 +153,37 @@ func (h *CarouselHandler) DeleteSlide(c *gin.Context) {
 		return
 	}
 
-	if err := h.carouselService.DeleteSlide(c.Request.Context(), id); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if err := h.carouselService.DeleteSlide(c.Request.Context(), id, version); err != nil {
+		writeMutationError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RestoreSlide godoc
+// @Summary Restore carousel slide
+// @Description Restore soft-deleted carousel slide by ID (admin only)
+// @Tags carousel
+// @Security BearerAuth
+// @Param id path int true "Slide ID"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Router /carousel/{id}/restore [post]
+func (h *CarouselHandler) RestoreSlide(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid slide ID"})
+		return
+	}
+
+	if err := h.carouselService.RestoreSlide(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
 
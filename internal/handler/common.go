@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"Clinic_backend/internal/entity"
+	"Clinic_backend/internal/repository"
+	"Clinic_backend/internal/utils"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// includeDeleted - показывать ли мягко удалённые записи. Учитывается только для администратора
+func includeDeleted(c *gin.Context) bool {
+	return c.Query("include_deleted") == "true" && c.GetString("role") == entity.RoleAdmin
+}
+
+// setETag выставляет ETag по версии записи
+func setETag(c *gin.Context, version int) {
+	c.Header("ETag", `"`+strconv.Itoa(version)+`"`)
+}
+
+// ifMatchVersion извлекает ожидаемую версию записи из обязательного заголовка If-Match.
+// Сравнение строгое (RFC 9110): слабые ETag не совпадают ни с чем. Учитывается число
+// до первого "-": остальное - производные поля представления (см. setDoctorETag),
+// которые клиент не редактирует. "*" снимает проверку версии (repository.AnyVersion).
+// Список допускается, если все строгие ETag в нём относятся к одной версии.
+// При ошибке ответ уже отправлен, обработчик должен завершиться.
+func ifMatchVersion(c *gin.Context) (int, bool) {
+	tags := utils.ETagList(c.GetHeader("If-Match"))
+	if len(tags) == 0 {
+		c.JSON(http.StatusPreconditionRequired, gin.H{"error": "If-Match header required"})
+		return 0, false
+	}
+
+	version := 0
+	for _, tag := range tags {
+		if tag == "*" {
+			return repository.AnyVersion, true
+		}
+		if strings.HasPrefix(tag, "W/") {
+			continue
+		}
+
+		value, _, _ := strings.Cut(strings.Trim(tag, `"`), "-")
+		v, err := strconv.Atoi(value)
+		if err != nil || v <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid If-Match header"})
+			return 0, false
+		}
+		if version != 0 && v != version {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "If-Match must name a single version"})
+			return 0, false
+		}
+		version = v
+	}
+
+	if version == 0 {
+		c.JSON(http.StatusPreconditionFailed, gin.H{"error": "If-Match requires a strong ETag"})
+		return 0, false
+	}
+	return version, true
+}
+
+// writeMutationError отвечает 412 при конфликте версий, иначе 400
+func writeMutationError(c *gin.Context, err error) {
+	if errors.Is(err, repository.ErrVersionConflict) {
+		c.JSON(http.StatusPreconditionFailed, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+}
+
+// currentUserID возвращает ID пользователя из токена.
+// При ошибке ответ уже отправлен, обработчик должен завершиться.
+func currentUserID(c *gin.Context) (int, bool) {
+	userIDValue, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return 0, false
+	}
+
+	userID, ok := userIDValue.(int)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID in token"})
+		return 0, false
+	}
+
+	return userID, true
+}
+
+// patientIDQuery читает необязательный параметр patient_id - пациента, от имени которого
+// действует пользователь. Без параметра запрос относится к его собственному профилю.
+// При ошибке ответ уже отправлен, обработчик должен завершиться.
+func patientIDQuery(c *gin.Context) (*int, bool) {
+	value := c.Query("patient_id")
+	if value == "" {
+		return nil, true
+	}
+
+	patientID, err := strconv.Atoi(value)
+	if err != nil || patientID < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid patient_id"})
+		return nil, false
+	}
+
+	return &patientID, true
+}
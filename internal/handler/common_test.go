@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"Clinic_backend/internal/repository"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestIfMatchVersion(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		header  string
+		version int
+		status  int
+	}{
+		{header: ``, status: http.StatusPreconditionRequired},
+		{header: `"7"`, version: 7},
+		{header: `"7-3-4.50"`, version: 7},
+		{header: `*`, version: repository.AnyVersion},
+		{header: `"7-3-4.50", "7-4-4.25"`, version: 7},
+		{header: `W/"6", "7"`, version: 7},
+		{header: `W/"7"`, status: http.StatusPreconditionFailed},
+		{header: `"6", "7"`, status: http.StatusBadRequest},
+		{header: `"abc"`, status: http.StatusBadRequest},
+		{header: `"0"`, status: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPut, "/", nil)
+			c.Request.Header.Set("If-Match", tt.header)
+
+			version, ok := ifMatchVersion(c)
+			if tt.status != 0 {
+				if ok || w.Code != tt.status {
+					t.Fatalf("ok=%v status=%d, want rejection with %d", ok, w.Code, tt.status)
+				}
+				return
+			}
+			if !ok || version != tt.version {
+				t.Fatalf("version=%d ok=%v (status %d), want %d", version, ok, w.Code, tt.version)
+			}
+		})
+	}
+}
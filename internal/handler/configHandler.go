@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"Clinic_backend/config"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goccy/go-yaml"
+)
+
+type ConfigHandler struct {
+	cfg *config.Holder
+}
+
+func NewConfigHandler(cfg *config.Holder) *ConfigHandler {
+	return &ConfigHandler{
+		cfg: cfg,
+	}
+}
+
+// Get godoc
+// @Summary Get effective configuration
+// @Description Get the configuration currently in effect, including reloaded values, with secrets redacted (admin only)
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} map[string]string
+// @Router /admin/config [get]
+func (h *ConfigHandler) Get(c *gin.Context) {
+	out, err := h.cfg.Get().Redacted().YAML()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Ключи и формат длительностей совпадают с файлом конфигурации
+	body, err := yaml.YAMLToJSON(out)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "application/json; charset=utf-8", body)
+}
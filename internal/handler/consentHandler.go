@@ -0,0 +1,284 @@
+package handler
+
+import (
+	"Clinic_backend/internal/entity"
+	"Clinic_backend/internal/repository"
+	"Clinic_backend/internal/service"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ConsentHandler struct {
+	consentService service.ConsentServiceInterface
+}
+
+func NewConsentHandler(consentService service.ConsentServiceInterface) *ConsentHandler {
+	return &ConsentHandler{
+		consentService: consentService,
+	}
+}
+
+// Current godoc
+// @Summary Current consent documents
+// @Description Get the current published version of every consent document with its full text. Registration must accept all mandatory ones
+// @Tags consents
+// @Produce json
+// @Success 200 {array} entity.ConsentDocument
+// @Router /consents/current [get]
+func (h *ConsentHandler) Current(c *gin.Context) {
+	documents, err := h.consentService.Current(c.Request.Context())
+	if err != nil {
+		writeConsentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, documents)
+}
+
+// ListDocuments godoc
+// @Summary List consent document versions
+// @Description List versions of consent documents without their text, newest first. Drafts are included for admins only
+// @Tags consents
+// @Produce json
+// @Param type query string false "Document type" Enums(privacy_policy, personal_data, marketing)
+// @Success 200 {array} entity.ConsentDocument
+// @Router /consents/documents [get]
+func (h *ConsentHandler) ListDocuments(c *gin.Context) {
+	var filter entity.ConsentDocumentFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	documents, err := h.consentService.ListDocuments(c.Request.Context(), &filter, c.GetString("role") != entity.RoleAdmin)
+	if err != nil {
+		writeConsentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, documents)
+}
+
+// GetDocument godoc
+// @Summary Get consent document version
+// @Description Get a version of a consent document with its text. Drafts are visible to admins only
+// @Tags consents
+// @Produce json
+// @Param id path int true "Consent document ID"
+// @Success 200 {object} entity.ConsentDocument
+// @Failure 404 {object} map[string]string
+// @Router /consents/documents/{id} [get]
+func (h *ConsentHandler) GetDocument(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid consent document ID"})
+		return
+	}
+
+	document, err := h.consentService.GetDocument(c.Request.Context(), id, c.GetString("role") != entity.RoleAdmin)
+	if err != nil {
+		writeConsentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, document)
+}
+
+// CreateDocument godoc
+// @Summary Create consent document draft
+// @Description Create a draft of the next version of a consent document. Users see it only after it is published (admin only)
+// @Tags consents
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body entity.ConsentDocumentRequest true "Document"
+// @Success 201 {object} entity.ConsentDocument
+// @Failure 400 {object} map[string]string
+// @Router /consents/documents [post]
+func (h *ConsentHandler) CreateDocument(c *gin.Context) {
+	var req entity.ConsentDocumentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	document, err := h.consentService.CreateDocument(c.Request.Context(), &req)
+	if err != nil {
+		writeConsentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, document)
+}
+
+// UpdateDocument godoc
+// @Summary Update consent document draft
+// @Description Edit the title and text of an unpublished draft; published versions are immutable (admin only)
+// @Tags consents
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Consent document ID"
+// @Param request body entity.ConsentDocumentUpdateRequest true "Document"
+// @Success 200 {object} entity.ConsentDocument
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /consents/documents/{id} [put]
+func (h *ConsentHandler) UpdateDocument(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid consent document ID"})
+		return
+	}
+
+	var req entity.ConsentDocumentUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	document, err := h.consentService.UpdateDocument(c.Request.Context(), id, &req)
+	if err != nil {
+		writeConsentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, document)
+}
+
+// PublishDocument godoc
+// @Summary Publish consent document
+// @Description Make a draft the current version of its document type. Users who accepted an earlier version are asked to accept it again on their next login (admin only)
+// @Tags consents
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Consent document ID"
+// @Success 200 {object} entity.ConsentDocument
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /consents/documents/{id}/publish [post]
+func (h *ConsentHandler) PublishDocument(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid consent document ID"})
+		return
+	}
+
+	document, err := h.consentService.PublishDocument(c.Request.Context(), id)
+	if err != nil {
+		writeConsentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, document)
+}
+
+// Status godoc
+// @Summary My consents
+// @Description Get the current user's consent for every document type compared with the current version; reconsent marks versions that have to be accepted again
+// @Tags consents
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} entity.ConsentStatus
+// @Failure 401 {object} map[string]string
+// @Router /users/me/consents [get]
+func (h *ConsentHandler) Status(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	statuses, err := h.consentService.Status(c.Request.Context(), userID)
+	if err != nil {
+		writeConsentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, statuses)
+}
+
+// Accept godoc
+// @Summary Accept consent documents
+// @Description Accept current versions of consent documents, e.g. after a new version is published or to opt in to marketing. The acceptance time and client IP are stored
+// @Tags consents
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body entity.ConsentAcceptRequest true "Accepted documents"
+// @Success 200 {array} entity.ConsentStatus
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /users/me/consents [post]
+func (h *ConsentHandler) Accept(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req entity.ConsentAcceptRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	statuses, err := h.consentService.Accept(c.Request.Context(), userID, req.DocumentIDs)
+	if err != nil {
+		writeConsentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, statuses)
+}
+
+// Withdraw godoc
+// @Summary Withdraw consent
+// @Description Withdraw an optional consent. Mandatory consents cannot be withdrawn; request erasure of personal data instead
+// @Tags consents
+// @Security BearerAuth
+// @Produce json
+// @Param type path string true "Document type" Enums(marketing)
+// @Success 200 {object} entity.UserConsent
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /users/me/consents/{type} [delete]
+func (h *ConsentHandler) Withdraw(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	consentType := c.Param("type")
+	if !slices.Contains(entity.ConsentTypes, consentType) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid consent type"})
+		return
+	}
+
+	consent, err := h.consentService.Withdraw(c.Request.Context(), userID, consentType)
+	if err != nil {
+		writeConsentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, consent)
+}
+
+func writeConsentError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrConsentDocumentNotCurrent), errors.Is(err, service.ErrMandatoryConsentMissing):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrConsentDocumentPublished), errors.Is(err, service.ErrConsentWithdrawalForbidden):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrConsentDocumentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Consent document not found"})
+	case errors.Is(err, repository.ErrConsentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Consent not found"})
+	default:
+		writePatientError(c, err)
+	}
+}
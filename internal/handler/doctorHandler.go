@@ -3,6 +3,7 @@ package handler
 import (
 	"Clinic_backend/internal/entity"
 	"Clinic_backend/internal/service"
+	"fmt"
 	"net/http"
 	"strconv"
 
@@ -43,18 +44,28 @@ func (h *DoctorHandler) CreateDoctor(c *gin.Context) {
 		return
 	}
 
+	setDoctorETag(c, doctor)
 	c.JSON(http.StatusCreated, doctor)
 }
 
 // GetAllDoctors godoc
 // @Summary Get all doctors
-// @Description Get list of all doctors
+// @Description Get list of all doctors with their rating from approved reviews
 // @Tags doctors
 // @Produce json
 // @Success 200 {array} entity.Doctor
+// @Failure 400 {object} map[string]string
+// @Param include_deleted query bool false "Include soft-deleted records (admin only)"
+// @Param sort query string false "Sort order: by ID (default) or by rating, best first" Enums(id, rating)
 // @Router /doctors [get]
 func (h *DoctorHandler) GetAllDoctors(c *gin.Context) {
-	doctors, err := h.doctorService.GetAllDoctors(c.Request.Context())
+	sort := c.DefaultQuery("sort", "id")
+	if sort != "id" && sort != entity.DoctorSortRating {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sort: expected id or rating"})
+		return
+	}
+
+	doctors, err := h.doctorService.GetAllDoctors(c.Request.Context(), includeDeleted(c), sort)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -65,7 +76,7 @@ func (h *DoctorHandler) GetAllDoctors(c *gin.Context) {
 
 // GetDoctorByID godoc
 // @Summary Get doctor by ID
-// @Description Get doctor details by ID
+// @Description Get doctor details by ID, including the rating from approved reviews
 // @Tags doctors
 // @Produce json
 // @Param id path int true "Doctor ID"
@@ -85,6 +96,7 @@ func (h *DoctorHandler) GetDoctorByID(c *gin.Context) {
 		return
 	}
 
+	setDoctorETag(c, doctor)
 	c.JSON(http.StatusOK, doctor)
 }
 
@@ -146,8 +158,11 @@ func (h *DoctorHandler) GetDoctorSchedule(c *gin.Context) {
 // @Produce json
 // @Param id path int true "Doctor ID"
 // @Param request body entity.DoctorUpdateRequest true "Doctor update data"
+// @Param If-Match header string true "ETag of the current version"
 // @Success 200 {object} entity.Doctor
 // @Failure 400 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 428 {object} map[string]string
 // @Router /doctors/{id} [put]
 func (h *DoctorHandler) UpdateDoctor(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
@@ -156,18 +171,24 @@ func (h *DoctorHandler) UpdateDoctor(c *gin.Context) {
 		return
 	}
 
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
 	var req entity.DoctorUpdateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	doctor, err := h.doctorService.UpdateDoctor(c.Request.Context(), id, &req)
+	doctor, err := h.doctorService.UpdateDoctor(c.Request.Context(), id, version, &req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeMutationError(c, err)
 		return
 	}
 
+	setDoctorETag(c, doctor)
 	c.JSON(http.StatusOK, doctor)
 }
 
@@ -177,8 +198,11 @@ func (h *DoctorHandler) UpdateDoctor(c *gin.Context) {
 // @Tags doctors
 // @Security BearerAuth
 // @Param id path int true "Doctor ID"
+// @Param If-Match header string true "ETag of the current version"
 // @Success 204
 // @Failure 400 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 428 {object} map[string]string
 // @Router /doctors/{id} [delete]
 func (h *DoctorHandler) DeleteDoctor(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
@@ -187,10 +211,50 @@ func (h *DoctorHandler) DeleteDoctor(c *gin.Context) {
 		return
 	}
 
-	if err := h.doctorService.DeleteDoctor(c.Request.Context(), id); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if err := h.doctorService.DeleteDoctor(c.Request.Context(), id, version); err != nil {
+		writeMutationError(c, err)
 		return
 	}
 
 	c.Status(http.StatusNoContent)
 }
+
+// RestoreDoctor godoc
+// @Summary Restore doctor
+// @Description Restore soft-deleted doctor by ID (admin only)
+// @Tags doctors
+// @Security BearerAuth
+// @Param id path int true "Doctor ID"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Router /doctors/{id}/restore [post]
+func (h *DoctorHandler) RestoreDoctor(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid doctor ID"})
+		return
+	}
+
+	if err := h.doctorService.RestoreDoctor(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// setDoctorETag выставляет ETag по версии врача и его рейтингу. Рейтинг пересчитывается
+// при модерации отзывов без изменения версии, поэтому без него If-None-Match отдавал бы
+// 304 с устаревшей оценкой. Для If-Match значима только версия (см. ifMatchVersion)
+func setDoctorETag(c *gin.Context, doctor *entity.Doctor) {
+	average := "0"
+	if doctor.RatingAverage != nil {
+		average = strconv.FormatFloat(*doctor.RatingAverage, 'f', 2, 64)
+	}
+	c.Header("ETag", fmt.Sprintf(`"%d-%d-%s"`, doctor.Version, doctor.RatingCount, average))
+}
@@ -0,0 +1,421 @@
+package handler
+
+import (
+	"Clinic_backend/internal/entity"
+	"Clinic_backend/internal/repository"
+	"Clinic_backend/internal/service"
+	"Clinic_backend/internal/utils"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type EncounterHandler struct {
+	encounterService service.EncounterServiceInterface
+}
+
+func NewEncounterHandler(encounterService service.EncounterServiceInterface) *EncounterHandler {
+	return &EncounterHandler{
+		encounterService: encounterService,
+	}
+}
+
+// SearchICD10 godoc
+// @Summary Search ICD-10 codes
+// @Description Search diagnoses by code prefix (J06, j069) or by words of the title
+// @Tags emr
+// @Security BearerAuth
+// @Produce json
+// @Param q query string true "Code prefix or title words"
+// @Param limit query int false "Maximum results (default 20, max 50)"
+// @Success 200 {array} icd10.Code
+// @Failure 400 {object} map[string]string
+// @Router /icd10 [get]
+func (h *EncounterHandler) SearchICD10(c *gin.Context) {
+	query := c.Query("q")
+	if query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Query parameter q is required"})
+		return
+	}
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	c.JSON(http.StatusOK, h.encounterService.SearchICD10(query, limit))
+}
+
+// Create godoc
+// @Summary Create encounter
+// @Description Create a draft visit record for a patient (doctor only). The caller must be assigned to the patient (see /patients/{id}/doctors) or have recorded an earlier visit with them
+// @Tags emr
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body entity.EncounterCreateRequest true "Encounter"
+// @Success 201 {object} entity.Encounter
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /encounters [post]
+func (h *EncounterHandler) Create(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req entity.EncounterCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	encounter, err := h.encounterService.Create(c.Request.Context(), userID, &req)
+	if err != nil {
+		writeEncounterError(c, err)
+		return
+	}
+
+	setETag(c, encounter.Version)
+	c.JSON(http.StatusCreated, encounter)
+}
+
+// List godoc
+// @Summary List encounters
+// @Description List signed encounters and the caller's own drafts (doctor, admin)
+// @Tags emr
+// @Security BearerAuth
+// @Produce json
+// @Param patient_id query int false "Patient profile ID"
+// @Param doctor_user_id query int false "Treating doctor user ID"
+// @Param status query string false "draft or signed"
+// @Param page query int false "Page number"
+// @Param limit query int false "Page size (max 100)"
+// @Success 200 {object} utils.PaginatedData
+// @Failure 403 {object} map[string]string
+// @Router /encounters [get]
+func (h *EncounterHandler) List(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var filter entity.EncounterFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	encounters, total, err := h.encounterService.List(c.Request.Context(), userID, &filter)
+	if err != nil {
+		writeEncounterError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.PaginatedData{
+		Data:  encounters,
+		Page:  filter.Page,
+		Limit: filter.Limit,
+		Total: total,
+	})
+}
+
+// GetByID godoc
+// @Summary Get encounter
+// @Description Get encounter by ID. Drafts are visible only to the treating doctor (doctor, admin)
+// @Tags emr
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Encounter ID"
+// @Success 200 {object} entity.Encounter
+// @Failure 404 {object} map[string]string
+// @Router /encounters/{id} [get]
+func (h *EncounterHandler) GetByID(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid encounter ID"})
+		return
+	}
+
+	encounter, err := h.encounterService.GetByID(c.Request.Context(), userID, id)
+	if err != nil {
+		writeEncounterError(c, err)
+		return
+	}
+
+	setETag(c, encounter.Version)
+	c.JSON(http.StatusOK, encounter)
+}
+
+// ListRevisions godoc
+// @Summary List encounter revisions
+// @Description Signed revisions of an encounter: the original signature and every amendment with its reason (doctor, admin)
+// @Tags emr
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Encounter ID"
+// @Success 200 {array} entity.EncounterRevision
+// @Failure 404 {object} map[string]string
+// @Router /encounters/{id}/revisions [get]
+func (h *EncounterHandler) ListRevisions(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid encounter ID"})
+		return
+	}
+
+	revisions, err := h.encounterService.ListRevisions(c.Request.Context(), userID, id)
+	if err != nil {
+		writeEncounterError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, revisions)
+}
+
+// UpdateDraft godoc
+// @Summary Update encounter draft
+// @Description Replace the content of a draft. Only the treating doctor may edit; signed encounters require an amendment
+// @Tags emr
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Encounter ID"
+// @Param If-Match header string true "Current encounter version (ETag)"
+// @Param request body entity.EncounterContentRequest true "Encounter content"
+// @Success 200 {object} entity.Encounter
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 428 {object} map[string]string
+// @Router /encounters/{id} [put]
+func (h *EncounterHandler) UpdateDraft(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid encounter ID"})
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	var req entity.EncounterContentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	encounter, err := h.encounterService.UpdateDraft(c.Request.Context(), userID, id, version, &req)
+	if err != nil {
+		writeEncounterError(c, err)
+		return
+	}
+
+	setETag(c, encounter.Version)
+	c.JSON(http.StatusOK, encounter)
+}
+
+// Sign godoc
+// @Summary Sign encounter
+// @Description Finalise a draft: it becomes revision 1 and is locked. A primary diagnosis is required
+// @Tags emr
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Encounter ID"
+// @Param If-Match header string true "Current encounter version (ETag)"
+// @Success 200 {object} entity.Encounter
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Router /encounters/{id}/sign [post]
+func (h *EncounterHandler) Sign(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid encounter ID"})
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	encounter, err := h.encounterService.Sign(c.Request.Context(), userID, id, version)
+	if err != nil {
+		writeEncounterError(c, err)
+		return
+	}
+
+	setETag(c, encounter.Version)
+	c.JSON(http.StatusOK, encounter)
+}
+
+// Amend godoc
+// @Summary Amend signed encounter
+// @Description Store the full corrected content of a signed encounter as a new revision with a reason
+// @Tags emr
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Encounter ID"
+// @Param If-Match header string true "Current encounter version (ETag)"
+// @Param request body entity.EncounterAmendmentRequest true "Amendment"
+// @Success 200 {object} entity.Encounter
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Router /encounters/{id}/amendments [post]
+func (h *EncounterHandler) Amend(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid encounter ID"})
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	var req entity.EncounterAmendmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	encounter, err := h.encounterService.Amend(c.Request.Context(), userID, id, version, &req)
+	if err != nil {
+		writeEncounterError(c, err)
+		return
+	}
+
+	setETag(c, encounter.Version)
+	c.JSON(http.StatusOK, encounter)
+}
+
+// ListMine godoc
+// @Summary List my encounters
+// @Description Signed visit records of the current user or, with patient_id, of a dependent
+// @Tags emr
+// @Security BearerAuth
+// @Produce json
+// @Param patient_id query int false "Dependent's patient profile ID"
+// @Param page query int false "Page number"
+// @Param limit query int false "Page size (max 100)"
+// @Success 200 {object} utils.PaginatedData
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /users/me/encounters [get]
+func (h *EncounterHandler) ListMine(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	patientID, ok := patientIDQuery(c)
+	if !ok {
+		return
+	}
+
+	var filter entity.EncounterFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	encounters, total, err := h.encounterService.ListForPatient(c.Request.Context(), userID, patientID, &filter)
+	if err != nil {
+		writeEncounterError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.PaginatedData{
+		Data:  encounters,
+		Page:  filter.Page,
+		Limit: filter.Limit,
+		Total: total,
+	})
+}
+
+// GetMine godoc
+// @Summary Get my encounter
+// @Description Signed visit record of the current user or, with patient_id, of a dependent
+// @Tags emr
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Encounter ID"
+// @Param patient_id query int false "Dependent's patient profile ID"
+// @Success 200 {object} entity.Encounter
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /users/me/encounters/{id} [get]
+func (h *EncounterHandler) GetMine(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	patientID, ok := patientIDQuery(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid encounter ID"})
+		return
+	}
+
+	encounter, err := h.encounterService.GetForPatient(c.Request.Context(), userID, patientID, id)
+	if err != nil {
+		writeEncounterError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, encounter)
+}
+
+// writeEncounterError отвечает кодом, соответствующим ошибке работы с медицинской картой
+func writeEncounterError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidEncounter):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrEncounterForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrEncounterLocked), errors.Is(err, service.ErrEncounterNotSigned):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrVersionConflict):
+		c.JSON(http.StatusPreconditionFailed, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrEncounterNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Encounter not found"})
+	default:
+		writePatientError(c, err)
+	}
+}
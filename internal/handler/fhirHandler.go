@@ -0,0 +1,225 @@
+package handler
+
+import (
+	"Clinic_backend/internal/entity"
+	"Clinic_backend/internal/fhir"
+	"Clinic_backend/internal/service"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Параметр поиска со ссылкой для каждого типа ресурса
+var fhirReferenceParams = map[string]string{
+	"PractitionerRole": "practitioner",
+	"Schedule":         "actor",
+	"Slot":             "schedule",
+}
+
+type FHIRHandler struct {
+	fhirService service.FHIRServiceInterface
+	// Дата публикации CapabilityStatement - запуск сервера
+	started time.Time
+}
+
+func NewFHIRHandler(fhirService service.FHIRServiceInterface) *FHIRHandler {
+	return &FHIRHandler{
+		fhirService: fhirService,
+		started:     time.Now(),
+	}
+}
+
+// Metadata godoc
+// @Summary FHIR CapabilityStatement
+// @Description Describes the read-only FHIR R4 facade: supported resources, interactions and search parameters
+// @Tags fhir
+// @Produce json
+// @Success 200 {object} fhir.CapabilityStatement
+// @Failure 406 {object} fhir.OperationOutcome
+// @Router /fhir/metadata [get]
+func (h *FHIRHandler) Metadata(c *gin.Context) {
+	if !fhirAcceptsJSON(c) {
+		return
+	}
+	writeFHIR(c, http.StatusOK, fhir.NewCapabilityStatement(apiBase(c)+"/fhir", "Clinic_backend", h.started.Format(time.DateOnly)))
+}
+
+// Search godoc
+// @Summary Search FHIR resources
+// @Description Returns a searchset Bundle. Supported parameters: _id (comma-separated), name (Practitioner, HealthcareService, Patient), practitioner (PractitionerRole), actor (Schedule), schedule (Slot), _count (up to 100) and _page. Slots are generated from weekly schedules for the next 4 weeks and are always free. Patient requires a doctor or admin token
+// @Tags fhir
+// @Produce json
+// @Param _id query string false "Resource IDs, comma-separated"
+// @Param name query string false "Start of any word of the name"
+// @Param _count query int false "Page size"
+// @Param _page query int false "Page number"
+// @Success 200 {object} fhir.Bundle
+// @Failure 400 {object} fhir.OperationOutcome
+// @Failure 406 {object} fhir.OperationOutcome
+// @Router /fhir/Practitioner [get]
+// @Router /fhir/PractitionerRole [get]
+// @Router /fhir/HealthcareService [get]
+// @Router /fhir/Schedule [get]
+// @Router /fhir/Slot [get]
+// @Router /fhir/Patient [get]
+func (h *FHIRHandler) Search(resourceType string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !fhirAcceptsJSON(c) {
+			return
+		}
+
+		query := &service.FHIRQuery{
+			Name:  c.Query("name"),
+			Count: fhirNumber(c.Query("_count")),
+			Page:  fhirNumber(c.Query("_page")),
+			// Без фильтра пациентов перечисляет только администратор
+			Unrestricted: c.GetString("role") == entity.RoleAdmin,
+		}
+		for _, id := range strings.Split(c.Query("_id"), ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				query.IDs = append(query.IDs, id)
+			}
+		}
+		if param, ok := fhirReferenceParams[resourceType]; ok {
+			query.Reference = c.Query(param)
+		}
+
+		resources, total, err := h.fhirService.Search(c.Request.Context(), apiBase(c), resourceType, query)
+		if err != nil {
+			writeFHIRError(c, err)
+			return
+		}
+
+		base := apiBase(c) + "/fhir"
+		bundle := fhir.Bundle{
+			ResourceType: "Bundle",
+			Type:         "searchset",
+			Total:        total,
+			Link:         []fhir.BundleLink{{Relation: "self", URL: fhirPageURL(c, base, resourceType, query.Page, query.Count)}},
+			Entry:        make([]fhir.BundleEntry, 0, len(resources)),
+		}
+		if query.Page*query.Count < total {
+			bundle.Link = append(bundle.Link, fhir.BundleLink{Relation: "next", URL: fhirPageURL(c, base, resourceType, query.Page+1, query.Count)})
+		}
+		for _, resource := range resources {
+			bundle.Entry = append(bundle.Entry, fhir.BundleEntry{
+				FullURL:  base + "/" + resource.Reference(),
+				Resource: resource,
+				Search:   &fhir.EntrySearch{Mode: "match"},
+			})
+		}
+		writeFHIR(c, http.StatusOK, bundle)
+	}
+}
+
+// Read godoc
+// @Summary Read a FHIR resource
+// @Description Returns a single resource by ID. Slot IDs have the form {doctor_id}-{YYYYMMDD}. Patient requires a doctor or admin token
+// @Tags fhir
+// @Produce json
+// @Param id path string true "Resource ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} fhir.OperationOutcome
+// @Failure 406 {object} fhir.OperationOutcome
+// @Router /fhir/Practitioner/{id} [get]
+// @Router /fhir/PractitionerRole/{id} [get]
+// @Router /fhir/HealthcareService/{id} [get]
+// @Router /fhir/Schedule/{id} [get]
+// @Router /fhir/Slot/{id} [get]
+// @Router /fhir/Patient/{id} [get]
+func (h *FHIRHandler) Read(resourceType string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !fhirAcceptsJSON(c) {
+			return
+		}
+
+		resource, err := h.fhirService.Read(c.Request.Context(), apiBase(c), resourceType, c.Param("id"))
+		if err != nil {
+			writeFHIRError(c, err)
+			return
+		}
+		writeFHIR(c, http.StatusOK, resource)
+	}
+}
+
+// apiBase - внешний адрес REST API, от которого строятся ссылки FHIR
+func apiBase(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	} else if proto := c.GetHeader("X-Forwarded-Proto"); proto == "https" || proto == "http" {
+		scheme = proto
+	}
+	return scheme + "://" + c.Request.Host + "/api/v1"
+}
+
+// fhirPageURL - ссылка на страницу поиска с теми же параметрами
+func fhirPageURL(c *gin.Context, base, resourceType string, page, count int) string {
+	values := url.Values{}
+	for key, value := range c.Request.URL.Query() {
+		values[key] = value
+	}
+	values.Set("_page", strconv.Itoa(page))
+	values.Set("_count", strconv.Itoa(count))
+	return base + "/" + resourceType + "?" + values.Encode()
+}
+
+// fhirNumber - числовой параметр; некорректные значения заменяются значениями по умолчанию
+func fhirNumber(value string) int {
+	n, _ := strconv.Atoi(value)
+	return n
+}
+
+// fhirAcceptsJSON проверяет _format и Accept: поддерживается только JSON.
+// При отказе ответ уже отправлен, обработчик должен завершиться.
+func fhirAcceptsJSON(c *gin.Context) bool {
+	if format := c.Query("_format"); format != "" {
+		switch format {
+		case "json", "application/json", fhir.ContentType:
+			return true
+		}
+		writeFHIR(c, http.StatusNotAcceptable, fhir.NewOperationOutcome("not-supported", "only JSON format is supported"))
+		return false
+	}
+
+	accept := c.GetHeader("Accept")
+	if accept == "" {
+		return true
+	}
+	for _, mediaType := range strings.Split(accept, ",") {
+		mediaType, _, _ = strings.Cut(mediaType, ";")
+		switch strings.TrimSpace(mediaType) {
+		case "*/*", "application/*", "application/json", fhir.ContentType:
+			return true
+		}
+	}
+	writeFHIR(c, http.StatusNotAcceptable, fhir.NewOperationOutcome("not-supported", "only JSON format is supported"))
+	return false
+}
+
+func writeFHIR(c *gin.Context, status int, body any) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to encode resource"})
+		return
+	}
+	c.Data(status, fhir.ContentType+"; charset=utf-8", data)
+}
+
+// writeFHIRError отвечает ошибкой в виде OperationOutcome
+func writeFHIRError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrFHIRNotFound):
+		writeFHIR(c, http.StatusNotFound, fhir.NewOperationOutcome("not-found", "Resource not found"))
+	case errors.Is(err, service.ErrFHIRInvalidSearch), errors.Is(err, service.ErrFHIRUnknownResource):
+		writeFHIR(c, http.StatusBadRequest, fhir.NewOperationOutcome("invalid", err.Error()))
+	default:
+		writeFHIR(c, http.StatusInternalServerError, fhir.NewOperationOutcome("exception", "Failed to load resources"))
+	}
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"Clinic_backend/internal/entity"
+	"Clinic_backend/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HealthHandler struct {
+	healthService service.HealthServiceInterface
+}
+
+func NewHealthHandler(healthService service.HealthServiceInterface) *HealthHandler {
+	return &HealthHandler{
+		healthService: healthService,
+	}
+}
+
+// Live godoc
+// @Summary Liveness probe
+// @Description Reports that the process is running. Does not check dependencies
+// @Tags health
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Router /health/live [get]
+func (h *HealthHandler) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": entity.HealthStatusOK})
+}
+
+// Ready godoc
+// @Summary Readiness probe
+// @Description Checks the database, schema migrations and optional dependencies with per-component latency. Fails during graceful shutdown
+// @Tags health
+// @Produce json
+// @Success 200 {object} entity.HealthReport
+// @Failure 503 {object} entity.HealthReport
+// @Router /health/ready [get]
+func (h *HealthHandler) Ready(c *gin.Context) {
+	report := h.healthService.Ready(c.Request.Context())
+
+	status := http.StatusOK
+	if report.Status == entity.HealthStatusFailing || report.Status == entity.HealthStatusShuttingDown {
+		status = http.StatusServiceUnavailable
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(status, report)
+}
@@ -0,0 +1,559 @@
+package handler
+
+import (
+	"Clinic_backend/config"
+	"Clinic_backend/internal/entity"
+	"Clinic_backend/internal/lab"
+	"Clinic_backend/internal/repository"
+	"Clinic_backend/internal/service"
+	"Clinic_backend/internal/utils"
+	"bytes"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LabHandler struct {
+	labService service.LabServiceInterface
+	cfg        *config.Holder
+}
+
+func NewLabHandler(labService service.LabServiceInterface, cfg *config.Holder) *LabHandler {
+	return &LabHandler{
+		labService: labService,
+		cfg:        cfg,
+	}
+}
+
+// Create godoc
+// @Summary Create lab order
+// @Description Order laboratory tests for a patient on behalf of the doctor linked to the current account (doctor only)
+// @Tags lab
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body entity.LabOrderCreateRequest true "Lab order"
+// @Success 201 {object} entity.LabOrder
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /lab-orders [post]
+func (h *LabHandler) Create(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req entity.LabOrderCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	order, err := h.labService.CreateOrder(c.Request.Context(), userID, &req)
+	if err != nil {
+		writeLabError(c, err)
+		return
+	}
+
+	setETag(c, order.Version)
+	c.JSON(http.StatusCreated, order)
+}
+
+// List godoc
+// @Summary List lab orders
+// @Description List active lab orders without results (doctor, admin)
+// @Tags lab
+// @Security BearerAuth
+// @Produce json
+// @Param patient_id query int false "Patient profile ID"
+// @Param doctor_id query int false "Ordering doctor ID"
+// @Param status query string false "Status" Enums(ordered, sampled, in_progress, ready)
+// @Param page query int false "Page number"
+// @Param limit query int false "Page size (max 100)"
+// @Success 200 {object} utils.PaginatedData
+// @Router /lab-orders [get]
+func (h *LabHandler) List(c *gin.Context) {
+	var filter entity.LabOrderFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	orders, total, err := h.labService.ListOrders(c.Request.Context(), &filter)
+	if err != nil {
+		writeLabError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.PaginatedData{
+		Data:  orders,
+		Page:  filter.Page,
+		Limit: filter.Limit,
+		Total: total,
+	})
+}
+
+// GetByID godoc
+// @Summary Get lab order
+// @Description Get lab order with all results, including preliminary ones, and attachments (doctor, admin)
+// @Tags lab
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Lab order ID"
+// @Success 200 {object} entity.LabOrder
+// @Failure 404 {object} map[string]string
+// @Router /lab-orders/{id} [get]
+func (h *LabHandler) GetByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid lab order ID"})
+		return
+	}
+
+	order, err := h.labService.GetOrder(c.Request.Context(), id)
+	if err != nil {
+		writeLabError(c, err)
+		return
+	}
+
+	setETag(c, order.Version)
+	c.JSON(http.StatusOK, order)
+}
+
+// UpdateStatus godoc
+// @Summary Change lab order status
+// @Description Move a lab order forward: ordered -> sampled -> in_progress -> ready. A ready order needs results or an attachment (doctor, admin)
+// @Tags lab
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Lab order ID"
+// @Param If-Match header string true "Current lab order version (ETag)"
+// @Param request body entity.LabStatusRequest true "New status"
+// @Success 200 {object} entity.LabOrder
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 428 {object} map[string]string
+// @Router /lab-orders/{id}/status [post]
+func (h *LabHandler) UpdateStatus(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid lab order ID"})
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	var req entity.LabStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	order, err := h.labService.UpdateStatus(c.Request.Context(), id, version, req.Status)
+	if err != nil {
+		writeLabError(c, err)
+		return
+	}
+
+	setETag(c, order.Version)
+	c.JSON(http.StatusOK, order)
+}
+
+// Cancel godoc
+// @Summary Cancel lab order
+// @Description Cancel a lab order that is still awaiting sampling. Only the ordering doctor may cancel it
+// @Tags lab
+// @Security BearerAuth
+// @Param id path int true "Lab order ID"
+// @Param If-Match header string true "Current lab order version (ETag)"
+// @Success 204
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 428 {object} map[string]string
+// @Router /lab-orders/{id} [delete]
+func (h *LabHandler) Cancel(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid lab order ID"})
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if err := h.labService.CancelOrder(c.Request.Context(), userID, id, version); err != nil {
+		writeLabError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// AddResults godoc
+// @Summary Enter lab results
+// @Description Enter or correct results manually. Flags are computed from the reference range unless given. The order becomes ready once every ordered test has a final result (doctor, admin)
+// @Tags lab
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Lab order ID"
+// @Param request body entity.LabResultsRequest true "Results"
+// @Success 200 {object} entity.LabOrder
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /lab-orders/{id}/results [post]
+func (h *LabHandler) AddResults(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid lab order ID"})
+		return
+	}
+
+	var req entity.LabResultsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	order, err := h.labService.AddResults(c.Request.Context(), id, &req)
+	if err != nil {
+		writeLabError(c, err)
+		return
+	}
+
+	setETag(c, order.Version)
+	c.JSON(http.StatusOK, order)
+}
+
+// UploadAttachment godoc
+// @Summary Attach PDF result
+// @Description Attach a PDF result form to a lab order as multipart "file". Staff use a bearer token (doctor, admin), laboratories the X-Lab-Token header
+// @Tags lab
+// @Security BearerAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Lab order ID"
+// @Param file formData file true "PDF document"
+// @Success 201 {object} entity.LabAttachment
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 413 {object} map[string]string
+// @Failure 415 {object} map[string]string
+// @Router /lab-orders/{id}/attachments [post]
+// @Router /lab/orders/{id}/attachments [post]
+func (h *LabHandler) UploadAttachment(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid lab order ID"})
+		return
+	}
+
+	maxSize := h.cfg.Get().Lab.MaxAttachmentSize
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize+multipartOverhead)
+
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": service.ErrLabAttachmentTooLarge.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+	defer file.Close()
+
+	if header.Size > maxSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": service.ErrLabAttachmentTooLarge.Error()})
+		return
+	}
+
+	attachment, err := h.labService.AddAttachment(c.Request.Context(), id, header.Filename, file)
+	if err != nil {
+		writeLabError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, attachment)
+}
+
+// DownloadAttachment godoc
+// @Summary Download PDF result
+// @Description Download a PDF result form of a lab order (doctor, admin)
+// @Tags lab
+// @Security BearerAuth
+// @Produce application/pdf
+// @Param id path int true "Lab order ID"
+// @Param attachment_id path int true "Attachment ID"
+// @Success 200 {file} file
+// @Failure 404 {object} map[string]string
+// @Router /lab-orders/{id}/attachments/{attachment_id} [get]
+func (h *LabHandler) DownloadAttachment(c *gin.Context) {
+	orderID, attachmentID, ok := attachmentParams(c)
+	if !ok {
+		return
+	}
+
+	attachment, body, err := h.labService.OpenAttachment(c.Request.Context(), orderID, attachmentID)
+	if err != nil {
+		writeLabError(c, err)
+		return
+	}
+	defer body.Close()
+
+	writeLabAttachment(c, attachment, body)
+}
+
+// Import godoc
+// @Summary Import lab results
+// @Description Import results sent by a laboratory, authenticated with the X-Lab-Token header. Accepts CSV (text/csv; columns order_id, test_code, value and optionally test_name, unit, reference_range, flag, status, observed_at) or an HL7 v2 ORU^R01 message (application/hl7-v2) where OBR-2 is the lab order ID; OBX segments of type ED carry Base64 PDF result forms. The file is applied entirely or not at all
+// @Tags lab
+// @Accept text/csv,application/hl7-v2
+// @Produce json
+// @Param X-Lab-Token header string true "Laboratory token"
+// @Success 200 {object} entity.LabImportSummary
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 413 {object} map[string]string
+// @Failure 415 {object} map[string]string
+// @Router /lab/results [post]
+func (h *LabHandler) Import(c *gin.Context) {
+	// В HL7 бланки передаются в base64, который длиннее содержимого на треть
+	maxSize := h.cfg.Get().Lab.MaxAttachmentSize
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize/3*4+multipartOverhead)
+
+	data, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "import file is too large"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body"})
+		return
+	}
+
+	format, ok := labImportFormat(c.ContentType(), data)
+	if !ok {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "expected text/csv or application/hl7-v2"})
+		return
+	}
+
+	summary, err := h.labService.Import(c.Request.Context(), format, data)
+	if err != nil {
+		writeLabError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// ListMine godoc
+// @Summary List my lab orders
+// @Description Lab orders of the current user or, with patient_id, of a dependent
+// @Tags lab
+// @Security BearerAuth
+// @Produce json
+// @Param patient_id query int false "Dependent's patient profile ID"
+// @Param status query string false "Status" Enums(ordered, sampled, in_progress, ready)
+// @Param page query int false "Page number"
+// @Param limit query int false "Page size (max 100)"
+// @Success 200 {object} utils.PaginatedData
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /users/me/lab-orders [get]
+func (h *LabHandler) ListMine(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	patientID, ok := patientIDQuery(c)
+	if !ok {
+		return
+	}
+
+	var filter entity.LabOrderFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	orders, total, err := h.labService.ListForPatient(c.Request.Context(), userID, patientID, &filter)
+	if err != nil {
+		writeLabError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.PaginatedData{
+		Data:  orders,
+		Page:  filter.Page,
+		Limit: filter.Limit,
+		Total: total,
+	})
+}
+
+// GetMine godoc
+// @Summary Get my lab order
+// @Description Lab order of the current user or, with patient_id, of a dependent. Results and attachments are included once the order is ready
+// @Tags lab
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Lab order ID"
+// @Param patient_id query int false "Dependent's patient profile ID"
+// @Success 200 {object} entity.LabOrder
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /users/me/lab-orders/{id} [get]
+func (h *LabHandler) GetMine(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	patientID, ok := patientIDQuery(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid lab order ID"})
+		return
+	}
+
+	order, err := h.labService.GetForPatient(c.Request.Context(), userID, patientID, id)
+	if err != nil {
+		writeLabError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+// DownloadMineAttachment godoc
+// @Summary Download my PDF result
+// @Description PDF result form of a ready lab order of the current user or, with patient_id, of a dependent
+// @Tags lab
+// @Security BearerAuth
+// @Produce application/pdf
+// @Param id path int true "Lab order ID"
+// @Param attachment_id path int true "Attachment ID"
+// @Param patient_id query int false "Dependent's patient profile ID"
+// @Success 200 {file} file
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /users/me/lab-orders/{id}/attachments/{attachment_id} [get]
+func (h *LabHandler) DownloadMineAttachment(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	patientID, ok := patientIDQuery(c)
+	if !ok {
+		return
+	}
+
+	orderID, attachmentID, ok := attachmentParams(c)
+	if !ok {
+		return
+	}
+
+	attachment, body, err := h.labService.OpenAttachmentForPatient(c.Request.Context(), userID, patientID, orderID, attachmentID)
+	if err != nil {
+		writeLabError(c, err)
+		return
+	}
+	defer body.Close()
+
+	writeLabAttachment(c, attachment, body)
+}
+
+// attachmentParams читает ID направления и бланка из пути.
+// При ошибке ответ уже отправлен, обработчик должен завершиться.
+func attachmentParams(c *gin.Context) (int, int, bool) {
+	orderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid lab order ID"})
+		return 0, 0, false
+	}
+
+	attachmentID, err := strconv.Atoi(c.Param("attachment_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid attachment ID"})
+		return 0, 0, false
+	}
+
+	return orderID, attachmentID, true
+}
+
+// writeLabAttachment отдаёт бланк на скачивание. Документ содержит медицинские данные,
+// поэтому не кэшируется
+func writeLabAttachment(c *gin.Context, attachment *entity.LabAttachment, body io.Reader) {
+	c.DataFromReader(http.StatusOK, attachment.Size, "application/pdf", body, map[string]string{
+		"Content-Disposition": mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}),
+		"Cache-Control":       "no-store",
+	})
+}
+
+// labImportFormat определяет формат файла по Content-Type; для универсальных типов -
+// по содержимому: сообщение HL7 начинается с сегмента MSH
+func labImportFormat(contentType string, data []byte) (string, bool) {
+	switch contentType {
+	case "text/csv", "application/csv":
+		return service.LabFormatCSV, true
+	case "application/hl7-v2", "x-application/hl7-v2+er7", "application/edi-hl7":
+		return service.LabFormatHL7, true
+	case "", "text/plain", "application/octet-stream":
+		if bytes.HasPrefix(bytes.TrimLeft(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")), " \t\r\n"), []byte("MSH")) {
+			return service.LabFormatHL7, true
+		}
+		return service.LabFormatCSV, true
+	}
+	return "", false
+}
+
+// writeLabError отвечает кодом, соответствующим ошибке работы с анализами
+func writeLabError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidLabOrder), errors.Is(err, lab.ErrInvalidImport):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrDoctorProfileRequired), errors.Is(err, service.ErrLabOrderForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrLabStatusTransition),
+		errors.Is(err, service.ErrLabOrderNotCancellable),
+		errors.Is(err, service.ErrLabResultsMissing):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrVersionConflict):
+		c.JSON(http.StatusPreconditionFailed, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrLabAttachmentTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrLabAttachmentNotPDF):
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrLabOrderNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Lab order not found"})
+	case errors.Is(err, repository.ErrLabAttachmentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Attachment not found"})
+	default:
+		writePatientError(c, err)
+	}
+}
@@ -43,6 +43,7 @@ func (h *LicenseHandler) CreateLicense(c *gin.Context) {
 		return
 	}
 
+	setETag(c, created.Version)
 	c.JSON(http.StatusCreated, created)
 }
 
@@ -52,9 +53,10 @@ func (h *LicenseHandler) CreateLicense(c *gin.Context) {
 // @Tags licenses
 // @Produce json
 // @Success 200 {array} entity.License
+// @Param include_deleted query bool false "Include soft-deleted records (admin only)"
 // @Router /licenses [get]
 func (h *LicenseHandler) GetAllLicenses(c *gin.Context) {
-	licenses, err := h.licenseService.GetAllLicenses(c.Request.Context())
+	licenses, err := h.licenseService.GetAllLicenses(c.Request.Context(), includeDeleted(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -85,6 +87,7 @@ func (h *LicenseHandler) GetLicenseByID(c *gin.Context) {
 		return
 	}
 
+	setETag(c, license.Version)
 	c.JSON(http.StatusOK, license)
 }
 
@@ -97,8 +100,11 @@ func (h *LicenseHandler) GetLicenseByID(c *gin.Context) {
 // @Produce json
 // @Param id path int true "License ID"
 // @Param request body entity.License true "License update data"
+// @Param If-Match header string true "ETag of the current version"
 // @Success 200 {object} entity.License
 // @Failure 400 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 428 {object} map[string]string
 // @Router /licenses/{id} [put]
 func (h *LicenseHandler) UpdateLicense(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
@@ -107,18 +113,24 @@ func (h *LicenseHandler) UpdateLicense(c *gin.Context) {
 		return
 	}
 
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
 	var license entity.License
 	if err := c.ShouldBindJSON(&license); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	updated, err := h.licenseService.UpdateLicense(c.Request.Context(), id, &license)
+	updated, err := h.licenseService.UpdateLicense(c.Request.Context(), id, version, &license)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeMutationError(c, err)
 		return
 	}
 
+	setETag(c, updated.Version)
 	c.JSON(http.StatusOK, updated)
 }
 
@@ -128,8 +140,11 @@ func (h *LicenseHandler) UpdateLicense(c *gin.Context) {
 // @Tags licenses
 // @Security BearerAuth
 // @Param id path int true "License ID"
+// @Param If-Match header string true "ETag of the current version"
 // @Success 204
 // @Failure 400 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 428 {object} map[string]string
 // @Router /licenses/{id} [delete]
 func (h *LicenseHandler) DeleteLicense(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
@@ -138,8 +153,37 @@ func (h *LicenseHandler) DeleteLicense(c *gin.Context) {
 		return
 	}
 
-	if err := h.licenseService.DeleteLicense(c.Request.Context(), id); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if err := h.licenseService.DeleteLicense(c.Request.Context(), id, version); err != nil {
+		writeMutationError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RestoreLicense godoc
+// @Summary Restore license
+// @Description Restore soft-deleted license by ID (admin only)
+// @Tags licenses
+// @Security BearerAuth
+// @Param id path int true "License ID"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Router /licenses/{id}/restore [post]
+func (h *LicenseHandler) RestoreLicense(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid license ID"})
+		return
+	}
+
+	if err := h.licenseService.RestoreLicense(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
 
@@ -0,0 +1,233 @@
+package handler
+
+import (
+	"Clinic_backend/config"
+	"Clinic_backend/internal/entity"
+	"Clinic_backend/internal/media"
+	"Clinic_backend/internal/repository"
+	"Clinic_backend/internal/service"
+	"Clinic_backend/internal/utils"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Запас на заголовки и границы multipart поверх размера самого файла
+const multipartOverhead = 1 << 20
+
+type MediaHandler struct {
+	mediaService service.MediaServiceInterface
+	cfg          *config.Holder
+}
+
+func NewMediaHandler(mediaService service.MediaServiceInterface, cfg *config.Holder) *MediaHandler {
+	return &MediaHandler{
+		mediaService: mediaService,
+		cfg:          cfg,
+	}
+}
+
+// Upload godoc
+// @Summary Upload image
+// @Description Upload a JPEG, PNG or WebP image (admin only) as multipart "file" or as JSON with a data URI / base64 string. The type is detected from the content, EXIF/XMP metadata is removed. Returns the media ID to reference from doctors, services, categories, licenses and carousel slides. Resized variants are generated in the background
+// @Tags media
+// @Security BearerAuth
+// @Accept multipart/form-data,json
+// @Produce json
+// @Param file formData file false "Image file"
+// @Param request body entity.MediaUploadRequest false "Base64 image"
+// @Success 201 {object} entity.Media
+// @Failure 400 {object} map[string]string
+// @Failure 413 {object} map[string]string
+// @Failure 415 {object} map[string]string
+// @Router /media [post]
+func (h *MediaHandler) Upload(c *gin.Context) {
+	maxSize := h.cfg.Get().Media.MaxUploadSize
+
+	if c.ContentType() == "application/json" {
+		h.uploadBase64(c, maxSize)
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize+multipartOverhead)
+
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": service.ErrMediaTooLarge.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+	defer file.Close()
+
+	if header.Size > maxSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": service.ErrMediaTooLarge.Error()})
+		return
+	}
+
+	created, err := h.mediaService.Upload(c.Request.Context(), header.Filename, file)
+	if err != nil {
+		uploadError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+func (h *MediaHandler) uploadBase64(c *gin.Context, maxSize int64) {
+	// base64 длиннее содержимого на треть
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize/3*4+multipartOverhead)
+
+	var req entity.MediaUploadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": service.ErrMediaTooLarge.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	created, err := h.mediaService.UploadBase64(c.Request.Context(), req.Filename, req.Data)
+	if err != nil {
+		uploadError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+func uploadError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrMediaTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+	case errors.Is(err, media.ErrUnsupportedType):
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+	case errors.Is(err, media.ErrInvalidImage), errors.Is(err, media.ErrTooManyPixels):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store file"})
+	}
+}
+
+// Serve godoc
+// @Summary Get image
+// @Description Get image content by media ID. Content never changes for an ID, so responses are cacheable forever. With w the resized variant of that width is returned (one of media.variant_widths, as listed in *_srcset); until it is generated the original is returned with a short cache lifetime
+// @Tags media
+// @Produce image/jpeg,image/png,image/webp
+// @Param id path string true "Media ID"
+// @Param w query int false "Variant width"
+// @Success 200 {file} file
+// @Success 304 "Not modified"
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /media/{id} [get]
+func (h *MediaHandler) Serve(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	m, err := h.mediaService.GetByID(ctx, c.Param("id"))
+	if err != nil {
+		if errors.Is(err, repository.ErrMediaNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Media not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if c.Query("w") != "" {
+		if h.serveVariant(c, m) {
+			return
+		}
+		// Копия ещё не готова или не нужна (изображение уже узкое) - отдаём оригинал,
+		// но ненадолго, чтобы клиент потом получил копию
+		c.Header("Cache-Control", "public, max-age=300")
+	} else {
+		c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	}
+
+	etag := `"` + m.ID + `"`
+	c.Header("ETag", etag)
+	c.Header("Last-Modified", m.CreatedAt.UTC().Format(http.TimeFormat))
+
+	if utils.ETagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	body, err := h.mediaService.Open(ctx, m.ID)
+	if err != nil {
+		c.Header("Cache-Control", "no-store")
+		if errors.Is(err, repository.ErrMediaNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Media not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read file"})
+		return
+	}
+	defer body.Close()
+
+	c.DataFromReader(http.StatusOK, m.Size, m.ContentType, body, map[string]string{
+		"Content-Disposition": "inline",
+	})
+}
+
+// serveVariant отдаёт копию нужной ширины. Возвращает false, если копии нет
+// и нужно отдать оригинал; в остальных случаях ответ уже отправлен.
+func (h *MediaHandler) serveVariant(c *gin.Context, m *entity.Media) bool {
+	ctx := c.Request.Context()
+
+	width, err := strconv.Atoi(c.Query("w"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid width"})
+		return true
+	}
+
+	variant, err := h.mediaService.GetVariant(ctx, m.ID, width)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrMediaWidthNotAllowed):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return true
+		case errors.Is(err, repository.ErrMediaVariantNotFound):
+			return false
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return true
+		}
+	}
+
+	etag := `"` + m.ID + `-w` + strconv.Itoa(width) + `"`
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	c.Header("Last-Modified", m.CreatedAt.UTC().Format(http.TimeFormat))
+
+	if utils.ETagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return true
+	}
+
+	body, err := h.mediaService.OpenVariant(ctx, m.ID, width)
+	if errors.Is(err, repository.ErrMediaVariantNotFound) {
+		// Запись о копии есть, а объекта в хранилище нет - отдаём оригинал
+		c.Header("ETag", "")
+		return false
+	}
+	if err != nil {
+		c.Header("Cache-Control", "no-store")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read file"})
+		return true
+	}
+	defer body.Close()
+
+	c.DataFromReader(http.StatusOK, variant.Size, variant.ContentType, body, map[string]string{
+		"Content-Disposition": "inline",
+	})
+	return true
+}
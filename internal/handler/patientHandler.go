@@ -0,0 +1,406 @@
+package handler
+
+import (
+	"Clinic_backend/internal/entity"
+	"Clinic_backend/internal/repository"
+	"Clinic_backend/internal/service"
+	"Clinic_backend/internal/utils"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PatientHandler struct {
+	patientService service.PatientServiceInterface
+}
+
+func NewPatientHandler(patientService service.PatientServiceInterface) *PatientHandler {
+	return &PatientHandler{
+		patientService: patientService,
+	}
+}
+
+// GetMyProfile godoc
+// @Summary Get current user's patient profile
+// @Description Get personal and medical identifiers of the authenticated user or, with patient_id, of a dependent
+// @Tags patients
+// @Security BearerAuth
+// @Produce json
+// @Param patient_id query int false "Dependent's patient profile ID"
+// @Success 200 {object} entity.PatientProfile
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /users/me/profile [get]
+func (h *PatientHandler) GetMyProfile(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	patientID, ok := patientIDQuery(c)
+	if !ok {
+		return
+	}
+
+	profile, err := h.patientService.GetMyProfile(c.Request.Context(), userID, patientID)
+	if err != nil {
+		writePatientError(c, err)
+		return
+	}
+
+	setETag(c, profile.Version)
+	c.JSON(http.StatusOK, profile)
+}
+
+// SaveMyProfile godoc
+// @Summary Create or replace current user's patient profile
+// @Description Phone is normalized to E.164, SNILS is checked against its checksum, insurance policy must have 16 digits. Omitted optional fields are cleared. With patient_id the dependent's profile is replaced; the dependent's birth_date is kept as set when the dependent was added (omit it or send the same value, a different one is rejected with 403).
+// @Tags patients
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param patient_id query int false "Dependent's patient profile ID"
+// @Param request body entity.PatientProfileRequest true "Patient profile"
+// @Success 200 {object} entity.PatientProfile
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /users/me/profile [put]
+func (h *PatientHandler) SaveMyProfile(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	patientID, ok := patientIDQuery(c)
+	if !ok {
+		return
+	}
+
+	var req entity.PatientProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	profile, err := h.patientService.SaveMyProfile(c.Request.Context(), userID, patientID, &req)
+	if err != nil {
+		writePatientError(c, err)
+		return
+	}
+
+	setETag(c, profile.Version)
+	c.JSON(http.StatusOK, profile)
+}
+
+// ListDependents godoc
+// @Summary List dependents
+// @Description List patient profiles managed by the current user with the access currently granted by age and consent
+// @Tags patients
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} entity.Dependent
+// @Failure 401 {object} map[string]string
+// @Router /users/me/dependents [get]
+func (h *PatientHandler) ListDependents(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	dependents, err := h.patientService.ListDependents(c.Request.Context(), userID)
+	if err != nil {
+		writePatientError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dependents)
+}
+
+// AddDependent godoc
+// @Summary Add dependent
+// @Description Create a patient profile without its own login (a child, an elderly relative) managed by the current user. Dependents aged 15+ need confirmed consent for consent flags, adults need it for guardianship itself.
+// @Tags patients
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body entity.DependentCreateRequest true "Dependent"
+// @Success 201 {object} entity.Dependent
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /users/me/dependents [post]
+func (h *PatientHandler) AddDependent(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req entity.DependentCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	dependent, err := h.patientService.AddDependent(c.Request.Context(), userID, &req)
+	if err != nil {
+		writePatientError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dependent)
+}
+
+// UpdateDependent godoc
+// @Summary Update guardianship
+// @Description Change relationship and consent flags of a dependent. Granting consent for a dependent aged 15+ requires consent_confirmed.
+// @Tags patients
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param patient_id path int true "Dependent's patient profile ID"
+// @Param request body entity.GuardianshipUpdateRequest true "Guardianship changes"
+// @Success 200 {object} entity.Dependent
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /users/me/dependents/{patient_id} [put]
+func (h *PatientHandler) UpdateDependent(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	patientID, err := strconv.Atoi(c.Param("patient_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid patient ID"})
+		return
+	}
+
+	var req entity.GuardianshipUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	dependent, err := h.patientService.UpdateDependent(c.Request.Context(), userID, patientID, &req)
+	if err != nil {
+		writePatientError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dependent)
+}
+
+// RemoveDependent godoc
+// @Summary Remove dependent
+// @Description Stop managing a dependent. The patient profile and its medical data are kept.
+// @Tags patients
+// @Security BearerAuth
+// @Param patient_id path int true "Dependent's patient profile ID"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Router /users/me/dependents/{patient_id} [delete]
+func (h *PatientHandler) RemoveDependent(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	patientID, err := strconv.Atoi(c.Param("patient_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid patient ID"})
+		return
+	}
+
+	if err := h.patientService.RemoveDependent(c.Request.Context(), userID, patientID); err != nil {
+		writePatientError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Search godoc
+// @Summary Search patients
+// @Description Search patient profiles by name words, phone digits or exact insurance policy number (admin only)
+// @Tags patients
+// @Security BearerAuth
+// @Produce json
+// @Param name query string false "Words of the full name"
+// @Param phone query string false "Phone digits"
+// @Param policy query string false "Insurance policy number"
+// @Param page query int false "Page number"
+// @Param limit query int false "Page size (max 100)"
+// @Success 200 {object} utils.PaginatedData
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /patients [get]
+func (h *PatientHandler) Search(c *gin.Context) {
+	var filter entity.PatientFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	profiles, total, err := h.patientService.Search(c.Request.Context(), &filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.PaginatedData{
+		Data:  profiles,
+		Page:  filter.Page,
+		Limit: filter.Limit,
+		Total: total,
+	})
+}
+
+// GetByID godoc
+// @Summary Get patient profile by ID
+// @Description Get patient profile by ID (admin only)
+// @Tags patients
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Patient profile ID"
+// @Success 200 {object} entity.PatientProfile
+// @Failure 404 {object} map[string]string
+// @Router /patients/{id} [get]
+func (h *PatientHandler) GetByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid patient ID"})
+		return
+	}
+
+	profile, err := h.patientService.GetByID(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrPatientNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Patient profile not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	setETag(c, profile.Version)
+	c.JSON(http.StatusOK, profile)
+}
+
+// ListTreatingDoctors godoc
+// @Summary List treating doctors
+// @Description List doctors assigned to the patient. Only they, and doctors who have already recorded a visit, can create the patient's encounters (admin only)
+// @Tags patients
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Patient profile ID"
+// @Success 200 {array} entity.TreatingDoctor
+// @Failure 404 {object} map[string]string
+// @Router /patients/{id}/doctors [get]
+func (h *PatientHandler) ListTreatingDoctors(c *gin.Context) {
+	patientID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid patient ID"})
+		return
+	}
+
+	doctors, err := h.patientService.ListTreatingDoctors(c.Request.Context(), patientID)
+	if err != nil {
+		writePatientError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, doctors)
+}
+
+// AssignTreatingDoctor godoc
+// @Summary Assign treating doctor
+// @Description Assign a doctor to the patient. doctor_user_id is the user account linked to a doctor profile; assigning twice is a no-op (admin only)
+// @Tags patients
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Patient profile ID"
+// @Param request body entity.TreatingDoctorRequest true "Doctor"
+// @Success 201 {object} entity.TreatingDoctor
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /patients/{id}/doctors [post]
+func (h *PatientHandler) AssignTreatingDoctor(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	patientID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid patient ID"})
+		return
+	}
+
+	var req entity.TreatingDoctorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	assigned, err := h.patientService.AssignTreatingDoctor(c.Request.Context(), userID, patientID, req.DoctorUserID)
+	if err != nil {
+		writePatientError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, assigned)
+}
+
+// RemoveTreatingDoctor godoc
+// @Summary Remove treating doctor
+// @Description Unassign a doctor from the patient. Encounters already recorded by the doctor are kept (admin only)
+// @Tags patients
+// @Security BearerAuth
+// @Param id path int true "Patient profile ID"
+// @Param doctor_user_id path int true "Doctor's user ID"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Router /patients/{id}/doctors/{doctor_user_id} [delete]
+func (h *PatientHandler) RemoveTreatingDoctor(c *gin.Context) {
+	patientID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid patient ID"})
+		return
+	}
+	doctorUserID, err := strconv.Atoi(c.Param("doctor_user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid doctor user ID"})
+		return
+	}
+
+	if err := h.patientService.RemoveTreatingDoctor(c.Request.Context(), patientID, doctorUserID); err != nil {
+		writePatientError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// writePatientError отвечает кодом, соответствующим ошибке работы с профилем пациента
+func writePatientError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidPatientProfile), errors.Is(err, service.ErrConsentRequired), errors.Is(err, repository.ErrNotADoctor):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrPatientAccessDenied), errors.Is(err, service.ErrDependentBirthDateLocked):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrPatientNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Patient profile not found"})
+	case errors.Is(err, repository.ErrGuardianshipNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Dependent not found"})
+	case errors.Is(err, repository.ErrTreatingDoctorNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrPatientIdentifierTaken):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
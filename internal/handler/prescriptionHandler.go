@@ -0,0 +1,374 @@
+package handler
+
+import (
+	"Clinic_backend/internal/entity"
+	"Clinic_backend/internal/repository"
+	"Clinic_backend/internal/service"
+	"Clinic_backend/internal/utils"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PrescriptionHandler struct {
+	prescriptionService service.PrescriptionServiceInterface
+}
+
+func NewPrescriptionHandler(prescriptionService service.PrescriptionServiceInterface) *PrescriptionHandler {
+	return &PrescriptionHandler{
+		prescriptionService: prescriptionService,
+	}
+}
+
+// Create godoc
+// @Summary Create prescription
+// @Description Issue a prescription to a patient on behalf of the doctor linked to the current account (doctor only)
+// @Tags prescriptions
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body entity.PrescriptionCreateRequest true "Prescription"
+// @Success 201 {object} entity.Prescription
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /prescriptions [post]
+func (h *PrescriptionHandler) Create(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req entity.PrescriptionCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	prescription, err := h.prescriptionService.Create(c.Request.Context(), userID, &req)
+	if err != nil {
+		writePrescriptionError(c, err)
+		return
+	}
+
+	setETag(c, prescription.Version)
+	c.JSON(http.StatusCreated, prescription)
+}
+
+// List godoc
+// @Summary List prescriptions
+// @Description List active prescriptions (doctor, admin)
+// @Tags prescriptions
+// @Security BearerAuth
+// @Produce json
+// @Param patient_id query int false "Patient profile ID"
+// @Param doctor_id query int false "Issuing doctor ID"
+// @Param page query int false "Page number"
+// @Param limit query int false "Page size (max 100)"
+// @Success 200 {object} utils.PaginatedData
+// @Router /prescriptions [get]
+func (h *PrescriptionHandler) List(c *gin.Context) {
+	var filter entity.PrescriptionFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	prescriptions, total, err := h.prescriptionService.List(c.Request.Context(), &filter)
+	if err != nil {
+		writePrescriptionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.PaginatedData{
+		Data:  prescriptions,
+		Page:  filter.Page,
+		Limit: filter.Limit,
+		Total: total,
+	})
+}
+
+// GetByID godoc
+// @Summary Get prescription
+// @Description Get prescription by ID (doctor, admin)
+// @Tags prescriptions
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Prescription ID"
+// @Success 200 {object} entity.Prescription
+// @Failure 404 {object} map[string]string
+// @Router /prescriptions/{id} [get]
+func (h *PrescriptionHandler) GetByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid prescription ID"})
+		return
+	}
+
+	prescription, err := h.prescriptionService.GetByID(c.Request.Context(), id)
+	if err != nil {
+		writePrescriptionError(c, err)
+		return
+	}
+
+	setETag(c, prescription.Version)
+	c.JSON(http.StatusOK, prescription)
+}
+
+// Update godoc
+// @Summary Update prescription
+// @Description Replace a prescription. Only the issuing doctor may edit it
+// @Tags prescriptions
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Prescription ID"
+// @Param If-Match header string true "Current prescription version (ETag)"
+// @Param request body entity.PrescriptionRequest true "Prescription"
+// @Success 200 {object} entity.Prescription
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 428 {object} map[string]string
+// @Router /prescriptions/{id} [put]
+func (h *PrescriptionHandler) Update(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid prescription ID"})
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	var req entity.PrescriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	prescription, err := h.prescriptionService.Update(c.Request.Context(), userID, id, version, &req)
+	if err != nil {
+		writePrescriptionError(c, err)
+		return
+	}
+
+	setETag(c, prescription.Version)
+	c.JSON(http.StatusOK, prescription)
+}
+
+// Delete godoc
+// @Summary Cancel prescription
+// @Description Cancel a prescription. It stays in the database but is no longer shown. Only the issuing doctor may cancel it
+// @Tags prescriptions
+// @Security BearerAuth
+// @Param id path int true "Prescription ID"
+// @Param If-Match header string true "Current prescription version (ETag)"
+// @Success 204
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 428 {object} map[string]string
+// @Router /prescriptions/{id} [delete]
+func (h *PrescriptionHandler) Delete(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid prescription ID"})
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if err := h.prescriptionService.Delete(c.Request.Context(), userID, id, version); err != nil {
+		writePrescriptionError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetPDF godoc
+// @Summary Printable prescription
+// @Description PDF with the clinic header, the prescription and the doctor's name and specializations (doctor, admin)
+// @Tags prescriptions
+// @Security BearerAuth
+// @Produce application/pdf
+// @Param id path int true "Prescription ID"
+// @Success 200 {file} file
+// @Failure 404 {object} map[string]string
+// @Router /prescriptions/{id}/pdf [get]
+func (h *PrescriptionHandler) GetPDF(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid prescription ID"})
+		return
+	}
+
+	document, err := h.prescriptionService.RenderPDF(c.Request.Context(), id)
+	if err != nil {
+		writePrescriptionError(c, err)
+		return
+	}
+
+	writePrescriptionPDF(c, id, document)
+}
+
+// ListMine godoc
+// @Summary List my prescriptions
+// @Description Active prescriptions of the current user or, with patient_id, of a dependent
+// @Tags prescriptions
+// @Security BearerAuth
+// @Produce json
+// @Param patient_id query int false "Dependent's patient profile ID"
+// @Param page query int false "Page number"
+// @Param limit query int false "Page size (max 100)"
+// @Success 200 {object} utils.PaginatedData
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /users/me/prescriptions [get]
+func (h *PrescriptionHandler) ListMine(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	patientID, ok := patientIDQuery(c)
+	if !ok {
+		return
+	}
+
+	var filter entity.PrescriptionFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	prescriptions, total, err := h.prescriptionService.ListForPatient(c.Request.Context(), userID, patientID, &filter)
+	if err != nil {
+		writePrescriptionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.PaginatedData{
+		Data:  prescriptions,
+		Page:  filter.Page,
+		Limit: filter.Limit,
+		Total: total,
+	})
+}
+
+// GetMine godoc
+// @Summary Get my prescription
+// @Description Prescription of the current user or, with patient_id, of a dependent
+// @Tags prescriptions
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Prescription ID"
+// @Param patient_id query int false "Dependent's patient profile ID"
+// @Success 200 {object} entity.Prescription
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /users/me/prescriptions/{id} [get]
+func (h *PrescriptionHandler) GetMine(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	patientID, ok := patientIDQuery(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid prescription ID"})
+		return
+	}
+
+	prescription, err := h.prescriptionService.GetForPatient(c.Request.Context(), userID, patientID, id)
+	if err != nil {
+		writePrescriptionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, prescription)
+}
+
+// GetMinePDF godoc
+// @Summary My printable prescription
+// @Description PDF of a prescription of the current user or, with patient_id, of a dependent
+// @Tags prescriptions
+// @Security BearerAuth
+// @Produce application/pdf
+// @Param id path int true "Prescription ID"
+// @Param patient_id query int false "Dependent's patient profile ID"
+// @Success 200 {file} file
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /users/me/prescriptions/{id}/pdf [get]
+func (h *PrescriptionHandler) GetMinePDF(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	patientID, ok := patientIDQuery(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid prescription ID"})
+		return
+	}
+
+	document, err := h.prescriptionService.RenderPDFForPatient(c.Request.Context(), userID, patientID, id)
+	if err != nil {
+		writePrescriptionError(c, err)
+		return
+	}
+
+	writePrescriptionPDF(c, id, document)
+}
+
+// writePrescriptionPDF отдаёт печатную форму для просмотра в браузере. Документ содержит
+// медицинские данные, поэтому не кэшируется
+func writePrescriptionPDF(c *gin.Context, id int, document []byte) {
+	c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="prescription-%d.pdf"`, id))
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "application/pdf", document)
+}
+
+// writePrescriptionError отвечает кодом, соответствующим ошибке работы с рецептами
+func writePrescriptionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidPrescription):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrDoctorProfileRequired), errors.Is(err, service.ErrPrescriptionForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrVersionConflict):
+		c.JSON(http.StatusPreconditionFailed, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrPrescriptionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Prescription not found"})
+	default:
+		writePatientError(c, err)
+	}
+}
@@ -0,0 +1,281 @@
+package handler
+
+import (
+	"Clinic_backend/internal/entity"
+	"Clinic_backend/internal/repository"
+	"Clinic_backend/internal/service"
+	"Clinic_backend/internal/utils"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PrivacyHandler struct {
+	privacyService service.PrivacyServiceInterface
+}
+
+func NewPrivacyHandler(privacyService service.PrivacyServiceInterface) *PrivacyHandler {
+	return &PrivacyHandler{
+		privacyService: privacyService,
+	}
+}
+
+// Export godoc
+// @Summary Export my personal data
+// @Description Download a ZIP archive with JSON files (account, patient profile, dependents, signed encounters, prescriptions, lab orders, data requests, consents, doctor reviews, own actions from the audit log) and PDF result forms of ready lab orders. Every export is recorded as a completed data request
+// @Tags privacy
+// @Security BearerAuth
+// @Produce application/zip
+// @Success 200 {file} file
+// @Failure 401 {object} map[string]string
+// @Router /users/me/data-export [get]
+func (h *PrivacyHandler) Export(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	archive, err := h.privacyService.Export(c.Request.Context(), userID)
+	if err != nil {
+		writePrivacyError(c, err)
+		return
+	}
+
+	filename := fmt.Sprintf("personal-data-%d-%s.zip", userID, time.Now().Format("20060102"))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "application/zip", archive)
+}
+
+// RequestErasure godoc
+// @Summary Request erasure of my personal data
+// @Description Submit a request to erase personal data. An admin reviews it; on approval the account is anonymised while medical records required by law are kept. Only one request may be pending at a time
+// @Tags privacy
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body entity.ErasureRequest false "Optional reason"
+// @Success 201 {object} entity.DataRequest
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /users/me/data-requests/erasure [post]
+func (h *PrivacyHandler) RequestErasure(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req entity.ErasureRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	request, err := h.privacyService.RequestErasure(c.Request.Context(), userID, &req)
+	if err != nil {
+		writePrivacyError(c, err)
+		return
+	}
+
+	setETag(c, request.Version)
+	c.JSON(http.StatusCreated, request)
+}
+
+// ListMine godoc
+// @Summary List my data requests
+// @Description List the current user's exports and erasure requests, newest first
+// @Tags privacy
+// @Security BearerAuth
+// @Produce json
+// @Param type query string false "Request type" Enums(export, erasure)
+// @Param status query string false "Status" Enums(pending, completed, rejected)
+// @Param page query int false "Page number"
+// @Param limit query int false "Page size (max 100)"
+// @Success 200 {object} utils.PaginatedData
+// @Router /users/me/data-requests [get]
+func (h *PrivacyHandler) ListMine(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var filter entity.DataRequestFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	requests, total, err := h.privacyService.ListMine(c.Request.Context(), userID, &filter)
+	if err != nil {
+		writePrivacyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.PaginatedData{
+		Data:  requests,
+		Page:  filter.Page,
+		Limit: filter.Limit,
+		Total: total,
+	})
+}
+
+// List godoc
+// @Summary List data requests
+// @Description List data requests of all users, newest first (admin only)
+// @Tags privacy
+// @Security BearerAuth
+// @Produce json
+// @Param user_id query int false "User ID"
+// @Param type query string false "Request type" Enums(export, erasure)
+// @Param status query string false "Status" Enums(pending, completed, rejected)
+// @Param page query int false "Page number"
+// @Param limit query int false "Page size (max 100)"
+// @Success 200 {object} utils.PaginatedData
+// @Router /data-requests [get]
+func (h *PrivacyHandler) List(c *gin.Context) {
+	var filter entity.DataRequestFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	requests, total, err := h.privacyService.List(c.Request.Context(), &filter)
+	if err != nil {
+		writePrivacyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.PaginatedData{
+		Data:  requests,
+		Page:  filter.Page,
+		Limit: filter.Limit,
+		Total: total,
+	})
+}
+
+// GetByID godoc
+// @Summary Get data request
+// @Description Get a data request by ID (admin only)
+// @Tags privacy
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Data request ID"
+// @Success 200 {object} entity.DataRequest
+// @Failure 404 {object} map[string]string
+// @Router /data-requests/{id} [get]
+func (h *PrivacyHandler) GetByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid data request ID"})
+		return
+	}
+
+	request, err := h.privacyService.GetByID(c.Request.Context(), id)
+	if err != nil {
+		writePrivacyError(c, err)
+		return
+	}
+
+	setETag(c, request.Version)
+	c.JSON(http.StatusOK, request)
+}
+
+// Approve godoc
+// @Summary Approve erasure request
+// @Description Approve a pending erasure request and anonymise the account in the same transaction: credentials and contacts are wiped, the account is blocked, guardianships and doctor reviews are removed, a patient profile with medical records is detached and stripped of contacts, otherwise deleted. The append-only audit log is not rewritten: account updates are logged as changed field names only, but entries written before that change still hold the old username and email (admin only)
+// @Tags privacy
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Data request ID"
+// @Param If-Match header string true "Current data request version (ETag)"
+// @Param request body entity.DataRequestDecision false "Optional comment"
+// @Success 200 {object} entity.DataRequest
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 428 {object} map[string]string
+// @Router /data-requests/{id}/approve [post]
+func (h *PrivacyHandler) Approve(c *gin.Context) {
+	h.decide(c, h.privacyService.Approve)
+}
+
+// Reject godoc
+// @Summary Reject erasure request
+// @Description Reject a pending erasure request with a mandatory explanation (admin only)
+// @Tags privacy
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Data request ID"
+// @Param If-Match header string true "Current data request version (ETag)"
+// @Param request body entity.DataRequestDecision true "Reason for rejection"
+// @Success 200 {object} entity.DataRequest
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 428 {object} map[string]string
+// @Router /data-requests/{id}/reject [post]
+func (h *PrivacyHandler) Reject(c *gin.Context) {
+	h.decide(c, h.privacyService.Reject)
+}
+
+func (h *PrivacyHandler) decide(c *gin.Context, resolve func(ctx context.Context, reviewerID, id, version int, req *entity.DataRequestDecision) (*entity.DataRequest, error)) {
+	reviewerID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid data request ID"})
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	var req entity.DataRequestDecision
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	request, err := resolve(c.Request.Context(), reviewerID, id, version, &req)
+	if err != nil {
+		writePrivacyError(c, err)
+		return
+	}
+
+	setETag(c, request.Version)
+	c.JSON(http.StatusOK, request)
+}
+
+func writePrivacyError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrResolutionRequired):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrErasurePending), errors.Is(err, service.ErrDataRequestResolved):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrVersionConflict):
+		c.JSON(http.StatusPreconditionFailed, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrDataRequestNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Data request not found"})
+	case errors.Is(err, repository.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+	default:
+		writePatientError(c, err)
+	}
+}
@@ -0,0 +1,386 @@
+package handler
+
+import (
+	"Clinic_backend/internal/entity"
+	"Clinic_backend/internal/repository"
+	"Clinic_backend/internal/service"
+	"Clinic_backend/internal/utils"
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReviewHandler struct {
+	reviewService service.ReviewServiceInterface
+}
+
+func NewReviewHandler(reviewService service.ReviewServiceInterface) *ReviewHandler {
+	return &ReviewHandler{
+		reviewService: reviewService,
+	}
+}
+
+// ListForDoctor godoc
+// @Summary List doctor reviews
+// @Description List approved reviews of a doctor, newest first
+// @Tags reviews
+// @Produce json
+// @Param id path int true "Doctor ID"
+// @Param page query int false "Page number"
+// @Param limit query int false "Page size (max 100)"
+// @Success 200 {object} utils.PaginatedData
+// @Failure 404 {object} map[string]string
+// @Router /doctors/{id}/reviews [get]
+func (h *ReviewHandler) ListForDoctor(c *gin.Context) {
+	doctorID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid doctor ID"})
+		return
+	}
+
+	var filter entity.DoctorReviewFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	reviews, total, err := h.reviewService.ListForDoctor(c.Request.Context(), doctorID, &filter)
+	if err != nil {
+		writeReviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.PaginatedData{
+		Data:  reviews,
+		Page:  filter.Page,
+		Limit: filter.Limit,
+		Total: total,
+	})
+}
+
+// Create godoc
+// @Summary Review a doctor
+// @Description Leave a review (1-5 stars and text). One review per user per doctor; it is published after moderation
+// @Tags reviews
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Doctor ID"
+// @Param request body entity.DoctorReviewRequest true "Review"
+// @Success 201 {object} entity.DoctorReview
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /doctors/{id}/reviews [post]
+func (h *ReviewHandler) Create(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	doctorID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid doctor ID"})
+		return
+	}
+
+	var req entity.DoctorReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	review, err := h.reviewService.Create(c.Request.Context(), userID, doctorID, &req)
+	if err != nil {
+		writeReviewError(c, err)
+		return
+	}
+
+	setETag(c, review.Version)
+	c.JSON(http.StatusCreated, review)
+}
+
+// ListMine godoc
+// @Summary List my reviews
+// @Description List the current user's reviews with their moderation status, newest first
+// @Tags reviews
+// @Security BearerAuth
+// @Produce json
+// @Param doctor_id query int false "Doctor ID"
+// @Param status query string false "Status" Enums(pending, approved, rejected)
+// @Param page query int false "Page number"
+// @Param limit query int false "Page size (max 100)"
+// @Success 200 {object} utils.PaginatedData
+// @Failure 401 {object} map[string]string
+// @Router /users/me/reviews [get]
+func (h *ReviewHandler) ListMine(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var filter entity.DoctorReviewFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	reviews, total, err := h.reviewService.ListMine(c.Request.Context(), userID, &filter)
+	if err != nil {
+		writeReviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.PaginatedData{
+		Data:  reviews,
+		Page:  filter.Page,
+		Limit: filter.Limit,
+		Total: total,
+	})
+}
+
+// UpdateMine godoc
+// @Summary Update my review
+// @Description Change the rating and text of own review. The review returns to the moderation queue and is hidden until approved again
+// @Tags reviews
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Review ID"
+// @Param If-Match header string true "Current review version (ETag)"
+// @Param request body entity.DoctorReviewRequest true "Review"
+// @Success 200 {object} entity.DoctorReview
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 428 {object} map[string]string
+// @Router /users/me/reviews/{id} [put]
+func (h *ReviewHandler) UpdateMine(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid review ID"})
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	var req entity.DoctorReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	review, err := h.reviewService.UpdateMine(c.Request.Context(), userID, id, version, &req)
+	if err != nil {
+		writeReviewError(c, err)
+		return
+	}
+
+	setETag(c, review.Version)
+	c.JSON(http.StatusOK, review)
+}
+
+// DeleteMine godoc
+// @Summary Delete my review
+// @Description Delete own review; an approved review stops counting towards the doctor's rating
+// @Tags reviews
+// @Security BearerAuth
+// @Param id path int true "Review ID"
+// @Param If-Match header string true "Current review version (ETag)"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 428 {object} map[string]string
+// @Router /users/me/reviews/{id} [delete]
+func (h *ReviewHandler) DeleteMine(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid review ID"})
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if err := h.reviewService.DeleteMine(c.Request.Context(), userID, id, version); err != nil {
+		writeReviewError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// List godoc
+// @Summary List reviews for moderation
+// @Description List reviews of all doctors. With status=pending this is the moderation queue, oldest first (admin only)
+// @Tags reviews
+// @Security BearerAuth
+// @Produce json
+// @Param doctor_id query int false "Doctor ID"
+// @Param user_id query int false "Author user ID"
+// @Param status query string false "Status" Enums(pending, approved, rejected)
+// @Param page query int false "Page number"
+// @Param limit query int false "Page size (max 100)"
+// @Success 200 {object} utils.PaginatedData
+// @Router /reviews [get]
+func (h *ReviewHandler) List(c *gin.Context) {
+	var filter entity.DoctorReviewFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	reviews, total, err := h.reviewService.List(c.Request.Context(), &filter)
+	if err != nil {
+		writeReviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.PaginatedData{
+		Data:  reviews,
+		Page:  filter.Page,
+		Limit: filter.Limit,
+		Total: total,
+	})
+}
+
+// GetByID godoc
+// @Summary Get review
+// @Description Get a review by ID (admin only)
+// @Tags reviews
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Review ID"
+// @Success 200 {object} entity.DoctorReview
+// @Failure 404 {object} map[string]string
+// @Router /reviews/{id} [get]
+func (h *ReviewHandler) GetByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid review ID"})
+		return
+	}
+
+	review, err := h.reviewService.GetByID(c.Request.Context(), id)
+	if err != nil {
+		writeReviewError(c, err)
+		return
+	}
+
+	setETag(c, review.Version)
+	c.JSON(http.StatusOK, review)
+}
+
+// Approve godoc
+// @Summary Approve review
+// @Description Publish a review and recalculate the doctor's rating (admin only)
+// @Tags reviews
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Review ID"
+// @Param If-Match header string true "Current review version (ETag)"
+// @Param request body entity.ReviewModerationRequest false "Optional comment"
+// @Success 200 {object} entity.DoctorReview
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 428 {object} map[string]string
+// @Router /reviews/{id}/approve [post]
+func (h *ReviewHandler) Approve(c *gin.Context) {
+	h.moderate(c, h.reviewService.Approve)
+}
+
+// Reject godoc
+// @Summary Reject review
+// @Description Hide a pending or previously approved review; an approved review stops counting towards the doctor's rating (admin only)
+// @Tags reviews
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Review ID"
+// @Param If-Match header string true "Current review version (ETag)"
+// @Param request body entity.ReviewModerationRequest false "Optional comment for the author"
+// @Success 200 {object} entity.DoctorReview
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 428 {object} map[string]string
+// @Router /reviews/{id}/reject [post]
+func (h *ReviewHandler) Reject(c *gin.Context) {
+	h.moderate(c, h.reviewService.Reject)
+}
+
+func (h *ReviewHandler) moderate(c *gin.Context, decide func(ctx context.Context, moderatorID, id, version int, req *entity.ReviewModerationRequest) (*entity.DoctorReview, error)) {
+	moderatorID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid review ID"})
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	var req entity.ReviewModerationRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	review, err := decide(c.Request.Context(), moderatorID, id, version, &req)
+	if err != nil {
+		writeReviewError(c, err)
+		return
+	}
+
+	setETag(c, review.Version)
+	c.JSON(http.StatusOK, review)
+}
+
+func writeReviewError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrReviewEmpty):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrReviewOwnProfile):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrReviewExists), errors.Is(err, service.ErrReviewStatusUnchanged):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrVersionConflict):
+		c.JSON(http.StatusPreconditionFailed, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrReviewNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Review not found"})
+	case errors.Is(err, service.ErrReviewDoctorNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Doctor not found"})
+	default:
+		writePatientError(c, err)
+	}
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"Clinic_backend/internal/entity"
+	"Clinic_backend/internal/service"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SearchHandler struct {
+	searchService service.SearchServiceInterface
+}
+
+func NewSearchHandler(searchService service.SearchServiceInterface) *SearchHandler {
+	return &SearchHandler{
+		searchService: searchService,
+	}
+}
+
+// Search godoc
+// @Summary Search catalog
+// @Description Full-text search over doctors, services, service categories and specializations (Russian and English, prefix matching, typo-tolerant names). Results are ranked; title and snippet are HTML-escaped with matches wrapped in <mark>
+// @Tags search
+// @Produce json
+// @Param q query string true "Search query"
+// @Param type query string false "Comma-separated result types: doctor, service, service_category, specialization"
+// @Param limit query int false "Max results (default 20, max 50)"
+// @Success 200 {object} entity.SearchResponse
+// @Failure 400 {object} map[string]string
+// @Router /search [get]
+func (h *SearchHandler) Search(c *gin.Context) {
+	var req entity.SearchRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := h.searchService.Search(c.Request.Context(), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidSearchQuery) || errors.Is(err, service.ErrInvalidSearchType) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -43,6 +43,7 @@ func (h *CategoryHandler) CreateCategory(c *gin.Context) {
 		return
 	}
 
+	setETag(c, created.Version)
 	c.JSON(http.StatusCreated, created)
 }
 
@@ -52,9 +53,10 @@ func (h *CategoryHandler) CreateCategory(c *gin.Context) {
 // @Tags categories
 // @Produce json
 // @Success 200 {array} entity.ServiceCategory
+// @Param include_deleted query bool false "Include soft-deleted records (admin only)"
 // @Router /service-categories [get]
 func (h *CategoryHandler) GetAllCategories(c *gin.Context) {
-	categories, err := h.categoryService.GetAllCategories(c.Request.Context())
+	categories, err := h.categoryService.GetAllCategories(c.Request.Context(), includeDeleted(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -85,6 +87,7 @@ func (h *CategoryHandler) GetCategoryByID(c *gin.Context) {
 		return
 	}
 
+	setETag(c, category.Version)
 	c.JSON(http.StatusOK, category)
 }
 
@@ -114,8 +117,11 @@ func (h *CategoryHandler) GetFavorites(c *gin.Context) {
 // @Produce json
 // @Param id path int true "Category ID"
 // @Param request body entity.ServiceCategory true "Category update data"
+// @Param If-Match header string true "ETag of the current version"
 // @Success 200 {object} entity.ServiceCategory
 // @Failure 400 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 428 {object} map[string]string
 // @Router /service-categories/{id} [put]
 func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
@@ -124,18 +130,24 @@ func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
 		return
 	}
 
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
 	var category entity.ServiceCategory
 	if err := c.ShouldBindJSON(&category); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	updated, err := h.categoryService.UpdateCategory(c.Request.Context(), id, &category)
+	updated, err := h.categoryService.UpdateCategory(c.Request.Context(), id, version, &category)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeMutationError(c, err)
 		return
 	}
 
+	setETag(c, updated.Version)
 	c.JSON(http.StatusOK, updated)
 }
 
@@ -145,8 +157,11 @@ func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
 // @Tags categories
 // @Security BearerAuth
 // @Param id path int true "Category ID"
+// @Param If-Match header string true "ETag of the current version"
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 428 {object} map[string]string
 // @Router /service-categories/{id}/favorite [patch]
 func (h *CategoryHandler) ToggleFavorite(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
@@ -155,8 +170,13 @@ func (h *CategoryHandler) ToggleFavorite(c *gin.Context) {
 		return
 	}
 
-	if err := h.categoryService.ToggleFavorite(c.Request.Context(), id); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if err := h.categoryService.ToggleFavorite(c.Request.Context(), id, version); err != nil {
+		writeMutationError(c, err)
 		return
 	}
 
@@ -169,8 +189,11 @@ func (h *CategoryHandler) ToggleFavorite(c *gin.Context) {
 // @Tags categories
 // @Security BearerAuth
 // @Param id path int true "Category ID"
+// @Param If-Match header string true "ETag of the current version"
 // @Success 204
 // @Failure 400 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 428 {object} map[string]string
 // @Router /service-categories/{id} [delete]
 func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
@@ -179,8 +202,37 @@ func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
 		return
 	}
 
-	if err := h.categoryService.DeleteCategory(c.Request.Context(), id); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if err := h.categoryService.DeleteCategory(c.Request.Context(), id, version); err != nil {
+		writeMutationError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RestoreCategory godoc
+// @Summary Restore category
+// @Description Restore soft-deleted category by ID (admin only)
+// @Tags categories
+// @Security BearerAuth
+// @Param id path int true "Category ID"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Router /service-categories/{id}/restore [post]
+func (h *CategoryHandler) RestoreCategory(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+		return
+	}
+
+	if err := h.categoryService.RestoreCategory(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
 
@@ -43,6 +43,7 @@ func (h *ServiceHandler) CreateService(c *gin.Context) {
 		return
 	}
 
+	setETag(c, svc.Version)
 	c.JSON(http.StatusCreated, svc)
 }
 
@@ -52,9 +53,10 @@ func (h *ServiceHandler) CreateService(c *gin.Context) {
 // @Tags services
 // @Produce json
 // @Success 200 {array} entity.Service
+// @Param include_deleted query bool false "Include soft-deleted records (admin only)"
 // @Router /services [get]
 func (h *ServiceHandler) GetAllServices(c *gin.Context) {
-	services, err := h.serviceService.GetAllServices(c.Request.Context())
+	services, err := h.serviceService.GetAllServices(c.Request.Context(), includeDeleted(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -85,6 +87,7 @@ func (h *ServiceHandler) GetServiceByID(c *gin.Context) {
 		return
 	}
 
+	setETag(c, svc.Version)
 	c.JSON(http.StatusOK, svc)
 }
 
@@ -145,8 +148,11 @@ func (h *ServiceHandler) GetBySpecialization(c *gin.Context) {
 // @Produce json
 // @Param id path int true "Service ID"
 // @Param request body entity.ServiceCreateRequest true "Service update data"
+// @Param If-Match header string true "ETag of the current version"
 // @Success 200 {object} entity.Service
 // @Failure 400 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 428 {object} map[string]string
 // @Router /services/{id} [put]
 func (h *ServiceHandler) UpdateService(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
@@ -155,18 +161,24 @@ func (h *ServiceHandler) UpdateService(c *gin.Context) {
 		return
 	}
 
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
 	var req entity.ServiceCreateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	svc, err := h.serviceService.UpdateService(c.Request.Context(), id, &req)
+	svc, err := h.serviceService.UpdateService(c.Request.Context(), id, version, &req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeMutationError(c, err)
 		return
 	}
 
+	setETag(c, svc.Version)
 	c.JSON(http.StatusOK, svc)
 }
 
@@ -176,8 +188,11 @@ func (h *ServiceHandler) UpdateService(c *gin.Context) {
 // @Tags services
 // @Security BearerAuth
 // @Param id path int true "Service ID"
+// @Param If-Match header string true "ETag of the current version"
 // @Success 204
 // @Failure 400 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 428 {object} map[string]string
 // @Router /services/{id} [delete]
 func (h *ServiceHandler) DeleteService(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
@@ -186,8 +201,37 @@ func (h *ServiceHandler) DeleteService(c *gin.Context) {
 		return
 	}
 
-	if err := h.serviceService.DeleteService(c.Request.Context(), id); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if err := h.serviceService.DeleteService(c.Request.Context(), id, version); err != nil {
+		writeMutationError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RestoreService godoc
+// @Summary Restore service
+// @Description Restore soft-deleted service by ID (admin only)
+// @Tags services
+// @Security BearerAuth
+// @Param id path int true "Service ID"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Router /services/{id}/restore [post]
+func (h *ServiceHandler) RestoreService(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid service ID"})
+		return
+	}
+
+	if err := h.serviceService.RestoreService(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
 
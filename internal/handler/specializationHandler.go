@@ -43,6 +43,7 @@ func (h *SpecializationHandler) CreateSpecialization(c *gin.Context) {
 		return
 	}
 
+	setETag(c, created.Version)
 	c.JSON(http.StatusCreated, created)
 }
 
@@ -52,9 +53,10 @@ func (h *SpecializationHandler) CreateSpecialization(c *gin.Context) {
 // @Tags specializations
 // @Produce json
 // @Success 200 {array} entity.Specialization
+// @Param include_deleted query bool false "Include soft-deleted records (admin only)"
 // @Router /specializations [get]
 func (h *SpecializationHandler) GetAllSpecializations(c *gin.Context) {
-	specializations, err := h.specService.GetAllSpecializations(c.Request.Context())
+	specializations, err := h.specService.GetAllSpecializations(c.Request.Context(), includeDeleted(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -85,6 +87,7 @@ func (h *SpecializationHandler) GetSpecializationByID(c *gin.Context) {
 		return
 	}
 
+	setETag(c, spec.Version)
 	c.JSON(http.StatusOK, spec)
 }
 
@@ -97,8 +100,11 @@ func (h *SpecializationHandler) GetSpecializationByID(c *gin.Context) {
 // @Produce json
 // @Param id path int true "Specialization ID"
 // @Param request body entity.Specialization true "Specialization update data"
+// @Param If-Match header string true "ETag of the current version"
 // @Success 200 {object} entity.Specialization
 // @Failure 400 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 428 {object} map[string]string
 // @Router /specializations/{id} [put]
 func (h *SpecializationHandler) UpdateSpecialization(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
@@ -107,18 +113,24 @@ func (h *SpecializationHandler) UpdateSpecialization(c *gin.Context) {
 		return
 	}
 
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
 	var spec entity.Specialization
 	if err := c.ShouldBindJSON(&spec); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	updated, err := h.specService.UpdateSpecialization(c.Request.Context(), id, &spec)
+	updated, err := h.specService.UpdateSpecialization(c.Request.Context(), id, version, &spec)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeMutationError(c, err)
 		return
 	}
 
+	setETag(c, updated.Version)
 	c.JSON(http.StatusOK, updated)
 }
 
@@ -128,8 +140,11 @@ func (h *SpecializationHandler) UpdateSpecialization(c *gin.Context) {
 // @Tags specializations
 // @Security BearerAuth
 // @Param id path int true "Specialization ID"
+// @Param If-Match header string true "ETag of the current version"
 // @Success 204
 // @Failure 400 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 428 {object} map[string]string
 // @Router /specializations/{id} [delete]
 func (h *SpecializationHandler) DeleteSpecialization(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
@@ -138,8 +153,37 @@ func (h *SpecializationHandler) DeleteSpecialization(c *gin.Context) {
 		return
 	}
 
-	if err := h.specService.DeleteSpecialization(c.Request.Context(), id); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if err := h.specService.DeleteSpecialization(c.Request.Context(), id, version); err != nil {
+		writeMutationError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RestoreSpecialization godoc
+// @Summary Restore specialization
+// @Description Restore soft-deleted specialization by ID (admin only)
+// @Tags specializations
+// @Security BearerAuth
+// @Param id path int true "Specialization ID"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Router /specializations/{id}/restore [post]
+func (h *SpecializationHandler) RestoreSpecialization(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid specialization ID"})
+		return
+	}
+
+	if err := h.specService.RestoreSpecialization(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
 
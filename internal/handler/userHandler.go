@@ -3,6 +3,8 @@ package handler
 import (
 	"Clinic_backend/internal/entity"
 	"Clinic_backend/internal/repository"
+	"Clinic_backend/internal/service"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -10,12 +12,12 @@ import (
 )
 
 type UserHandler struct {
-	userRepo repository.UserRepositoryInterface
+	userService service.UserServiceInterface
 }
 
-func NewUserHandler(userRepo repository.UserRepositoryInterface) *UserHandler {
+func NewUserHandler(userService service.UserServiceInterface) *UserHandler {
 	return &UserHandler{
-		userRepo: userRepo,
+		userService: userService,
 	}
 }
 
@@ -29,19 +31,12 @@ func NewUserHandler(userRepo repository.UserRepositoryInterface) *UserHandler {
 // @Failure 401 {object} map[string]string
 // @Router /users/me [get]
 func (h *UserHandler) GetMe(c *gin.Context) {
-	userIDValue, exists := c.Get("user_id")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
-		return
-	}
-
-	userID, ok := userIDValue.(int)
+	userID, ok := currentUserID(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID in token"})
 		return
 	}
 
-	user, err := h.userRepo.GetByID(c.Request.Context(), userID)
+	user, err := h.userService.GetByID(c.Request.Context(), userID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
 		return
@@ -62,15 +57,8 @@ func (h *UserHandler) GetMe(c *gin.Context) {
 // @Failure 400 {object} map[string]string
 // @Router /users/me [put]
 func (h *UserHandler) UpdateMe(c *gin.Context) {
-	userIDValue, exists := c.Get("user_id")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
-		return
-	}
-
-	userID, ok := userIDValue.(int)
+	userID, ok := currentUserID(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID in token"})
 		return
 	}
 
@@ -80,7 +68,7 @@ func (h *UserHandler) UpdateMe(c *gin.Context) {
 		return
 	}
 
-	updatedUser, err := h.userRepo.Update(c.Request.Context(), userID, &req)
+	updatedUser, err := h.userService.Update(c.Request.Context(), userID, &req)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -99,7 +87,7 @@ func (h *UserHandler) UpdateMe(c *gin.Context) {
 // @Failure 403 {object} map[string]string
 // @Router /users [get]
 func (h *UserHandler) GetAll(c *gin.Context) {
-	users, err := h.userRepo.GetAll(c.Request.Context())
+	users, err := h.userService.GetAll(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -130,7 +118,7 @@ func (h *UserHandler) GetByID(c *gin.Context) {
 		return
 	}
 
-	user, err := h.userRepo.GetByID(c.Request.Context(), id)
+	user, err := h.userService.GetByID(c.Request.Context(), id)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
 		return
@@ -164,7 +152,7 @@ func (h *UserHandler) Update(c *gin.Context) {
 		return
 	}
 
-	updatedUser, err := h.userRepo.Update(c.Request.Context(), id, &req)
+	updatedUser, err := h.userService.Update(c.Request.Context(), id, &req)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -175,12 +163,13 @@ func (h *UserHandler) Update(c *gin.Context) {
 
 // Delete godoc
 // @Summary Delete user
-// @Description Delete user by ID (admin only)
+// @Description Anonymises the user account by ID (admin only). The row is kept for medical records: credentials and contact details are wiped, the account is blocked, the patient profile is detached and stripped of contacts if it has medical records and deleted otherwise
 // @Tags users
 // @Security BearerAuth
 // @Param id path int true "User ID"
 // @Success 204
 // @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
 // @Router /users/{id} [delete]
 func (h *UserHandler) Delete(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
@@ -189,7 +178,11 @@ func (h *UserHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := h.userRepo.Delete(c.Request.Context(), id); err != nil {
+	if err := h.userService.Delete(c.Request.Context(), id); err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
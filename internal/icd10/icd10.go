@@ -0,0 +1,172 @@
+// Package icd10 - справочник диагнозов МКБ-10 в памяти. По умолчанию используется
+// встроенный в бинарник список частых диагнозов, полный справочник загружается из файла.
+package icd10
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+//go:embed icd10.tsv
+var defaultData string
+
+// codeRegex - рубрика (J06) или подрубрика (J06.9)
+var codeRegex = regexp.MustCompile(`^[A-Z][0-9]{2}(\.[0-9A-Z]{1,2})?$`)
+
+// Кириллические буквы, совпадающие по начертанию с латинскими, - частая опечатка в кодах
+var codeReplacer = strings.NewReplacer(
+	"А", "A", "В", "B", "С", "C", "Е", "E", "Н", "H", "К", "K",
+	"М", "M", "О", "O", "Р", "P", "Т", "T", "Х", "X", ",", ".",
+)
+
+type Code struct {
+	Code  string `json:"code" example:"J06.9"`
+	Title string `json:"title" example:"Острая инфекция верхних дыхательных путей неуточненная"`
+}
+
+// Table - неизменяемый справочник, безопасен для одновременного использования
+type Table struct {
+	codes  []Code
+	byCode map[string]int
+	// titles - названия в нижнем регистре для поиска по словам
+	titles []string
+}
+
+var (
+	defaultOnce  sync.Once
+	defaultTable *Table
+)
+
+// Default возвращает встроенный справочник
+func Default() *Table {
+	defaultOnce.Do(func() {
+		table, err := Load(strings.NewReader(defaultData))
+		if err != nil {
+			panic(fmt.Sprintf("icd10: invalid embedded table: %v", err))
+		}
+		defaultTable = table
+	})
+	return defaultTable
+}
+
+// LoadFile загружает справочник из файла: строки "код<TAB>название",
+// пустые строки и строки, начинающиеся с #, пропускаются
+func LoadFile(path string) (*Table, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open icd10 table: %w", err)
+	}
+	defer f.Close()
+
+	return Load(f)
+}
+
+func Load(r io.Reader) (*Table, error) {
+	table := &Table{byCode: make(map[string]int)}
+
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		rawCode, title, found := strings.Cut(text, "\t")
+		title = strings.TrimSpace(title)
+		code := Normalize(rawCode)
+		if !found || title == "" || !codeRegex.MatchString(code) {
+			return nil, fmt.Errorf("icd10 table line %d: expected \"code<TAB>title\", got %q", line, text)
+		}
+		if _, exists := table.byCode[code]; exists {
+			return nil, fmt.Errorf("icd10 table line %d: duplicate code %s", line, code)
+		}
+
+		table.byCode[code] = len(table.codes)
+		table.codes = append(table.codes, Code{Code: code, Title: title})
+		table.titles = append(table.titles, foldTitle(title))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read icd10 table: %w", err)
+	}
+	if len(table.codes) == 0 {
+		return nil, fmt.Errorf("icd10 table is empty")
+	}
+
+	return table, nil
+}
+
+// Normalize приводит код к виду справочника: "j069" и "J06,9" -> "J06.9", "М54.5" с кириллической М -> "M54.5"
+func Normalize(code string) string {
+	code = codeReplacer.Replace(strings.ToUpper(strings.TrimSpace(code)))
+	if len(code) > 3 && !strings.Contains(code, ".") {
+		code = code[:3] + "." + code[3:]
+	}
+	return code
+}
+
+func (t *Table) Len() int {
+	return len(t.codes)
+}
+
+func (t *Table) Lookup(code string) (Code, bool) {
+	i, ok := t.byCode[Normalize(code)]
+	if !ok {
+		return Code{}, false
+	}
+	return t.codes[i], true
+}
+
+// Search ищет сначала по началу кода, затем по названию: в нём должны встречаться
+// все слова запроса. Результаты идут в порядке справочника.
+func (t *Table) Search(query string, limit int) []Code {
+	query = strings.TrimSpace(query)
+	results := []Code{}
+	if query == "" || limit < 1 {
+		return results
+	}
+
+	seen := make(map[int]bool)
+	prefix := Normalize(query)
+	for i, code := range t.codes {
+		if len(results) == limit {
+			return results
+		}
+		if strings.HasPrefix(code.Code, prefix) {
+			results = append(results, code)
+			seen[i] = true
+		}
+	}
+
+	words := strings.Fields(foldTitle(query))
+	for i, title := range t.titles {
+		if len(results) == limit {
+			break
+		}
+		if !seen[i] && containsAll(title, words) {
+			results = append(results, t.codes[i])
+		}
+	}
+
+	return results
+}
+
+func foldTitle(title string) string {
+	return strings.ReplaceAll(strings.ToLower(title), "ё", "е")
+}
+
+func containsAll(title string, words []string) bool {
+	for _, word := range words {
+		if !strings.Contains(title, word) {
+			return false
+		}
+	}
+	return true
+}
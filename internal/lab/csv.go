@@ -0,0 +1,172 @@
+package lab
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Колонки CSV-файла; порядок произвольный, регистр заголовков не важен
+const (
+	columnOrderID        = "order_id"
+	columnTestCode       = "test_code"
+	columnTestName       = "test_name"
+	columnValue          = "value"
+	columnUnit           = "unit"
+	columnReferenceRange = "reference_range"
+	columnFlag           = "flag"
+	columnStatus         = "status"
+	columnObservedAt     = "observed_at"
+)
+
+var requiredColumns = []string{columnOrderID, columnTestCode, columnValue}
+
+// ParseCSV разбирает таблицу результатов с обязательной строкой заголовков.
+// Разделитель - запятая или точка с запятой (определяется по заголовку).
+// Колонка status принимает final или preliminary; по умолчанию результат окончательный.
+func ParseCSV(r io.Reader) (*Batch, error) {
+	reader := bufio.NewReader(r)
+	delimiter, err := detectDelimiter(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	records := csv.NewReader(reader)
+	records.Comma = delimiter
+	records.TrimLeadingSpace = true
+	records.FieldsPerRecord = -1
+
+	header, err := records.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: empty file", ErrInvalidImport)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImport, err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if _, ok := columns[name]; ok {
+			return nil, fmt.Errorf("%w: line 1: duplicate column %q", ErrInvalidImport, name)
+		}
+		columns[name] = i
+	}
+	for _, name := range requiredColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("%w: line 1: missing column %q", ErrInvalidImport, name)
+		}
+	}
+
+	batch := &Batch{}
+	for {
+		record, err := records.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidImport, err)
+		}
+		if isBlank(record) {
+			continue
+		}
+		line, _ := records.FieldPos(0)
+
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		observation, err := csvObservation(field)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidImport, line, err)
+		}
+		batch.Observations = append(batch.Observations, observation)
+	}
+
+	if len(batch.Observations) == 0 {
+		return nil, fmt.Errorf("%w: no results", ErrInvalidImport)
+	}
+	return batch, nil
+}
+
+func csvObservation(field func(string) string) (Observation, error) {
+	orderID, err := strconv.Atoi(field(columnOrderID))
+	if err != nil || orderID <= 0 {
+		return Observation{}, fmt.Errorf("invalid %s %q", columnOrderID, field(columnOrderID))
+	}
+
+	observation := Observation{
+		OrderID:        orderID,
+		TestCode:       field(columnTestCode),
+		TestName:       field(columnTestName),
+		Value:          field(columnValue),
+		Unit:           field(columnUnit),
+		ReferenceRange: field(columnReferenceRange),
+		Flag:           field(columnFlag),
+		Final:          true,
+	}
+	if observation.TestCode == "" {
+		return Observation{}, fmt.Errorf("%s is required", columnTestCode)
+	}
+	if observation.Value == "" {
+		return Observation{}, fmt.Errorf("%s is required", columnValue)
+	}
+
+	switch status := strings.ToLower(field(columnStatus)); status {
+	case "", "final", "f", "c", "corrected":
+	case "preliminary", "p":
+		observation.Final = false
+	default:
+		return Observation{}, fmt.Errorf("invalid %s %q", columnStatus, status)
+	}
+
+	if value := field(columnObservedAt); value != "" {
+		observedAt, err := parseTimestamp(value)
+		if err != nil {
+			return Observation{}, fmt.Errorf("invalid %s %q", columnObservedAt, value)
+		}
+		observation.ObservedAt = &observedAt
+	}
+	return observation, nil
+}
+
+// detectDelimiter смотрит на строку заголовков, не извлекая её из потока
+func detectDelimiter(reader *bufio.Reader) (rune, error) {
+	head, err := reader.Peek(4096)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return 0, fmt.Errorf("failed to read import: %w", err)
+	}
+	if i := bytes.IndexByte(head, '\n'); i >= 0 {
+		head = head[:i]
+	}
+	if bytes.Count(head, []byte{';'}) > bytes.Count(head, []byte{','}) {
+		return ';', nil
+	}
+	return ',', nil
+}
+
+func isBlank(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// parseTimestamp принимает RFC 3339 и "YYYY-MM-DD HH:MM" (время без зоны считается UTC)
+func parseTimestamp(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02 15:04", value)
+}
@@ -0,0 +1,246 @@
+package lab
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// hl7Delimiters - разделители сообщения, объявленные в сегменте MSH
+type hl7Delimiters struct {
+	field, component, repetition, escape, subcomponent byte
+}
+
+// hl7Segment - поля сегмента; field(n) возвращает поле с номером n по спецификации
+type hl7Segment struct {
+	name   string
+	fields []string
+}
+
+func (s hl7Segment) field(n int) string {
+	if n <= 0 || n > len(s.fields) {
+		return ""
+	}
+	return s.fields[n-1]
+}
+
+// ParseHL7 разбирает сообщение HL7 v2 ORU^R01. Номер направления берётся из OBR-2
+// (placer order number), показатели - из сегментов OBX. OBX с типом ED и данными
+// в Base64 (^application^pdf^Base64^...) считаются PDF-бланком результата.
+func ParseHL7(data []byte) (*Batch, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	// Сегменты разделяются CR; LF и CRLF встречаются при передаче файлом
+	lines := strings.FieldsFunc(string(data), func(r rune) bool { return r == '\r' || r == '\n' })
+	if len(lines) == 0 || !strings.HasPrefix(lines[0], "MSH") || len(lines[0]) < 8 {
+		return nil, fmt.Errorf("%w: message must start with MSH segment", ErrInvalidImport)
+	}
+
+	header := lines[0]
+	d := hl7Delimiters{
+		field:        header[3],
+		component:    header[4],
+		repetition:   header[5],
+		escape:       header[6],
+		subcomponent: header[7],
+	}
+
+	// В MSH первым полем считается сам разделитель, поэтому нумерация смещена на единицу
+	msh := hl7Segment{name: "MSH", fields: append([]string{string(d.field)}, strings.Split(header[3:], string(d.field))[1:]...)}
+	messageType := strings.Split(msh.field(9), string(d.component))
+	if len(messageType) < 2 || messageType[0] != "ORU" || messageType[1] != "R01" {
+		return nil, fmt.Errorf("%w: unsupported message type %q", ErrInvalidImport, msh.field(9))
+	}
+
+	batch := &Batch{}
+	var (
+		orderID     int
+		orderStatus string
+	)
+	for i, line := range lines[1:] {
+		number := i + 2
+		parts := strings.Split(line, string(d.field))
+		segment := hl7Segment{name: parts[0], fields: parts[1:]}
+
+		switch segment.name {
+		case "OBR":
+			placer := d.component1(segment.field(2))
+			id, err := strconv.Atoi(placer)
+			if err != nil || id <= 0 {
+				return nil, fmt.Errorf("%w: segment %d: invalid placer order number %q", ErrInvalidImport, number, placer)
+			}
+			orderID = id
+			orderStatus = segment.field(25)
+
+		case "OBX":
+			if orderID == 0 {
+				return nil, fmt.Errorf("%w: segment %d: OBX without preceding OBR", ErrInvalidImport, number)
+			}
+			if segment.field(2) == "ED" {
+				attachment, err := d.attachment(segment, orderID)
+				if err != nil {
+					return nil, fmt.Errorf("%w: segment %d: %v", ErrInvalidImport, number, err)
+				}
+				batch.Attachments = append(batch.Attachments, attachment)
+				continue
+			}
+
+			observation, err := d.observation(segment, orderID, orderStatus)
+			if err != nil {
+				return nil, fmt.Errorf("%w: segment %d: %v", ErrInvalidImport, number, err)
+			}
+			batch.Observations = append(batch.Observations, observation)
+		}
+	}
+
+	if len(batch.Observations) == 0 && len(batch.Attachments) == 0 {
+		return nil, fmt.Errorf("%w: no results", ErrInvalidImport)
+	}
+	return batch, nil
+}
+
+func (d hl7Delimiters) observation(segment hl7Segment, orderID int, orderStatus string) (Observation, error) {
+	identifier := strings.Split(segment.field(3), string(d.component))
+	observation := Observation{
+		OrderID:        orderID,
+		TestCode:       d.unescape(identifier[0]),
+		Value:          d.unescape(d.firstRepetition(segment.field(5))),
+		Unit:           d.unescape(d.component1(segment.field(6))),
+		ReferenceRange: d.unescape(segment.field(7)),
+		Flag:           d.component1(d.firstRepetition(segment.field(8))),
+	}
+	if len(identifier) > 1 {
+		observation.TestName = d.unescape(identifier[1])
+	}
+	if observation.TestCode == "" {
+		return Observation{}, fmt.Errorf("OBX-3 observation identifier is required")
+	}
+	if observation.Value == "" {
+		return Observation{}, fmt.Errorf("OBX-5 value is required for %q", observation.TestCode)
+	}
+
+	// OBX-11: F - окончательный, C - исправленный, P - предварительный. Без статуса
+	// наблюдения действует статус всего направления из OBR-25
+	status := segment.field(11)
+	if status == "" {
+		status = orderStatus
+	}
+	switch status {
+	case "", "F", "C":
+		observation.Final = true
+	case "P", "R", "I", "S":
+	default:
+		return Observation{}, fmt.Errorf("unsupported result status %q", status)
+	}
+
+	if value := segment.field(14); value != "" {
+		observedAt, err := parseHL7Time(value)
+		if err != nil {
+			return Observation{}, fmt.Errorf("invalid OBX-14 date/time %q", value)
+		}
+		observation.ObservedAt = &observedAt
+	}
+	return observation, nil
+}
+
+// attachment разбирает OBX-5 типа ED: источник^тип^подтип^кодировка^данные
+func (d hl7Delimiters) attachment(segment hl7Segment, orderID int) (Attachment, error) {
+	components := strings.Split(segment.field(5), string(d.component))
+	if len(components) < 5 {
+		return Attachment{}, fmt.Errorf("OBX-5 encapsulated data must have 5 components")
+	}
+	if !strings.EqualFold(components[1], "application") || !strings.EqualFold(components[2], "pdf") {
+		return Attachment{}, fmt.Errorf("unsupported attachment type %s/%s", components[1], components[2])
+	}
+	if !strings.EqualFold(components[3], "Base64") {
+		return Attachment{}, fmt.Errorf("unsupported attachment encoding %q", components[3])
+	}
+
+	data, err := base64.StdEncoding.DecodeString(d.unescape(components[4]))
+	if err != nil {
+		return Attachment{}, fmt.Errorf("invalid base64 attachment: %v", err)
+	}
+
+	identifier := strings.Split(segment.field(3), string(d.component))
+	name := identifier[0]
+	if len(identifier) > 1 && identifier[1] != "" {
+		name = identifier[1]
+	}
+	if name == "" {
+		name = "result"
+	}
+	return Attachment{OrderID: orderID, Filename: d.unescape(name) + ".pdf", Data: data}, nil
+}
+
+func (d hl7Delimiters) component1(value string) string {
+	before, _, _ := strings.Cut(value, string(d.component))
+	return before
+}
+
+func (d hl7Delimiters) firstRepetition(value string) string {
+	before, _, _ := strings.Cut(value, string(d.repetition))
+	return before
+}
+
+// unescape раскрывает escape-последовательности HL7: \F\ \S\ \T\ \R\ \E\ и \.br\
+func (d hl7Delimiters) unescape(value string) string {
+	escape := string(d.escape)
+	if !strings.Contains(value, escape) {
+		return value
+	}
+
+	var b strings.Builder
+	for {
+		start := strings.Index(value, escape)
+		if start < 0 {
+			break
+		}
+		end := strings.Index(value[start+1:], escape)
+		if end < 0 {
+			break
+		}
+		b.WriteString(value[:start])
+		switch sequence := value[start+1 : start+1+end]; sequence {
+		case "F":
+			b.WriteByte(d.field)
+		case "S":
+			b.WriteByte(d.component)
+		case "T":
+			b.WriteByte(d.subcomponent)
+		case "R":
+			b.WriteByte(d.repetition)
+		case "E":
+			b.WriteByte(d.escape)
+		case ".br":
+			b.WriteByte('\n')
+		default:
+			// Неизвестные последовательности (форматирование, шестнадцатеричные коды) отбрасываются
+		}
+		value = value[start+end+2:]
+	}
+	b.WriteString(value)
+	return b.String()
+}
+
+// parseHL7Time разбирает тип DTM: YYYYMMDD[HHMM[SS[.S+]]][+/-ZZZZ]. Время без зоны считается UTC
+func parseHL7Time(value string) (time.Time, error) {
+	zone := ""
+	if i := strings.IndexAny(value, "+-"); i >= 0 {
+		value, zone = value[:i], value[i:]
+	}
+	if i := strings.IndexByte(value, '.'); i >= 0 {
+		value = value[:i]
+	}
+
+	layouts := map[int]string{8: "20060102", 12: "200601021504", 14: "20060102150405"}
+	layout, ok := layouts[len(value)]
+	if !ok {
+		return time.Time{}, fmt.Errorf("unsupported length %d", len(value))
+	}
+	if zone != "" {
+		return time.Parse(layout+"-0700", value+zone)
+	}
+	return time.Parse(layout, value)
+}
@@ -0,0 +1,132 @@
+// Package lab разбирает результаты анализов, присылаемые лабораторией: CSV-таблицы
+// и сообщения HL7 v2 ORU^R01, - и определяет выход значений за референсный интервал.
+package lab
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidImport - файл импорта не удалось разобрать; текст ошибки указывает строку
+var ErrInvalidImport = errors.New("invalid lab import")
+
+// Флаги результата относительно референсного интервала
+const (
+	FlagNormal   = "normal"
+	FlagLow      = "low"
+	FlagHigh     = "high"
+	FlagAbnormal = "abnormal"
+)
+
+// Observation - результат одного показателя из файла лаборатории
+type Observation struct {
+	OrderID        int
+	TestCode       string
+	TestName       string
+	Value          string
+	Unit           string
+	ReferenceRange string
+	// Флаг лаборатории в исходном виде (H, L, A, N, high...); пустой - не указан
+	Flag  string
+	Final bool
+	// Время взятия или выполнения; nil - не указано
+	ObservedAt *time.Time
+}
+
+// Attachment - PDF-бланк результата, переданный лабораторией вместе с показателями
+type Attachment struct {
+	OrderID  int
+	Filename string
+	Data     []byte
+}
+
+// Batch - содержимое одного файла импорта
+type Batch struct {
+	Observations []Observation
+	Attachments  []Attachment
+}
+
+var (
+	numberPattern = regexp.MustCompile(`^-?\d+(?:[.,]\d+)?$`)
+	rangePattern  = regexp.MustCompile(`^(-?\d+(?:[.,]\d+)?)\s*[-–—]\s*(-?\d+(?:[.,]\d+)?)$`)
+)
+
+// Evaluate возвращает флаг результата и признак выхода за интервал. Флаг лаборатории
+// имеет приоритет; без него числовое значение сравнивается с интервалом вида "3.5-5.0",
+// "<5" или ">1". Если ни то ни другое не применимо, флаг пустой.
+func Evaluate(value, referenceRange, labFlag string) (string, bool) {
+	if flag := normalizeFlag(labFlag); flag != "" {
+		return flag, flag != FlagNormal
+	}
+
+	number, ok := parseNumber(value)
+	if !ok {
+		return "", false
+	}
+
+	low, high, ok := parseRange(referenceRange)
+	if !ok {
+		return "", false
+	}
+	switch {
+	case low != nil && number < *low:
+		return FlagLow, true
+	case high != nil && number > *high:
+		return FlagHigh, true
+	}
+	return FlagNormal, false
+}
+
+// normalizeFlag переводит флаги HL7 (таблица 0078) и их словесные варианты в флаги результата
+func normalizeFlag(flag string) string {
+	switch strings.ToUpper(strings.TrimSpace(flag)) {
+	case "N", "NORMAL":
+		return FlagNormal
+	case "L", "LL", "<", "LOW":
+		return FlagLow
+	case "H", "HH", ">", "HIGH":
+		return FlagHigh
+	case "A", "AA", "ABNORMAL":
+		return FlagAbnormal
+	}
+	return ""
+}
+
+// parseRange разбирает интервал "a-b", "<b", "<=b", ">a", ">=a". Граница nil - не задана
+func parseRange(value string) (low, high *float64, ok bool) {
+	value = strings.NewReplacer("≤", "<=", "≥", ">=", " ", "").Replace(strings.TrimSpace(value))
+	if m := rangePattern.FindStringSubmatch(value); m != nil {
+		a, _ := parseNumber(m[1])
+		b, _ := parseNumber(m[2])
+		return &a, &b, a <= b
+	}
+
+	for _, prefix := range []string{"<=", ">=", "<", ">"} {
+		rest, found := strings.CutPrefix(value, prefix)
+		if !found {
+			continue
+		}
+		bound, isNumber := parseNumber(rest)
+		if !isNumber {
+			return nil, nil, false
+		}
+		if prefix[0] == '<' {
+			return nil, &bound, true
+		}
+		return &bound, nil, true
+	}
+	return nil, nil, false
+}
+
+// parseNumber принимает десятичную запятую. Значения вида "<0.1" числом не считаются
+func parseNumber(value string) (float64, bool) {
+	value = strings.TrimSpace(value)
+	if !numberPattern.MatchString(value) {
+		return 0, false
+	}
+	number, err := strconv.ParseFloat(strings.Replace(value, ",", ".", 1), 64)
+	return number, err == nil
+}
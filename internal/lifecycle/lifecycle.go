@@ -0,0 +1,189 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Manager запускает компоненты приложения и останавливает их в заданном порядке.
+//
+// Задачи, запущенные через Run (например, ListenAndServe), при ошибке инициируют
+// остановку всего приложения. Воркеры, запущенные через Worker, останавливаются
+// отменой своего контекста на шаге StopWorkers. Шаги остановки выполняются
+// в порядке регистрации и делят общий срок gracePeriod. Шаг, не уложившийся в срок,
+// бросается, и остановка продолжается со следующего шага: закрыть пул соединений
+// нужно и после зависшего сервера.
+type Manager struct {
+	gracePeriod time.Duration
+	// overrun - сколько ждать шаг, начатый после истечения gracePeriod
+	overrun time.Duration
+
+	errCh chan error
+
+	workerCtx    context.Context
+	cancelWorker context.CancelFunc
+	workers      sync.WaitGroup
+
+	mu    sync.Mutex
+	steps []step
+}
+
+type step struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+func New(gracePeriod time.Duration) *Manager {
+	workerCtx, cancel := context.WithCancel(context.Background())
+	return &Manager{
+		gracePeriod:  gracePeriod,
+		overrun:      time.Second,
+		errCh:        make(chan error, 1),
+		workerCtx:    workerCtx,
+		cancelWorker: cancel,
+	}
+}
+
+// Run запускает задачу в фоне. Ошибка или паника задачи приводит к остановке приложения.
+func (m *Manager) Run(name string, fn func() error) {
+	go func() {
+		if err := safeCall(fn); err != nil {
+			m.fail(fmt.Errorf("%s: %w", name, err))
+		}
+	}()
+}
+
+// Worker запускает фоновый воркер, который работает до отмены контекста
+func (m *Manager) Worker(name string, fn func(ctx context.Context)) {
+	m.workers.Add(1)
+	go func() {
+		defer m.workers.Done()
+		err := safeCall(func() error {
+			fn(m.workerCtx)
+			return nil
+		})
+		if err != nil {
+			m.fail(fmt.Errorf("%s: %w", name, err))
+		}
+	}()
+}
+
+// OnShutdown добавляет шаг остановки. Шаги выполняются в порядке добавления.
+func (m *Manager) OnShutdown(name string, fn func(ctx context.Context) error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.steps = append(m.steps, step{name: name, fn: fn})
+}
+
+// StopWorkers отменяет контекст воркеров и ждёт их завершения. Используется как шаг остановки.
+func (m *Manager) StopWorkers(ctx context.Context) error {
+	m.cancelWorker()
+
+	done := make(chan struct{})
+	go func() {
+		m.workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("workers did not stop in time: %w", ctx.Err())
+	}
+}
+
+// Wait блокируется до отмены ctx (сигнал) или ошибки одной из задач, затем
+// выполняет шаги остановки. Возвращает ошибку задачи и ошибки шагов остановки.
+func (m *Manager) Wait(ctx context.Context) error {
+	var runErr error
+	select {
+	case <-ctx.Done():
+		slog.Info("Shutdown signal received")
+	case runErr = <-m.errCh:
+		slog.Error("Component failed, shutting down", "error", runErr.Error())
+	}
+
+	return errors.Join(runErr, m.shutdown())
+}
+
+func (m *Manager) shutdown() error {
+	// Контекст сигнала уже отменён, поэтому срок остановки отсчитывается от нового контекста
+	ctx, cancel := context.WithTimeout(context.Background(), m.gracePeriod)
+	defer cancel()
+
+	m.mu.Lock()
+	steps := append([]step(nil), m.steps...)
+	m.mu.Unlock()
+
+	var errs []error
+	for _, s := range steps {
+		start := time.Now()
+		if err := m.runStep(ctx, s); err != nil {
+			slog.Error("Shutdown step failed", "step", s.name, "error", err.Error())
+			errs = append(errs, fmt.Errorf("shutdown %s: %w", s.name, err))
+			continue
+		}
+		slog.Info("Shutdown step completed", "step", s.name, "duration_ms", time.Since(start).Milliseconds())
+	}
+
+	// Воркеры останавливаются и без явного шага
+	m.cancelWorker()
+
+	return errors.Join(errs...)
+}
+
+// runStep ждёт шаг до истечения срока остановки. Шаги, которые начались уже после него
+// (например, закрытие пула), получают ещё overrun, чтобы не бросать быстрые шаги
+func (m *Manager) runStep(ctx context.Context, s step) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- safeCall(func() error { return s.fn(ctx) })
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+	}
+
+	timer := time.NewTimer(m.overrun)
+	defer timer.Stop()
+	select {
+	case err := <-done:
+		return err
+	case <-timer.C:
+		return fmt.Errorf("step did not finish in time: %w", ctx.Err())
+	}
+}
+
+// ExitCode - код завершения процесса по результату Wait
+func ExitCode(err error) int {
+	if err != nil {
+		return 1
+	}
+	return 0
+}
+
+// fail сохраняет первую ошибку; последующие только логируются
+func (m *Manager) fail(err error) {
+	select {
+	case m.errCh <- err:
+	default:
+		slog.Error("Component failed during shutdown", "error", err.Error())
+	}
+}
+
+// safeCall превращает панику задачи в ошибку
+func safeCall(fn func() error) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+	return fn()
+}
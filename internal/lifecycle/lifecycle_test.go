@@ -0,0 +1,194 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder запоминает порядок выполненных шагов остановки
+type recorder struct {
+	mu    sync.Mutex
+	steps []string
+}
+
+func (r *recorder) step(name string, err error) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.steps = append(r.steps, name)
+		return err
+	}
+}
+
+func (r *recorder) got() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return strings.Join(r.steps, ",")
+}
+
+func cancelled() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	return ctx
+}
+
+func TestShutdownStepsRunInOrder(t *testing.T) {
+	m := New(time.Second)
+	var r recorder
+	m.OnShutdown("server", r.step("server", nil))
+	m.OnShutdown("workers", m.StopWorkers)
+	m.OnShutdown("tracing", r.step("tracing", nil))
+	m.OnShutdown("database", r.step("database", nil))
+
+	if err := m.Wait(cancelled()); err != nil {
+		t.Fatalf("Wait() = %v, want nil", err)
+	}
+	if got := r.got(); got != "server,tracing,database" {
+		t.Errorf("steps ran as %q, want server,tracing,database", got)
+	}
+}
+
+func TestFailingStepDoesNotStopLaterSteps(t *testing.T) {
+	m := New(time.Second)
+	var r recorder
+	stepErr := errors.New("boom")
+	m.OnShutdown("server", r.step("server", stepErr))
+	m.OnShutdown("panicking", func(ctx context.Context) error { panic("oops") })
+	m.OnShutdown("database", r.step("database", nil))
+
+	err := m.Wait(cancelled())
+	if !errors.Is(err, stepErr) {
+		t.Errorf("Wait() = %v, want it to wrap the step error", err)
+	}
+	if err == nil || !strings.Contains(err.Error(), "shutdown panicking: panic: oops") {
+		t.Errorf("Wait() = %v, want the panic reported as a step error", err)
+	}
+	if got := r.got(); got != "server,database" {
+		t.Errorf("steps ran as %q, want server,database", got)
+	}
+}
+
+func TestShutdownStepTimeout(t *testing.T) {
+	m := New(50 * time.Millisecond)
+	m.overrun = 20 * time.Millisecond
+	var r recorder
+
+	release := make(chan struct{})
+	defer close(release)
+	// Шаг игнорирует контекст - его нужно бросить по истечении срока
+	m.OnShutdown("stuck", func(ctx context.Context) error {
+		<-release
+		return nil
+	})
+	m.OnShutdown("database", r.step("database", nil))
+
+	start := time.Now()
+	err := m.Wait(cancelled())
+	elapsed := time.Since(start)
+
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "shutdown stuck") {
+		t.Errorf("Wait() = %v, want a deadline error for the stuck step", err)
+	}
+	if elapsed > time.Second {
+		t.Errorf("Wait() took %v, want it bounded by the grace period", elapsed)
+	}
+	if got := r.got(); got != "database" {
+		t.Errorf("steps after the stuck one: %q, want database", got)
+	}
+}
+
+func TestStepReceivesGraceDeadline(t *testing.T) {
+	m := New(time.Minute)
+	var remaining time.Duration
+	m.OnShutdown("server", func(ctx context.Context) error {
+		deadline, ok := ctx.Deadline()
+		if !ok {
+			return errors.New("no deadline")
+		}
+		remaining = time.Until(deadline)
+		return nil
+	})
+
+	// Сигнальный контекст уже отменён, но срок остановки отсчитывается заново
+	if err := m.Wait(cancelled()); err != nil {
+		t.Fatalf("Wait() = %v, want nil", err)
+	}
+	if remaining < 50*time.Second || remaining > time.Minute {
+		t.Errorf("step deadline in %v, want about the grace period", remaining)
+	}
+}
+
+func TestRunFailureTriggersShutdown(t *testing.T) {
+	m := New(time.Second)
+	var r recorder
+	m.OnShutdown("database", r.step("database", nil))
+
+	runErr := errors.New("address already in use")
+	m.Run("http server", func() error { return runErr })
+
+	err := m.Wait(context.Background())
+	if !errors.Is(err, runErr) || !strings.Contains(err.Error(), "http server") {
+		t.Errorf("Wait() = %v, want the http server error", err)
+	}
+	if got := r.got(); got != "database" {
+		t.Errorf("steps ran as %q, want database", got)
+	}
+}
+
+func TestStopWorkersCancelsWorkers(t *testing.T) {
+	m := New(time.Second)
+	stopped := make(chan struct{})
+	m.Worker("purge", func(ctx context.Context) {
+		<-ctx.Done()
+		close(stopped)
+	})
+	m.OnShutdown("workers", m.StopWorkers)
+
+	if err := m.Wait(cancelled()); err != nil {
+		t.Fatalf("Wait() = %v, want nil", err)
+	}
+	select {
+	case <-stopped:
+	default:
+		t.Error("worker was not stopped before Wait returned")
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name   string
+		signal bool
+		setup  func(m *Manager)
+		want   int
+	}{
+		{name: "clean shutdown", signal: true, setup: func(m *Manager) {}, want: 0},
+		{name: "step failure", signal: true, setup: func(m *Manager) {
+			m.OnShutdown("server", func(ctx context.Context) error { return errors.New("boom") })
+		}, want: 1},
+		{name: "component failure", setup: func(m *Manager) {
+			m.Run("http server", func() error { return errors.New("listen failed") })
+		}, want: 1},
+		{name: "component panic", setup: func(m *Manager) {
+			m.Run("http server", func() error { panic("oops") })
+		}, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New(time.Second)
+			tt.setup(m)
+
+			ctx := context.Background()
+			if tt.signal {
+				ctx = cancelled()
+			}
+			if got := ExitCode(m.Wait(ctx)); got != tt.want {
+				t.Errorf("ExitCode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
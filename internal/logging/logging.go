@@ -0,0 +1,89 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"unicode/utf8"
+)
+
+type loggerKey struct{}
+
+// WithLogger сохраняет логгер запроса в контексте
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext возвращает логгер запроса (с request_id, user_id, role, route)
+// или глобальный логгер, если контекст создан вне HTTP-запроса
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
+
+// New создаёт JSON-логгер, маскирующий секреты и персональные данные.
+// Для смены уровня на лету передаётся *slog.LevelVar.
+func New(w io.Writer, level slog.Leveler) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{
+		Level:       level,
+		ReplaceAttr: redact,
+	}))
+}
+
+// ParseLevel разбирает уровень логирования: debug, info, warn, error
+func ParseLevel(value string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(value)); err != nil {
+		return level, fmt.Errorf("invalid log level %q: %w", value, err)
+	}
+	return level, nil
+}
+
+const redacted = "[REDACTED]"
+
+// Ключи, значения которых никогда не пишутся в лог
+var secretKeys = map[string]bool{
+	"password":      true,
+	"token":         true,
+	"access_token":  true,
+	"refresh_token": true,
+	"authorization": true,
+	"secret":        true,
+	"jwt_secret":    true,
+	"cookie":        true,
+}
+
+// Ключи с персональными данными, которые пишутся частично
+var piiKeys = map[string]bool{
+	"email": true,
+	"phone": true,
+	"snils": true,
+}
+
+func redact(_ []string, a slog.Attr) slog.Attr {
+	key := strings.ToLower(a.Key)
+	switch {
+	case secretKeys[key]:
+		return slog.String(a.Key, redacted)
+	case piiKeys[key]:
+		return slog.String(a.Key, mask(a.Value.String()))
+	}
+	return a
+}
+
+// mask оставляет первый символ и домен почты: j***@example.com, остальное - только длину
+func mask(value string) string {
+	if value == "" {
+		return value
+	}
+	if at := strings.LastIndex(value, "@"); at > 0 {
+		// Первый символ целиком, а не первый байт: у кириллицы он двухбайтовый
+		_, size := utf8.DecodeRuneInString(value)
+		return value[:size] + "***" + value[at:]
+	}
+	return strings.Repeat("*", len([]rune(value)))
+}
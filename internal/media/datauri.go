@@ -0,0 +1,36 @@
+package media
+
+import (
+	"encoding/base64"
+	"strings"
+)
+
+// DecodeBase64 возвращает содержимое data URI ("data:image/png;base64,...") или
+// base64-строки без префикса. Тип из data URI не используется - он определяется по содержимому.
+func DecodeBase64(value string) ([]byte, error) {
+	value = strings.TrimSpace(value)
+	if rest, ok := strings.CutPrefix(value, "data:"); ok {
+		header, payload, found := strings.Cut(rest, ",")
+		if !found || !strings.HasSuffix(header, ";base64") {
+			return nil, ErrInvalidImage
+		}
+		value = payload
+	}
+
+	// Переносы строк допустимы в base64 из писем и некоторых редакторов
+	value = strings.NewReplacer("\n", "", "\r", "", " ", "").Replace(value)
+
+	data, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		data, err = base64.RawStdEncoding.DecodeString(value)
+	}
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+	return data, nil
+}
+
+// IsDataURI сообщает, содержит ли значение изображение в виде data URI
+func IsDataURI(value string) bool {
+	return strings.HasPrefix(strings.TrimSpace(value), "data:")
+}
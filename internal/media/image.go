@@ -0,0 +1,353 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"net/http"
+)
+
+const (
+	TypeJPEG = "image/jpeg"
+	TypePNG  = "image/png"
+	TypeWebP = "image/webp"
+
+	// Защита от «бомб» - маленьких файлов с огромным разрешением
+	maxPixels = 50_000_000
+)
+
+var (
+	ErrUnsupportedType = errors.New("unsupported image type, expected JPEG, PNG or WebP")
+	ErrInvalidImage    = errors.New("invalid or corrupted image")
+	ErrTooManyPixels   = errors.New("image resolution is too large")
+)
+
+// Image - проверенное изображение без метаданных
+type Image struct {
+	ContentType string
+	// Размеры при отображении, с учётом поворота из EXIF
+	Width  int
+	Height int
+	Data   []byte
+}
+
+// Process определяет тип по содержимому (а не по имени файла или заголовку клиента),
+// удаляет EXIF/XMP и текстовые метаданные и проверяет, что изображение читается.
+// Пиксели не перекодируются: от EXIF остаётся только тег Orientation, иначе снимки
+// с телефона отображались бы повёрнутыми. Уменьшенные копии поворачиваются при создании.
+func Process(data []byte) (*Image, error) {
+	contentType := http.DetectContentType(data)
+
+	stripped, orientation, err := strip(contentType, data)
+	if err != nil {
+		return nil, err
+	}
+
+	width, height, err := dimensions(contentType, stripped)
+	if err != nil {
+		return nil, err
+	}
+	if width <= 0 || height <= 0 {
+		return nil, ErrInvalidImage
+	}
+	if width*height > maxPixels {
+		return nil, ErrTooManyPixels
+	}
+	if swapsAxes(orientation) {
+		width, height = height, width
+	}
+
+	return &Image{ContentType: contentType, Width: width, Height: height, Data: stripped}, nil
+}
+
+// strip удаляет метаданные и возвращает ориентацию из EXIF
+func strip(contentType string, data []byte) ([]byte, int, error) {
+	switch contentType {
+	case TypeJPEG:
+		return stripJPEG(data)
+	case TypePNG:
+		return stripPNG(data)
+	case TypeWebP:
+		return stripWebP(data)
+	}
+	return nil, 0, ErrUnsupportedType
+}
+
+func dimensions(contentType string, data []byte) (int, int, error) {
+	if contentType == TypeWebP {
+		return webpDimensions(data)
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return 0, 0, ErrInvalidImage
+	}
+	return cfg.Width, cfg.Height, nil
+}
+
+// stripJPEG удаляет сегменты APP1-APP15 (EXIF, XMP, IPTC и т.п.) и комментарии.
+// APP0 (JFIF), ICC-профиль в APP2 и APP14 (Adobe) влияют на отображение и сохраняются.
+// Сегмент EXIF с поворотом заменяется минимальным на том же месте.
+func stripJPEG(data []byte) ([]byte, int, error) {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return nil, 0, ErrInvalidImage
+	}
+
+	// 0 - сегмент EXIF ещё не встречался
+	orientation := 0
+
+	out := make([]byte, 0, len(data))
+	out = append(out, 0xFF, 0xD8)
+
+	i := 2
+	for i < len(data) {
+		if data[i] != 0xFF {
+			return nil, 0, ErrInvalidImage
+		}
+		// Маркеру может предшествовать любое число байтов-заполнителей 0xFF
+		for i < len(data) && data[i] == 0xFF {
+			i++
+		}
+		if i >= len(data) {
+			return nil, 0, ErrInvalidImage
+		}
+		marker := data[i]
+		i++
+
+		switch {
+		case marker == 0xD9: // EOI
+			return append(out, 0xFF, marker), max(orientation, orientationNormal), nil
+		case marker >= 0xD0 && marker <= 0xD7 || marker == 0x01: // маркеры без длины
+			out = append(out, 0xFF, marker)
+			continue
+		}
+
+		if i+2 > len(data) {
+			return nil, 0, ErrInvalidImage
+		}
+		length := int(binary.BigEndian.Uint16(data[i:]))
+		if length < 2 || i+length > len(data) {
+			return nil, 0, ErrInvalidImage
+		}
+
+		// После SOS идут сжатые данные - копируются как есть
+		if marker == 0xDA {
+			out = append(out, 0xFF, marker)
+			return append(out, data[i:]...), max(orientation, orientationNormal), nil
+		}
+
+		payload := data[i+2 : i+length]
+		if marker == 0xE1 && orientation == 0 && bytes.HasPrefix(payload, exifHeader) {
+			orientation = exifOrientation(payload)
+			if orientation != orientationNormal {
+				segment := append(exifHeader[:len(exifHeader):len(exifHeader)], minimalExif(orientation)...)
+				out = append(out, 0xFF, marker)
+				out = binary.BigEndian.AppendUint16(out, uint16(len(segment)+2))
+				out = append(out, segment...)
+			}
+		} else if keepJPEGSegment(marker, payload) {
+			out = append(out, 0xFF, marker)
+			out = append(out, data[i:i+length]...)
+		}
+		i += length
+	}
+
+	return nil, 0, ErrInvalidImage
+}
+
+func keepJPEGSegment(marker byte, payload []byte) bool {
+	switch {
+	case marker == 0xE0, marker == 0xEE:
+		return true
+	case marker == 0xE2:
+		return bytes.HasPrefix(payload, []byte("ICC_PROFILE\x00"))
+	case marker >= 0xE1 && marker <= 0xEF, marker == 0xFE:
+		return false
+	}
+	return true
+}
+
+// Чанки PNG с метаданными: текст, EXIF, время изменения
+var pngMetadataChunks = map[string]bool{
+	"tEXt": true,
+	"zTXt": true,
+	"iTXt": true,
+	"eXIf": true,
+	"tIME": true,
+}
+
+func stripPNG(data []byte) ([]byte, int, error) {
+	const signature = "\x89PNG\r\n\x1a\n"
+	if !bytes.HasPrefix(data, []byte(signature)) {
+		return nil, 0, ErrInvalidImage
+	}
+
+	orientation := orientationNormal
+
+	out := make([]byte, 0, len(data))
+	out = append(out, signature...)
+
+	i := len(signature)
+	for i+12 <= len(data) {
+		length := binary.BigEndian.Uint32(data[i:])
+		if uint64(length) > uint64(len(data)-i-12) {
+			return nil, 0, ErrInvalidImage
+		}
+		chunkType := string(data[i+4 : i+8])
+		end := i + 12 + int(length)
+
+		if chunkType == "eXIf" {
+			// Чанк EXIF заменяется минимальным на том же месте - до IDAT, как требует формат
+			orientation = exifOrientation(data[i+8 : end-4])
+			if orientation != orientationNormal {
+				out = appendPNGChunk(out, chunkType, minimalExif(orientation))
+			}
+		} else if !pngMetadataChunks[chunkType] {
+			out = append(out, data[i:end]...)
+		}
+		i = end
+
+		if chunkType == "IEND" {
+			return out, orientation, nil
+		}
+	}
+
+	return nil, 0, ErrInvalidImage
+}
+
+func appendPNGChunk(out []byte, chunkType string, payload []byte) []byte {
+	out = binary.BigEndian.AppendUint32(out, uint32(len(payload)))
+	start := len(out)
+	out = append(out, chunkType...)
+	out = append(out, payload...)
+	return binary.BigEndian.AppendUint32(out, crc32.ChecksumIEEE(out[start:]))
+}
+
+// Флаги наличия EXIF и XMP в чанке VP8X
+const (
+	webpFlagEXIF = 0x08
+	webpFlagXMP  = 0x04
+)
+
+// stripWebP удаляет чанки EXIF и XMP и сбрасывает соответствующие флаги VP8X.
+// Чанк EXIF с поворотом заменяется минимальным.
+func stripWebP(data []byte) ([]byte, int, error) {
+	chunks, err := webpChunks(data)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	orientation := orientationNormal
+	for _, chunk := range chunks {
+		if chunk.fourCC == "EXIF" {
+			orientation = exifOrientation(chunk.payload)
+			break
+		}
+	}
+
+	exifWritten := false
+	out := make([]byte, 0, len(data))
+	out = append(out, data[:12]...)
+	for _, chunk := range chunks {
+		switch chunk.fourCC {
+		case "XMP ":
+			continue
+		case "EXIF":
+			// EXIF допускается только в расширенном формате с VP8X, так что флаг уже выставлен
+			if orientation != orientationNormal && !exifWritten {
+				exif := minimalExif(orientation)
+				out = append(out, "EXIF"...)
+				out = binary.LittleEndian.AppendUint32(out, uint32(len(exif)))
+				out = append(out, exif...)
+				exifWritten = true
+			}
+		case "VP8X":
+			start := len(out)
+			out = append(out, chunk.raw...)
+			if len(chunk.raw) > 8 {
+				out[start+8] &^= webpFlagXMP
+				if orientation == orientationNormal {
+					out[start+8] &^= webpFlagEXIF
+				}
+			}
+		default:
+			out = append(out, chunk.raw...)
+		}
+	}
+
+	binary.LittleEndian.PutUint32(out[4:8], uint32(len(out)-8))
+	return out, orientation, nil
+}
+
+func webpDimensions(data []byte) (int, int, error) {
+	chunks, err := webpChunks(data)
+	if err != nil || len(chunks) == 0 {
+		return 0, 0, ErrInvalidImage
+	}
+
+	p := chunks[0].payload
+	switch chunks[0].fourCC {
+	case "VP8X":
+		if len(p) >= 10 {
+			return 1 + le24(p[4:7]), 1 + le24(p[7:10]), nil
+		}
+	case "VP8 ":
+		// Ключевой кадр: 3 байта тега кадра, стартовый код 9d 01 2a, затем размеры по 14 бит
+		if len(p) >= 10 && p[3] == 0x9d && p[4] == 0x01 && p[5] == 0x2a {
+			width := int(binary.LittleEndian.Uint16(p[6:8]) & 0x3fff)
+			height := int(binary.LittleEndian.Uint16(p[8:10]) & 0x3fff)
+			return width, height, nil
+		}
+	case "VP8L":
+		if len(p) >= 5 && p[0] == 0x2f {
+			bits := binary.LittleEndian.Uint32(p[1:5])
+			return 1 + int(bits&0x3fff), 1 + int(bits>>14&0x3fff), nil
+		}
+	}
+	return 0, 0, ErrInvalidImage
+}
+
+type webpChunk struct {
+	fourCC  string
+	payload []byte
+	// Чанк целиком: заголовок, данные и байт выравнивания
+	raw []byte
+}
+
+func webpChunks(data []byte) ([]webpChunk, error) {
+	if len(data) < 12 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return nil, ErrInvalidImage
+	}
+	riffEnd := 8 + int(binary.LittleEndian.Uint32(data[4:8]))
+	if riffEnd > len(data) || riffEnd < 12 {
+		return nil, ErrInvalidImage
+	}
+
+	var chunks []webpChunk
+	for i := 12; i < riffEnd; {
+		if i+8 > riffEnd {
+			return nil, ErrInvalidImage
+		}
+		size := int(binary.LittleEndian.Uint32(data[i+4 : i+8]))
+		if size < 0 || size > riffEnd-i-8 {
+			return nil, ErrInvalidImage
+		}
+		end := min(i+8+size+size%2, riffEnd)
+
+		chunks = append(chunks, webpChunk{
+			fourCC:  string(data[i : i+4]),
+			payload: data[i+8 : i+8+size],
+			raw:     data[i:end],
+		})
+		i = end
+	}
+	return chunks, nil
+}
+
+func le24(b []byte) int {
+	return int(b[0]) | int(b[1])<<8 | int(b[2])<<16
+}
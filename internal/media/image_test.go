@@ -0,0 +1,273 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+// cameraExif - EXIF в порядке байтов Intel с маркой камеры и ориентацией
+func cameraExif(orientation int) []byte {
+	le := binary.LittleEndian
+	tiff := []byte("II*\x00")
+	tiff = le.AppendUint32(tiff, 8)
+	tiff = le.AppendUint16(tiff, 2)
+	// Make: ASCII, значение за пределами IFD
+	tiff = le.AppendUint16(tiff, 0x010F)
+	tiff = le.AppendUint16(tiff, 2)
+	tiff = le.AppendUint32(tiff, 6)
+	tiff = le.AppendUint32(tiff, 8+2+2*12+4)
+	tiff = le.AppendUint16(tiff, exifOrientationTag)
+	tiff = le.AppendUint16(tiff, exifTypeShort)
+	tiff = le.AppendUint32(tiff, 1)
+	tiff = le.AppendUint16(tiff, uint16(orientation))
+	tiff = append(tiff, 0, 0)
+	tiff = le.AppendUint32(tiff, 0)
+	return append(tiff, "Canon\x00"...)
+}
+
+// testImage - 4x2: левая половина красная, правая синяя
+func testImage() *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	for y := range 2 {
+		for x := range 4 {
+			c := color.RGBA{R: 255, A: 255}
+			if x >= 2 {
+				c = color.RGBA{B: 255, A: 255}
+			}
+			img.SetRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func jpegWithExif(t *testing.T, orientation int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, testImage(), &jpeg.Options{Quality: 95}); err != nil {
+		t.Fatal(err)
+	}
+	encoded := buf.Bytes()
+
+	payload := append([]byte("Exif\x00\x00"), cameraExif(orientation)...)
+	segment := []byte{0xFF, 0xE1}
+	segment = binary.BigEndian.AppendUint16(segment, uint16(len(payload)+2))
+	segment = append(segment, payload...)
+
+	out := append([]byte{}, encoded[:2]...)
+	out = append(out, segment...)
+	return append(out, encoded[2:]...)
+}
+
+func TestProcessJPEGKeepsOnlyOrientation(t *testing.T) {
+	img, err := Process(jpegWithExif(t, 6))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if img.Width != 2 || img.Height != 4 {
+		t.Errorf("dimensions %dx%d, want 2x4 after a 90 degree rotation", img.Width, img.Height)
+	}
+	if bytes.Contains(img.Data, []byte("Canon")) {
+		t.Error("camera make survived metadata stripping")
+	}
+	if !bytes.Contains(img.Data, append([]byte("Exif\x00\x00"), minimalExif(6)...)) {
+		t.Error("orientation-only EXIF segment is missing")
+	}
+	if _, err := jpeg.Decode(bytes.NewReader(img.Data)); err != nil {
+		t.Errorf("processed JPEG does not decode: %v", err)
+	}
+
+	// Повторная обработка сохранённого оригинала даёт тот же результат
+	again, err := Process(img.Data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(again.Data, img.Data) || again.Width != 2 {
+		t.Error("processing is not idempotent")
+	}
+}
+
+func TestProcessJPEGNormalOrientationDropsExif(t *testing.T) {
+	img, err := Process(jpegWithExif(t, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(img.Data, []byte("Exif")) {
+		t.Error("EXIF kept although the orientation is normal")
+	}
+	if img.Width != 4 || img.Height != 2 {
+		t.Errorf("dimensions %dx%d, want 4x2", img.Width, img.Height)
+	}
+}
+
+func TestProcessPNGKeepsOnlyOrientation(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, testImage()); err != nil {
+		t.Fatal(err)
+	}
+	encoded := buf.Bytes()
+
+	// eXIf и tEXt вставляются сразу после IHDR (8 байт сигнатуры + 25 байт чанка)
+	const afterIHDR = 8 + 25
+	out := append([]byte{}, encoded[:afterIHDR]...)
+	out = appendPNGChunk(out, "eXIf", cameraExif(8))
+	out = appendPNGChunk(out, "tEXt", []byte("Comment\x00secret"))
+	out = append(out, encoded[afterIHDR:]...)
+
+	img, err := Process(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Width != 2 || img.Height != 4 {
+		t.Errorf("dimensions %dx%d, want 2x4", img.Width, img.Height)
+	}
+	if bytes.Contains(img.Data, []byte("Canon")) || bytes.Contains(img.Data, []byte("secret")) {
+		t.Error("metadata survived stripping")
+	}
+	if _, orientation, _ := stripPNG(img.Data); orientation != 8 {
+		t.Errorf("orientation %d after processing, want 8", orientation)
+	}
+	// png проверяет CRC чанков
+	if _, err := png.Decode(bytes.NewReader(img.Data)); err != nil {
+		t.Errorf("processed PNG does not decode: %v", err)
+	}
+}
+
+func riffChunk(fourCC string, payload []byte) []byte {
+	chunk := append([]byte(fourCC), binary.LittleEndian.AppendUint32(nil, uint32(len(payload)))...)
+	chunk = append(chunk, payload...)
+	if len(payload)%2 == 1 {
+		chunk = append(chunk, 0)
+	}
+	return chunk
+}
+
+func TestProcessWebPKeepsOnlyOrientation(t *testing.T) {
+	// Размеры 4x2 в VP8X и заголовке VP8L; сами пиксели Process не декодирует
+	vp8x := []byte{webpFlagEXIF | webpFlagXMP, 0, 0, 0, 3, 0, 0, 1, 0, 0}
+	vp8l := []byte{0x2f}
+	vp8l = binary.LittleEndian.AppendUint32(vp8l, 3|1<<14)
+
+	var body []byte
+	body = append(body, "WEBP"...)
+	body = append(body, riffChunk("VP8X", vp8x)...)
+	body = append(body, riffChunk("VP8L", vp8l)...)
+	body = append(body, riffChunk("EXIF", cameraExif(3))...)
+	body = append(body, riffChunk("XMP ", []byte("<x:xmpmeta/>"))...)
+	data := append([]byte("RIFF"), binary.LittleEndian.AppendUint32(nil, uint32(len(body)))...)
+	data = append(data, body...)
+
+	img, err := Process(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Width != 4 || img.Height != 2 {
+		t.Errorf("dimensions %dx%d, want 4x2 for a 180 degree rotation", img.Width, img.Height)
+	}
+
+	chunks, err := webpChunks(img.Data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, chunk := range chunks {
+		names = append(names, chunk.fourCC)
+		if chunk.fourCC == "EXIF" && !bytes.Equal(chunk.payload, minimalExif(3)) {
+			t.Error("EXIF chunk is not reduced to the orientation")
+		}
+	}
+	if got := strings.Join(names, ","); got != "VP8X,VP8L,EXIF" {
+		t.Errorf("chunks %s, want VP8X,VP8L,EXIF", got)
+	}
+	if flags := chunks[0].payload[0]; flags != webpFlagEXIF {
+		t.Errorf("VP8X flags %#x, want only the EXIF flag", flags)
+	}
+}
+
+func TestOrient(t *testing.T) {
+	// 3x2 с уникальными значениями: R = x, G = y
+	src := image.NewRGBA(image.Rect(0, 0, 3, 2))
+	for y := range 2 {
+		for x := range 3 {
+			src.SetRGBA(x, y, color.RGBA{R: uint8(x), G: uint8(y), A: 255})
+		}
+	}
+
+	tests := []struct {
+		orientation int
+		// Исходные координаты для левого верхнего и правого верхнего пикселей результата
+		topLeft, topRight [2]uint8
+		width             int
+	}{
+		{orientation: 1, topLeft: [2]uint8{0, 0}, topRight: [2]uint8{2, 0}, width: 3},
+		{orientation: 2, topLeft: [2]uint8{2, 0}, topRight: [2]uint8{0, 0}, width: 3},
+		{orientation: 3, topLeft: [2]uint8{2, 1}, topRight: [2]uint8{0, 1}, width: 3},
+		{orientation: 4, topLeft: [2]uint8{0, 1}, topRight: [2]uint8{2, 1}, width: 3},
+		{orientation: 5, topLeft: [2]uint8{0, 0}, topRight: [2]uint8{0, 1}, width: 2},
+		{orientation: 6, topLeft: [2]uint8{0, 1}, topRight: [2]uint8{0, 0}, width: 2},
+		{orientation: 7, topLeft: [2]uint8{2, 1}, topRight: [2]uint8{2, 0}, width: 2},
+		{orientation: 8, topLeft: [2]uint8{2, 0}, topRight: [2]uint8{2, 1}, width: 2},
+	}
+
+	for _, tt := range tests {
+		dst := orient(src, tt.orientation)
+		if dst.Rect.Dx() != tt.width {
+			t.Errorf("orientation %d: width %d, want %d", tt.orientation, dst.Rect.Dx(), tt.width)
+			continue
+		}
+		tl, tr := dst.RGBAAt(0, 0), dst.RGBAAt(tt.width-1, 0)
+		if [2]uint8{tl.R, tl.G} != tt.topLeft || [2]uint8{tr.R, tr.G} != tt.topRight {
+			t.Errorf("orientation %d: top row from %v..%v, want %v..%v",
+				tt.orientation, [2]uint8{tl.R, tl.G}, [2]uint8{tr.R, tr.G}, tt.topLeft, tt.topRight)
+		}
+	}
+}
+
+func TestResizeAppliesOrientation(t *testing.T) {
+	// Большое изображение, чтобы копия была меньше оригинала
+	wide := image.NewRGBA(image.Rect(0, 0, 400, 200))
+	for y := range 200 {
+		for x := range 400 {
+			c := color.RGBA{R: 255, A: 255}
+			if x >= 200 {
+				c = color.RGBA{B: 255, A: 255}
+			}
+			wide.SetRGBA(x, y, c)
+		}
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, wide, &jpeg.Options{Quality: 95}); err != nil {
+		t.Fatal(err)
+	}
+	payload := append([]byte("Exif\x00\x00"), minimalExif(6)...)
+	data := []byte{0xFF, 0xD8, 0xFF, 0xE1}
+	data = binary.BigEndian.AppendUint16(data, uint16(len(payload)+2))
+	data = append(data, payload...)
+	data = append(data, buf.Bytes()[2:]...)
+
+	// Отображается как 200x400: копия шириной 300 не нужна, 100 - нужна
+	variants, err := Resize(data, []int{100, 300}, 90)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(variants) != 1 || variants[0].Width != 100 || variants[0].Height != 200 {
+		t.Fatalf("variants %+v, want a single 100x200 copy", variants)
+	}
+
+	decoded, err := jpeg.Decode(bytes.NewReader(variants[0].Data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// После поворота на 90 по часовой левая (красная) половина оказывается сверху
+	top := color.RGBAModel.Convert(decoded.At(50, 20)).(color.RGBA)
+	bottom := color.RGBAModel.Convert(decoded.At(50, 180)).(color.RGBA)
+	if top.R < 200 || top.B > 50 || bottom.B < 200 || bottom.R > 50 {
+		t.Errorf("top %v, bottom %v: want red on top and blue at the bottom", top, bottom)
+	}
+}
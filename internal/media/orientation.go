@@ -0,0 +1,120 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+)
+
+const (
+	// Тег Orientation в IFD0
+	exifOrientationTag = 0x0112
+	// Тип SHORT - 16-битное беззнаковое
+	exifTypeShort = 3
+
+	orientationNormal = 1
+)
+
+// Префикс EXIF в сегменте APP1 JPEG; в PNG и WebP данные обычно начинаются сразу с TIFF
+var exifHeader = []byte("Exif\x00\x00")
+
+// exifOrientation читает тег Orientation из IFD0. Отсутствующий, повреждённый
+// или недопустимый тег означает обычную ориентацию.
+func exifOrientation(payload []byte) int {
+	tiff := bytes.TrimPrefix(payload, exifHeader)
+	if len(tiff) < 8 {
+		return orientationNormal
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:4]) {
+	case "II*\x00":
+		order = binary.LittleEndian
+	case "MM\x00*":
+		order = binary.BigEndian
+	default:
+		return orientationNormal
+	}
+
+	ifd := int(order.Uint32(tiff[4:8]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return orientationNormal
+	}
+	count := int(order.Uint16(tiff[ifd:]))
+	for i := range count {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			break
+		}
+		if order.Uint16(tiff[entry:]) != exifOrientationTag {
+			continue
+		}
+		if order.Uint16(tiff[entry+2:]) != exifTypeShort || order.Uint32(tiff[entry+4:]) != 1 {
+			return orientationNormal
+		}
+		if value := int(order.Uint16(tiff[entry+8:])); value >= 1 && value <= 8 {
+			return value
+		}
+		return orientationNormal
+	}
+	return orientationNormal
+}
+
+// minimalExif - TIFF-структура из одного тега Orientation: всё остальное из EXIF
+// (GPS, модель камеры, даты) при загрузке удаляется
+func minimalExif(orientation int) []byte {
+	tiff := make([]byte, 0, 26)
+	tiff = append(tiff, "MM\x00*"...)
+	tiff = binary.BigEndian.AppendUint32(tiff, 8)
+	tiff = binary.BigEndian.AppendUint16(tiff, 1)
+	tiff = binary.BigEndian.AppendUint16(tiff, exifOrientationTag)
+	tiff = binary.BigEndian.AppendUint16(tiff, exifTypeShort)
+	tiff = binary.BigEndian.AppendUint32(tiff, 1)
+	tiff = binary.BigEndian.AppendUint16(tiff, uint16(orientation))
+	tiff = append(tiff, 0, 0)
+	// Следующего IFD нет
+	return binary.BigEndian.AppendUint32(tiff, 0)
+}
+
+// swapsAxes сообщает, поворачивает ли ориентация изображение на 90 градусов
+func swapsAxes(orientation int) bool {
+	return orientation >= 5 && orientation <= 8
+}
+
+// orient поворачивает и отражает пиксели так, как изображение должно отображаться
+func orient(src *image.RGBA, orientation int) *image.RGBA {
+	if orientation <= orientationNormal || orientation > 8 {
+		return src
+	}
+
+	w, h := src.Rect.Dx(), src.Rect.Dy()
+	dstW, dstH := w, h
+	if swapsAxes(orientation) {
+		dstW, dstH = h, w
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+	for y := range dstH {
+		for x := range dstW {
+			var sx, sy int
+			switch orientation {
+			case 2: // отражение по горизонтали
+				sx, sy = w-1-x, y
+			case 3: // поворот на 180
+				sx, sy = w-1-x, h-1-y
+			case 4: // отражение по вертикали
+				sx, sy = x, h-1-y
+			case 5: // отражение относительно главной диагонали
+				sx, sy = y, x
+			case 6: // поворот на 90 по часовой
+				sx, sy = y, h-1-x
+			case 7: // отражение относительно побочной диагонали
+				sx, sy = w-1-y, h-1-x
+			case 8: // поворот на 90 против часовой
+				sx, sy = w-1-y, x
+			}
+			copy(dst.Pix[y*dst.Stride+x*4:y*dst.Stride+x*4+4], src.Pix[sy*src.Stride+sx*4:])
+		}
+	}
+	return dst
+}
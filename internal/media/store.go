@@ -0,0 +1,67 @@
+package media
+
+import (
+	"Clinic_backend/config"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// ErrNotFound - объекта с таким ключом нет в хранилище
+var ErrNotFound = errors.New("blob not found")
+
+// BlobStore - хранилище содержимого файлов. Ключ - хэш содержимого (content-addressed),
+// поэтому запись идемпотентна, а объекты никогда не изменяются.
+type BlobStore interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Exists(ctx context.Context, key string) (bool, error)
+	Delete(ctx context.Context, key string) error
+	// Ping проверяет доступность хранилища для readiness-пробы
+	Ping(ctx context.Context) error
+}
+
+// Ключ - путь из сегментов [a-z0-9._-], без ".." и ведущего "/"
+var keyPattern = regexp.MustCompile(`^[a-z0-9_-][a-z0-9._-]*(/[a-z0-9_-][a-z0-9._-]*)*$`)
+
+// PrivatePrefix - префикс ключей с медицинскими документами (бланки анализов). Такие
+// объекты отдаются только через API с проверкой доступа: их нельзя кэшировать в CDN
+// и прокси и открывать по прямой ссылке на бакет
+const PrivatePrefix = "lab/"
+
+// objectHeaders - заголовки, с которыми объект сохраняется в хранилище
+func objectHeaders(key, contentType string) map[string][]string {
+	if strings.HasPrefix(key, PrivatePrefix) {
+		return map[string][]string{
+			"Content-Type":  {contentType},
+			"Cache-Control": {"private, no-store"},
+			"X-Amz-Acl":     {"private"},
+		}
+	}
+	return map[string][]string{
+		"Content-Type":  {contentType},
+		"Cache-Control": {"public, max-age=31536000, immutable"},
+	}
+}
+
+func validateKey(key string) error {
+	if !keyPattern.MatchString(key) {
+		return fmt.Errorf("invalid blob key %q", key)
+	}
+	return nil
+}
+
+// NewStore создаёт хранилище, выбранное в конфигурации
+func NewStore(cfg config.MediaConfig) (BlobStore, error) {
+	switch cfg.Storage {
+	case "local":
+		return NewLocalStore(cfg.LocalDir)
+	case "s3":
+		return NewS3Store(cfg.S3)
+	default:
+		return nil, fmt.Errorf("unknown media storage: %s", cfg.Storage)
+	}
+}
@@ -0,0 +1,175 @@
+package media
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"math"
+	"net/http"
+
+	_ "golang.org/x/image/webp"
+)
+
+// Variant - уменьшенная копия изображения
+type Variant struct {
+	Width       int
+	Height      int
+	ContentType string
+	Data        []byte
+}
+
+// CanResize сообщает, умеет ли сервер декодировать изображения этого типа
+func CanResize(contentType string) bool {
+	return contentType == TypeJPEG || contentType == TypePNG || contentType == TypeWebP
+}
+
+// Resize создаёт копии указанных ширин с сохранением пропорций. Ширины не меньше
+// исходной пропускаются - изображения не увеличиваются. Непрозрачные изображения
+// кодируются в JPEG, изображения с прозрачностью - в PNG. Поворот из EXIF применяется
+// к пикселям: в копиях метаданных нет.
+func Resize(data []byte, widths []int, quality int) ([]Variant, error) {
+	_, orientation, err := strip(http.DetectContentType(data), data)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+	if cfg.Width*cfg.Height > maxPixels {
+		return nil, ErrTooManyPixels
+	}
+
+	displayWidth := cfg.Width
+	if swapsAxes(orientation) {
+		displayWidth = cfg.Height
+	}
+
+	var targets []int
+	for _, width := range widths {
+		if width < displayWidth {
+			targets = append(targets, width)
+		}
+	}
+	if len(targets) == 0 {
+		return nil, nil
+	}
+
+	decoded, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+
+	var src *image.RGBA
+	if format == "webp" {
+		src = webpRGBA(decoded)
+	}
+	if src == nil {
+		src = image.NewRGBA(image.Rect(0, 0, decoded.Bounds().Dx(), decoded.Bounds().Dy()))
+		draw.Draw(src, src.Rect, decoded, decoded.Bounds().Min, draw.Src)
+	}
+	src = orient(src, orientation)
+	opaque := src.Opaque()
+
+	variants := make([]Variant, 0, len(targets))
+	for _, width := range targets {
+		height := max(1, int(math.Round(float64(src.Rect.Dy())*float64(width)/float64(src.Rect.Dx()))))
+		scaled := scale(src, width, height)
+
+		var buf bytes.Buffer
+		contentType := TypeJPEG
+		if opaque {
+			err = jpeg.Encode(&buf, scaled, &jpeg.Options{Quality: quality})
+		} else {
+			contentType = TypePNG
+			err = png.Encode(&buf, scaled)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %dpx variant: %w", width, err)
+		}
+
+		variants = append(variants, Variant{Width: width, Height: height, ContentType: contentType, Data: buf.Bytes()})
+	}
+
+	return variants, nil
+}
+
+// scale уменьшает изображение усреднением по площади: пиксель результата - среднее
+// покрываемых им исходных пикселей с весами по доле перекрытия. Сначала по горизонтали,
+// затем по вертикали. Цвета в RGBA предумножены на альфу, поэтому прозрачные
+// пиксели не окрашивают соседние.
+func scale(src *image.RGBA, width, height int) *image.RGBA {
+	srcW, srcH := src.Rect.Dx(), src.Rect.Dy()
+
+	tmp := image.NewRGBA(image.Rect(0, 0, width, srcH))
+	columns := weights(srcW, width)
+	for y := range srcH {
+		row := src.Pix[y*src.Stride:]
+		out := tmp.Pix[y*tmp.Stride:]
+		for x, w := range columns {
+			var acc [4]float32
+			for k, weight := range w.weights {
+				p := row[(w.start+k)*4:]
+				acc[0] += weight * float32(p[0])
+				acc[1] += weight * float32(p[1])
+				acc[2] += weight * float32(p[2])
+				acc[3] += weight * float32(p[3])
+			}
+			store(out[x*4:], acc)
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y, w := range weights(srcH, height) {
+		out := dst.Pix[y*dst.Stride:]
+		for x := range width {
+			var acc [4]float32
+			for k, weight := range w.weights {
+				p := tmp.Pix[(w.start+k)*tmp.Stride+x*4:]
+				acc[0] += weight * float32(p[0])
+				acc[1] += weight * float32(p[1])
+				acc[2] += weight * float32(p[2])
+				acc[3] += weight * float32(p[3])
+			}
+			store(out[x*4:], acc)
+		}
+	}
+
+	return dst
+}
+
+type span struct {
+	start   int
+	weights []float32
+}
+
+// weights для каждого пикселя результата возвращает исходные пиксели, которые он
+// покрывает, и их веса; сумма весов равна 1
+func weights(srcSize, dstSize int) []span {
+	ratio := float64(srcSize) / float64(dstSize)
+
+	spans := make([]span, dstSize)
+	for i := range spans {
+		lo, hi := float64(i)*ratio, float64(i+1)*ratio
+		start := int(lo)
+		end := min(int(math.Ceil(hi)), srcSize)
+
+		w := make([]float32, end-start)
+		for j := start; j < end; j++ {
+			overlap := math.Min(hi, float64(j+1)) - math.Max(lo, float64(j))
+			w[j-start] = float32(overlap / ratio)
+		}
+		spans[i] = span{start: start, weights: w}
+	}
+	return spans
+}
+
+func store(p []byte, acc [4]float32) {
+	for c := range acc {
+		p[c] = uint8(min(255, max(0, acc[c]+0.5)))
+	}
+}
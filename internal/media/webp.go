@@ -0,0 +1,61 @@
+package media
+
+import "image"
+
+// webpRGBA переводит результат декодера WebP в RGBA. Декодер отдаёт YCbCr
+// ограниченного диапазона, а стандартное преобразование Go рассчитано на полный
+// диапазон JPEG - без этого уменьшенные копии WebP выходят блёклыми.
+func webpRGBA(m image.Image) *image.RGBA {
+	var (
+		ycbcr  *image.YCbCr
+		alpha  []byte
+		stride int
+	)
+	switch m := m.(type) {
+	case *image.YCbCr:
+		ycbcr = m
+	case *image.NYCbCrA:
+		ycbcr, alpha, stride = &m.YCbCr, m.A, m.AStride
+	default:
+		return nil
+	}
+
+	b := ycbcr.Rect
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			yy := int32(ycbcr.Y[ycbcr.YOffset(x, y)])
+			ci := ycbcr.COffset(x, y)
+			cb, cr := int32(ycbcr.Cb[ci]), int32(ycbcr.Cr[ci])
+
+			// Формулы libwebp с 14-битной точностью
+			luma := yy * 19077 >> 8
+			r := webpClip(luma + cr*26149>>8 - 14234)
+			g := webpClip(luma - cb*6419>>8 - cr*13320>>8 + 8708)
+			bl := webpClip(luma + cb*33050>>8 - 17685)
+
+			p := dst.Pix[(y-b.Min.Y)*dst.Stride+(x-b.Min.X)*4:]
+			p[0], p[1], p[2], p[3] = r, g, bl, 255
+			if alpha != nil {
+				// RGBA хранит цвет, предумноженный на альфу
+				a := uint32(alpha[(y-b.Min.Y)*stride+(x-b.Min.X)])
+				p[0] = uint8(uint32(r) * a / 255)
+				p[1] = uint8(uint32(g) * a / 255)
+				p[2] = uint8(uint32(bl) * a / 255)
+				p[3] = uint8(a)
+			}
+		}
+	}
+	return dst
+}
+
+func webpClip(v int32) uint8 {
+	v >>= 6
+	if v < 0 {
+		return 0
+	}
+	if v > 255 {
+		return 255
+	}
+	return uint8(v)
+}
@@ -0,0 +1,45 @@
+package media
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"testing"
+)
+
+// halvesWebP - WebP с потерями 64x32: левая половина красная, правая синяя
+const halvesWebP = "UklGRmAAAABXRUJQVlA4IFQAAACwAQCdASpAACAAAIAMAAAeRPi4DJTzAP77sCv//vPn//c+f/9z5/0Z///1dF//1dF//1dF/6ui/XaL///Wjn//rRz//1o5/60c//+Ur/43f7st4AA="
+
+func TestResizeWebPSource(t *testing.T) {
+	data, err := base64.StdEncoding.DecodeString(halvesWebP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !CanResize(TypeWebP) {
+		t.Fatal("CanResize(image/webp) = false")
+	}
+
+	variants, err := Resize(data, []int{16}, 90)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(variants) != 1 || variants[0].ContentType != TypeJPEG {
+		t.Fatalf("variants %+v, want a single JPEG copy", variants)
+	}
+
+	v := variants[0]
+	if v.Width != 16 || v.Height != 8 {
+		t.Errorf("copy is %dx%d, want 16x8", v.Width, v.Height)
+	}
+	decoded, _, err := image.Decode(bytes.NewReader(v.Data))
+	if err != nil {
+		t.Fatalf("copy does not decode: %v", err)
+	}
+	// Ограниченный диапазон YCbCr переведён верно: красный остаётся насыщенным
+	left := color.RGBAModel.Convert(decoded.At(3, 4)).(color.RGBA)
+	right := color.RGBAModel.Convert(decoded.At(12, 4)).(color.RGBA)
+	if left.R < 235 || left.B > 30 || right.B < 235 || right.R > 30 {
+		t.Errorf("left %v, right %v; want saturated red and blue", left, right)
+	}
+}
@@ -0,0 +1,84 @@
+package metrics
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	HTTPRequestsTotal = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "Total number of HTTP requests by route template and status.",
+	}, []string{"method", "route", "status"})
+	HTTPRequestDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency by route template.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route"})
+	DBQueryDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "db_query_duration_seconds",
+		Help:    "Database query latency by repository method.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"repository", "method"})
+	RegistrationsTotal = factory.NewCounter(prometheus.CounterOpts{
+		Name: "auth_registrations_total",
+		Help: "Total number of successful user registrations.",
+	})
+	LoginsTotal = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_logins_total",
+		Help: "Total number of login attempts by result.",
+	}, []string{"result"})
+)
+
+var (
+	poolStatsOnce sync.Once
+	statsPool     atomic.Pointer[pgxpool.Pool]
+)
+
+// RegisterPoolStats публикует статистику пула соединений, снимаемую в момент сбора.
+// Коллектор регистрируется один раз; повторный вызов (например, при повторной сборке
+// роутера в тестах) только подменяет пул, иначе Registry вернул бы ошибку дубликата.
+func RegisterPoolStats(pool *pgxpool.Pool) {
+	statsPool.Store(pool)
+	poolStatsOnce.Do(func() {
+		Registry.MustRegister(poolCollector{})
+	})
+}
+
+var (
+	poolAcquiredDesc = prometheus.NewDesc("db_pool_acquired_connections", "Connections currently acquired from the pool.", nil, nil)
+	poolIdleDesc     = prometheus.NewDesc("db_pool_idle_connections", "Idle connections in the pool.", nil, nil)
+	poolTotalDesc    = prometheus.NewDesc("db_pool_total_connections", "Total connections in the pool.", nil, nil)
+	poolMaxDesc      = prometheus.NewDesc("db_pool_max_connections", "Maximum size of the pool.", nil, nil)
+	poolEmptyDesc    = prometheus.NewDesc("db_pool_empty_acquire_total", "Acquires that had to wait for a connection.", nil, nil)
+	poolWaitDesc     = prometheus.NewDesc("db_pool_acquire_wait_seconds_total", "Total time spent acquiring connections.", nil, nil)
+)
+
+// poolCollector снимает pgxpool.Stat один раз на сбор, чтобы значения были согласованы
+type poolCollector struct{}
+
+func (poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- poolAcquiredDesc
+	ch <- poolIdleDesc
+	ch <- poolTotalDesc
+	ch <- poolMaxDesc
+	ch <- poolEmptyDesc
+	ch <- poolWaitDesc
+}
+
+func (poolCollector) Collect(ch chan<- prometheus.Metric) {
+	pool := statsPool.Load()
+	if pool == nil {
+		return
+	}
+	s := pool.Stat()
+	ch <- prometheus.MustNewConstMetric(poolAcquiredDesc, prometheus.GaugeValue, float64(s.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(poolIdleDesc, prometheus.GaugeValue, float64(s.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(poolTotalDesc, prometheus.GaugeValue, float64(s.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(poolMaxDesc, prometheus.GaugeValue, float64(s.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(poolEmptyDesc, prometheus.CounterValue, float64(s.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(poolWaitDesc, prometheus.CounterValue, s.AcquireDuration().Seconds())
+}
@@ -2,6 +2,8 @@ package middleware
 
 import (
 	"Clinic_backend/config"
+	"Clinic_backend/internal/entity"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -10,51 +12,94 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func AuthMiddleware(cfg *config.Config) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
-			c.Abort()
-			return
-		}
+var (
+	errAuthHeaderRequired = errors.New("Authorization header required")
+	errInvalidAuthFormat  = errors.New("Invalid authorization format")
+	errInvalidToken       = errors.New("Invalid or expired token")
+	errInvalidClaims      = errors.New("Invalid token claims")
+)
 
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization format"})
-			c.Abort()
-			return
+// tokenClaims - данные пользователя из JWT
+type tokenClaims struct {
+	UserID int
+	Email  string
+	Role   string
+}
+
+// parseToken проверяет заголовок Authorization вида "Bearer <token>" и подпись токена.
+// Текст ошибки можно отдавать клиенту.
+func parseToken(cfg *config.Holder, header string) (*tokenClaims, error) {
+	if header == "" {
+		return nil, errAuthHeaderRequired
+	}
+
+	parts := strings.Split(header, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		return nil, errInvalidAuthFormat
+	}
+
+	token, err := jwt.Parse(parts[1], func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
+		return []byte(cfg.Get().Auth.JWTSecret), nil
+	})
+	if err != nil || !token.Valid {
+		return nil, errInvalidToken
+	}
 
-		tokenString := parts[1]
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			fmt.Println("token: ", token)
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-			}
-			return []byte(cfg.Env.JWTSecret), nil
-		})
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, errInvalidClaims
+	}
+	userID, okID := claims["user_id"].(float64)
+	email, okEmail := claims["email"].(string)
+	role, okRole := claims["role"].(string)
+	if !okID || !okEmail || !okRole {
+		return nil, errInvalidClaims
+	}
+
+	return &tokenClaims{UserID: int(userID), Email: email, Role: role}, nil
+}
 
-		if err != nil || !token.Valid {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+func AuthMiddleware(cfg *config.Holder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, err := parseToken(cfg, c.GetHeader("Authorization"))
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 			c.Abort()
 			return
 		}
+		setClaims(c, claims)
 
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
-			c.Abort()
-			return
+		// Автор изменения для журнала аудита
+		c.Request = c.Request.WithContext(entity.ContextWithActor(c.Request.Context(), entity.Actor{
+			UserID:    &claims.UserID,
+			IP:        c.ClientIP(),
+			RequestID: c.GetString("request_id"),
+		}))
+		c.Next()
+	}
+}
+
+// OptionalAuthMiddleware разбирает токен, если он передан, но не требует авторизации.
+// Используется на публичных маршрутах, поведение которых зависит от роли.
+func OptionalAuthMiddleware(cfg *config.Holder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if claims, err := parseToken(cfg, c.GetHeader("Authorization")); err == nil {
+			setClaims(c, claims)
 		}
-		fmt.Println("claims: ", claims)
-		c.Set("user_id", int(claims["user_id"].(float64)))
-		c.Set("email", claims["email"].(string))
-		c.Set("role", claims["role"].(string))
 		c.Next()
 	}
 }
 
+func setClaims(c *gin.Context, claims *tokenClaims) {
+	c.Set("user_id", claims.UserID)
+	c.Set("email", claims.Email)
+	c.Set("role", claims.Role)
+	setUserLogger(c, claims.UserID, claims.Role)
+}
+
 func RoleMiddleware(allowedRoles ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		role, exists := c.Get("role")
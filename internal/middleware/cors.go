@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"Clinic_backend/config"
+	"slices"
+	"sync/atomic"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+)
+
+// CORSMiddleware применяет список разрешённых источников из текущей конфигурации.
+// При перезагрузке конфигурации обработчик cors пересобирается.
+func CORSMiddleware(cfg *config.Holder) gin.HandlerFunc {
+	var handler atomic.Pointer[gin.HandlerFunc]
+	build := func(c *config.Config) {
+		h := cors.New(corsConfig(c.CORS.AllowedOrigins))
+		handler.Store(&h)
+	}
+	build(cfg.Get())
+	cfg.OnReload(build)
+
+	return func(c *gin.Context) {
+		(*handler.Load())(c)
+	}
+}
+
+func corsConfig(origins []string) cors.Config {
+	corsConfig := cors.DefaultConfig()
+	if slices.Contains(origins, "*") {
+		// Браузеры не принимают credentials вместе с "*"
+		corsConfig.AllowAllOrigins = true
+	} else {
+		corsConfig.AllowOrigins = origins
+		corsConfig.AllowCredentials = true
+	}
+	corsConfig.AllowMethods = []string{"GET", "POST", "PUT", "DELETE", "PATCH", "OPTIONS"}
+	corsConfig.AllowHeaders = []string{"Origin", "Content-Type", "Authorization", "If-Match", "If-None-Match", "traceparent", "X-Request-ID"}
+	corsConfig.ExposeHeaders = []string{"ETag", "X-Request-ID", "Retry-After", "X-RateLimit-Limit", "X-RateLimit-Remaining"}
+	return corsConfig
+}
@@ -1,7 +1,7 @@
 package middleware
 
 import (
-	"log/slog"
+	"Clinic_backend/internal/logging"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -14,11 +14,11 @@ func LoggerMiddleware() gin.HandlerFunc {
 		// Обработка запроса
 		c.Next()
 
-		// Логирование после обработки
+		// Логирование после обработки. Логгер берётся из контекста запроса,
+		// т.к. после авторизации он дополнен user_id и role
 		duration := time.Since(start)
 
-		slog.Info("HTTP Request",
-			"method", c.Request.Method,
+		logging.FromContext(c.Request.Context()).Info("HTTP Request",
 			"path", c.Request.URL.Path,
 			"status", c.Writer.Status(),
 			"duration_ms", duration.Milliseconds(),
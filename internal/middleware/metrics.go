@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"Clinic_backend/config"
+	"Clinic_backend/internal/metrics"
+	"crypto/subtle"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MetricsMiddleware считает запросы и их длительность по шаблону маршрута,
+// чтобы path-параметры не порождали отдельные ряды
+func MetricsMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		metrics.HTTPRequestsTotal.WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status())).Inc()
+		metrics.HTTPRequestDuration.WithLabelValues(c.Request.Method, route).Observe(time.Since(start).Seconds())
+	}
+}
+
+// MetricsAuthMiddleware ограничивает доступ к /metrics списком адресов и/или basic-auth.
+// Если ни то ни другое не настроено, endpoint открыт.
+func MetricsAuthMiddleware(cfg *config.Holder) gin.HandlerFunc {
+	var allowedNets atomic.Pointer[[]*net.IPNet]
+	parse := func(c *config.Config) {
+		nets := parseAllowedNets(c.Metrics.AllowedIPs)
+		allowedNets.Store(&nets)
+	}
+	parse(cfg.Get())
+	cfg.OnReload(parse)
+
+	return func(c *gin.Context) {
+		metricsCfg := cfg.Get().Metrics
+		username := metricsCfg.Username
+		password := metricsCfg.Password
+
+		allowed := *allowedNets.Load()
+		if len(allowed) > 0 && !ipAllowed(allowed, c.ClientIP()) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+			c.Abort()
+			return
+		}
+
+		if username != "" {
+			user, pass, ok := c.Request.BasicAuth()
+			if !ok ||
+				subtle.ConstantTimeCompare([]byte(user), []byte(username)) != 1 ||
+				subtle.ConstantTimeCompare([]byte(pass), []byte(password)) != 1 {
+				c.Header("WWW-Authenticate", `Basic realm="metrics"`)
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
+				c.Abort()
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// parseAllowedNets принимает как CIDR, так и одиночные адреса
+func parseAllowedNets(entries []string) []*net.IPNet {
+	var nets []*net.IPNet
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		if !strings.Contains(entry, "/") {
+			if ip := net.ParseIP(entry); ip != nil && ip.To4() != nil {
+				entry += "/32"
+			} else {
+				entry += "/128"
+			}
+		}
+
+		_, ipNet, err := net.ParseCIDR(entry)
+		if err != nil {
+			slog.Warn("Ignoring invalid metrics allowlist entry", "entry", entry, "error", err.Error())
+			continue
+		}
+		nets = append(nets, ipNet)
+	}
+	return nets
+}
+
+func ipAllowed(nets []*net.IPNet, addr string) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	for _, ipNet := range nets {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"Clinic_backend/internal/logging"
+	"crypto/rand"
+	"fmt"
+	"log/slog"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	RequestIDHeader = "X-Request-ID"
+	maxRequestIDLen = 128
+)
+
+// RequestIDMiddleware принимает X-Request-ID от клиента или генерирует новый,
+// возвращает его в ответе и кладёт в контекст логгер запроса
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = newRequestID()
+		}
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		c.Set("request_id", requestID)
+		c.Header(RequestIDHeader, requestID)
+
+		ctx := c.Request.Context()
+		trace.SpanFromContext(ctx).SetAttributes(attribute.String("http.request_id", requestID))
+
+		logger := logging.FromContext(ctx).With(
+			slog.String("request_id", requestID),
+			slog.String("method", c.Request.Method),
+			slog.String("route", route),
+		)
+		c.Request = c.Request.WithContext(logging.WithLogger(ctx, logger))
+
+		c.Next()
+	}
+}
+
+// setUserLogger дополняет логгер запроса данными авторизованного пользователя
+func setUserLogger(c *gin.Context, userID int, role string) {
+	ctx := c.Request.Context()
+	logger := logging.FromContext(ctx).With(slog.Int("user_id", userID), slog.String("role", role))
+	c.Request = c.Request.WithContext(logging.WithLogger(ctx, logger))
+}
+
+// validRequestID отсекает слишком длинные значения и символы, ломающие логи
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID генерирует UUID v4
+func newRequestID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
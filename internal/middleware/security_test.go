@@ -0,0 +1,170 @@
+package middleware
+
+import (
+	"Clinic_backend/config"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newTestConfig(modify func(*config.Config)) *config.Holder {
+	cfg := config.Default()
+	cfg.Features.RateLimit = true
+	if modify != nil {
+		modify(cfg)
+	}
+	return config.NewHolder("", cfg)
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cfg := newTestConfig(func(c *config.Config) {
+		c.Security.RateLimits = map[string]string{"default": "60:1", "auth": "60:2"}
+	})
+	store, err := NewRateLimitStore(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		if user := c.GetHeader("X-Test-User"); user != "" {
+			c.Set("user_id", len(user))
+		}
+	})
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	r.GET("/doctors", RateLimitMiddleware(cfg, store, "doctors"), ok)
+	r.GET("/services", RateLimitMiddleware(cfg, store, "services"), ok)
+	r.GET("/auth", RateLimitMiddleware(cfg, store, "auth"), ok)
+
+	// Запросы выполняются по порядку в рамках одной секунды
+	tests := []struct {
+		name       string
+		path       string
+		ip         string
+		user       string
+		status     int
+		retryAfter string
+	}{
+		{name: "first request", path: "/doctors", ip: "10.0.0.1", status: http.StatusOK},
+		{name: "burst exhausted", path: "/doctors", ip: "10.0.0.1", status: http.StatusTooManyRequests, retryAfter: "1"},
+		{name: "default policy is shared across groups", path: "/services", ip: "10.0.0.1", status: http.StatusTooManyRequests, retryAfter: "1"},
+		{name: "own policy has its own budget", path: "/auth", ip: "10.0.0.1", status: http.StatusOK},
+		{name: "other IP has its own budget", path: "/doctors", ip: "10.0.0.2", status: http.StatusOK},
+		{name: "user is counted per IP", path: "/doctors", ip: "10.0.0.3", user: "u", status: http.StatusOK},
+		{name: "same user from another IP", path: "/doctors", ip: "10.0.0.4", user: "u", status: http.StatusOK},
+		{name: "same user and IP", path: "/doctors", ip: "10.0.0.4", user: "u", status: http.StatusTooManyRequests, retryAfter: "1"},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		req.RemoteAddr = tt.ip + ":1234"
+		if tt.user != "" {
+			req.Header.Set("X-Test-User", tt.user)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != tt.status {
+			t.Errorf("%s: status %d, want %d", tt.name, w.Code, tt.status)
+		}
+		if got := w.Header().Get("Retry-After"); got != tt.retryAfter {
+			t.Errorf("%s: Retry-After %q, want %q", tt.name, got, tt.retryAfter)
+		}
+		if w.Header().Get("X-RateLimit-Limit") != "60" {
+			t.Errorf("%s: X-RateLimit-Limit %q, want 60", tt.name, w.Header().Get("X-RateLimit-Limit"))
+		}
+	}
+}
+
+func TestCORSPreflight(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(CORSMiddleware(newTestConfig(func(c *config.Config) {
+		c.CORS.AllowedOrigins = []string{"https://clinic.example"}
+	})))
+	r.PUT("/doctors/1", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	// Неразрешённый заголовок отклоняет браузер: его просто нет в Allow-Headers
+	tests := []struct {
+		name         string
+		origin       string
+		headers      string
+		status       int
+		allowOrigin  string
+		allowHeader  string
+		deniedHeader string
+	}{
+		{name: "allowed origin", origin: "https://clinic.example", headers: "Authorization, If-Match", status: http.StatusNoContent, allowOrigin: "https://clinic.example", allowHeader: "If-Match"},
+		{name: "unknown origin", origin: "https://evil.example", headers: "Authorization", status: http.StatusForbidden},
+		{name: "header not allowed", origin: "https://clinic.example", headers: "X-Custom", status: http.StatusNoContent, allowOrigin: "https://clinic.example", deniedHeader: "X-Custom"},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodOptions, "/doctors/1", nil)
+		req.Header.Set("Origin", tt.origin)
+		req.Header.Set("Access-Control-Request-Method", http.MethodPut)
+		req.Header.Set("Access-Control-Request-Headers", tt.headers)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != tt.status {
+			t.Errorf("%s: status %d, want %d", tt.name, w.Code, tt.status)
+		}
+		if got := w.Header().Get("Access-Control-Allow-Origin"); got != tt.allowOrigin {
+			t.Errorf("%s: Access-Control-Allow-Origin %q, want %q", tt.name, got, tt.allowOrigin)
+		}
+		allowed := strings.ToLower(w.Header().Get("Access-Control-Allow-Headers"))
+		if tt.allowHeader != "" && !strings.Contains(allowed, strings.ToLower(tt.allowHeader)) {
+			t.Errorf("%s: Access-Control-Allow-Headers %q lacks %s", tt.name, allowed, tt.allowHeader)
+		}
+		if tt.deniedHeader != "" && strings.Contains(allowed, strings.ToLower(tt.deniedHeader)) {
+			t.Errorf("%s: Access-Control-Allow-Headers %q allows %s", tt.name, allowed, tt.deniedHeader)
+		}
+	}
+}
+
+func TestSecurityHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name        string
+		environment string
+		path        string
+		csp         string
+		hsts        string
+	}{
+		{name: "api", environment: "development", path: "/doctors", csp: apiCSP},
+		{name: "swagger", environment: "development", path: "/swagger/index.html", csp: swaggerCSP},
+		{name: "production", environment: "production", path: "/doctors", csp: apiCSP, hsts: "max-age=31536000; includeSubDomains"},
+	}
+
+	for _, tt := range tests {
+		r := gin.New()
+		r.Use(SecurityHeadersMiddleware(newTestConfig(func(c *config.Config) {
+			c.Environment = tt.environment
+		})))
+		r.GET("/*path", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+		want := map[string]string{
+			"X-Content-Type-Options":    "nosniff",
+			"X-Frame-Options":           "DENY",
+			"Referrer-Policy":           "no-referrer",
+			"Content-Security-Policy":   tt.csp,
+			"Strict-Transport-Security": tt.hsts,
+		}
+		for header, value := range want {
+			if got := w.Header().Get(header); got != value {
+				t.Errorf("%s: %s = %q, want %q", tt.name, header, got, value)
+			}
+		}
+	}
+}
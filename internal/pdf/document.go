@@ -0,0 +1,283 @@
+// Package pdf формирует простые печатные документы (рецепты, результаты анализов):
+// текст с переносом по словам, выравнивание, горизонтальные линии и разрывы страниц.
+//
+// По умолчанию используется встроенный шрифт Helvetica, который не содержит кириллицы:
+// русский текст транслитерируется. Для печати кириллицей нужен TrueType-шрифт
+// (например, DejaVu Sans или PT Sans), он встраивается в документ.
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// Размер страницы A4 и поля в пунктах
+const (
+	pageWidth  = 595.28
+	pageHeight = 841.89
+	margin     = 50.0
+)
+
+type Align int
+
+const (
+	AlignLeft Align = iota
+	AlignCenter
+	AlignRight
+)
+
+// Style - оформление абзаца. Size - кегль в пунктах
+type Style struct {
+	Size  float64
+	Bold  bool
+	Align Align
+}
+
+// Document - документ A4, заполняемый сверху вниз. Новая страница начинается
+// автоматически, когда очередная строка не помещается на текущей.
+type Document struct {
+	font  font
+	title string
+	pages []*bytes.Buffer
+	// Расстояние от верхнего края страницы до следующей строки
+	y float64
+}
+
+// New создаёт документ. Без TrueType-шрифта используется Helvetica
+func New(ttf *TrueTypeFont) *Document {
+	d := &Document{font: helvetica{}}
+	if ttf != nil {
+		d.font = newEmbeddedFont(ttf)
+	}
+	d.newPage()
+	return d
+}
+
+// SetTitle задаёт заголовок документа, который показывают программы просмотра
+func (d *Document) SetTitle(title string) {
+	d.title = title
+}
+
+// Text выводит абзац с переносом по словам. Переводы строк в тексте сохраняются
+func (d *Document) Text(text string, style Style) {
+	if style.Size <= 0 {
+		style.Size = 11
+	}
+	lineHeight := style.Size * 1.35
+
+	for _, paragraph := range strings.Split(text, "\n") {
+		for _, line := range d.wrap(paragraph, style) {
+			d.ensureSpace(lineHeight)
+			d.y += style.Size
+
+			x := margin
+			switch style.Align {
+			case AlignCenter:
+				x = (pageWidth - d.width(line, style)) / 2
+			case AlignRight:
+				x = pageWidth - margin - d.width(line, style)
+			}
+
+			// Режим отрисовки входит в графическое состояние и сохраняется после ET,
+			// поэтому задаётся для каждой строки. У встроенного TrueType-шрифта нет
+			// начертания bold: контур обводится поверх заливки
+			render := "0 Tr"
+			if style.Bold && d.font.fakeBold() {
+				render = "2 Tr " + num(style.Size*0.03) + " w"
+			}
+			fmt.Fprintf(d.page(), "BT %s %s %s Tf %s %s Td %s Tj ET\n",
+				render, d.font.resource(style.Bold), num(style.Size), num(x), num(pageHeight-d.y), d.font.encode(line))
+
+			d.y += lineHeight - style.Size
+		}
+	}
+}
+
+// Space добавляет вертикальный отступ
+func (d *Document) Space(height float64) {
+	d.y += height
+	if d.y > pageHeight-margin {
+		d.newPage()
+	}
+}
+
+// Rule проводит горизонтальную линию во всю ширину текста
+func (d *Document) Rule() {
+	d.ensureSpace(6)
+	d.y += 3
+	fmt.Fprintf(d.page(), "0.5 w %s %s m %s %s l S\n",
+		num(margin), num(pageHeight-d.y), num(pageWidth-margin), num(pageHeight-d.y))
+	d.y += 3
+}
+
+// Bytes собирает документ
+func (d *Document) Bytes() ([]byte, error) {
+	w := &writer{}
+	w.buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	catalog := w.reserve()
+	pagesRef := w.reserve()
+
+	fonts, err := d.font.write(w)
+	if err != nil {
+		return nil, err
+	}
+
+	kids := make([]string, 0, len(d.pages))
+	for _, content := range d.pages {
+		contentRef, err := w.stream(content.Bytes(), "")
+		if err != nil {
+			return nil, err
+		}
+		page := w.object(fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %s %s] /Resources << /Font << %s >> >> /Contents %d 0 R >>",
+			pagesRef, num(pageWidth), num(pageHeight), fonts, contentRef))
+		kids = append(kids, fmt.Sprintf("%d 0 R", page))
+	}
+
+	w.define(pagesRef, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(kids)))
+	w.define(catalog, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pagesRef))
+
+	info := 0
+	if d.title != "" {
+		info = w.object(fmt.Sprintf("<< /Title %s /Producer (Clinic backend) >>", textString(d.title)))
+	}
+
+	return w.finish(catalog, info), nil
+}
+
+func (d *Document) page() *bytes.Buffer {
+	return d.pages[len(d.pages)-1]
+}
+
+func (d *Document) newPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+	d.y = margin
+}
+
+// ensureSpace начинает новую страницу, если блок высотой height не помещается на текущей
+func (d *Document) ensureSpace(height float64) {
+	if d.y+height > pageHeight-margin && d.y > margin {
+		d.newPage()
+	}
+}
+
+func (d *Document) width(text string, style Style) float64 {
+	return d.font.width(text, style.Bold) * style.Size / 1000
+}
+
+// wrap разбивает абзац на строки, помещающиеся по ширине. Слово длиннее строки
+// переносится по символам
+func (d *Document) wrap(paragraph string, style Style) []string {
+	maxWidth := pageWidth - 2*margin
+	words := strings.Fields(paragraph)
+	if len(words) == 0 {
+		return []string{""}
+	}
+
+	var lines []string
+	line := ""
+	for _, word := range words {
+		candidate := word
+		if line != "" {
+			candidate = line + " " + word
+		}
+		if d.width(candidate, style) <= maxWidth {
+			line = candidate
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+		line = word
+		for d.width(line, style) > maxWidth {
+			runes := []rune(line)
+			cut := len(runes) - 1
+			for cut > 1 && d.width(string(runes[:cut]), style) > maxWidth {
+				cut--
+			}
+			lines = append(lines, string(runes[:cut]))
+			line = string(runes[cut:])
+		}
+	}
+	return append(lines, line)
+}
+
+// writer последовательно записывает объекты PDF и запоминает их смещения для таблицы xref
+type writer struct {
+	buf     bytes.Buffer
+	offsets []int
+}
+
+// reserve выделяет номер объекта, который будет записан позже
+func (w *writer) reserve() int {
+	w.offsets = append(w.offsets, 0)
+	return len(w.offsets)
+}
+
+func (w *writer) define(ref int, body string) {
+	w.offsets[ref-1] = w.buf.Len()
+	fmt.Fprintf(&w.buf, "%d 0 obj\n%s\nendobj\n", ref, body)
+}
+
+func (w *writer) object(body string) int {
+	ref := w.reserve()
+	w.define(ref, body)
+	return ref
+}
+
+// stream записывает сжатый поток; extra - дополнительные ключи словаря потока
+func (w *writer) stream(data []byte, extra string) (int, error) {
+	var compressed bytes.Buffer
+	zw := zlib.NewWriter(&compressed)
+	if _, err := zw.Write(data); err != nil {
+		return 0, fmt.Errorf("failed to compress pdf stream: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return 0, fmt.Errorf("failed to compress pdf stream: %w", err)
+	}
+
+	ref := w.reserve()
+	w.offsets[ref-1] = w.buf.Len()
+	fmt.Fprintf(&w.buf, "%d 0 obj\n<< /Length %d /Filter /FlateDecode%s >>\nstream\n", ref, compressed.Len(), extra)
+	w.buf.Write(compressed.Bytes())
+	w.buf.WriteString("\nendstream\nendobj\n")
+	return ref, nil
+}
+
+func (w *writer) finish(catalog, info int) []byte {
+	xref := w.buf.Len()
+	fmt.Fprintf(&w.buf, "xref\n0 %d\n0000000000 65535 f \n", len(w.offsets)+1)
+	for _, offset := range w.offsets {
+		fmt.Fprintf(&w.buf, "%010d 00000 n \n", offset)
+	}
+
+	trailer := fmt.Sprintf("/Size %d /Root %d 0 R", len(w.offsets)+1, catalog)
+	if info != 0 {
+		trailer += fmt.Sprintf(" /Info %d 0 R", info)
+	}
+	fmt.Fprintf(&w.buf, "trailer\n<< %s >>\nstartxref\n%d\n%%%%EOF\n", trailer, xref)
+	return w.buf.Bytes()
+}
+
+// num форматирует число с точностью до сотых: PDF не принимает экспоненциальную запись
+func num(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
+
+// textString кодирует строку метаданных в UTF-16BE с меткой порядка байтов
+func textString(s string) string {
+	return "<FEFF" + utf16Hex(s) + ">"
+}
+
+func utf16Hex(s string) string {
+	var b strings.Builder
+	for _, c := range utf16.Encode([]rune(s)) {
+		fmt.Fprintf(&b, "%04X", c)
+	}
+	return b.String()
+}
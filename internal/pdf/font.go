@@ -0,0 +1,147 @@
+package pdf
+
+import (
+	"fmt"
+	"strings"
+)
+
+// font - шрифт документа: кодирование строк для оператора Tj, ширина строки
+// в тысячных долях кегля и запись словарей шрифта
+type font interface {
+	resource(bold bool) string
+	encode(text string) string
+	width(text string, bold bool) float64
+	// fakeBold - жирное начертание имитируется обводкой
+	fakeBold() bool
+	// write записывает объекты шрифтов и возвращает содержимое словаря /Font ресурсов страницы
+	write(w *writer) (string, error)
+}
+
+// helvetica - стандартный шрифт PDF, не требующий встраивания. Кодировка WinAnsi
+// совпадает с Latin-1 в диапазоне 0xA0-0xFF; кириллица транслитерируется
+type helvetica struct{}
+
+func (helvetica) resource(bold bool) string {
+	if bold {
+		return "/F2"
+	}
+	return "/F1"
+}
+
+func (helvetica) fakeBold() bool {
+	return false
+}
+
+func (helvetica) encode(text string) string {
+	var b strings.Builder
+	b.WriteByte('(')
+	for _, c := range winAnsi(text) {
+		switch c {
+		case '(', ')', '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		default:
+			if c < 0x20 || c > 0x7E {
+				fmt.Fprintf(&b, "\\%03o", c)
+			} else {
+				b.WriteByte(c)
+			}
+		}
+	}
+	b.WriteByte(')')
+	return b.String()
+}
+
+func (helvetica) width(text string, bold bool) float64 {
+	widths := &helveticaWidths
+	if bold {
+		widths = &helveticaBoldWidths
+	}
+
+	var total float64
+	for _, c := range winAnsi(text) {
+		if c >= 0x20 && c <= 0x7E {
+			total += float64(widths[c-0x20])
+		} else {
+			total += 556
+		}
+	}
+	return total
+}
+
+func (helvetica) write(w *writer) (string, error) {
+	regular := w.object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	bold := w.object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	return fmt.Sprintf("/F1 %d 0 R /F2 %d 0 R", regular, bold), nil
+}
+
+// winAnsi переводит текст в однобайтовую кодировку: кириллица транслитерируется,
+// остальные символы вне Latin-1 заменяются на "?"
+func winAnsi(text string) []byte {
+	out := make([]byte, 0, len(text))
+	runes := []rune(text)
+	for i, r := range runes {
+		switch {
+		case r < 0x80 || (r >= 0xA0 && r <= 0xFF):
+			out = append(out, byte(r))
+		case r == '№':
+			out = append(out, "No."...)
+		case r == '—' || r == '–':
+			out = append(out, '-')
+		case r == '«' || r == '»':
+			out = append(out, '"')
+		default:
+			if latin, ok := transliteration[r]; ok {
+				out = append(out, latin...)
+			} else if latin, ok := transliteration[toLowerCyrillic(r)]; ok && latin != "" {
+				// В словах из заглавных букв ("РЕЦЕПТ") заглавными остаются все буквы сочетания
+				if i+1 < len(runes) && toLowerCyrillic(runes[i+1]) != runes[i+1] {
+					out = append(out, strings.ToUpper(latin)...)
+				} else {
+					out = append(out, strings.ToUpper(latin[:1])+latin[1:]...)
+				}
+			} else if !ok {
+				out = append(out, '?')
+			}
+		}
+	}
+	return out
+}
+
+func toLowerCyrillic(r rune) rune {
+	switch {
+	case r >= 'А' && r <= 'Я':
+		return r + ('а' - 'А')
+	case r == 'Ё':
+		return 'ё'
+	}
+	return r
+}
+
+// transliteration - упрощённая транслитерация строчных букв (как в загранпаспортах)
+var transliteration = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "i", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "ie", 'ы': "y", 'ь': "", 'э': "e", 'ю': "iu",
+	'я': "ia",
+}
+
+// Ширины символов 0x20-0x7E из метрик AFM стандартных шрифтов
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+var helveticaBoldWidths = [95]int{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+	975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+	333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+	611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+}
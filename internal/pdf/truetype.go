@@ -0,0 +1,345 @@
+package pdf
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// ErrUnsupportedFont - файл не является шрифтом TrueType с контурами glyf
+var ErrUnsupportedFont = errors.New("unsupported font: a TrueType (.ttf) font is required")
+
+// TrueTypeFont - разобранный шрифт TrueType. Шрифт встраивается в документ целиком,
+// символы кодируются номерами глифов (Identity-H)
+type TrueTypeFont struct {
+	data       []byte
+	name       string
+	unitsPerEm int
+	ascent     int
+	descent    int
+	bbox       [4]int
+	glyphs     map[rune]uint16
+	advances   []uint16
+}
+
+// LoadTrueType читает шрифт из файла
+func LoadTrueType(path string) (*TrueTypeFont, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read font: %w", err)
+	}
+	font, err := ParseTrueType(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return font, nil
+}
+
+// ParseTrueType разбирает таблицы шрифта, нужные для вывода текста: метрики,
+// ширины глифов и соответствие символов глифам
+func ParseTrueType(data []byte) (*TrueTypeFont, error) {
+	r := &reader{data: data}
+	if version := r.u32(0); version != 0x00010000 && version != 0x74727565 { // 'true'
+		return nil, ErrUnsupportedFont
+	}
+
+	tables := make(map[string][]byte)
+	numTables := int(r.u16(4))
+	for i := range numTables {
+		entry := 12 + 16*i
+		tag := string(r.bytes(entry, 4))
+		offset, length := int(r.u32(entry+8)), int(r.u32(entry+12))
+		if r.err != nil || offset+length > len(data) {
+			return nil, errMalformedFont
+		}
+		tables[tag] = data[offset : offset+length]
+	}
+	for _, tag := range []string{"head", "hhea", "hmtx", "maxp", "cmap", "glyf"} {
+		if tables[tag] == nil {
+			return nil, fmt.Errorf("%w: missing %s table", ErrUnsupportedFont, tag)
+		}
+	}
+
+	head := &reader{data: tables["head"]}
+	hhea := &reader{data: tables["hhea"]}
+	maxp := &reader{data: tables["maxp"]}
+	font := &TrueTypeFont{
+		data:       data,
+		name:       postScriptName(tables["name"]),
+		unitsPerEm: int(head.u16(18)),
+		ascent:     int(int16(hhea.u16(4))),
+		descent:    int(int16(hhea.u16(6))),
+		bbox: [4]int{
+			int(int16(head.u16(36))), int(int16(head.u16(38))),
+			int(int16(head.u16(40))), int(int16(head.u16(42))),
+		},
+	}
+	numGlyphs := int(maxp.u16(4))
+	numHMetrics := int(hhea.u16(34))
+	if err := errors.Join(head.err, hhea.err, maxp.err); err != nil || font.unitsPerEm == 0 || numHMetrics == 0 {
+		return nil, errMalformedFont
+	}
+
+	hmtx := &reader{data: tables["hmtx"]}
+	font.advances = make([]uint16, max(numGlyphs, numHMetrics))
+	for i := range font.advances {
+		if i < numHMetrics {
+			font.advances[i] = hmtx.u16(4 * i)
+		} else {
+			font.advances[i] = font.advances[numHMetrics-1]
+		}
+	}
+	if hmtx.err != nil {
+		return nil, errMalformedFont
+	}
+
+	glyphs, err := parseCmap(tables["cmap"])
+	if err != nil {
+		return nil, err
+	}
+	font.glyphs = glyphs
+
+	return font, nil
+}
+
+var errMalformedFont = fmt.Errorf("%w: malformed font file", ErrUnsupportedFont)
+
+// parseCmap читает таблицу символов Unicode: формат 12 (все плоскости) или 4 (BMP)
+func parseCmap(data []byte) (map[rune]uint16, error) {
+	r := &reader{data: data}
+	best, bestFormat := -1, 0
+	for i := range int(r.u16(2)) {
+		platform, encoding := r.u16(4+8*i), r.u16(4+8*i+2)
+		offset := int(r.u32(4 + 8*i + 4))
+		unicode := platform == 0 || (platform == 3 && (encoding == 1 || encoding == 10))
+		if !unicode {
+			continue
+		}
+		format := int(r.u16(offset))
+		if (format == 12 || format == 4) && format > bestFormat {
+			best, bestFormat = offset, format
+		}
+	}
+	if r.err != nil {
+		return nil, errMalformedFont
+	}
+	if best < 0 {
+		return nil, fmt.Errorf("%w: no Unicode character map", ErrUnsupportedFont)
+	}
+
+	glyphs := make(map[rune]uint16)
+	if bestFormat == 12 {
+		groups := int(r.u32(best + 12))
+		for i := range groups {
+			group := best + 16 + 12*i
+			start, end, glyph := r.u32(group), r.u32(group+4), r.u32(group+8)
+			if r.err != nil || end < start || end > 0x10FFFF {
+				return nil, errMalformedFont
+			}
+			for c := start; c <= end; c++ {
+				glyphs[rune(c)] = uint16(glyph + c - start)
+			}
+		}
+		return glyphs, nil
+	}
+
+	segments := int(r.u16(best+6)) / 2
+	ends := best + 14
+	starts := ends + 2*segments + 2
+	deltas := starts + 2*segments
+	rangeOffsets := deltas + 2*segments
+	for i := range segments {
+		start, end := int(r.u16(starts+2*i)), int(r.u16(ends+2*i))
+		delta := int(r.u16(deltas + 2*i))
+		rangeOffset := int(r.u16(rangeOffsets + 2*i))
+		for c := start; c <= end && c != 0xFFFF; c++ {
+			glyph := 0
+			if rangeOffset == 0 {
+				glyph = (c + delta) & 0xFFFF
+			} else if g := int(r.u16(rangeOffsets + 2*i + rangeOffset + 2*(c-start))); g != 0 {
+				glyph = (g + delta) & 0xFFFF
+			}
+			if glyph != 0 {
+				glyphs[rune(c)] = uint16(glyph)
+			}
+		}
+		if r.err != nil {
+			return nil, errMalformedFont
+		}
+	}
+	return glyphs, nil
+}
+
+// postScriptName - имя шрифта из таблицы name (запись 6); пробелы в имени PDF недопустимы
+func postScriptName(data []byte) string {
+	r := &reader{data: data}
+	count, storage := int(r.u16(2)), int(r.u16(4))
+	for i := range count {
+		record := 6 + 12*i
+		platform, nameID := r.u16(record), r.u16(record+6)
+		length, offset := int(r.u16(record+8)), int(r.u16(record+10))
+		if nameID != 6 || r.err != nil {
+			continue
+		}
+		raw := r.bytes(storage+offset, length)
+		if r.err != nil {
+			break
+		}
+		var name string
+		if platform == 1 {
+			name = string(raw)
+		} else {
+			runes := make([]rune, 0, len(raw)/2)
+			for j := 0; j+1 < len(raw); j += 2 {
+				runes = append(runes, rune(binary.BigEndian.Uint16(raw[j:])))
+			}
+			name = string(runes)
+		}
+		name = strings.Map(func(r rune) rune {
+			if r <= ' ' || r > '~' || strings.ContainsRune("()<>[]{}/%#", r) {
+				return -1
+			}
+			return r
+		}, name)
+		if name != "" {
+			return name
+		}
+	}
+	return "EmbeddedFont"
+}
+
+// reader читает числа big-endian; выход за границы запоминается в err, а не вызывает панику
+type reader struct {
+	data []byte
+	err  error
+}
+
+func (r *reader) bytes(offset, n int) []byte {
+	if offset < 0 || n < 0 || offset+n > len(r.data) {
+		r.err = errMalformedFont
+		return make([]byte, n)
+	}
+	return r.data[offset : offset+n]
+}
+
+func (r *reader) u16(offset int) uint16 {
+	return binary.BigEndian.Uint16(r.bytes(offset, 2))
+}
+
+func (r *reader) u32(offset int) uint32 {
+	return binary.BigEndian.Uint32(r.bytes(offset, 4))
+}
+
+// embeddedFont - TrueType-шрифт в конкретном документе: запоминает использованные
+// глифы для таблицы ширин и обратного соответствия (поиск и копирование текста)
+type embeddedFont struct {
+	ttf  *TrueTypeFont
+	used map[uint16]rune
+}
+
+func newEmbeddedFont(ttf *TrueTypeFont) *embeddedFont {
+	return &embeddedFont{ttf: ttf, used: make(map[uint16]rune)}
+}
+
+func (f *embeddedFont) resource(bool) string {
+	return "/F1"
+}
+
+func (f *embeddedFont) fakeBold() bool {
+	return true
+}
+
+func (f *embeddedFont) encode(text string) string {
+	var b strings.Builder
+	b.WriteByte('<')
+	for _, r := range text {
+		glyph := f.ttf.glyphs[r]
+		if glyph != 0 {
+			f.used[glyph] = r
+		}
+		fmt.Fprintf(&b, "%04X", glyph)
+	}
+	b.WriteByte('>')
+	return b.String()
+}
+
+func (f *embeddedFont) width(text string, _ bool) float64 {
+	var total float64
+	for _, r := range text {
+		total += f.advance(f.ttf.glyphs[r])
+	}
+	return total
+}
+
+// advance - ширина глифа в тысячных долях кегля
+func (f *embeddedFont) advance(glyph uint16) float64 {
+	if int(glyph) >= len(f.ttf.advances) {
+		return 0
+	}
+	return float64(f.ttf.advances[glyph]) * 1000 / float64(f.ttf.unitsPerEm)
+}
+
+func (f *embeddedFont) write(w *writer) (string, error) {
+	ttf := f.ttf
+	scale := func(v int) string {
+		return num(float64(v) * 1000 / float64(ttf.unitsPerEm))
+	}
+
+	file, err := w.stream(ttf.data, fmt.Sprintf(" /Length1 %d", len(ttf.data)))
+	if err != nil {
+		return "", err
+	}
+	descriptor := w.object(fmt.Sprintf(
+		"<< /Type /FontDescriptor /FontName /%s /Flags 32 /FontBBox [%s %s %s %s] /ItalicAngle 0 /Ascent %s /Descent %s /CapHeight %s /StemV 80 /FontFile2 %d 0 R >>",
+		ttf.name, scale(ttf.bbox[0]), scale(ttf.bbox[1]), scale(ttf.bbox[2]), scale(ttf.bbox[3]),
+		scale(ttf.ascent), scale(ttf.descent), scale(ttf.ascent), file))
+
+	glyphs := make([]int, 0, len(f.used))
+	for glyph := range f.used {
+		glyphs = append(glyphs, int(glyph))
+	}
+	sort.Ints(glyphs)
+
+	var widths strings.Builder
+	for _, glyph := range glyphs {
+		fmt.Fprintf(&widths, "%d [%s] ", glyph, num(f.advance(uint16(glyph))))
+	}
+	cidFont := w.object(fmt.Sprintf(
+		"<< /Type /Font /Subtype /CIDFontType2 /BaseFont /%s /CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> /FontDescriptor %d 0 R /DW %s /W [%s] /CIDToGIDMap /Identity >>",
+		ttf.name, descriptor, num(f.advance(0)), widths.String()))
+
+	toUnicode, err := w.stream([]byte(f.toUnicode(glyphs)), "")
+	if err != nil {
+		return "", err
+	}
+	font := w.object(fmt.Sprintf(
+		"<< /Type /Font /Subtype /Type0 /BaseFont /%s /Encoding /Identity-H /DescendantFonts [%d 0 R] /ToUnicode %d 0 R >>",
+		ttf.name, cidFont, toUnicode))
+
+	return fmt.Sprintf("/F1 %d 0 R", font), nil
+}
+
+// toUnicode строит CMap обратного соответствия глифов символам
+func (f *embeddedFont) toUnicode(glyphs []int) string {
+	var b strings.Builder
+	b.WriteString("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n")
+	b.WriteString("/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n")
+	b.WriteString("/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n")
+	b.WriteString("1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+
+	// В одном блоке bfchar допускается не больше 100 записей
+	for start := 0; start < len(glyphs); start += 100 {
+		chunk := glyphs[start:min(start+100, len(glyphs))]
+		fmt.Fprintf(&b, "%d beginbfchar\n", len(chunk))
+		for _, glyph := range chunk {
+			fmt.Fprintf(&b, "<%04X> <%s>\n", glyph, utf16Hex(string(f.used[uint16(glyph)])))
+		}
+		b.WriteString("endbfchar\n")
+	}
+
+	b.WriteString("endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend\n")
+	return b.String()
+}
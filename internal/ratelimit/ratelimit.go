@@ -0,0 +1,191 @@
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultGroup - имя лимита для групп маршрутов без собственной настройки
+const DefaultGroup = "default"
+
+const (
+	idleTTL       = 10 * time.Minute
+	sweepInterval = time.Minute
+)
+
+// Spec - лимит в запросах в минуту с допустимым всплеском
+type Spec struct {
+	PerMinute float64
+	Burst     int
+}
+
+// ParseSpec разбирает лимит вида "600:100" (запросов в минуту : всплеск)
+func ParseSpec(value string) (Spec, error) {
+	rate, burst, ok := strings.Cut(strings.TrimSpace(value), ":")
+	if !ok {
+		return Spec{}, fmt.Errorf("invalid rate limit %q, expected <per-minute>:<burst>", value)
+	}
+
+	perMinute, err := strconv.ParseFloat(rate, 64)
+	if err != nil || perMinute <= 0 {
+		return Spec{}, fmt.Errorf("invalid rate limit %q: rate must be a positive number", value)
+	}
+	b, err := strconv.Atoi(burst)
+	if err != nil || b < 1 {
+		return Spec{}, fmt.Errorf("invalid rate limit %q: burst must be a positive integer", value)
+	}
+
+	return Spec{PerMinute: perMinute, Burst: b}, nil
+}
+
+// ParseLimits разбирает лимиты групп маршрутов. Лимит DefaultGroup обязателен.
+func ParseLimits(values map[string]string) (map[string]Spec, error) {
+	limits := make(map[string]Spec, len(values))
+	for group, value := range values {
+		spec, err := ParseSpec(value)
+		if err != nil {
+			return nil, fmt.Errorf("group %s: %w", group, err)
+		}
+		limits[group] = spec
+	}
+
+	if _, ok := limits[DefaultGroup]; !ok {
+		return nil, fmt.Errorf("rate limit for %q group is required", DefaultGroup)
+	}
+	return limits, nil
+}
+
+// Limiter - набор token bucket по ключу (IP или пользователь)
+type Limiter struct {
+	now func() time.Time
+
+	mu        sync.Mutex
+	spec      Spec
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func NewLimiter(spec Spec) *Limiter {
+	return &Limiter{
+		spec:    spec,
+		now:     time.Now,
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// SetSpec меняет лимит на лету. Накопленные токены сохраняются,
+// но не превышают новый всплеск.
+func (l *Limiter) SetSpec(spec Spec) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.spec = spec
+}
+
+// Result - решение по запросу и данные для заголовков X-RateLimit-*
+type Result struct {
+	Allowed bool
+	// Настроенное число запросов в минуту
+	Limit int
+	// Запросов, которые можно сделать сразу, не превышая всплеск
+	Remaining  int
+	RetryAfter time.Duration
+}
+
+// Allow расходует один токен ключа, если он есть
+func (l *Limiter) Allow(key string) Result {
+	now := l.now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	perSecond := l.spec.PerMinute / 60
+	limit := int(math.Ceil(l.spec.PerMinute))
+
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(l.spec.Burst), last: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens = math.Min(float64(l.spec.Burst), b.tokens+now.Sub(b.last).Seconds()*perSecond)
+	b.last = now
+
+	if b.tokens < 1 {
+		wait := time.Duration((1 - b.tokens) / perSecond * float64(time.Second))
+		return Result{Limit: limit, RetryAfter: wait}
+	}
+
+	b.tokens--
+	return Result{Allowed: true, Limit: limit, Remaining: int(b.tokens)}
+}
+
+// sweep удаляет давно неиспользуемые ключи, чтобы память не росла с числом клиентов
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+
+	for key, b := range l.buckets {
+		if now.Sub(b.last) > idleTTL {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// Store хранит по одному Limiter на политику. Группы маршрутов с одной политикой,
+// в том числе все группы без собственной настройки (DefaultGroup), расходуют общий
+// запас токенов: иначе каждая группа добавляла бы клиенту ещё один лимит.
+type Store struct {
+	mu       sync.Mutex
+	limits   map[string]Spec
+	limiters map[string]*Limiter
+}
+
+func NewStore(limits map[string]Spec) *Store {
+	return &Store{limits: limits, limiters: make(map[string]*Limiter)}
+}
+
+// SetLimits применяет новые лимиты. Накопленные токены сохраняются; счётчики
+// удалённых политик сбрасываются, их группы переходят на DefaultGroup.
+func (s *Store) SetLimits(limits map[string]Spec) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.limits = limits
+	for policy, limiter := range s.limiters {
+		if spec, ok := limits[policy]; ok {
+			limiter.SetSpec(spec)
+		} else {
+			delete(s.limiters, policy)
+		}
+	}
+}
+
+// For возвращает Limiter политики группы: собственной или DefaultGroup
+func (s *Store) For(group string) *Limiter {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	policy := group
+	if _, ok := s.limits[policy]; !ok {
+		policy = DefaultGroup
+	}
+	limiter, ok := s.limiters[policy]
+	if !ok {
+		limiter = NewLimiter(s.limits[policy])
+		s.limiters[policy] = limiter
+	}
+	return limiter
+}
@@ -0,0 +1,94 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLimiterRefill(t *testing.T) {
+	// 60 в минуту - один токен в секунду, всплеск 2
+	spec := Spec{PerMinute: 60, Burst: 2}
+
+	tests := []struct {
+		name       string
+		elapsed    []time.Duration // пауза перед каждым запросом
+		allowed    []bool
+		retryAfter time.Duration // у последнего отклонённого запроса
+	}{
+		{
+			name:       "burst then reject",
+			elapsed:    []time.Duration{0, 0, 0},
+			allowed:    []bool{true, true, false},
+			retryAfter: time.Second,
+		},
+		{
+			name:    "one token per second",
+			elapsed: []time.Duration{0, 0, time.Second},
+			allowed: []bool{true, true, true},
+		},
+		{
+			name:       "partial refill shortens the wait",
+			elapsed:    []time.Duration{0, 0, 400 * time.Millisecond},
+			allowed:    []bool{true, true, false},
+			retryAfter: 600 * time.Millisecond,
+		},
+		{
+			name:    "refill is capped by burst",
+			elapsed: []time.Duration{0, time.Hour, 0, 0},
+			allowed: []bool{true, true, true, false},
+			// Через час накоплено только 2 токена
+			retryAfter: time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Unix(0, 0)
+			l := NewLimiter(spec)
+			l.now = func() time.Time { return now }
+
+			var last Result
+			for i, pause := range tt.elapsed {
+				now = now.Add(pause)
+				last = l.Allow("ip:1")
+				if last.Allowed != tt.allowed[i] {
+					t.Fatalf("request %d: allowed=%v, want %v", i+1, last.Allowed, tt.allowed[i])
+				}
+			}
+			if !last.Allowed && (last.RetryAfter-tt.retryAfter).Abs() > time.Millisecond {
+				t.Errorf("RetryAfter = %v, want %v", last.RetryAfter, tt.retryAfter)
+			}
+		})
+	}
+}
+
+func TestStoreSharesLimiterPerPolicy(t *testing.T) {
+	store := NewStore(map[string]Spec{
+		DefaultGroup: {PerMinute: 600, Burst: 100},
+		"emr":        {PerMinute: 60, Burst: 1},
+	})
+
+	if store.For("emr") != store.For("emr") {
+		t.Error("groups with the same policy must share a limiter")
+	}
+	if store.For("doctors") != store.For("services") || store.For("doctors") != store.For(DefaultGroup) {
+		t.Error("groups without a policy must share the default limiter")
+	}
+	if store.For("emr") == store.For("doctors") {
+		t.Error("different policies must not share a limiter")
+	}
+
+	emr := store.For("emr")
+	store.SetLimits(map[string]Spec{
+		DefaultGroup: {PerMinute: 600, Burst: 100},
+		"emr":        {PerMinute: 120, Burst: 5},
+	})
+	if store.For("emr") != emr || store.For("emr").Allow("ip:1").Limit != 120 {
+		t.Error("reload must keep the limiter and apply the new spec")
+	}
+
+	store.SetLimits(map[string]Spec{DefaultGroup: {PerMinute: 600, Burst: 100}})
+	if store.For("emr") != store.For(DefaultGroup) {
+		t.Error("a removed policy must fall back to the default limiter")
+	}
+}
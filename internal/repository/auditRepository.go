@@ -0,0 +1,215 @@
+package repository
+
+import (
+	"Clinic_backend/internal/entity"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// auditChainLockID - ключ advisory-блокировки, упорядочивающей добавление записей в цепочку хэшей
+const auditChainLockID = 7_300_030
+
+type AuditRepositoryInterface interface {
+	LockChain(ctx context.Context) error
+	GetLastHash(ctx context.Context) (string, error)
+	Create(ctx context.Context, entry *entity.AuditLog) error
+	List(ctx context.Context, filter *entity.AuditLogFilter) ([]entity.AuditLog, int, error)
+	Iterate(ctx context.Context, fn func(entry *entity.AuditLog) error) error
+}
+
+type AuditRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewAuditRepository(db *pgxpool.Pool) AuditRepositoryInterface {
+	return &AuditRepository{db: db}
+}
+
+// LockChain берёт транзакционную блокировку цепочки; вызывать только внутри транзакции
+func (r *AuditRepository) LockChain(ctx context.Context) error {
+	_, err := getQuerier(ctx, r.db).Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, auditChainLockID)
+	if err != nil {
+		return fmt.Errorf("failed to lock audit chain: %w", err)
+	}
+	return nil
+}
+
+func (r *AuditRepository) GetLastHash(ctx context.Context) (string, error) {
+	query := `SELECT hash FROM audit_log ORDER BY id DESC LIMIT 1`
+
+	var hash string
+	err := getQuerier(ctx, r.db).QueryRow(ctx, query).Scan(&hash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get last audit hash: %w", err)
+	}
+
+	return hash, nil
+}
+
+func (r *AuditRepository) Create(ctx context.Context, entry *entity.AuditLog) error {
+	query := `
+		INSERT INTO audit_log (actor_id, ip, entity_type, entity_id, action, before, after, request_id, prev_hash, hash, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id
+	`
+
+	err := getQuerier(ctx, r.db).QueryRow(ctx, query,
+		entry.ActorID,
+		entry.IP,
+		entry.EntityType,
+		entry.EntityID,
+		entry.Action,
+		nullableJSON(entry.Before),
+		nullableJSON(entry.After),
+		entry.RequestID,
+		entry.PrevHash,
+		entry.Hash,
+		entry.CreatedAt,
+	).Scan(&entry.ID)
+
+	if err != nil {
+		return fmt.Errorf("failed to create audit log entry: %w", err)
+	}
+
+	return nil
+}
+
+func (r *AuditRepository) List(ctx context.Context, filter *entity.AuditLogFilter) ([]entity.AuditLog, int, error) {
+	where, args := auditFilterClause(filter)
+
+	var total int
+	countQuery := `SELECT count(*) FROM audit_log` + where
+	if err := getQuerier(ctx, r.db).QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count audit log: %w", err)
+	}
+
+	query := `
+		SELECT id, actor_id, ip, entity_type, entity_id, action, before, after, request_id, prev_hash, hash, created_at
+		FROM audit_log` + where + `
+		ORDER BY id DESC`
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit, (filter.Page-1)*filter.Limit)
+		query += fmt.Sprintf(` LIMIT $%d OFFSET $%d`, len(args)-1, len(args))
+	}
+
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query audit log: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []entity.AuditLog
+	for rows.Next() {
+		var entry entity.AuditLog
+		if err := scanAuditLog(rows, &entry); err != nil {
+			return nil, 0, err
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return entries, total, nil
+}
+
+// Iterate обходит весь журнал в порядке добавления, не загружая его в память целиком
+func (r *AuditRepository) Iterate(ctx context.Context, fn func(entry *entity.AuditLog) error) error {
+	query := `
+		SELECT id, actor_id, ip, entity_type, entity_id, action, before, after, request_id, prev_hash, hash, created_at
+		FROM audit_log
+		ORDER BY id
+	`
+
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to query audit log: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry entity.AuditLog
+		if err := scanAuditLog(rows, &entry); err != nil {
+			return err
+		}
+		if err := fn(&entry); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func scanAuditLog(rows pgx.Rows, entry *entity.AuditLog) error {
+	var before, after []byte
+	err := rows.Scan(
+		&entry.ID,
+		&entry.ActorID,
+		&entry.IP,
+		&entry.EntityType,
+		&entry.EntityID,
+		&entry.Action,
+		&before,
+		&after,
+		&entry.RequestID,
+		&entry.PrevHash,
+		&entry.Hash,
+		&entry.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to scan audit log entry: %w", err)
+	}
+	entry.Before = before
+	entry.After = after
+	return nil
+}
+
+func auditFilterClause(filter *entity.AuditLogFilter) (string, []any) {
+	var conditions []string
+	var args []any
+
+	add := func(condition string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.ActorID != nil {
+		add("actor_id = $%d", *filter.ActorID)
+	}
+	if filter.EntityType != "" {
+		add("entity_type = $%d", filter.EntityType)
+	}
+	if filter.EntityID != nil {
+		add("entity_id = $%d", *filter.EntityID)
+	}
+	if filter.Action != "" {
+		add("action = $%d", filter.Action)
+	}
+	if filter.From != nil {
+		add("created_at >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		add("created_at < $%d", *filter.To)
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+func nullableJSON(data []byte) any {
+	if len(data) == 0 {
+		return nil
+	}
+	return string(data)
+}
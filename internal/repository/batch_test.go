@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// countingTx подставляется в контекст вместо транзакции и считает запросы к базе.
+// Query отвечает строками, которые собирает rows по аргументам запроса
+type countingTx struct {
+	pgx.Tx
+	queries int
+	rows    func(args []any) [][]any
+}
+
+func (tx *countingTx) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	tx.queries++
+	return pgconn.CommandTag{}, nil
+}
+
+func (tx *countingTx) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	tx.queries++
+	return &fakeRows{rows: tx.rows(args)}, nil
+}
+
+func (tx *countingTx) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	tx.queries++
+	return &fakeRows{rows: tx.rows(args)}
+}
+
+type fakeRows struct {
+	pgx.Rows
+	rows [][]any
+	pos  int
+}
+
+func (r *fakeRows) Next() bool {
+	r.pos++
+	return r.pos <= len(r.rows)
+}
+
+func (r *fakeRows) Scan(dest ...any) error {
+	if r.pos == 0 {
+		r.pos = 1
+	}
+	if r.pos > len(r.rows) {
+		return pgx.ErrNoRows
+	}
+	row := r.rows[r.pos-1]
+	if len(row) != len(dest) {
+		return fmt.Errorf("scan: %d columns, %d destinations", len(row), len(dest))
+	}
+	for i, value := range row {
+		reflect.ValueOf(dest[i]).Elem().Set(reflect.ValueOf(value))
+	}
+	return nil
+}
+
+func (r *fakeRows) Err() error { return nil }
+
+func (r *fakeRows) Close() {}
+
+func withCountingTx(rows func(args []any) [][]any) (context.Context, *countingTx) {
+	tx := &countingTx{rows: rows}
+	return context.WithValue(context.Background(), txKey{}, tx), tx
+}
+
+func sequence(n int) []int {
+	ids := make([]int, n)
+	for i := range ids {
+		ids[i] = i + 1
+	}
+	return ids
+}
+
+// specializationRows - по две специализации на каждого запрошенного врача
+func specializationRows(args []any) [][]any {
+	now := time.Now()
+	var rows [][]any
+	for _, doctorID := range args[0].([]int) {
+		for spec := 1; spec <= 2; spec++ {
+			rows = append(rows, []any{doctorID, spec, fmt.Sprintf("spec-%d", spec), now, now, 1})
+		}
+	}
+	return rows
+}
+
+func scheduleRows(args []any) [][]any {
+	var rows [][]any
+	for _, id := range args[0].([]int) {
+		rows = append(rows, []any{id, id%7 + 1, "09:00", "18:00"})
+	}
+	return rows
+}
+
+var batchSizes = []int{1, 10, 100, 1000}
+
+func BenchmarkGetSpecializationsForDoctors(b *testing.B) {
+	repo := &DoctorRepository{}
+	for _, n := range batchSizes {
+		b.Run(fmt.Sprintf("doctors=%d", n), func(b *testing.B) {
+			ctx, tx := withCountingTx(specializationRows)
+			ids := sequence(n)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				result, err := repo.GetSpecializationsForDoctors(ctx, ids)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if len(result) != n {
+					b.Fatalf("got specializations for %d doctors, want %d", len(result), n)
+				}
+			}
+			b.StopTimer()
+
+			if tx.queries != b.N {
+				b.Fatalf("%d queries for %d calls, want one query per call", tx.queries, b.N)
+			}
+			b.ReportMetric(float64(tx.queries)/float64(b.N), "queries/op")
+		})
+	}
+}
+
+func BenchmarkScheduleGetByIDs(b *testing.B) {
+	repo := &ScheduleRepository{}
+	for _, n := range batchSizes {
+		b.Run(fmt.Sprintf("schedules=%d", n), func(b *testing.B) {
+			ctx, tx := withCountingTx(scheduleRows)
+			ids := sequence(n)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				result, err := repo.GetByIDs(ctx, ids)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if len(result) != n {
+					b.Fatalf("got %d schedules, want %d", len(result), n)
+				}
+			}
+			b.StopTimer()
+
+			if tx.queries != b.N {
+				b.Fatalf("%d queries for %d calls, want one query per call", tx.queries, b.N)
+			}
+			b.ReportMetric(float64(tx.queries)/float64(b.N), "queries/op")
+		})
+	}
+}
+
+// TestBatchLoadersQueryOnce - то же, что и бенчмарки, но выполняется в обычном go test
+func TestBatchLoadersQueryOnce(t *testing.T) {
+	for _, n := range batchSizes {
+		ctx, tx := withCountingTx(specializationRows)
+		if _, err := (&DoctorRepository{}).GetSpecializationsForDoctors(ctx, sequence(n)); err != nil {
+			t.Fatal(err)
+		}
+		if tx.queries != 1 {
+			t.Errorf("GetSpecializationsForDoctors(%d doctors): %d queries, want 1", n, tx.queries)
+		}
+
+		ctx, tx = withCountingTx(scheduleRows)
+		schedules, err := (&ScheduleRepository{}).GetByIDs(ctx, sequence(n))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tx.queries != 1 {
+			t.Errorf("ScheduleRepository.GetByIDs(%d schedules): %d queries, want 1", n, tx.queries)
+		}
+		for id, schedule := range schedules {
+			if schedule.ID != id {
+				t.Fatalf("GetByIDs: schedule under key %d has ID %d", id, schedule.ID)
+			}
+		}
+	}
+
+	ctx, tx := withCountingTx(scheduleRows)
+	if _, err := (&ScheduleRepository{}).GetByIDs(ctx, nil); err != nil {
+		t.Fatal(err)
+	}
+	if tx.queries != 0 {
+		t.Errorf("GetByIDs(nil): %d queries, want 0", tx.queries)
+	}
+}
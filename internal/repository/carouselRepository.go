@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -12,10 +13,12 @@ import (
 
 type CarouselRepositoryInterface interface {
 	Create(ctx context.Context, course *entity.Carousel) (*entity.Carousel, error)
-	GetAll(ctx context.Context) ([]entity.Carousel, error)
+	GetAll(ctx context.Context, includeDeleted bool) ([]entity.Carousel, error)
 	GetByID(ctx context.Context, id int) (*entity.Carousel, error)
 	Update(ctx context.Context, id int, carousel *entity.Carousel) (*entity.Carousel, error)
-	Delete(ctx context.Context, id int) error
+	Delete(ctx context.Context, id int, version int) error
+	Restore(ctx context.Context, id int) error
+	Purge(ctx context.Context, before time.Time) (int64, error)
 }
 
 type CarouselRepository struct {
@@ -28,15 +31,15 @@ func NewCarouselRepository(db *pgxpool.Pool) CarouselRepositoryInterface {
 
 func (r *CarouselRepository) Create(ctx context.Context, carousel *entity.Carousel) (*entity.Carousel, error) {
 	query := `
-		INSERT INTO main_carusel (image, header, description)
-		VALUES ($1, $2, $3)
-		RETURNING id, image, header, description, created_at, updated_at
+		INSERT INTO main_carusel (image, image_id, header, description)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, image, image_id, header, description, created_at, updated_at, version
 	`
 
 	var created entity.Carousel
-	err := r.db.QueryRow(ctx, query, carousel.Image, carousel.Header, carousel.Description).Scan(
-		&created.ID, &created.Image, &created.Header, &created.Description,
-		&created.CreatedAt, &created.UpdatedAt,
+	err := getQuerier(ctx, r.db).QueryRow(ctx, query, carousel.Image, carousel.ImageID, carousel.Header, carousel.Description).Scan(
+		&created.ID, &created.Image, &created.ImageID, &created.Header, &created.Description,
+		&created.CreatedAt, &created.UpdatedAt, &created.Version,
 	)
 
 	if err != nil {
@@ -46,14 +49,15 @@ func (r *CarouselRepository) Create(ctx context.Context, carousel *entity.Carous
 	return &created, nil
 }
 
-func (r *CarouselRepository) GetAll(ctx context.Context) ([]entity.Carousel, error) {
+func (r *CarouselRepository) GetAll(ctx context.Context, includeDeleted bool) ([]entity.Carousel, error) {
 	query := `
-		SELECT id, image, header, description, created_at, updated_at 
+		SELECT id, image, image_id, header, description, created_at, updated_at, version, deleted_at
 		FROM main_carusel 
+		WHERE $1 OR deleted_at IS NULL
 		ORDER BY id
 	`
 
-	rows, err := r.db.Query(ctx, query)
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query, includeDeleted)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query carousel: %w", err)
 	}
@@ -63,8 +67,8 @@ func (r *CarouselRepository) GetAll(ctx context.Context) ([]entity.Carousel, err
 	for rows.Next() {
 		var carousel entity.Carousel
 		err := rows.Scan(
-			&carousel.ID, &carousel.Image, &carousel.Header, &carousel.Description,
-			&carousel.CreatedAt, &carousel.UpdatedAt,
+			&carousel.ID, &carousel.Image, &carousel.ImageID, &carousel.Header, &carousel.Description,
+			&carousel.CreatedAt, &carousel.UpdatedAt, &carousel.Version, &carousel.DeletedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan carousel: %w", err)
@@ -77,15 +81,15 @@ func (r *CarouselRepository) GetAll(ctx context.Context) ([]entity.Carousel, err
 
 func (r *CarouselRepository) GetByID(ctx context.Context, id int) (*entity.Carousel, error) {
 	query := `
-		SELECT id, image, header, description, created_at, updated_at 
+		SELECT id, image, image_id, header, description, created_at, updated_at, version 
 		FROM main_carusel 
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 
 	var carousel entity.Carousel
-	err := r.db.QueryRow(ctx, query, id).Scan(
-		&carousel.ID, &carousel.Image, &carousel.Header, &carousel.Description,
-		&carousel.CreatedAt, &carousel.UpdatedAt,
+	err := getQuerier(ctx, r.db).QueryRow(ctx, query, id).Scan(
+		&carousel.ID, &carousel.Image, &carousel.ImageID, &carousel.Header, &carousel.Description,
+		&carousel.CreatedAt, &carousel.UpdatedAt, &carousel.Version,
 	)
 
 	if err != nil {
@@ -101,31 +105,63 @@ func (r *CarouselRepository) GetByID(ctx context.Context, id int) (*entity.Carou
 func (r *CarouselRepository) Update(ctx context.Context, id int, carousel *entity.Carousel) (*entity.Carousel, error) {
 	query := `
 		UPDATE main_carusel
-		SET image = $1, header = $2, description = $3, updated_at = CURRENT_TIMESTAMP
-		WHERE id = $4
-		RETURNING id, image, header, description, created_at, updated_at
+		SET image = $1, image_id = $2, header = $3, description = $4, updated_at = CURRENT_TIMESTAMP, version = version + 1
+		WHERE id = $5 AND deleted_at IS NULL AND $6 IN (0, version)
+		RETURNING id, image, image_id, header, description, created_at, updated_at, version
 	`
 
 	var updated entity.Carousel
-	err := r.db.QueryRow(ctx, query,
-		carousel.Image, carousel.Header, carousel.Description, id,
+	err := getQuerier(ctx, r.db).QueryRow(ctx, query,
+		carousel.Image, carousel.ImageID, carousel.Header, carousel.Description, id, carousel.Version,
 	).Scan(
-		&updated.ID, &updated.Image, &updated.Header, &updated.Description,
-		&updated.CreatedAt, &updated.UpdatedAt,
+		&updated.ID, &updated.Image, &updated.ImageID, &updated.Header, &updated.Description,
+		&updated.CreatedAt, &updated.UpdatedAt, &updated.Version,
 	)
 
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, resolveNoRows(ctx, getQuerier(ctx, r.db), "main_carusel", id, errors.New("carousel not found"))
+		}
 		return nil, fmt.Errorf("failed to update carousel: %w", err)
 	}
 
 	return &updated, nil
 }
 
-func (r *CarouselRepository) Delete(ctx context.Context, id int) error {
-	query := `DELETE FROM main_carusel WHERE id = $1`
-	_, err := r.db.Exec(ctx, query, id)
+func (r *CarouselRepository) Delete(ctx context.Context, id int, version int) error {
+	query := `
+		UPDATE main_carusel
+		SET deleted_at = CURRENT_TIMESTAMP, version = version + 1
+		WHERE id = $1 AND deleted_at IS NULL AND $2 IN (0, version)
+	`
+	tag, err := getQuerier(ctx, r.db).Exec(ctx, query, id, version)
 	if err != nil {
 		return fmt.Errorf("failed to delete carousel: %w", err)
 	}
+	if tag.RowsAffected() == 0 {
+		return resolveNoRows(ctx, getQuerier(ctx, r.db), "main_carusel", id, errors.New("carousel not found"))
+	}
+	return nil
+}
+
+func (r *CarouselRepository) Restore(ctx context.Context, id int) error {
+	query := `UPDATE main_carusel SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL`
+	tag, err := getQuerier(ctx, r.db).Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to restore carousel: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.New("deleted carousel not found")
+	}
 	return nil
 }
+
+// Purge окончательно удаляет записи, помеченные удалёнными раньше before
+func (r *CarouselRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	query := `DELETE FROM main_carusel WHERE deleted_at < $1`
+	tag, err := getQuerier(ctx, r.db).Exec(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge carousel slides: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,285 @@
+package repository
+
+import (
+	"Clinic_backend/internal/entity"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	// ErrConsentDocumentNotFound - документа нет
+	ErrConsentDocumentNotFound = errors.New("consent document not found")
+	// ErrConsentDocumentPublished - опубликованную версию менять нельзя, нужна новая
+	ErrConsentDocumentPublished = errors.New("consent document is already published")
+	// ErrConsentNotFound - действующего согласия этого типа у пользователя нет
+	ErrConsentNotFound = errors.New("consent not found")
+)
+
+const consentDocumentColumns = `id, type, version, title, content, published_at, created_at, updated_at`
+
+const userConsentColumns = `c.id, c.user_id, c.document_id, d.type, d.version, c.accepted_at, c.ip, c.withdrawn_at, c.withdrawn_ip`
+
+type ConsentRepositoryInterface interface {
+	CreateDocument(ctx context.Context, req *entity.ConsentDocumentRequest) (*entity.ConsentDocument, error)
+	GetDocument(ctx context.Context, id int) (*entity.ConsentDocument, error)
+	ListDocuments(ctx context.Context, filter *entity.ConsentDocumentFilter, publishedOnly bool) ([]entity.ConsentDocument, error)
+	UpdateDraft(ctx context.Context, id int, req *entity.ConsentDocumentUpdateRequest) (*entity.ConsentDocument, error)
+	Publish(ctx context.Context, id int) (*entity.ConsentDocument, error)
+	Current(ctx context.Context) ([]entity.ConsentDocument, error)
+	Accept(ctx context.Context, userID int, documentIDs []int, ip string) error
+	ListActive(ctx context.Context, userID int) ([]entity.UserConsent, error)
+	Withdraw(ctx context.Context, userID int, consentType, ip string) (*entity.UserConsent, error)
+	History(ctx context.Context, userID int) ([]entity.UserConsent, error)
+}
+
+type ConsentRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewConsentRepository(db *pgxpool.Pool) ConsentRepositoryInterface {
+	return &ConsentRepository{db: db}
+}
+
+// CreateDocument создаёт черновик следующей версии документа указанного типа
+func (r *ConsentRepository) CreateDocument(ctx context.Context, req *entity.ConsentDocumentRequest) (*entity.ConsentDocument, error) {
+	query := `
+		INSERT INTO consent_documents (type, version, title, content)
+		SELECT $1, COALESCE(MAX(version), 0) + 1, $2, $3
+		FROM consent_documents WHERE type = $1
+		RETURNING ` + consentDocumentColumns
+
+	document, err := scanConsentDocument(getQuerier(ctx, r.db).QueryRow(ctx, query, req.Type, req.Title, req.Content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create consent document: %w", err)
+	}
+	return document, nil
+}
+
+func (r *ConsentRepository) GetDocument(ctx context.Context, id int) (*entity.ConsentDocument, error) {
+	query := `SELECT ` + consentDocumentColumns + ` FROM consent_documents WHERE id = $1`
+
+	document, err := scanConsentDocument(getQuerier(ctx, r.db).QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrConsentDocumentNotFound
+		}
+		return nil, fmt.Errorf("failed to get consent document: %w", err)
+	}
+	return document, nil
+}
+
+// ListDocuments возвращает версии документов без текста, новые первыми
+func (r *ConsentRepository) ListDocuments(ctx context.Context, filter *entity.ConsentDocumentFilter, publishedOnly bool) ([]entity.ConsentDocument, error) {
+	query := `
+		SELECT id, type, version, title, '', published_at, created_at, updated_at
+		FROM consent_documents
+		WHERE ($1 = '' OR type = $1) AND (NOT $2 OR published_at IS NOT NULL)
+		ORDER BY type, version DESC`
+
+	return r.queryDocuments(ctx, query, filter.Type, publishedOnly)
+}
+
+// UpdateDraft меняет текст черновика
+func (r *ConsentRepository) UpdateDraft(ctx context.Context, id int, req *entity.ConsentDocumentUpdateRequest) (*entity.ConsentDocument, error) {
+	query := `
+		UPDATE consent_documents
+		SET title = $2, content = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND published_at IS NULL
+		RETURNING ` + consentDocumentColumns
+
+	document, err := scanConsentDocument(getQuerier(ctx, r.db).QueryRow(ctx, query, id, req.Title, req.Content))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, r.resolveDraft(ctx, id)
+		}
+		return nil, fmt.Errorf("failed to update consent document: %w", err)
+	}
+	return document, nil
+}
+
+// Publish делает черновик действующей версией документа
+func (r *ConsentRepository) Publish(ctx context.Context, id int) (*entity.ConsentDocument, error) {
+	query := `
+		UPDATE consent_documents
+		SET published_at = now(), updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND published_at IS NULL
+		RETURNING ` + consentDocumentColumns
+
+	document, err := scanConsentDocument(getQuerier(ctx, r.db).QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, r.resolveDraft(ctx, id)
+		}
+		return nil, fmt.Errorf("failed to publish consent document: %w", err)
+	}
+	return document, nil
+}
+
+// resolveDraft объясняет, почему черновик не найден: документа нет или он уже опубликован
+func (r *ConsentRepository) resolveDraft(ctx context.Context, id int) error {
+	if _, err := r.GetDocument(ctx, id); err != nil {
+		return err
+	}
+	return ErrConsentDocumentPublished
+}
+
+// Current возвращает действующие версии: последнюю опубликованную по каждому типу
+func (r *ConsentRepository) Current(ctx context.Context) ([]entity.ConsentDocument, error) {
+	query := `
+		SELECT DISTINCT ON (type) ` + consentDocumentColumns + `
+		FROM consent_documents
+		WHERE published_at IS NOT NULL
+		ORDER BY type, version DESC`
+
+	return r.queryDocuments(ctx, query)
+}
+
+func (r *ConsentRepository) queryDocuments(ctx context.Context, query string, args ...any) ([]entity.ConsentDocument, error) {
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query consent documents: %w", err)
+	}
+	defer rows.Close()
+
+	var documents []entity.ConsentDocument
+	for rows.Next() {
+		document, err := scanConsentDocument(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan consent document: %w", err)
+		}
+		documents = append(documents, *document)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return documents, nil
+}
+
+// Accept сохраняет согласия с документами. Уже действующие согласия не дублируются
+func (r *ConsentRepository) Accept(ctx context.Context, userID int, documentIDs []int, ip string) error {
+	query := `
+		INSERT INTO user_consents (user_id, document_id, ip)
+		SELECT $1, id, $3 FROM consent_documents WHERE id = ANY($2)
+		ON CONFLICT (user_id, document_id) WHERE withdrawn_at IS NULL DO NOTHING`
+
+	if _, err := getQuerier(ctx, r.db).Exec(ctx, query, userID, documentIDs, ip); err != nil {
+		return fmt.Errorf("failed to save consents: %w", err)
+	}
+	return nil
+}
+
+// ListActive возвращает действующие согласия пользователя, по последней версии на тип
+func (r *ConsentRepository) ListActive(ctx context.Context, userID int) ([]entity.UserConsent, error) {
+	query := `
+		SELECT DISTINCT ON (d.type) ` + userConsentColumns + `
+		FROM user_consents c
+		JOIN consent_documents d ON d.id = c.document_id
+		WHERE c.user_id = $1 AND c.withdrawn_at IS NULL
+		ORDER BY d.type, d.version DESC`
+
+	return r.queryConsents(ctx, query, userID)
+}
+
+// Withdraw отзывает все действующие согласия пользователя с документами указанного типа
+func (r *ConsentRepository) Withdraw(ctx context.Context, userID int, consentType, ip string) (*entity.UserConsent, error) {
+	query := `
+		WITH withdrawn AS (
+			UPDATE user_consents c
+			SET withdrawn_at = now(), withdrawn_ip = $3
+			FROM consent_documents d
+			WHERE d.id = c.document_id AND c.user_id = $1 AND d.type = $2 AND c.withdrawn_at IS NULL
+			RETURNING c.*, d.type, d.version
+		)
+		SELECT c.id, c.user_id, c.document_id, c.type, c.version, c.accepted_at, c.ip, c.withdrawn_at, c.withdrawn_ip
+		FROM withdrawn c
+		ORDER BY c.version DESC
+		LIMIT 1`
+
+	consent, err := scanUserConsent(getQuerier(ctx, r.db).QueryRow(ctx, query, userID, consentType, ip))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrConsentNotFound
+		}
+		return nil, fmt.Errorf("failed to withdraw consent: %w", err)
+	}
+	return consent, nil
+}
+
+// History возвращает все согласия пользователя, включая отозванные, новые первыми
+func (r *ConsentRepository) History(ctx context.Context, userID int) ([]entity.UserConsent, error) {
+	query := `
+		SELECT ` + userConsentColumns + `
+		FROM user_consents c
+		JOIN consent_documents d ON d.id = c.document_id
+		WHERE c.user_id = $1
+		ORDER BY c.accepted_at DESC, c.id DESC`
+
+	return r.queryConsents(ctx, query, userID)
+}
+
+func (r *ConsentRepository) queryConsents(ctx context.Context, query string, userID int) ([]entity.UserConsent, error) {
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query consents: %w", err)
+	}
+	defer rows.Close()
+
+	var consents []entity.UserConsent
+	for rows.Next() {
+		consent, err := scanUserConsent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan consent: %w", err)
+		}
+		consents = append(consents, *consent)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return consents, nil
+}
+
+func scanConsentDocument(row pgx.Row) (*entity.ConsentDocument, error) {
+	var document entity.ConsentDocument
+	err := row.Scan(
+		&document.ID,
+		&document.Type,
+		&document.Version,
+		&document.Title,
+		&document.Content,
+		&document.PublishedAt,
+		&document.CreatedAt,
+		&document.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	document.Mandatory = entity.ConsentMandatory(document.Type)
+	return &document, nil
+}
+
+func scanUserConsent(row pgx.Row) (*entity.UserConsent, error) {
+	var consent entity.UserConsent
+	err := row.Scan(
+		&consent.ID,
+		&consent.UserID,
+		&consent.DocumentID,
+		&consent.Type,
+		&consent.DocumentVersion,
+		&consent.AcceptedAt,
+		&consent.IP,
+		&consent.WithdrawnAt,
+		&consent.WithdrawnIP,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &consent, nil
+}
@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"Clinic_backend/internal/entity"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	// ErrDataRequestNotFound - обращения нет
+	ErrDataRequestNotFound = errors.New("data request not found")
+	// ErrErasurePending - у пользователя уже есть нерассмотренный запрос на удаление
+	ErrErasurePending = errors.New("erasure request is already pending")
+)
+
+const dataRequestColumns = `id, user_id, type, status, reason, resolution, reviewed_by, reviewed_at, completed_at, version, created_at, updated_at`
+
+type DataRequestRepositoryInterface interface {
+	Create(ctx context.Context, request *entity.DataRequest) (*entity.DataRequest, error)
+	GetByID(ctx context.Context, id int) (*entity.DataRequest, error)
+	Lock(ctx context.Context, id int) (*entity.DataRequest, error)
+	List(ctx context.Context, filter *entity.DataRequestFilter) ([]entity.DataRequest, int, error)
+	Resolve(ctx context.Context, id int, status string, resolution *string, reviewedBy *int) (*entity.DataRequest, error)
+}
+
+type DataRequestRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewDataRequestRepository(db *pgxpool.Pool) DataRequestRepositoryInterface {
+	return &DataRequestRepository{db: db}
+}
+
+// Create сохраняет обращение. Выгрузка создаётся сразу выполненной
+func (r *DataRequestRepository) Create(ctx context.Context, request *entity.DataRequest) (*entity.DataRequest, error) {
+	query := `
+		INSERT INTO data_requests (user_id, type, status, reason, completed_at)
+		VALUES ($1, $2, $3, $4, CASE WHEN $3 = 'completed' THEN now() END)
+		RETURNING ` + dataRequestColumns
+
+	created, err := scanDataRequest(getQuerier(ctx, r.db).QueryRow(ctx, query,
+		request.UserID, request.Type, request.Status, request.Reason))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrErasurePending
+		}
+		return nil, fmt.Errorf("failed to create data request: %w", err)
+	}
+
+	return created, nil
+}
+
+func (r *DataRequestRepository) GetByID(ctx context.Context, id int) (*entity.DataRequest, error) {
+	return r.get(ctx, `SELECT `+dataRequestColumns+` FROM data_requests WHERE id = $1`, id)
+}
+
+// Lock загружает обращение с блокировкой строки до конца транзакции
+func (r *DataRequestRepository) Lock(ctx context.Context, id int) (*entity.DataRequest, error) {
+	return r.get(ctx, `SELECT `+dataRequestColumns+` FROM data_requests WHERE id = $1 FOR UPDATE`, id)
+}
+
+func (r *DataRequestRepository) get(ctx context.Context, query string, id int) (*entity.DataRequest, error) {
+	request, err := scanDataRequest(getQuerier(ctx, r.db).QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrDataRequestNotFound
+		}
+		return nil, fmt.Errorf("failed to get data request: %w", err)
+	}
+	return request, nil
+}
+
+func (r *DataRequestRepository) List(ctx context.Context, filter *entity.DataRequestFilter) ([]entity.DataRequest, int, error) {
+	var conditions []string
+	var args []any
+
+	add := func(condition string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.UserID != nil {
+		add("user_id = $%d", *filter.UserID)
+	}
+	if filter.Type != "" {
+		add("type = $%d", filter.Type)
+	}
+	if filter.Status != "" {
+		add("status = $%d", filter.Status)
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	countQuery := `SELECT count(*) FROM data_requests` + where
+	if err := getQuerier(ctx, r.db).QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count data requests: %w", err)
+	}
+
+	args = append(args, filter.Limit, (filter.Page-1)*filter.Limit)
+	query := `SELECT ` + dataRequestColumns + ` FROM data_requests` + where + `
+		ORDER BY created_at DESC, id DESC` +
+		fmt.Sprintf(` LIMIT $%d OFFSET $%d`, len(args)-1, len(args))
+
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query data requests: %w", err)
+	}
+	defer rows.Close()
+
+	var requests []entity.DataRequest
+	for rows.Next() {
+		request, err := scanDataRequest(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan data request: %w", err)
+		}
+		requests = append(requests, *request)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return requests, total, nil
+}
+
+// Resolve фиксирует решение по обращению, заблокированному через Lock
+func (r *DataRequestRepository) Resolve(ctx context.Context, id int, status string, resolution *string, reviewedBy *int) (*entity.DataRequest, error) {
+	query := `
+		UPDATE data_requests
+		SET status = $2, resolution = $3, reviewed_by = $4, reviewed_at = now(),
+		    completed_at = CASE WHEN $2 = 'completed' THEN now() END,
+		    updated_at = CURRENT_TIMESTAMP, version = version + 1
+		WHERE id = $1
+		RETURNING ` + dataRequestColumns
+
+	request, err := scanDataRequest(getQuerier(ctx, r.db).QueryRow(ctx, query, id, status, resolution, reviewedBy))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrDataRequestNotFound
+		}
+		return nil, fmt.Errorf("failed to resolve data request: %w", err)
+	}
+	return request, nil
+}
+
+func scanDataRequest(row pgx.Row) (*entity.DataRequest, error) {
+	var request entity.DataRequest
+	err := row.Scan(
+		&request.ID,
+		&request.UserID,
+		&request.Type,
+		&request.Status,
+		&request.Reason,
+		&request.Resolution,
+		&request.ReviewedBy,
+		&request.ReviewedAt,
+		&request.CompletedAt,
+		&request.Version,
+		&request.CreatedAt,
+		&request.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
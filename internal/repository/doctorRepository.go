@@ -5,21 +5,29 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// ErrDoctorUserTaken - учётная запись уже привязана к другому врачу
+var ErrDoctorUserTaken = errors.New("user is already linked to another doctor")
+
 type DoctorRepositoryInterface interface {
 	Create(ctx context.Context, doctor *entity.Doctor) (*entity.Doctor, error)
-	GetAll(ctx context.Context) ([]entity.Doctor, error)
+	GetAll(ctx context.Context, includeDeleted bool, sort string) ([]entity.Doctor, error)
 	GetByID(ctx context.Context, id int) (*entity.Doctor, error)
+	GetByUserID(ctx context.Context, userID int) (*entity.Doctor, error)
 	GetBySpecialization(ctx context.Context, specializationID int) ([]entity.Doctor, error)
 	Update(ctx context.Context, id int, doctor *entity.Doctor) (*entity.Doctor, error)
-	Delete(ctx context.Context, id int) error
+	Delete(ctx context.Context, id int, version int) error
+	Restore(ctx context.Context, id int) error
+	Purge(ctx context.Context, before time.Time) (int64, error)
 	AddSpecialization(ctx context.Context, doctorID, specializationID int) error
 	RemoveSpecialization(ctx context.Context, doctorID, specializationID int) error
 	GetSpecializations(ctx context.Context, doctorID int) ([]entity.Specialization, error)
+	GetSpecializationsForDoctors(ctx context.Context, doctorIDs []int) (map[int][]entity.Specialization, error)
 }
 
 type DoctorRepository struct {
@@ -32,42 +40,57 @@ func NewDoctorRepository(db *pgxpool.Pool) DoctorRepositoryInterface {
 
 func (r *DoctorRepository) Create(ctx context.Context, doctor *entity.Doctor) (*entity.Doctor, error) {
 	query := `
-		INSERT INTO doctors (fullname, description, doctor_photo, schedule_id)
-		VALUES ($1, $2, $3, $4)
-		RETURNING id, fullname, description, doctor_photo, schedule_id, created_at, updated_at
+		INSERT INTO doctors (fullname, description, doctor_photo, doctor_photo_id, schedule_id, user_id)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, fullname, description, doctor_photo, doctor_photo_id, schedule_id, user_id, created_at, updated_at, version
 	`
 
 	var created entity.Doctor
-	err := r.db.QueryRow(ctx, query,
+	err := getQuerier(ctx, r.db).QueryRow(ctx, query,
 		doctor.Fullname,
 		doctor.Description,
 		doctor.DoctorPhoto,
+		doctor.DoctorPhotoID,
 		doctor.ScheduleID,
+		doctor.UserID,
 	).Scan(
 		&created.ID,
 		&created.Fullname,
 		&created.Description,
 		&created.DoctorPhoto,
+		&created.DoctorPhotoID,
 		&created.ScheduleID,
+		&created.UserID,
 		&created.CreatedAt,
 		&created.UpdatedAt,
+		&created.Version,
 	)
 
 	if err != nil {
+		if isConstraintViolation(err, "doctors_user_id_key") {
+			return nil, ErrDoctorUserTaken
+		}
 		return nil, fmt.Errorf("failed to create doctor: %w", err)
 	}
 
 	return &created, nil
 }
 
-func (r *DoctorRepository) GetAll(ctx context.Context) ([]entity.Doctor, error) {
+// GetAll возвращает врачей по id либо по рейтингу: сначала выше средняя оценка, при
+// равной - больше отзывов, врачи без отзывов в конце
+func (r *DoctorRepository) GetAll(ctx context.Context, includeDeleted bool, sort string) ([]entity.Doctor, error) {
+	orderBy := "id"
+	if sort == entity.DoctorSortRating {
+		orderBy = "rating_avg DESC NULLS LAST, rating_count DESC, id"
+	}
+
 	query := `
-		SELECT id, fullname, description, doctor_photo, schedule_id, created_at, updated_at
+		SELECT id, fullname, description, doctor_photo, doctor_photo_id, schedule_id, user_id, rating_avg::float8, rating_count, created_at, updated_at, version, deleted_at
 		FROM doctors
-		ORDER BY id
-	`
+		WHERE $1 OR deleted_at IS NULL
+		ORDER BY ` + orderBy
 
-	rows, err := r.db.Query(ctx, query)
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query, includeDeleted)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query doctors: %w", err)
 	}
@@ -81,9 +104,15 @@ func (r *DoctorRepository) GetAll(ctx context.Context) ([]entity.Doctor, error)
 			&doctor.Fullname,
 			&doctor.Description,
 			&doctor.DoctorPhoto,
+			&doctor.DoctorPhotoID,
 			&doctor.ScheduleID,
+			&doctor.UserID,
+			&doctor.RatingAverage,
+			&doctor.RatingCount,
 			&doctor.CreatedAt,
 			&doctor.UpdatedAt,
+			&doctor.Version,
+			&doctor.DeletedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan doctor: %w", err)
@@ -96,20 +125,57 @@ func (r *DoctorRepository) GetAll(ctx context.Context) ([]entity.Doctor, error)
 
 func (r *DoctorRepository) GetByID(ctx context.Context, id int) (*entity.Doctor, error) {
 	query := `
-		SELECT fullname, description, doctor_photo, schedule_id
+		SELECT id, fullname, description, doctor_photo, doctor_photo_id, schedule_id, user_id, rating_avg::float8, rating_count, created_at, updated_at, version
+		FROM doctors
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	var doctor entity.Doctor
+	err := getQuerier(ctx, r.db).QueryRow(ctx, query, id).Scan(
+		&doctor.ID,
+		&doctor.Fullname,
+		&doctor.Description,
+		&doctor.DoctorPhoto,
+		&doctor.DoctorPhotoID,
+		&doctor.ScheduleID,
+		&doctor.UserID,
+		&doctor.RatingAverage,
+		&doctor.RatingCount,
+		&doctor.CreatedAt,
+		&doctor.UpdatedAt,
+		&doctor.Version,
+	)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.New("doctor not found")
+		}
+		return nil, fmt.Errorf("failed to get doctor: %w", err)
+	}
+
+	return &doctor, nil
+}
+
+// GetByUserID возвращает врача, привязанного к учётной записи
+func (r *DoctorRepository) GetByUserID(ctx context.Context, userID int) (*entity.Doctor, error) {
+	query := `
+		SELECT id, fullname, description, doctor_photo, doctor_photo_id, schedule_id, user_id, created_at, updated_at, version
 		FROM doctors
-		WHERE id = $1
+		WHERE user_id = $1 AND deleted_at IS NULL
 	`
 
 	var doctor entity.Doctor
-	err := r.db.QueryRow(ctx, query, id).Scan(
-		//&doctor.ID,
+	err := getQuerier(ctx, r.db).QueryRow(ctx, query, userID).Scan(
+		&doctor.ID,
 		&doctor.Fullname,
 		&doctor.Description,
 		&doctor.DoctorPhoto,
+		&doctor.DoctorPhotoID,
 		&doctor.ScheduleID,
-		//&doctor.CreatedAt,
-		//&doctor.UpdatedAt,
+		&doctor.UserID,
+		&doctor.CreatedAt,
+		&doctor.UpdatedAt,
+		&doctor.Version,
 	)
 
 	if err != nil {
@@ -124,14 +190,14 @@ func (r *DoctorRepository) GetByID(ctx context.Context, id int) (*entity.Doctor,
 
 func (r *DoctorRepository) GetBySpecialization(ctx context.Context, specializationID int) ([]entity.Doctor, error) {
 	query := `
-		SELECT d.fullname, d.description, d.doctor_photo, d.schedule_id
+		SELECT d.id, d.fullname, d.description, d.doctor_photo, d.doctor_photo_id, d.schedule_id, d.user_id, d.rating_avg::float8, d.rating_count, d.created_at, d.updated_at, d.version
 		FROM doctors d
 		INNER JOIN doctor_specializations ds ON d.id = ds.doctor_id
-		WHERE ds.specialization_id = $1
+		WHERE ds.specialization_id = $1 AND d.deleted_at IS NULL
 		ORDER BY d.id
 	`
 
-	rows, err := r.db.Query(ctx, query, specializationID)
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query, specializationID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query doctors by specialization: %w", err)
 	}
@@ -141,13 +207,18 @@ func (r *DoctorRepository) GetBySpecialization(ctx context.Context, specializati
 	for rows.Next() {
 		var doctor entity.Doctor
 		err := rows.Scan(
-			//&doctor.ID,
+			&doctor.ID,
 			&doctor.Fullname,
 			&doctor.Description,
 			&doctor.DoctorPhoto,
+			&doctor.DoctorPhotoID,
 			&doctor.ScheduleID,
-			//&doctor.UpdatedAt,
-			//&doctor.CreatedAt,
+			&doctor.UserID,
+			&doctor.RatingAverage,
+			&doctor.RatingCount,
+			&doctor.CreatedAt,
+			&doctor.UpdatedAt,
+			&doctor.Version,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan doctor: %w", err)
@@ -161,41 +232,61 @@ func (r *DoctorRepository) GetBySpecialization(ctx context.Context, specializati
 func (r *DoctorRepository) Update(ctx context.Context, id int, doctor *entity.Doctor) (*entity.Doctor, error) {
 	query := `
 		UPDATE doctors
-		SET fullname = $1, description = $2, doctor_photo = $3, schedule_id = $4, updated_at = CURRENT_TIMESTAMP
-		WHERE id = $5
-		RETURNING fullname, description, doctor_photo, schedule_id
+		SET fullname = $1, description = $2, doctor_photo = $3, doctor_photo_id = $4, schedule_id = $5, user_id = $6,
+		    updated_at = CURRENT_TIMESTAMP, version = version + 1
+		WHERE id = $7 AND deleted_at IS NULL AND $8 IN (0, version)
+		RETURNING fullname, description, doctor_photo, doctor_photo_id, schedule_id, user_id, version
 	`
 
 	updated := entity.Doctor{}
-	err := r.db.QueryRow(ctx, query,
+	err := getQuerier(ctx, r.db).QueryRow(ctx, query,
 		doctor.Fullname,
 		doctor.Description,
 		doctor.DoctorPhoto,
+		doctor.DoctorPhotoID,
 		doctor.ScheduleID,
+		doctor.UserID,
 		id,
+		doctor.Version,
 	).Scan(
 		//&updated.ID,
 		&updated.Fullname,
 		&updated.Description,
 		&updated.DoctorPhoto,
+		&updated.DoctorPhotoID,
 		&updated.ScheduleID,
+		&updated.UserID,
 		//&updated.CreatedAt,
 		//&updated.UpdatedAt,
+		&updated.Version,
 	)
 
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, resolveNoRows(ctx, getQuerier(ctx, r.db), "doctors", id, errors.New("doctor not found"))
+		}
+		if isConstraintViolation(err, "doctors_user_id_key") {
+			return nil, ErrDoctorUserTaken
+		}
 		return nil, fmt.Errorf("failed to update doctor: %w", err)
 	}
 
 	return &updated, nil
 }
 
-func (r *DoctorRepository) Delete(ctx context.Context, id int) error {
-	query := `DELETE FROM doctors WHERE id = $1`
-	_, err := r.db.Exec(ctx, query, id)
+func (r *DoctorRepository) Delete(ctx context.Context, id int, version int) error {
+	query := `
+		UPDATE doctors
+		SET deleted_at = CURRENT_TIMESTAMP, version = version + 1
+		WHERE id = $1 AND deleted_at IS NULL AND $2 IN (0, version)
+	`
+	tag, err := getQuerier(ctx, r.db).Exec(ctx, query, id, version)
 	if err != nil {
 		return fmt.Errorf("failed to delete doctor: %w", err)
 	}
+	if tag.RowsAffected() == 0 {
+		return resolveNoRows(ctx, getQuerier(ctx, r.db), "doctors", id, errors.New("doctor not found"))
+	}
 	return nil
 }
 
@@ -205,7 +296,7 @@ func (r *DoctorRepository) AddSpecialization(ctx context.Context, doctorID, spec
 		VALUES ($1, $2)
 		ON CONFLICT DO NOTHING
 	`
-	_, err := r.db.Exec(ctx, query, doctorID, specializationID)
+	_, err := getQuerier(ctx, r.db).Exec(ctx, query, doctorID, specializationID)
 	if err != nil {
 		return fmt.Errorf("failed to add specialization: %w", err)
 	}
@@ -217,7 +308,7 @@ func (r *DoctorRepository) RemoveSpecialization(ctx context.Context, doctorID, s
 		DELETE FROM doctor_specializations
 		WHERE doctor_id = $1 AND specialization_id = $2
 	`
-	_, err := r.db.Exec(ctx, query, doctorID, specializationID)
+	_, err := getQuerier(ctx, r.db).Exec(ctx, query, doctorID, specializationID)
 	if err != nil {
 		return fmt.Errorf("failed to remove specialization: %w", err)
 	}
@@ -226,14 +317,14 @@ func (r *DoctorRepository) RemoveSpecialization(ctx context.Context, doctorID, s
 
 func (r *DoctorRepository) GetSpecializations(ctx context.Context, doctorID int) ([]entity.Specialization, error) {
 	query := `
-		SELECT s.id, s.name, s.created_at, s.updated_at
+		SELECT s.id, s.name, s.created_at, s.updated_at, s.version
 		FROM specializations s
 		INNER JOIN doctor_specializations ds ON s.id = ds.specialization_id
-		WHERE ds.doctor_id = $1
+		WHERE ds.doctor_id = $1 AND s.deleted_at IS NULL
 		ORDER BY s.id
 	`
 
-	rows, err := r.db.Query(ctx, query, doctorID)
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query, doctorID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query specializations: %w", err)
 	}
@@ -242,7 +333,7 @@ func (r *DoctorRepository) GetSpecializations(ctx context.Context, doctorID int)
 	var specializations []entity.Specialization
 	for rows.Next() {
 		var spec entity.Specialization
-		err := rows.Scan(&spec.ID, &spec.Name, &spec.CreatedAt, &spec.UpdatedAt)
+		err := rows.Scan(&spec.ID, &spec.Name, &spec.CreatedAt, &spec.UpdatedAt, &spec.Version)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan specialization: %w", err)
 		}
@@ -251,3 +342,71 @@ func (r *DoctorRepository) GetSpecializations(ctx context.Context, doctorID int)
 
 	return specializations, nil
 }
+
+// GetSpecializationsForDoctors загружает специализации сразу для набора врачей одним запросом
+func (r *DoctorRepository) GetSpecializationsForDoctors(ctx context.Context, doctorIDs []int) (map[int][]entity.Specialization, error) {
+	result := make(map[int][]entity.Specialization, len(doctorIDs))
+	if len(doctorIDs) == 0 {
+		return result, nil
+	}
+
+	query := `
+		SELECT ds.doctor_id, s.id, s.name, s.created_at, s.updated_at, s.version
+		FROM specializations s
+		INNER JOIN doctor_specializations ds ON s.id = ds.specialization_id
+		WHERE ds.doctor_id = ANY($1) AND s.deleted_at IS NULL
+		ORDER BY ds.doctor_id, s.id
+	`
+
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query, doctorIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query specializations: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var doctorID int
+		var spec entity.Specialization
+		err := rows.Scan(&doctorID, &spec.ID, &spec.Name, &spec.CreatedAt, &spec.UpdatedAt, &spec.Version)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan specialization: %w", err)
+		}
+		result[doctorID] = append(result[doctorID], spec)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return result, nil
+}
+
+func (r *DoctorRepository) Restore(ctx context.Context, id int) error {
+	query := `UPDATE doctors SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL`
+	tag, err := getQuerier(ctx, r.db).Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to restore doctor: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.New("deleted doctor not found")
+	}
+	return nil
+}
+
+// Purge окончательно удаляет записи, помеченные удалёнными раньше before.
+// Врачи, выписавшие рецепты или направления на анализы, остаются: документы ссылаются на них.
+// Остаются и врачи с отзывами - иначе отзывы пациентов удалились бы каскадом.
+func (r *DoctorRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	query := `
+		DELETE FROM doctors d
+		WHERE d.deleted_at < $1
+		  AND NOT EXISTS (SELECT 1 FROM prescriptions p WHERE p.doctor_id = d.id)
+		  AND NOT EXISTS (SELECT 1 FROM lab_orders lo WHERE lo.doctor_id = d.id)
+		  AND NOT EXISTS (SELECT 1 FROM doctor_reviews dr WHERE dr.doctor_id = d.id)
+	`
+	tag, err := getQuerier(ctx, r.db).Exec(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge doctors: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
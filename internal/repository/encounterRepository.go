@@ -0,0 +1,305 @@
+package repository
+
+import (
+	"Clinic_backend/internal/entity"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ErrEncounterNotFound - записи о приёме нет
+var ErrEncounterNotFound = errors.New("encounter not found")
+
+const encounterSelect = `
+	SELECT e.id, e.patient_id, p.last_name || ' ' || p.first_name || coalesce(' ' || p.middle_name, ''),
+	       e.doctor_user_id, u.username, to_char(e.encounter_date, 'YYYY-MM-DD'), e.complaints, e.anamnesis,
+	       e.examination, e.diagnoses, e.recommendations, e.status, e.revision, e.content_hash, e.signed_at,
+	       e.version, e.created_at, e.updated_at
+	FROM encounters e
+	JOIN patient_profiles p ON p.id = e.patient_id
+	JOIN users u ON u.id = e.doctor_user_id`
+
+type EncounterRepositoryInterface interface {
+	Create(ctx context.Context, encounter *entity.Encounter) (*entity.Encounter, error)
+	GetByID(ctx context.Context, id int) (*entity.Encounter, error)
+	List(ctx context.Context, filter *entity.EncounterFilter) ([]entity.Encounter, int, error)
+	UpdateDraft(ctx context.Context, id int, version int, content *entity.EncounterContent) (*entity.Encounter, error)
+	Sign(ctx context.Context, id int, version int, contentHash string) (*entity.Encounter, error)
+	Amend(ctx context.Context, id int, version int, revision int, content *entity.EncounterContent, contentHash string) (*entity.Encounter, error)
+	CreateRevision(ctx context.Context, revision *entity.EncounterRevision) error
+	ListRevisions(ctx context.Context, encounterID int) ([]entity.EncounterRevision, error)
+}
+
+type EncounterRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewEncounterRepository(db *pgxpool.Pool) EncounterRepositoryInterface {
+	return &EncounterRepository{db: db}
+}
+
+func (r *EncounterRepository) Create(ctx context.Context, encounter *entity.Encounter) (*entity.Encounter, error) {
+	query := `
+		INSERT INTO encounters (patient_id, doctor_user_id, encounter_date, complaints, anamnesis, examination, diagnoses, recommendations)
+		VALUES ($1, $2, $3::date, $4, $5, $6, $7, $8)
+		RETURNING id
+	`
+
+	var id int
+	err := getQuerier(ctx, r.db).QueryRow(ctx, query,
+		encounter.PatientID,
+		encounter.DoctorUserID,
+		encounter.EncounterDate,
+		encounter.Complaints,
+		encounter.Anamnesis,
+		encounter.Examination,
+		encounter.Diagnoses,
+		encounter.Recommendations,
+	).Scan(&id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create encounter: %w", err)
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+func (r *EncounterRepository) GetByID(ctx context.Context, id int) (*entity.Encounter, error) {
+	query := encounterSelect + ` WHERE e.id = $1`
+
+	encounter, err := scanEncounter(getQuerier(ctx, r.db).QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrEncounterNotFound
+		}
+		return nil, fmt.Errorf("failed to get encounter: %w", err)
+	}
+
+	return encounter, nil
+}
+
+func (r *EncounterRepository) List(ctx context.Context, filter *entity.EncounterFilter) ([]entity.Encounter, int, error) {
+	var conditions []string
+	var args []any
+
+	add := func(condition string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	add("(e.status = 'signed' OR e.doctor_user_id = $%d)", filter.ViewerUserID)
+	if filter.PatientID != nil {
+		add("e.patient_id = $%d", *filter.PatientID)
+	}
+	if filter.DoctorUserID != nil {
+		add("e.doctor_user_id = $%d", *filter.DoctorUserID)
+	}
+	if filter.Status != "" {
+		add("e.status = $%d", filter.Status)
+	}
+	where := " WHERE " + strings.Join(conditions, " AND ")
+
+	var total int
+	countQuery := `SELECT count(*) FROM encounters e` + where
+	if err := getQuerier(ctx, r.db).QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count encounters: %w", err)
+	}
+
+	args = append(args, filter.Limit, (filter.Page-1)*filter.Limit)
+	query := encounterSelect + where + `
+		ORDER BY e.encounter_date DESC, e.id DESC` +
+		fmt.Sprintf(` LIMIT $%d OFFSET $%d`, len(args)-1, len(args))
+
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query encounters: %w", err)
+	}
+	defer rows.Close()
+
+	var encounters []entity.Encounter
+	for rows.Next() {
+		encounter, err := scanEncounter(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan encounter: %w", err)
+		}
+		encounters = append(encounters, *encounter)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return encounters, total, nil
+}
+
+// UpdateDraft меняет содержание черновика. Подписанную запись условие не затрагивает
+func (r *EncounterRepository) UpdateDraft(ctx context.Context, id int, version int, content *entity.EncounterContent) (*entity.Encounter, error) {
+	query := `
+		UPDATE encounters
+		SET encounter_date = $3::date, complaints = $4, anamnesis = $5, examination = $6, diagnoses = $7,
+		    recommendations = $8, updated_at = CURRENT_TIMESTAMP, version = version + 1
+		WHERE id = $1 AND $2 IN (0, version) AND status = 'draft'
+	`
+
+	return r.execMutation(ctx, id, query, id, version,
+		content.EncounterDate,
+		content.Complaints,
+		content.Anamnesis,
+		content.Examination,
+		content.Diagnoses,
+		content.Recommendations,
+	)
+}
+
+// Sign подписывает черновик: он становится первой редакцией и блокируется
+func (r *EncounterRepository) Sign(ctx context.Context, id int, version int, contentHash string) (*entity.Encounter, error) {
+	query := `
+		UPDATE encounters
+		SET status = 'signed', revision = 1, content_hash = $3, signed_at = now(),
+		    updated_at = CURRENT_TIMESTAMP, version = version + 1
+		WHERE id = $1 AND $2 IN (0, version) AND status = 'draft'
+	`
+
+	return r.execMutation(ctx, id, query, id, version, contentHash)
+}
+
+// Amend заменяет содержание подписанной записи новой редакцией
+func (r *EncounterRepository) Amend(ctx context.Context, id int, version int, revision int, content *entity.EncounterContent, contentHash string) (*entity.Encounter, error) {
+	query := `
+		UPDATE encounters
+		SET encounter_date = $3::date, complaints = $4, anamnesis = $5, examination = $6, diagnoses = $7,
+		    recommendations = $8, revision = $9, content_hash = $10, signed_at = now(),
+		    updated_at = CURRENT_TIMESTAMP, version = version + 1
+		WHERE id = $1 AND $2 IN (0, version) AND status = 'signed'
+	`
+
+	return r.execMutation(ctx, id, query, id, version,
+		content.EncounterDate,
+		content.Complaints,
+		content.Anamnesis,
+		content.Examination,
+		content.Diagnoses,
+		content.Recommendations,
+		revision,
+		contentHash,
+	)
+}
+
+// execMutation выполняет условный UPDATE и возвращает запись. Сервис проверяет запись
+// до изменения в той же транзакции, поэтому пустой результат означает, что её успели
+// изменить параллельно
+func (r *EncounterRepository) execMutation(ctx context.Context, id int, query string, args ...any) (*entity.Encounter, error) {
+	result, err := getQuerier(ctx, r.db).Exec(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update encounter: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return nil, ErrVersionConflict
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+func (r *EncounterRepository) CreateRevision(ctx context.Context, revision *entity.EncounterRevision) error {
+	query := `
+		INSERT INTO encounter_revisions (encounter_id, revision, author_user_id, reason, encounter_date, complaints,
+		                                 anamnesis, examination, diagnoses, recommendations, content_hash)
+		VALUES ($1, $2, $3, $4, $5::date, $6, $7, $8, $9, $10, $11)
+		RETURNING created_at
+	`
+
+	err := getQuerier(ctx, r.db).QueryRow(ctx, query,
+		revision.EncounterID,
+		revision.Revision,
+		revision.AuthorUserID,
+		revision.Reason,
+		revision.EncounterDate,
+		revision.Complaints,
+		revision.Anamnesis,
+		revision.Examination,
+		revision.Diagnoses,
+		revision.Recommendations,
+		revision.ContentHash,
+	).Scan(&revision.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create encounter revision: %w", err)
+	}
+
+	return nil
+}
+
+func (r *EncounterRepository) ListRevisions(ctx context.Context, encounterID int) ([]entity.EncounterRevision, error) {
+	query := `
+		SELECT encounter_id, revision, author_user_id, reason, to_char(encounter_date, 'YYYY-MM-DD'), complaints,
+		       anamnesis, examination, diagnoses, recommendations, content_hash, created_at
+		FROM encounter_revisions
+		WHERE encounter_id = $1
+		ORDER BY revision
+	`
+
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query, encounterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query encounter revisions: %w", err)
+	}
+	defer rows.Close()
+
+	var revisions []entity.EncounterRevision
+	for rows.Next() {
+		var revision entity.EncounterRevision
+		err := rows.Scan(
+			&revision.EncounterID,
+			&revision.Revision,
+			&revision.AuthorUserID,
+			&revision.Reason,
+			&revision.EncounterDate,
+			&revision.Complaints,
+			&revision.Anamnesis,
+			&revision.Examination,
+			&revision.Diagnoses,
+			&revision.Recommendations,
+			&revision.ContentHash,
+			&revision.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan encounter revision: %w", err)
+		}
+		revisions = append(revisions, revision)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return revisions, nil
+}
+
+func scanEncounter(row pgx.Row) (*entity.Encounter, error) {
+	var encounter entity.Encounter
+	err := row.Scan(
+		&encounter.ID,
+		&encounter.PatientID,
+		&encounter.PatientName,
+		&encounter.DoctorUserID,
+		&encounter.DoctorName,
+		&encounter.EncounterDate,
+		&encounter.Complaints,
+		&encounter.Anamnesis,
+		&encounter.Examination,
+		&encounter.Diagnoses,
+		&encounter.Recommendations,
+		&encounter.Status,
+		&encounter.Revision,
+		&encounter.ContentHash,
+		&encounter.SignedAt,
+		&encounter.Version,
+		&encounter.CreatedAt,
+		&encounter.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &encounter, nil
+}
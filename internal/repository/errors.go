@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ErrVersionConflict - запись была изменена другим запросом после чтения клиентом
+var ErrVersionConflict = errors.New("version conflict: record was modified")
+
+// AnyVersion - ожидаемая версия при If-Match: *, изменение проходит при любой текущей версии.
+// Настоящие версии начинаются с 1, в запросах условие записывается как $N IN (0, version).
+const AnyVersion = 0
+
+// resolveNoRows определяет причину, по которой условный UPDATE не затронул ни одной строки:
+// запись существует - значит версия устарела, иначе возвращается notFound
+func resolveNoRows(ctx context.Context, q Querier, table string, id int, notFound error) error {
+	var exists bool
+	query := fmt.Sprintf(`SELECT EXISTS(SELECT 1 FROM %s WHERE id = $1 AND deleted_at IS NULL)`, table)
+	if err := q.QueryRow(ctx, query, id).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to check %s existence: %w", table, err)
+	}
+	if exists {
+		return ErrVersionConflict
+	}
+	return notFound
+}
+
+// isUniqueViolation сообщает, нарушено ли ограничение уникальности (код 23505)
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
+// isConstraintViolation сообщает, нарушено ли ограничение с указанным именем
+func isConstraintViolation(err error, constraint string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.ConstraintName == constraint
+}
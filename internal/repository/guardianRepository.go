@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"Clinic_backend/internal/entity"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ErrGuardianshipNotFound - пользователь не является опекуном этого пациента
+var ErrGuardianshipNotFound = errors.New("guardianship not found")
+
+const guardianColumns = `id, guardian_user_id, patient_id, relationship, can_book, can_view_records, consent_confirmed_at, created_at, updated_at`
+
+type GuardianRepositoryInterface interface {
+	Create(ctx context.Context, guardian *entity.PatientGuardian) (*entity.PatientGuardian, error)
+	Get(ctx context.Context, guardianUserID, patientID int) (*entity.PatientGuardian, error)
+	ListByGuardian(ctx context.Context, guardianUserID int) ([]entity.PatientGuardian, error)
+	Update(ctx context.Context, guardian *entity.PatientGuardian) (*entity.PatientGuardian, error)
+	Delete(ctx context.Context, guardianUserID, patientID int) error
+}
+
+type GuardianRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewGuardianRepository(db *pgxpool.Pool) GuardianRepositoryInterface {
+	return &GuardianRepository{db: db}
+}
+
+func (r *GuardianRepository) Create(ctx context.Context, guardian *entity.PatientGuardian) (*entity.PatientGuardian, error) {
+	query := `
+		INSERT INTO patient_guardians (guardian_user_id, patient_id, relationship, can_book, can_view_records, consent_confirmed_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + guardianColumns
+
+	created, err := scanGuardian(getQuerier(ctx, r.db).QueryRow(ctx, query,
+		guardian.GuardianUserID,
+		guardian.PatientID,
+		guardian.Relationship,
+		guardian.CanBook,
+		guardian.CanViewRecords,
+		guardian.ConsentConfirmedAt,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create guardianship: %w", err)
+	}
+
+	return created, nil
+}
+
+func (r *GuardianRepository) Get(ctx context.Context, guardianUserID, patientID int) (*entity.PatientGuardian, error) {
+	query := `SELECT ` + guardianColumns + ` FROM patient_guardians WHERE guardian_user_id = $1 AND patient_id = $2`
+
+	guardian, err := scanGuardian(getQuerier(ctx, r.db).QueryRow(ctx, query, guardianUserID, patientID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrGuardianshipNotFound
+		}
+		return nil, fmt.Errorf("failed to get guardianship: %w", err)
+	}
+
+	return guardian, nil
+}
+
+func (r *GuardianRepository) ListByGuardian(ctx context.Context, guardianUserID int) ([]entity.PatientGuardian, error) {
+	query := `
+		SELECT ` + guardianColumns + `
+		FROM patient_guardians
+		WHERE guardian_user_id = $1
+		ORDER BY created_at, id
+	`
+
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query, guardianUserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query guardianships: %w", err)
+	}
+	defer rows.Close()
+
+	var guardians []entity.PatientGuardian
+	for rows.Next() {
+		guardian, err := scanGuardian(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan guardianship: %w", err)
+		}
+		guardians = append(guardians, *guardian)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return guardians, nil
+}
+
+func (r *GuardianRepository) Update(ctx context.Context, guardian *entity.PatientGuardian) (*entity.PatientGuardian, error) {
+	query := `
+		UPDATE patient_guardians
+		SET relationship = $3, can_book = $4, can_view_records = $5, consent_confirmed_at = $6,
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE guardian_user_id = $1 AND patient_id = $2
+		RETURNING ` + guardianColumns
+
+	updated, err := scanGuardian(getQuerier(ctx, r.db).QueryRow(ctx, query,
+		guardian.GuardianUserID,
+		guardian.PatientID,
+		guardian.Relationship,
+		guardian.CanBook,
+		guardian.CanViewRecords,
+		guardian.ConsentConfirmedAt,
+	))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrGuardianshipNotFound
+		}
+		return nil, fmt.Errorf("failed to update guardianship: %w", err)
+	}
+
+	return updated, nil
+}
+
+func (r *GuardianRepository) Delete(ctx context.Context, guardianUserID, patientID int) error {
+	query := `DELETE FROM patient_guardians WHERE guardian_user_id = $1 AND patient_id = $2`
+
+	result, err := getQuerier(ctx, r.db).Exec(ctx, query, guardianUserID, patientID)
+	if err != nil {
+		return fmt.Errorf("failed to delete guardianship: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrGuardianshipNotFound
+	}
+
+	return nil
+}
+
+func scanGuardian(row pgx.Row) (*entity.PatientGuardian, error) {
+	var guardian entity.PatientGuardian
+	err := row.Scan(
+		&guardian.ID,
+		&guardian.GuardianUserID,
+		&guardian.PatientID,
+		&guardian.Relationship,
+		&guardian.CanBook,
+		&guardian.CanViewRecords,
+		&guardian.ConsentConfirmedAt,
+		&guardian.CreatedAt,
+		&guardian.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &guardian, nil
+}
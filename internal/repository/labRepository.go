@@ -0,0 +1,371 @@
+package repository
+
+import (
+	"Clinic_backend/internal/entity"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	// ErrLabOrderNotFound - направления нет или оно отменено
+	ErrLabOrderNotFound = errors.New("lab order not found")
+	// ErrLabAttachmentNotFound - бланка нет у этого направления
+	ErrLabAttachmentNotFound = errors.New("lab attachment not found")
+)
+
+const labOrderSelect = `
+	SELECT lo.id, lo.patient_id, p.last_name || ' ' || p.first_name || coalesce(' ' || p.middle_name, ''),
+	       lo.doctor_id, d.fullname, lo.encounter_id, lo.tests, lo.comment, lo.status, lo.sampled_at,
+	       lo.ready_at, lo.version, lo.created_at, lo.updated_at
+	FROM lab_orders lo
+	JOIN patient_profiles p ON p.id = lo.patient_id
+	JOIN doctors d ON d.id = lo.doctor_id`
+
+type LabRepositoryInterface interface {
+	CreateOrder(ctx context.Context, order *entity.LabOrder) (*entity.LabOrder, error)
+	GetOrder(ctx context.Context, id int) (*entity.LabOrder, error)
+	LockOrder(ctx context.Context, id int) (*entity.LabOrder, error)
+	ListOrders(ctx context.Context, filter *entity.LabOrderFilter) ([]entity.LabOrder, int, error)
+	UpdateStatus(ctx context.Context, id int, version int, status string) (*entity.LabOrder, error)
+	DeleteOrder(ctx context.Context, id int, version int) error
+	UpsertResult(ctx context.Context, result *entity.LabResult) error
+	ListResults(ctx context.Context, orderID int) ([]entity.LabResult, error)
+	CreateAttachment(ctx context.Context, attachment *entity.LabAttachment) (*entity.LabAttachment, error)
+	ListAttachments(ctx context.Context, orderID int) ([]entity.LabAttachment, error)
+	GetAttachment(ctx context.Context, orderID, id int) (*entity.LabAttachment, error)
+}
+
+type LabRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewLabRepository(db *pgxpool.Pool) LabRepositoryInterface {
+	return &LabRepository{db: db}
+}
+
+func (r *LabRepository) CreateOrder(ctx context.Context, order *entity.LabOrder) (*entity.LabOrder, error) {
+	query := `
+		INSERT INTO lab_orders (patient_id, doctor_id, encounter_id, tests, comment)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`
+
+	var id int
+	err := getQuerier(ctx, r.db).QueryRow(ctx, query,
+		order.PatientID,
+		order.DoctorID,
+		order.EncounterID,
+		order.Tests,
+		order.Comment,
+	).Scan(&id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create lab order: %w", err)
+	}
+
+	return r.GetOrder(ctx, id)
+}
+
+func (r *LabRepository) GetOrder(ctx context.Context, id int) (*entity.LabOrder, error) {
+	return r.getOrder(ctx, labOrderSelect+` WHERE lo.id = $1 AND lo.deleted_at IS NULL`, id)
+}
+
+// LockOrder читает направление с блокировкой строки до конца транзакции, чтобы
+// одновременные импорты по одному направлению не перезаписали статус друг друга
+func (r *LabRepository) LockOrder(ctx context.Context, id int) (*entity.LabOrder, error) {
+	return r.getOrder(ctx, labOrderSelect+` WHERE lo.id = $1 AND lo.deleted_at IS NULL FOR UPDATE OF lo`, id)
+}
+
+func (r *LabRepository) getOrder(ctx context.Context, query string, id int) (*entity.LabOrder, error) {
+	order, err := scanLabOrder(getQuerier(ctx, r.db).QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrLabOrderNotFound
+		}
+		return nil, fmt.Errorf("failed to get lab order: %w", err)
+	}
+
+	return order, nil
+}
+
+func (r *LabRepository) ListOrders(ctx context.Context, filter *entity.LabOrderFilter) ([]entity.LabOrder, int, error) {
+	conditions := []string{"lo.deleted_at IS NULL"}
+	var args []any
+
+	add := func(condition string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.PatientID != nil {
+		add("lo.patient_id = $%d", *filter.PatientID)
+	}
+	if filter.DoctorID != nil {
+		add("lo.doctor_id = $%d", *filter.DoctorID)
+	}
+	if filter.Status != "" {
+		add("lo.status = $%d", filter.Status)
+	}
+	where := " WHERE " + strings.Join(conditions, " AND ")
+
+	var total int
+	countQuery := `SELECT count(*) FROM lab_orders lo` + where
+	if err := getQuerier(ctx, r.db).QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count lab orders: %w", err)
+	}
+
+	args = append(args, filter.Limit, (filter.Page-1)*filter.Limit)
+	query := labOrderSelect + where + `
+		ORDER BY lo.created_at DESC, lo.id DESC` +
+		fmt.Sprintf(` LIMIT $%d OFFSET $%d`, len(args)-1, len(args))
+
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query lab orders: %w", err)
+	}
+	defer rows.Close()
+
+	var orders []entity.LabOrder
+	for rows.Next() {
+		order, err := scanLabOrder(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan lab order: %w", err)
+		}
+		orders = append(orders, *order)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return orders, total, nil
+}
+
+// UpdateStatus переводит направление в новый статус. Время взятия материала фиксируется
+// при первом уходе из ordered, время готовности - при переходе в ready
+func (r *LabRepository) UpdateStatus(ctx context.Context, id int, version int, status string) (*entity.LabOrder, error) {
+	query := `
+		UPDATE lab_orders
+		SET status = $3,
+		    sampled_at = CASE WHEN $3 <> 'ordered' THEN coalesce(sampled_at, now()) END,
+		    ready_at = CASE WHEN $3 = 'ready' THEN coalesce(ready_at, now()) END,
+		    updated_at = CURRENT_TIMESTAMP, version = version + 1
+		WHERE id = $1 AND deleted_at IS NULL AND $2 IN (0, version)
+	`
+
+	result, err := getQuerier(ctx, r.db).Exec(ctx, query, id, version, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update lab order status: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return nil, resolveNoRows(ctx, getQuerier(ctx, r.db), "lab_orders", id, ErrLabOrderNotFound)
+	}
+
+	return r.GetOrder(ctx, id)
+}
+
+// DeleteOrder отменяет направление. Запись остаётся в базе как часть медицинской документации
+func (r *LabRepository) DeleteOrder(ctx context.Context, id int, version int) error {
+	query := `
+		UPDATE lab_orders
+		SET deleted_at = CURRENT_TIMESTAMP, version = version + 1
+		WHERE id = $1 AND deleted_at IS NULL AND $2 IN (0, version)
+	`
+	result, err := getQuerier(ctx, r.db).Exec(ctx, query, id, version)
+	if err != nil {
+		return fmt.Errorf("failed to delete lab order: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return resolveNoRows(ctx, getQuerier(ctx, r.db), "lab_orders", id, ErrLabOrderNotFound)
+	}
+	return nil
+}
+
+// UpsertResult сохраняет значение показателя; повторный результат по тому же коду заменяет прежний
+func (r *LabRepository) UpsertResult(ctx context.Context, result *entity.LabResult) error {
+	query := `
+		INSERT INTO lab_results (order_id, test_code, test_name, value, unit, reference_range, flag, out_of_range, final, observed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (order_id, test_code) DO UPDATE
+		SET test_name = EXCLUDED.test_name, value = EXCLUDED.value, unit = EXCLUDED.unit,
+		    reference_range = EXCLUDED.reference_range, flag = EXCLUDED.flag, out_of_range = EXCLUDED.out_of_range,
+		    final = EXCLUDED.final, observed_at = EXCLUDED.observed_at, updated_at = CURRENT_TIMESTAMP
+	`
+
+	_, err := getQuerier(ctx, r.db).Exec(ctx, query,
+		result.OrderID,
+		result.TestCode,
+		result.TestName,
+		result.Value,
+		result.Unit,
+		result.ReferenceRange,
+		result.Flag,
+		result.OutOfRange,
+		result.Final,
+		result.ObservedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save lab result: %w", err)
+	}
+	return nil
+}
+
+func (r *LabRepository) ListResults(ctx context.Context, orderID int) ([]entity.LabResult, error) {
+	query := `
+		SELECT id, order_id, test_code, test_name, value, unit, reference_range, flag, out_of_range, final,
+		       observed_at, updated_at
+		FROM lab_results
+		WHERE order_id = $1
+		ORDER BY id
+	`
+
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query lab results: %w", err)
+	}
+	defer rows.Close()
+
+	var results []entity.LabResult
+	for rows.Next() {
+		var result entity.LabResult
+		err := rows.Scan(
+			&result.ID,
+			&result.OrderID,
+			&result.TestCode,
+			&result.TestName,
+			&result.Value,
+			&result.Unit,
+			&result.ReferenceRange,
+			&result.Flag,
+			&result.OutOfRange,
+			&result.Final,
+			&result.ObservedAt,
+			&result.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan lab result: %w", err)
+		}
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return results, nil
+}
+
+func (r *LabRepository) CreateAttachment(ctx context.Context, attachment *entity.LabAttachment) (*entity.LabAttachment, error) {
+	query := `
+		INSERT INTO lab_attachments (order_id, blob_key, filename, size, uploaded_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+
+	created := *attachment
+	err := getQuerier(ctx, r.db).QueryRow(ctx, query,
+		attachment.OrderID,
+		attachment.BlobKey,
+		attachment.Filename,
+		attachment.Size,
+		attachment.UploadedBy,
+	).Scan(&created.ID, &created.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create lab attachment: %w", err)
+	}
+
+	return &created, nil
+}
+
+func (r *LabRepository) ListAttachments(ctx context.Context, orderID int) ([]entity.LabAttachment, error) {
+	query := `
+		SELECT id, order_id, blob_key, filename, size, uploaded_by, created_at
+		FROM lab_attachments
+		WHERE order_id = $1
+		ORDER BY id
+	`
+
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query lab attachments: %w", err)
+	}
+	defer rows.Close()
+
+	var attachments []entity.LabAttachment
+	for rows.Next() {
+		attachment, err := scanLabAttachment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan lab attachment: %w", err)
+		}
+		attachments = append(attachments, *attachment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return attachments, nil
+}
+
+func (r *LabRepository) GetAttachment(ctx context.Context, orderID, id int) (*entity.LabAttachment, error) {
+	query := `
+		SELECT id, order_id, blob_key, filename, size, uploaded_by, created_at
+		FROM lab_attachments
+		WHERE order_id = $1 AND id = $2
+	`
+
+	attachment, err := scanLabAttachment(getQuerier(ctx, r.db).QueryRow(ctx, query, orderID, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrLabAttachmentNotFound
+		}
+		return nil, fmt.Errorf("failed to get lab attachment: %w", err)
+	}
+
+	return attachment, nil
+}
+
+func scanLabOrder(row pgx.Row) (*entity.LabOrder, error) {
+	var order entity.LabOrder
+	err := row.Scan(
+		&order.ID,
+		&order.PatientID,
+		&order.PatientName,
+		&order.DoctorID,
+		&order.DoctorName,
+		&order.EncounterID,
+		&order.Tests,
+		&order.Comment,
+		&order.Status,
+		&order.SampledAt,
+		&order.ReadyAt,
+		&order.Version,
+		&order.CreatedAt,
+		&order.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func scanLabAttachment(row pgx.Row) (*entity.LabAttachment, error) {
+	var attachment entity.LabAttachment
+	err := row.Scan(
+		&attachment.ID,
+		&attachment.OrderID,
+		&attachment.BlobKey,
+		&attachment.Filename,
+		&attachment.Size,
+		&attachment.UploadedBy,
+		&attachment.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -12,10 +13,12 @@ import (
 
 type LicenseRepositoryInterface interface {
 	Create(ctx context.Context, license *entity.License) (*entity.License, error)
-	GetAll(ctx context.Context) ([]entity.License, error)
+	GetAll(ctx context.Context, includeDeleted bool) ([]entity.License, error)
 	GetByID(ctx context.Context, id int) (*entity.License, error)
 	Update(ctx context.Context, id int, license *entity.License) (*entity.License, error)
-	Delete(ctx context.Context, id int) error
+	Delete(ctx context.Context, id int, version int) error
+	Restore(ctx context.Context, id int) error
+	Purge(ctx context.Context, before time.Time) (int64, error)
 }
 
 type LicenseRepository struct {
@@ -28,15 +31,15 @@ func NewLicenseRepository(db *pgxpool.Pool) LicenseRepositoryInterface {
 
 func (r *LicenseRepository) Create(ctx context.Context, license *entity.License) (*entity.License, error) {
 	query := `
-		INSERT INTO licenses (photo, name, description)
-		VALUES ($1, $2, $3)
-		RETURNING id, photo, name, description, created_at, updated_at
+		INSERT INTO licenses (photo, photo_id, name, description)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, photo, photo_id, name, description, created_at, updated_at, version
 	`
 
 	var created entity.License
-	err := r.db.QueryRow(ctx, query, license.Photo, license.Name, license.Description).Scan(
-		&created.ID, &created.Photo, &created.Name, &created.Description,
-		&created.CreatedAt, &created.UpdatedAt,
+	err := getQuerier(ctx, r.db).QueryRow(ctx, query, license.Photo, license.PhotoID, license.Name, license.Description).Scan(
+		&created.ID, &created.Photo, &created.PhotoID, &created.Name, &created.Description,
+		&created.CreatedAt, &created.UpdatedAt, &created.Version,
 	)
 
 	if err != nil {
@@ -46,14 +49,15 @@ func (r *LicenseRepository) Create(ctx context.Context, license *entity.License)
 	return &created, nil
 }
 
-func (r *LicenseRepository) GetAll(ctx context.Context) ([]entity.License, error) {
+func (r *LicenseRepository) GetAll(ctx context.Context, includeDeleted bool) ([]entity.License, error) {
 	query := `
-		SELECT id, photo, name, description, created_at, updated_at 
+		SELECT id, photo, photo_id, name, description, created_at, updated_at, version, deleted_at
 		FROM licenses 
+		WHERE $1 OR deleted_at IS NULL
 		ORDER BY id
 	`
 
-	rows, err := r.db.Query(ctx, query)
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query, includeDeleted)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query licenses: %w", err)
 	}
@@ -63,8 +67,8 @@ func (r *LicenseRepository) GetAll(ctx context.Context) ([]entity.License, error
 	for rows.Next() {
 		var license entity.License
 		err := rows.Scan(
-			&license.ID, &license.Photo, &license.Name, &license.Description,
-			&license.CreatedAt, &license.UpdatedAt,
+			&license.ID, &license.Photo, &license.PhotoID, &license.Name, &license.Description,
+			&license.CreatedAt, &license.UpdatedAt, &license.Version, &license.DeletedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan license: %w", err)
@@ -77,15 +81,15 @@ func (r *LicenseRepository) GetAll(ctx context.Context) ([]entity.License, error
 
 func (r *LicenseRepository) GetByID(ctx context.Context, id int) (*entity.License, error) {
 	query := `
-		SELECT id, photo, name, description, created_at, updated_at 
+		SELECT id, photo, photo_id, name, description, created_at, updated_at, version 
 		FROM licenses 
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 
 	var license entity.License
-	err := r.db.QueryRow(ctx, query, id).Scan(
-		&license.ID, &license.Photo, &license.Name, &license.Description,
-		&license.CreatedAt, &license.UpdatedAt,
+	err := getQuerier(ctx, r.db).QueryRow(ctx, query, id).Scan(
+		&license.ID, &license.Photo, &license.PhotoID, &license.Name, &license.Description,
+		&license.CreatedAt, &license.UpdatedAt, &license.Version,
 	)
 
 	if err != nil {
@@ -101,31 +105,63 @@ func (r *LicenseRepository) GetByID(ctx context.Context, id int) (*entity.Licens
 func (r *LicenseRepository) Update(ctx context.Context, id int, license *entity.License) (*entity.License, error) {
 	query := `
 		UPDATE licenses
-		SET photo = $1, name = $2, description = $3, updated_at = CURRENT_TIMESTAMP
-		WHERE id = $4
-		RETURNING id, photo, name, description, created_at, updated_at
+		SET photo = $1, photo_id = $2, name = $3, description = $4, updated_at = CURRENT_TIMESTAMP, version = version + 1
+		WHERE id = $5 AND deleted_at IS NULL AND $6 IN (0, version)
+		RETURNING id, photo, photo_id, name, description, created_at, updated_at, version
 	`
 
 	var updated entity.License
-	err := r.db.QueryRow(ctx, query,
-		license.Photo, license.Name, license.Description, id,
+	err := getQuerier(ctx, r.db).QueryRow(ctx, query,
+		license.Photo, license.PhotoID, license.Name, license.Description, id, license.Version,
 	).Scan(
-		&updated.ID, &updated.Photo, &updated.Name, &updated.Description,
-		&updated.CreatedAt, &updated.UpdatedAt,
+		&updated.ID, &updated.Photo, &updated.PhotoID, &updated.Name, &updated.Description,
+		&updated.CreatedAt, &updated.UpdatedAt, &updated.Version,
 	)
 
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, resolveNoRows(ctx, getQuerier(ctx, r.db), "licenses", id, errors.New("license not found"))
+		}
 		return nil, fmt.Errorf("failed to update license: %w", err)
 	}
 
 	return &updated, nil
 }
 
-func (r *LicenseRepository) Delete(ctx context.Context, id int) error {
-	query := `DELETE FROM licenses WHERE id = $1`
-	_, err := r.db.Exec(ctx, query, id)
+func (r *LicenseRepository) Delete(ctx context.Context, id int, version int) error {
+	query := `
+		UPDATE licenses
+		SET deleted_at = CURRENT_TIMESTAMP, version = version + 1
+		WHERE id = $1 AND deleted_at IS NULL AND $2 IN (0, version)
+	`
+	tag, err := getQuerier(ctx, r.db).Exec(ctx, query, id, version)
 	if err != nil {
 		return fmt.Errorf("failed to delete license: %w", err)
 	}
+	if tag.RowsAffected() == 0 {
+		return resolveNoRows(ctx, getQuerier(ctx, r.db), "licenses", id, errors.New("license not found"))
+	}
+	return nil
+}
+
+func (r *LicenseRepository) Restore(ctx context.Context, id int) error {
+	query := `UPDATE licenses SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL`
+	tag, err := getQuerier(ctx, r.db).Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to restore license: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.New("deleted license not found")
+	}
 	return nil
 }
+
+// Purge окончательно удаляет записи, помеченные удалёнными раньше before
+func (r *LicenseRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	query := `DELETE FROM licenses WHERE deleted_at < $1`
+	tag, err := getQuerier(ctx, r.db).Exec(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge licenses: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,306 @@
+package repository
+
+import (
+	"Clinic_backend/internal/entity"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	// ErrMediaNotFound - изображения с таким ID нет
+	ErrMediaNotFound = errors.New("media not found")
+	// ErrMediaVariantNotFound - копия нужной ширины ещё не создана или не нужна
+	ErrMediaVariantNotFound = errors.New("media variant not found")
+)
+
+// photoColumn - поле записи каталога со ссылкой на изображение.
+// Имена таблиц и колонок фиксированы здесь и не приходят извне.
+type photoColumn struct {
+	entityType string
+	table      string
+	url        string
+	id         string
+}
+
+var photoColumns = []photoColumn{
+	{entityType: entity.AuditEntityDoctor, table: "doctors", url: "doctor_photo", id: "doctor_photo_id"},
+	{entityType: entity.AuditEntityServiceCategory, table: "service_categories", url: "category_photo", id: "category_photo_id"},
+	{entityType: entity.AuditEntityService, table: "services", url: "specific_photo", id: "specific_photo_id"},
+	{entityType: entity.AuditEntityLicense, table: "licenses", url: "photo", id: "photo_id"},
+	{entityType: entity.AuditEntityCarousel, table: "main_carusel", url: "image", id: "image_id"},
+}
+
+type MediaRepositoryInterface interface {
+	Create(ctx context.Context, media *entity.Media) (*entity.Media, error)
+	GetByID(ctx context.Context, id string) (*entity.Media, error)
+	GetByIDs(ctx context.Context, ids []string) (map[string]entity.Media, error)
+	ListPendingVariants(ctx context.Context, limit int) ([]entity.Media, error)
+	SaveVariants(ctx context.Context, mediaID string, variants []entity.MediaVariant) error
+	SetVariantsStatus(ctx context.Context, mediaID string, status string) error
+	GetVariant(ctx context.Context, mediaID string, width int) (*entity.MediaVariant, error)
+	RequeueVariants(ctx context.Context, widths []int) (int64, error)
+	ListUnlinkedPhotos(ctx context.Context) ([]entity.MediaPhotoRef, error)
+	LinkPhoto(ctx context.Context, ref *entity.MediaPhotoRef, media *entity.Media) (bool, error)
+}
+
+type MediaRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewMediaRepository(db *pgxpool.Pool) MediaRepositoryInterface {
+	return &MediaRepository{db: db}
+}
+
+// Create сохраняет описание изображения. Если такое содержимое уже загружалось,
+// возвращается существующая запись
+func (r *MediaRepository) Create(ctx context.Context, media *entity.Media) (*entity.Media, error) {
+	query := `
+		INSERT INTO media (id, content_type, size, width, height, original_name, uploaded_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (id) DO NOTHING
+	`
+
+	_, err := getQuerier(ctx, r.db).Exec(ctx, query,
+		media.ID, media.ContentType, media.Size, media.Width, media.Height, media.OriginalName, media.UploadedBy,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create media: %w", err)
+	}
+
+	return r.GetByID(ctx, media.ID)
+}
+
+const mediaColumns = `id, content_type, size, width, height, original_name, uploaded_by, variants_status, created_at`
+
+func scanMedia(row pgx.Row) (*entity.Media, error) {
+	var media entity.Media
+	err := row.Scan(
+		&media.ID, &media.ContentType, &media.Size, &media.Width, &media.Height,
+		&media.OriginalName, &media.UploadedBy, &media.VariantsStatus, &media.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	media.URL = entity.MediaURL(media.ID)
+	return &media, nil
+}
+
+func (r *MediaRepository) GetByID(ctx context.Context, id string) (*entity.Media, error) {
+	query := `SELECT ` + mediaColumns + ` FROM media WHERE id = $1`
+
+	media, err := scanMedia(getQuerier(ctx, r.db).QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrMediaNotFound
+		}
+		return nil, fmt.Errorf("failed to get media: %w", err)
+	}
+
+	return media, nil
+}
+
+// GetByIDs загружает изображения одним запросом. Отсутствующие ID пропускаются
+func (r *MediaRepository) GetByIDs(ctx context.Context, ids []string) (map[string]entity.Media, error) {
+	result := make(map[string]entity.Media, len(ids))
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	query := `SELECT ` + mediaColumns + ` FROM media WHERE id = ANY($1)`
+
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get media: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		media, err := scanMedia(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan media: %w", err)
+		}
+		result[media.ID] = *media
+	}
+
+	return result, rows.Err()
+}
+
+// ListPendingVariants возвращает изображения, для которых ещё не созданы копии, в порядке загрузки
+func (r *MediaRepository) ListPendingVariants(ctx context.Context, limit int) ([]entity.Media, error) {
+	query := `
+		SELECT ` + mediaColumns + `
+		FROM media
+		WHERE variants_status = $1
+		ORDER BY created_at
+		LIMIT $2
+	`
+
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query, entity.MediaVariantsPending, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending media: %w", err)
+	}
+	defer rows.Close()
+
+	var result []entity.Media
+	for rows.Next() {
+		media, err := scanMedia(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan media: %w", err)
+		}
+		result = append(result, *media)
+	}
+
+	return result, rows.Err()
+}
+
+// SaveVariants сохраняет описания копий и отмечает изображение обработанным одним запросом
+func (r *MediaRepository) SaveVariants(ctx context.Context, mediaID string, variants []entity.MediaVariant) error {
+	widths := make([]int, len(variants))
+	heights := make([]int, len(variants))
+	contentTypes := make([]string, len(variants))
+	sizes := make([]int64, len(variants))
+	for i, v := range variants {
+		widths[i], heights[i], contentTypes[i], sizes[i] = v.Width, v.Height, v.ContentType, v.Size
+	}
+
+	query := `
+		WITH saved AS (
+			INSERT INTO media_variants (media_id, width, height, content_type, size)
+			SELECT $1::varchar, v.width, v.height, v.content_type, v.size
+			FROM unnest($2::int[], $3::int[], $4::text[], $5::bigint[]) AS v(width, height, content_type, size)
+			ON CONFLICT (media_id, width) DO UPDATE
+			SET height = EXCLUDED.height, content_type = EXCLUDED.content_type, size = EXCLUDED.size
+		)
+		UPDATE media SET variants_status = $6 WHERE id = $1
+	`
+
+	_, err := getQuerier(ctx, r.db).Exec(ctx, query,
+		mediaID, widths, heights, contentTypes, sizes, entity.MediaVariantsDone,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save media variants: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MediaRepository) SetVariantsStatus(ctx context.Context, mediaID string, status string) error {
+	query := `UPDATE media SET variants_status = $2 WHERE id = $1`
+
+	_, err := getQuerier(ctx, r.db).Exec(ctx, query, mediaID, status)
+	if err != nil {
+		return fmt.Errorf("failed to update media variants status: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MediaRepository) GetVariant(ctx context.Context, mediaID string, width int) (*entity.MediaVariant, error) {
+	query := `
+		SELECT media_id, width, height, content_type, size
+		FROM media_variants
+		WHERE media_id = $1 AND width = $2
+	`
+
+	var variant entity.MediaVariant
+	err := getQuerier(ctx, r.db).QueryRow(ctx, query, mediaID, width).Scan(
+		&variant.MediaID, &variant.Width, &variant.Height, &variant.ContentType, &variant.Size,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrMediaVariantNotFound
+		}
+		return nil, fmt.Errorf("failed to get media variant: %w", err)
+	}
+
+	return &variant, nil
+}
+
+// RequeueVariants возвращает в очередь обработанные изображения, у которых нет копии
+// одной из указанных ширин (например, после изменения media.variant_widths)
+func (r *MediaRepository) RequeueVariants(ctx context.Context, widths []int) (int64, error) {
+	query := `
+		UPDATE media m
+		SET variants_status = $2
+		WHERE m.variants_status = $3
+		  AND EXISTS (
+			SELECT 1 FROM unnest($1::int[]) AS w(width)
+			WHERE w.width < m.width
+			  AND NOT EXISTS (SELECT 1 FROM media_variants v WHERE v.media_id = m.id AND v.width = w.width)
+		  )
+	`
+
+	tag, err := getQuerier(ctx, r.db).Exec(ctx, query, widths, entity.MediaVariantsPending, entity.MediaVariantsDone)
+	if err != nil {
+		return 0, fmt.Errorf("failed to requeue media variants: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// ListUnlinkedPhotos возвращает записи каталога (включая удалённые), у которых
+// изображение задано только строкой: внешний URL, data URI и т.п.
+func (r *MediaRepository) ListUnlinkedPhotos(ctx context.Context) ([]entity.MediaPhotoRef, error) {
+	var result []entity.MediaPhotoRef
+
+	for _, column := range photoColumns {
+		query := fmt.Sprintf(`
+			SELECT id, %[1]s
+			FROM %[2]s
+			WHERE %[3]s IS NULL AND COALESCE(%[1]s, '') <> ''
+			ORDER BY id
+		`, column.url, column.table, column.id)
+
+		rows, err := getQuerier(ctx, r.db).Query(ctx, query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s photos: %w", column.table, err)
+		}
+
+		for rows.Next() {
+			ref := entity.MediaPhotoRef{EntityType: column.entityType, URLField: column.url, IDField: column.id}
+			if err := rows.Scan(&ref.EntityID, &ref.Value); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan %s photo: %w", column.table, err)
+			}
+			result = append(result, ref)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("failed to list %s photos: %w", column.table, err)
+		}
+	}
+
+	return result, nil
+}
+
+// LinkPhoto заменяет строковую ссылку на загруженное изображение и увеличивает версию записи.
+// Возвращает false, если значение успели изменить после чтения.
+func (r *MediaRepository) LinkPhoto(ctx context.Context, ref *entity.MediaPhotoRef, media *entity.Media) (bool, error) {
+	var column *photoColumn
+	for i := range photoColumns {
+		if photoColumns[i].entityType == ref.EntityType && photoColumns[i].id == ref.IDField {
+			column = &photoColumns[i]
+		}
+	}
+	if column == nil {
+		return false, fmt.Errorf("unknown photo entity: %s", ref.EntityType)
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE %[1]s
+		SET %[2]s = $1, %[3]s = $2, updated_at = CURRENT_TIMESTAMP, version = version + 1
+		WHERE id = $3 AND %[2]s IS NULL AND %[3]s = $4
+	`, column.table, column.id, column.url)
+
+	tag, err := getQuerier(ctx, r.db).Exec(ctx, query, media.ID, media.URL, ref.EntityID, ref.Value)
+	if err != nil {
+		return false, fmt.Errorf("failed to link %s photo: %w", column.table, err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
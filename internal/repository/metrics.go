@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"Clinic_backend/internal/logging"
+	"Clinic_backend/internal/metrics"
+	"Clinic_backend/internal/tracing"
+	"context"
+	"errors"
+	"log/slog"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// queryLabels - репозиторий и метод, из которых выполняется запрос
+type queryLabels struct {
+	repository string
+	method     string
+}
+
+// Разбор имени функции по адресу вызова кэшируется, т.к. getQuerier вызывается на каждый запрос
+var callerLabels sync.Map
+
+func labelsForCaller(pc uintptr) queryLabels {
+	if cached, ok := callerLabels.Load(pc); ok {
+		return cached.(queryLabels)
+	}
+
+	labels := queryLabels{repository: "unknown", method: "unknown"}
+	if fn := runtime.FuncForPC(pc); fn != nil {
+		labels = parseFuncName(fn.Name())
+	}
+	callerLabels.Store(pc, labels)
+	return labels
+}
+
+// parseFuncName разбирает имя вида
+// "Clinic_backend/internal/repository.(*DoctorRepository).GetByID.func1"
+func parseFuncName(name string) queryLabels {
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+
+	parts := strings.Split(name, ".")
+	if len(parts) < 2 {
+		return queryLabels{repository: "unknown", method: name}
+	}
+	if len(parts) == 2 || !strings.HasPrefix(parts[1], "(") {
+		return queryLabels{repository: parts[0], method: parts[1]}
+	}
+	return queryLabels{
+		repository: strings.TrimSuffix(strings.TrimPrefix(parts[1], "(*"), ")"),
+		method:     parts[2],
+	}
+}
+
+// instrumentedQuerier замеряет длительность запросов репозитория
+// и пишет их в логгер запроса
+type instrumentedQuerier struct {
+	q      Querier
+	labels queryLabels
+}
+
+func (q instrumentedQuerier) observe(ctx context.Context, start time.Time, err error) {
+	duration := time.Since(start)
+	metrics.DBQueryDuration.WithLabelValues(q.labels.repository, q.labels.method).Observe(duration.Seconds())
+
+	logger := logging.FromContext(ctx)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		logger.Error("DB query failed",
+			slog.String("repository", q.labels.repository),
+			slog.String("method", q.labels.method),
+			slog.Int64("duration_ms", duration.Milliseconds()),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+	logger.Debug("DB query",
+		slog.String("repository", q.labels.repository),
+		slog.String("method", q.labels.method),
+		slog.Int64("duration_ms", duration.Milliseconds()),
+	)
+}
+
+// withOperation называет спан запроса по методу репозитория
+func (q instrumentedQuerier) withOperation(ctx context.Context) context.Context {
+	return tracing.WithOperation(ctx, q.labels.repository+"."+q.labels.method)
+}
+
+func (q instrumentedQuerier) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	start := time.Now()
+	tag, err := q.q.Exec(q.withOperation(ctx), sql, args...)
+	q.observe(ctx, start, err)
+	return tag, err
+}
+
+func (q instrumentedQuerier) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	start := time.Now()
+	rows, err := q.q.Query(q.withOperation(ctx), sql, args...)
+	if err != nil {
+		q.observe(ctx, start, err)
+		return rows, err
+	}
+	return &instrumentedRows{Rows: rows, done: func(err error) { q.observe(ctx, start, err) }}, nil
+}
+
+func (q instrumentedQuerier) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	start := time.Now()
+	return instrumentedRow{row: q.q.QueryRow(q.withOperation(ctx), sql, args...), done: func(err error) { q.observe(ctx, start, err) }}
+}
+
+// instrumentedRows фиксирует длительность после чтения всех строк
+type instrumentedRows struct {
+	pgx.Rows
+	once sync.Once
+	done func(err error)
+}
+
+func (r *instrumentedRows) Close() {
+	r.Rows.Close()
+	r.once.Do(func() { r.done(r.Rows.Err()) })
+}
+
+type instrumentedRow struct {
+	row  pgx.Row
+	done func(err error)
+}
+
+func (r instrumentedRow) Scan(dest ...any) error {
+	err := r.row.Scan(dest...)
+	r.done(err)
+	return err
+}
@@ -0,0 +1,296 @@
+package repository
+
+import (
+	"Clinic_backend/internal/entity"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	// ErrPatientNotFound - профиля пациента нет
+	ErrPatientNotFound = errors.New("patient profile not found")
+	// ErrPatientIdentifierTaken - полис ОМС или СНИЛС уже указан в профиле другого пациента
+	ErrPatientIdentifierTaken = errors.New("insurance policy or snils already belongs to another patient")
+)
+
+// patientFullName - ФИО одной строкой; выражение совпадает с триграммным индексом
+const patientFullName = `(last_name || ' ' || first_name || coalesce(' ' || middle_name, ''))`
+
+const patientColumns = `id, user_id, last_name, first_name, middle_name, ` + patientFullName + `,
+	to_char(birth_date, 'YYYY-MM-DD'), sex, phone, address, insurance_policy, snils, version, created_at, updated_at`
+
+// likeEscaper экранирует спецсимволы шаблона LIKE в пользовательском вводе
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+type PatientRepositoryInterface interface {
+	GetByID(ctx context.Context, id int) (*entity.PatientProfile, error)
+	GetByIDs(ctx context.Context, ids []int) (map[int]entity.PatientProfile, error)
+	GetByUserID(ctx context.Context, userID int) (*entity.PatientProfile, error)
+	UpsertByUserID(ctx context.Context, profile *entity.PatientProfile) (*entity.PatientProfile, error)
+	Create(ctx context.Context, profile *entity.PatientProfile) (*entity.PatientProfile, error)
+	Update(ctx context.Context, id int, profile *entity.PatientProfile) (*entity.PatientProfile, error)
+	Search(ctx context.Context, filter *entity.PatientFilter) ([]entity.PatientProfile, int, error)
+}
+
+type PatientRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPatientRepository(db *pgxpool.Pool) PatientRepositoryInterface {
+	return &PatientRepository{db: db}
+}
+
+func (r *PatientRepository) GetByID(ctx context.Context, id int) (*entity.PatientProfile, error) {
+	query := `SELECT ` + patientColumns + ` FROM patient_profiles WHERE id = $1`
+
+	profile, err := scanPatient(getQuerier(ctx, r.db).QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrPatientNotFound
+		}
+		return nil, fmt.Errorf("failed to get patient profile: %w", err)
+	}
+
+	return profile, nil
+}
+
+func (r *PatientRepository) GetByIDs(ctx context.Context, ids []int) (map[int]entity.PatientProfile, error) {
+	profiles := make(map[int]entity.PatientProfile, len(ids))
+	if len(ids) == 0 {
+		return profiles, nil
+	}
+
+	query := `SELECT ` + patientColumns + ` FROM patient_profiles WHERE id = ANY($1)`
+
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query patient profiles: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		profile, err := scanPatient(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan patient profile: %w", err)
+		}
+		profiles[profile.ID] = *profile
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return profiles, nil
+}
+
+func (r *PatientRepository) GetByUserID(ctx context.Context, userID int) (*entity.PatientProfile, error) {
+	query := `SELECT ` + patientColumns + ` FROM patient_profiles WHERE user_id = $1`
+
+	profile, err := scanPatient(getQuerier(ctx, r.db).QueryRow(ctx, query, userID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrPatientNotFound
+		}
+		return nil, fmt.Errorf("failed to get patient profile: %w", err)
+	}
+
+	return profile, nil
+}
+
+// UpsertByUserID создаёт профиль пользователя или заменяет существующий.
+// Одновременные первые сохранения не создают второй профиль - срабатывает ON CONFLICT.
+func (r *PatientRepository) UpsertByUserID(ctx context.Context, profile *entity.PatientProfile) (*entity.PatientProfile, error) {
+	query := `
+		INSERT INTO patient_profiles (user_id, last_name, first_name, middle_name, birth_date, sex, phone, address, insurance_policy, snils)
+		VALUES ($1, $2, $3, $4, $5::date, $6, $7, $8, $9, $10)
+		ON CONFLICT (user_id) DO UPDATE SET
+			last_name = EXCLUDED.last_name,
+			first_name = EXCLUDED.first_name,
+			middle_name = EXCLUDED.middle_name,
+			birth_date = EXCLUDED.birth_date,
+			sex = EXCLUDED.sex,
+			phone = EXCLUDED.phone,
+			address = EXCLUDED.address,
+			insurance_policy = EXCLUDED.insurance_policy,
+			snils = EXCLUDED.snils,
+			updated_at = CURRENT_TIMESTAMP,
+			version = patient_profiles.version + 1
+		RETURNING ` + patientColumns
+
+	saved, err := scanPatient(getQuerier(ctx, r.db).QueryRow(ctx, query,
+		profile.UserID,
+		profile.LastName,
+		profile.FirstName,
+		profile.MiddleName,
+		profile.BirthDate,
+		profile.Sex,
+		profile.Phone,
+		profile.Address,
+		profile.InsurancePolicy,
+		profile.SNILS,
+	))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrPatientIdentifierTaken
+		}
+		return nil, fmt.Errorf("failed to save patient profile: %w", err)
+	}
+
+	return saved, nil
+}
+
+// Create создаёт профиль без учётной записи - например, профиль подопечного
+func (r *PatientRepository) Create(ctx context.Context, profile *entity.PatientProfile) (*entity.PatientProfile, error) {
+	query := `
+		INSERT INTO patient_profiles (last_name, first_name, middle_name, birth_date, sex, phone, address, insurance_policy, snils)
+		VALUES ($1, $2, $3, $4::date, $5, $6, $7, $8, $9)
+		RETURNING ` + patientColumns
+
+	created, err := scanPatient(getQuerier(ctx, r.db).QueryRow(ctx, query,
+		profile.LastName,
+		profile.FirstName,
+		profile.MiddleName,
+		profile.BirthDate,
+		profile.Sex,
+		profile.Phone,
+		profile.Address,
+		profile.InsurancePolicy,
+		profile.SNILS,
+	))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrPatientIdentifierTaken
+		}
+		return nil, fmt.Errorf("failed to create patient profile: %w", err)
+	}
+
+	return created, nil
+}
+
+func (r *PatientRepository) Update(ctx context.Context, id int, profile *entity.PatientProfile) (*entity.PatientProfile, error) {
+	query := `
+		UPDATE patient_profiles
+		SET last_name = $2, first_name = $3, middle_name = $4, birth_date = $5::date, sex = $6,
+		    phone = $7, address = $8, insurance_policy = $9, snils = $10,
+		    updated_at = CURRENT_TIMESTAMP, version = version + 1
+		WHERE id = $1
+		RETURNING ` + patientColumns
+
+	updated, err := scanPatient(getQuerier(ctx, r.db).QueryRow(ctx, query,
+		id,
+		profile.LastName,
+		profile.FirstName,
+		profile.MiddleName,
+		profile.BirthDate,
+		profile.Sex,
+		profile.Phone,
+		profile.Address,
+		profile.InsurancePolicy,
+		profile.SNILS,
+	))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrPatientNotFound
+		}
+		if isUniqueViolation(err) {
+			return nil, ErrPatientIdentifierTaken
+		}
+		return nil, fmt.Errorf("failed to update patient profile: %w", err)
+	}
+
+	return updated, nil
+}
+
+func (r *PatientRepository) Search(ctx context.Context, filter *entity.PatientFilter) ([]entity.PatientProfile, int, error) {
+	where, args := patientFilterClause(filter)
+
+	var total int
+	countQuery := `SELECT count(*) FROM patient_profiles` + where
+	if err := getQuerier(ctx, r.db).QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count patient profiles: %w", err)
+	}
+
+	args = append(args, filter.Limit, (filter.Page-1)*filter.Limit)
+	query := `SELECT ` + patientColumns + ` FROM patient_profiles` + where + `
+		ORDER BY last_name, first_name, id` +
+		fmt.Sprintf(` LIMIT $%d OFFSET $%d`, len(args)-1, len(args))
+
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search patient profiles: %w", err)
+	}
+	defer rows.Close()
+
+	var profiles []entity.PatientProfile
+	for rows.Next() {
+		profile, err := scanPatient(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan patient profile: %w", err)
+		}
+		profiles = append(profiles, *profile)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return profiles, total, nil
+}
+
+// patientFilterClause строит условие поиска. Значения фильтра уже нормализованы сервисом:
+// телефон - только цифры, полис - 16 цифр
+func patientFilterClause(filter *entity.PatientFilter) (string, []any) {
+	var conditions []string
+	var args []any
+
+	add := func(condition string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	// Каждое слово должно входить в ФИО: "Иван Петров" находит "Петров Иван Сергеевич"
+	for _, word := range strings.Fields(filter.Name) {
+		add(patientFullName+" ILIKE $%d", "%"+likeEscaper.Replace(word)+"%")
+	}
+	if filter.Phone != "" {
+		add("phone LIKE $%d", "%"+filter.Phone+"%")
+	}
+	if filter.Policy != "" {
+		add("insurance_policy = $%d", filter.Policy)
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+func scanPatient(row pgx.Row) (*entity.PatientProfile, error) {
+	var profile entity.PatientProfile
+	err := row.Scan(
+		&profile.ID,
+		&profile.UserID,
+		&profile.LastName,
+		&profile.FirstName,
+		&profile.MiddleName,
+		&profile.FullName,
+		&profile.BirthDate,
+		&profile.Sex,
+		&profile.Phone,
+		&profile.Address,
+		&profile.InsurancePolicy,
+		&profile.SNILS,
+		&profile.Version,
+		&profile.CreatedAt,
+		&profile.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"Clinic_backend/internal/entity"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ErrPrescriptionNotFound - рецепта нет или он отменён
+var ErrPrescriptionNotFound = errors.New("prescription not found")
+
+// Врач берётся и из удалённых: рецепт остаётся действительным после ухода врача из клиники
+const prescriptionSelect = `
+	SELECT rx.id, rx.patient_id, p.last_name || ' ' || p.first_name || coalesce(' ' || p.middle_name, ''),
+	       rx.doctor_id, d.fullname, rx.encounter_id, rx.drug_name, rx.dosage, rx.frequency, rx.duration,
+	       rx.instructions, to_char(rx.issued_on, 'YYYY-MM-DD'), rx.version, rx.created_at, rx.updated_at
+	FROM prescriptions rx
+	JOIN patient_profiles p ON p.id = rx.patient_id
+	JOIN doctors d ON d.id = rx.doctor_id`
+
+type PrescriptionRepositoryInterface interface {
+	Create(ctx context.Context, prescription *entity.Prescription) (*entity.Prescription, error)
+	GetByID(ctx context.Context, id int) (*entity.Prescription, error)
+	List(ctx context.Context, filter *entity.PrescriptionFilter) ([]entity.Prescription, int, error)
+	Update(ctx context.Context, id int, version int, encounterID *int, content *entity.PrescriptionContent) (*entity.Prescription, error)
+	Delete(ctx context.Context, id int, version int) error
+}
+
+type PrescriptionRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPrescriptionRepository(db *pgxpool.Pool) PrescriptionRepositoryInterface {
+	return &PrescriptionRepository{db: db}
+}
+
+func (r *PrescriptionRepository) Create(ctx context.Context, prescription *entity.Prescription) (*entity.Prescription, error) {
+	query := `
+		INSERT INTO prescriptions (patient_id, doctor_id, encounter_id, drug_name, dosage, frequency, duration, instructions, issued_on)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9::date)
+		RETURNING id
+	`
+
+	var id int
+	err := getQuerier(ctx, r.db).QueryRow(ctx, query,
+		prescription.PatientID,
+		prescription.DoctorID,
+		prescription.EncounterID,
+		prescription.DrugName,
+		prescription.Dosage,
+		prescription.Frequency,
+		prescription.Duration,
+		prescription.Instructions,
+		prescription.IssuedOn,
+	).Scan(&id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create prescription: %w", err)
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+func (r *PrescriptionRepository) GetByID(ctx context.Context, id int) (*entity.Prescription, error) {
+	query := prescriptionSelect + ` WHERE rx.id = $1 AND rx.deleted_at IS NULL`
+
+	prescription, err := scanPrescription(getQuerier(ctx, r.db).QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrPrescriptionNotFound
+		}
+		return nil, fmt.Errorf("failed to get prescription: %w", err)
+	}
+
+	return prescription, nil
+}
+
+func (r *PrescriptionRepository) List(ctx context.Context, filter *entity.PrescriptionFilter) ([]entity.Prescription, int, error) {
+	conditions := []string{"rx.deleted_at IS NULL"}
+	var args []any
+
+	add := func(condition string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.PatientID != nil {
+		add("rx.patient_id = $%d", *filter.PatientID)
+	}
+	if filter.DoctorID != nil {
+		add("rx.doctor_id = $%d", *filter.DoctorID)
+	}
+	where := " WHERE " + strings.Join(conditions, " AND ")
+
+	var total int
+	countQuery := `SELECT count(*) FROM prescriptions rx` + where
+	if err := getQuerier(ctx, r.db).QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count prescriptions: %w", err)
+	}
+
+	args = append(args, filter.Limit, (filter.Page-1)*filter.Limit)
+	query := prescriptionSelect + where + `
+		ORDER BY rx.issued_on DESC, rx.id DESC` +
+		fmt.Sprintf(` LIMIT $%d OFFSET $%d`, len(args)-1, len(args))
+
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query prescriptions: %w", err)
+	}
+	defer rows.Close()
+
+	var prescriptions []entity.Prescription
+	for rows.Next() {
+		prescription, err := scanPrescription(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan prescription: %w", err)
+		}
+		prescriptions = append(prescriptions, *prescription)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return prescriptions, total, nil
+}
+
+func (r *PrescriptionRepository) Update(ctx context.Context, id int, version int, encounterID *int, content *entity.PrescriptionContent) (*entity.Prescription, error) {
+	query := `
+		UPDATE prescriptions
+		SET encounter_id = $3, drug_name = $4, dosage = $5, frequency = $6, duration = $7, instructions = $8,
+		    issued_on = $9::date, updated_at = CURRENT_TIMESTAMP, version = version + 1
+		WHERE id = $1 AND deleted_at IS NULL AND $2 IN (0, version)
+	`
+
+	result, err := getQuerier(ctx, r.db).Exec(ctx, query, id, version,
+		encounterID,
+		content.DrugName,
+		content.Dosage,
+		content.Frequency,
+		content.Duration,
+		content.Instructions,
+		content.IssuedOn,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update prescription: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return nil, resolveNoRows(ctx, getQuerier(ctx, r.db), "prescriptions", id, ErrPrescriptionNotFound)
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+// Delete отменяет рецепт. Запись остаётся в базе как часть медицинской документации
+func (r *PrescriptionRepository) Delete(ctx context.Context, id int, version int) error {
+	query := `
+		UPDATE prescriptions
+		SET deleted_at = CURRENT_TIMESTAMP, version = version + 1
+		WHERE id = $1 AND deleted_at IS NULL AND $2 IN (0, version)
+	`
+	result, err := getQuerier(ctx, r.db).Exec(ctx, query, id, version)
+	if err != nil {
+		return fmt.Errorf("failed to delete prescription: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return resolveNoRows(ctx, getQuerier(ctx, r.db), "prescriptions", id, ErrPrescriptionNotFound)
+	}
+	return nil
+}
+
+func scanPrescription(row pgx.Row) (*entity.Prescription, error) {
+	var prescription entity.Prescription
+	err := row.Scan(
+		&prescription.ID,
+		&prescription.PatientID,
+		&prescription.PatientName,
+		&prescription.DoctorID,
+		&prescription.DoctorName,
+		&prescription.EncounterID,
+		&prescription.DrugName,
+		&prescription.Dosage,
+		&prescription.Frequency,
+		&prescription.Duration,
+		&prescription.Instructions,
+		&prescription.IssuedOn,
+		&prescription.Version,
+		&prescription.CreatedAt,
+		&prescription.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &prescription, nil
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"Clinic_backend/internal/storage"
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// testDB создаёт в базе TEST_DATABASE_URL отдельную схему с текущим init.sql и удаляет
+// её после теста. Без TEST_DATABASE_URL тест пропускается.
+func testDB(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	ctx := context.Background()
+
+	schema := fmt.Sprintf("test_%d", time.Now().UnixNano())
+	admin, err := pgxpool.New(ctx, url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(admin.Close)
+	if _, err := admin.Exec(ctx, "CREATE SCHEMA "+schema); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if _, err := admin.Exec(context.Background(), "DROP SCHEMA "+schema+" CASCADE"); err != nil {
+			t.Error(err)
+		}
+	})
+
+	cfg, err := pgxpool.ParseConfig(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Расширения (pg_trgm) остаются в public
+	cfg.ConnConfig.RuntimeParams["search_path"] = schema + ",public"
+	db, err := pgxpool.NewWithConfig(ctx, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(db.Close)
+
+	if err := storage.CheckAndMigrate(db); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// insertID выполняет INSERT ... RETURNING id
+func insertID(t *testing.T, db *pgxpool.Pool, query string, args ...any) int {
+	t.Helper()
+	var id int
+	if err := db.QueryRow(context.Background(), query, args...).Scan(&id); err != nil {
+		t.Fatalf("%s: %v", query, err)
+	}
+	return id
+}
+
+func exists(t *testing.T, db *pgxpool.Pool, table string, id int) bool {
+	t.Helper()
+	var found bool
+	err := db.QueryRow(context.Background(), "SELECT EXISTS(SELECT 1 FROM "+table+" WHERE id = $1)", id).Scan(&found)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return found
+}
+
+func TestPurgeKeepsRowsWithDependents(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+	deleted := time.Now().Add(-48 * time.Hour)
+	before := time.Now().Add(-24 * time.Hour)
+
+	// Категория и специализация удалены, но услуга в них действует
+	usedSpec := insertID(t, db, `INSERT INTO specializations (name, deleted_at) VALUES ('used', $1) RETURNING id`, deleted)
+	usedCategory := insertID(t, db, `INSERT INTO service_categories (name, deleted_at) VALUES ('used', $1) RETURNING id`, deleted)
+	insertID(t, db, `INSERT INTO services (name, service_category_id, specialization_id) VALUES ('live', $1, $2) RETURNING id`, usedCategory, usedSpec)
+
+	// Специализация удалена, но действующий врач ей по-прежнему владеет
+	linkedSpec := insertID(t, db, `INSERT INTO specializations (name, deleted_at) VALUES ('linked', $1) RETURNING id`, deleted)
+	liveDoctor := insertID(t, db, `INSERT INTO doctors (fullname) VALUES ('live') RETURNING id`)
+	if _, err := db.Exec(ctx, `INSERT INTO doctor_specializations (doctor_id, specialization_id) VALUES ($1, $2)`, liveDoctor, linkedSpec); err != nil {
+		t.Fatal(err)
+	}
+
+	freeSpec := insertID(t, db, `INSERT INTO specializations (name, deleted_at) VALUES ('free', $1) RETURNING id`, deleted)
+	freeCategory := insertID(t, db, `INSERT INTO service_categories (name, deleted_at) VALUES ('free', $1) RETURNING id`, deleted)
+
+	// Удалённые врачи: с рецептом, с направлением, с отзывом и без ссылок
+	userID := insertID(t, db, `INSERT INTO users (username, email) VALUES ('patient', 'patient@example.com') RETURNING id`)
+	patientID := insertID(t, db, `INSERT INTO patient_profiles (last_name, first_name) VALUES ('Иванов', 'Иван') RETURNING id`)
+	doctorWith := func(name string) int {
+		return insertID(t, db, `INSERT INTO doctors (fullname, deleted_at) VALUES ($1, $2) RETURNING id`, name, deleted)
+	}
+	prescribing := doctorWith("prescribing")
+	insertID(t, db, `INSERT INTO prescriptions (patient_id, doctor_id, drug_name, dosage, frequency, duration)
+		VALUES ($1, $2, 'drug', '1', 'daily', '7 days') RETURNING id`, patientID, prescribing)
+	ordering := doctorWith("ordering")
+	insertID(t, db, `INSERT INTO lab_orders (patient_id, doctor_id, tests) VALUES ($1, $2, '[]') RETURNING id`, patientID, ordering)
+	reviewed := doctorWith("reviewed")
+	insertID(t, db, `INSERT INTO doctor_reviews (doctor_id, user_id, rating, text) VALUES ($1, $2, 5, 'good') RETURNING id`, reviewed, userID)
+	unreferenced := doctorWith("unreferenced")
+
+	// Порядок как в воркере: сначала зависимые таблицы
+	purgers := []struct {
+		name   string
+		purger interface {
+			Purge(ctx context.Context, before time.Time) (int64, error)
+		}
+	}{
+		{"services", NewServiceRepository(db)},
+		{"doctors", NewDoctorRepository(db)},
+		{"service_categories", NewServiceCategoryRepository(db)},
+		{"specializations", NewSpecializationRepository(db)},
+	}
+	for _, p := range purgers {
+		if _, err := p.purger.Purge(ctx, before); err != nil {
+			t.Fatalf("%s: %v", p.name, err)
+		}
+	}
+
+	for _, tc := range []struct {
+		table string
+		id    int
+		kept  bool
+	}{
+		{"specializations", usedSpec, true},
+		{"service_categories", usedCategory, true},
+		{"specializations", linkedSpec, true},
+		{"specializations", freeSpec, false},
+		{"service_categories", freeCategory, false},
+		{"doctors", prescribing, true},
+		{"doctors", ordering, true},
+		{"doctors", reviewed, true},
+		{"doctors", unreferenced, false},
+		{"doctors", liveDoctor, true},
+	} {
+		if got := exists(t, db, tc.table, tc.id); got != tc.kept {
+			t.Errorf("%s %d: kept = %v, want %v", tc.table, tc.id, got, tc.kept)
+		}
+	}
+
+	var reviews int
+	if err := db.QueryRow(ctx, `SELECT count(*) FROM doctor_reviews WHERE doctor_id = $1`, reviewed).Scan(&reviews); err != nil {
+		t.Fatal(err)
+	}
+	if reviews != 1 {
+		t.Errorf("reviews of a kept doctor: %d, want 1", reviews)
+	}
+}
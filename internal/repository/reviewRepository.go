@@ -0,0 +1,236 @@
+package repository
+
+import (
+	"Clinic_backend/internal/entity"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	// ErrReviewNotFound - отзыва нет
+	ErrReviewNotFound = errors.New("review not found")
+	// ErrReviewExists - пользователь уже оставил отзыв об этом враче
+	ErrReviewExists = errors.New("you have already reviewed this doctor")
+)
+
+const reviewColumns = `r.id, r.doctor_id, r.user_id, u.username, r.rating, r.text, r.status, r.moderation_comment, r.moderated_by, r.moderated_at, r.version, r.created_at, r.updated_at`
+
+// refreshDoctorRatingsQuery пересчитывает рейтинг врачей по одобренным отзывам
+const refreshDoctorRatingsQuery = `
+	UPDATE doctors d
+	SET rating_avg = s.average, rating_count = s.total
+	FROM (
+		SELECT d.id, round(avg(r.rating), 2) AS average, count(r.id) AS total
+		FROM doctors d
+		LEFT JOIN doctor_reviews r ON r.doctor_id = d.id AND r.status = 'approved'
+		WHERE d.id = ANY($1)
+		GROUP BY d.id
+	) s
+	WHERE d.id = s.id`
+
+type ReviewRepositoryInterface interface {
+	Create(ctx context.Context, review *entity.DoctorReview) (*entity.DoctorReview, error)
+	GetByID(ctx context.Context, id int) (*entity.DoctorReview, error)
+	Lock(ctx context.Context, id int) (*entity.DoctorReview, error)
+	List(ctx context.Context, filter *entity.DoctorReviewFilter) ([]entity.DoctorReview, int, error)
+	Update(ctx context.Context, id int, req *entity.DoctorReviewRequest) (*entity.DoctorReview, error)
+	Moderate(ctx context.Context, id int, status string, comment *string, moderatedBy int) (*entity.DoctorReview, error)
+	Delete(ctx context.Context, id int) error
+	RefreshDoctorRating(ctx context.Context, doctorID int) error
+}
+
+type ReviewRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewReviewRepository(db *pgxpool.Pool) ReviewRepositoryInterface {
+	return &ReviewRepository{db: db}
+}
+
+// Create сохраняет отзыв на модерацию
+func (r *ReviewRepository) Create(ctx context.Context, review *entity.DoctorReview) (*entity.DoctorReview, error) {
+	query := `
+		INSERT INTO doctor_reviews (doctor_id, user_id, rating, text)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id`
+
+	var id int
+	err := getQuerier(ctx, r.db).QueryRow(ctx, query, review.DoctorID, review.UserID, review.Rating, review.Text).Scan(&id)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrReviewExists
+		}
+		return nil, fmt.Errorf("failed to create review: %w", err)
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+func (r *ReviewRepository) GetByID(ctx context.Context, id int) (*entity.DoctorReview, error) {
+	return r.get(ctx, `SELECT `+reviewColumns+` FROM doctor_reviews r JOIN users u ON u.id = r.user_id WHERE r.id = $1`, id)
+}
+
+// Lock загружает отзыв с блокировкой строки до конца транзакции
+func (r *ReviewRepository) Lock(ctx context.Context, id int) (*entity.DoctorReview, error) {
+	return r.get(ctx, `SELECT `+reviewColumns+` FROM doctor_reviews r JOIN users u ON u.id = r.user_id WHERE r.id = $1 FOR UPDATE OF r`, id)
+}
+
+func (r *ReviewRepository) get(ctx context.Context, query string, id int) (*entity.DoctorReview, error) {
+	review, err := scanReview(getQuerier(ctx, r.db).QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrReviewNotFound
+		}
+		return nil, fmt.Errorf("failed to get review: %w", err)
+	}
+	return review, nil
+}
+
+// List возвращает отзывы, новые первыми. Очередь модерации отдаётся в порядке поступления
+func (r *ReviewRepository) List(ctx context.Context, filter *entity.DoctorReviewFilter) ([]entity.DoctorReview, int, error) {
+	var conditions []string
+	var args []any
+
+	add := func(condition string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.DoctorID != nil {
+		add("r.doctor_id = $%d", *filter.DoctorID)
+	}
+	if filter.UserID != nil {
+		add("r.user_id = $%d", *filter.UserID)
+	}
+	if filter.Status != "" {
+		add("r.status = $%d", filter.Status)
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	countQuery := `SELECT count(*) FROM doctor_reviews r` + where
+	if err := getQuerier(ctx, r.db).QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count reviews: %w", err)
+	}
+
+	order := "r.created_at DESC, r.id DESC"
+	if filter.Status == entity.ReviewStatusPending {
+		order = "r.created_at, r.id"
+	}
+
+	args = append(args, filter.Limit, (filter.Page-1)*filter.Limit)
+	query := `SELECT ` + reviewColumns + ` FROM doctor_reviews r JOIN users u ON u.id = r.user_id` + where + `
+		ORDER BY ` + order +
+		fmt.Sprintf(` LIMIT $%d OFFSET $%d`, len(args)-1, len(args))
+
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query reviews: %w", err)
+	}
+	defer rows.Close()
+
+	var reviews []entity.DoctorReview
+	for rows.Next() {
+		review, err := scanReview(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan review: %w", err)
+		}
+		reviews = append(reviews, *review)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return reviews, total, nil
+}
+
+// Update меняет текст и оценку отзыва, заблокированного через Lock, и возвращает его на модерацию
+func (r *ReviewRepository) Update(ctx context.Context, id int, req *entity.DoctorReviewRequest) (*entity.DoctorReview, error) {
+	query := `
+		UPDATE doctor_reviews
+		SET rating = $2, text = $3, status = 'pending',
+		    moderation_comment = NULL, moderated_by = NULL, moderated_at = NULL,
+		    updated_at = CURRENT_TIMESTAMP, version = version + 1
+		WHERE id = $1`
+
+	tag, err := getQuerier(ctx, r.db).Exec(ctx, query, id, req.Rating, req.Text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update review: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, ErrReviewNotFound
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+// Moderate фиксирует решение модератора по отзыву, заблокированному через Lock
+func (r *ReviewRepository) Moderate(ctx context.Context, id int, status string, comment *string, moderatedBy int) (*entity.DoctorReview, error) {
+	query := `
+		UPDATE doctor_reviews
+		SET status = $2, moderation_comment = $3, moderated_by = $4, moderated_at = now(),
+		    updated_at = CURRENT_TIMESTAMP, version = version + 1
+		WHERE id = $1`
+
+	tag, err := getQuerier(ctx, r.db).Exec(ctx, query, id, status, comment, moderatedBy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to moderate review: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, ErrReviewNotFound
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+func (r *ReviewRepository) Delete(ctx context.Context, id int) error {
+	tag, err := getQuerier(ctx, r.db).Exec(ctx, `DELETE FROM doctor_reviews WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete review: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrReviewNotFound
+	}
+	return nil
+}
+
+// RefreshDoctorRating пересчитывает рейтинг врача. Версия записи врача не меняется:
+// рейтинг не редактируется администратором и не должен ломать его If-Match
+func (r *ReviewRepository) RefreshDoctorRating(ctx context.Context, doctorID int) error {
+	if _, err := getQuerier(ctx, r.db).Exec(ctx, refreshDoctorRatingsQuery, []int{doctorID}); err != nil {
+		return fmt.Errorf("failed to refresh doctor rating: %w", err)
+	}
+	return nil
+}
+
+func scanReview(row pgx.Row) (*entity.DoctorReview, error) {
+	var review entity.DoctorReview
+	err := row.Scan(
+		&review.ID,
+		&review.DoctorID,
+		&review.UserID,
+		&review.AuthorName,
+		&review.Rating,
+		&review.Text,
+		&review.Status,
+		&review.ModerationComment,
+		&review.ModeratedBy,
+		&review.ModeratedAt,
+		&review.Version,
+		&review.CreatedAt,
+		&review.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
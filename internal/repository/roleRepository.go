@@ -27,7 +27,7 @@ func NewRoleRepository(db *pgxpool.Pool) RoleRepositoryInterface {
 func (r *RoleRepository) GetAll(ctx context.Context) ([]entity.Role, error) {
 	query := `SELECT id, name, created_at, updated_at FROM roles ORDER BY id`
 
-	rows, err := r.db.Query(ctx, query)
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query roles: %w", err)
 	}
@@ -50,7 +50,7 @@ func (r *RoleRepository) GetByID(ctx context.Context, id int) (*entity.Role, err
 	query := `SELECT id, name, created_at, updated_at FROM roles WHERE id = $1`
 
 	var role entity.Role
-	err := r.db.QueryRow(ctx, query, id).Scan(
+	err := getQuerier(ctx, r.db).QueryRow(ctx, query, id).Scan(
 		&role.ID, &role.Name, &role.CreatedAt, &role.UpdatedAt,
 	)
 
@@ -68,7 +68,7 @@ func (r *RoleRepository) GetByName(ctx context.Context, name string) (*entity.Ro
 	query := `SELECT id, name, created_at, updated_at FROM roles WHERE name = $1`
 
 	var role entity.Role
-	err := r.db.QueryRow(ctx, query, name).Scan(
+	err := getQuerier(ctx, r.db).QueryRow(ctx, query, name).Scan(
 		&role.ID, &role.Name, &role.CreatedAt, &role.UpdatedAt,
 	)
 
@@ -14,6 +14,7 @@ type ScheduleRepositoryInterface interface {
 	Create(ctx context.Context, schedule *entity.Schedule) (*entity.Schedule, error)
 	GetAll(ctx context.Context) ([]entity.Schedule, error)
 	GetByID(ctx context.Context, id int) (*entity.Schedule, error)
+	GetByIDs(ctx context.Context, ids []int) (map[int]*entity.Schedule, error)
 	GetByDay(ctx context.Context, day int) ([]entity.Schedule, error)
 	Update(ctx context.Context, id int, schedule *entity.Schedule) (*entity.Schedule, error)
 	Delete(ctx context.Context, id int) error
@@ -35,7 +36,7 @@ func (r *ScheduleRepository) Create(ctx context.Context, schedule *entity.Schedu
 	`
 
 	var created entity.Schedule
-	err := r.db.QueryRow(ctx, query, schedule.Day, schedule.TimeFrom, schedule.TimeTo).Scan(
+	err := getQuerier(ctx, r.db).QueryRow(ctx, query, schedule.Day, schedule.TimeFrom, schedule.TimeTo).Scan(
 		&created.ID, &created.Day, &created.TimeFrom, &created.TimeTo,
 		&created.CreatedAt, &created.UpdatedAt,
 	)
@@ -54,7 +55,7 @@ func (r *ScheduleRepository) GetAll(ctx context.Context) ([]entity.Schedule, err
 		ORDER BY day, time_from
 	`
 
-	rows, err := r.db.Query(ctx, query)
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query schedules: %w", err)
 	}
@@ -84,7 +85,7 @@ func (r *ScheduleRepository) GetByID(ctx context.Context, id int) (*entity.Sched
 	`
 
 	var schedule entity.Schedule
-	err := r.db.QueryRow(ctx, query, id).Scan(
+	err := getQuerier(ctx, r.db).QueryRow(ctx, query, id).Scan(
 		//&schedule.ID,
 		&schedule.Day,
 		&schedule.TimeFrom,
@@ -103,6 +104,46 @@ func (r *ScheduleRepository) GetByID(ctx context.Context, id int) (*entity.Sched
 	return &schedule, nil
 }
 
+// GetByIDs загружает набор расписаний одним запросом
+func (r *ScheduleRepository) GetByIDs(ctx context.Context, ids []int) (map[int]*entity.Schedule, error) {
+	result := make(map[int]*entity.Schedule, len(ids))
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	query := `
+		SELECT id, day, time_from, time_to
+		FROM schedules
+		WHERE id = ANY($1)
+	`
+
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query schedules by ids: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var schedule entity.Schedule
+		err := rows.Scan(
+			&schedule.ID,
+			&schedule.Day,
+			&schedule.TimeFrom,
+			&schedule.TimeTo,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan schedule: %w", err)
+		}
+		result[schedule.ID] = &schedule
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return result, nil
+}
+
 func (r *ScheduleRepository) GetByDay(ctx context.Context, day int) ([]entity.Schedule, error) {
 	query := `
 		SELECT day, time_from, time_to
@@ -111,7 +152,7 @@ func (r *ScheduleRepository) GetByDay(ctx context.Context, day int) ([]entity.Sc
 		ORDER BY time_from
 	`
 
-	rows, err := r.db.Query(ctx, query, day)
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query, day)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query schedules by day: %w", err)
 	}
@@ -145,7 +186,7 @@ func (r *ScheduleRepository) Update(ctx context.Context, id int, schedule *entit
 	`
 
 	var updated entity.Schedule
-	err := r.db.QueryRow(ctx, query,
+	err := getQuerier(ctx, r.db).QueryRow(ctx, query,
 		schedule.Day, schedule.TimeFrom, schedule.TimeTo, id,
 	).Scan(
 		//&updated.ID,
@@ -165,7 +206,7 @@ func (r *ScheduleRepository) Update(ctx context.Context, id int, schedule *entit
 
 func (r *ScheduleRepository) Delete(ctx context.Context, id int) error {
 	query := `DELETE FROM schedules WHERE id = $1`
-	_, err := r.db.Exec(ctx, query, id)
+	_, err := getQuerier(ctx, r.db).Exec(ctx, query, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete schedule: %w", err)
 	}
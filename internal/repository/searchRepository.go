@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"Clinic_backend/internal/entity"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Минимальное сходство слова запроса с названием для поиска с опечатками.
+// По умолчанию в pg_trgm 0.6 - слишком строго для коротких русских слов.
+const searchSimilarityThreshold = "0.4"
+
+// Маркеры выделения совпадений в ts_headline - символы из области частного использования
+// Юникода. Из исходного текста они удаляются, поэтому никакая разметка в нём (например,
+// "<mark>" или "<b>") не сойдёт за выделение. Сервис экранирует текст и заменяет маркеры тегами.
+const (
+	HighlightStart = "\uE000"
+	HighlightStop  = "\uE001"
+)
+
+const searchHeadlineOptions = `StartSel="` + HighlightStart + `", StopSel="` + HighlightStop + `"`
+
+type SearchRepositoryInterface interface {
+	Search(ctx context.Context, query *entity.SearchQuery) ([]entity.SearchResult, error)
+}
+
+type SearchRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewSearchRepository(db *pgxpool.Pool) SearchRepositoryInterface {
+	return &SearchRepository{db: db}
+}
+
+// Search ищет по полнотекстовому индексу (по началу слов) и по триграммам названий.
+// Вызывается в транзакции: порог сходства триграмм задаётся только на её время.
+func (r *SearchRepository) Search(ctx context.Context, query *entity.SearchQuery) ([]entity.SearchResult, error) {
+	q := getQuerier(ctx, r.db)
+
+	if _, err := q.Exec(ctx, `SELECT set_config('pg_trgm.word_similarity_threshold', $1, true)`, searchSimilarityThreshold); err != nil {
+		return nil, fmt.Errorf("failed to configure search: %w", err)
+	}
+
+	sql := `
+		WITH q AS (
+			SELECT to_tsquery('russian', $1) || to_tsquery('english', $1) AS query, $2::text AS raw
+		),
+		hits AS (
+			SELECT 'doctor' AS type, d.id, d.fullname AS title, d.description AS body,
+			       ts_rank_cd(d.search_vector, q.query, 32) + 0.5 * word_similarity(q.raw, d.fullname) AS rank
+			FROM doctors d, q
+			WHERE d.deleted_at IS NULL AND (d.search_vector @@ q.query OR q.raw <% d.fullname)
+			UNION ALL
+			SELECT 'service', s.id, s.name, s.description,
+			       ts_rank_cd(s.search_vector, q.query, 32) + 0.5 * word_similarity(q.raw, s.name)
+			FROM services s, q
+			WHERE s.deleted_at IS NULL AND (s.search_vector @@ q.query OR q.raw <% s.name)
+			UNION ALL
+			SELECT 'service_category', c.id, c.name, c.description,
+			       ts_rank_cd(c.search_vector, q.query, 32) + 0.5 * word_similarity(q.raw, c.name)
+			FROM service_categories c, q
+			WHERE c.deleted_at IS NULL AND (c.search_vector @@ q.query OR q.raw <% c.name)
+			UNION ALL
+			SELECT 'specialization', sp.id, sp.name, NULL,
+			       ts_rank_cd(sp.search_vector, q.query, 32) + 0.5 * word_similarity(q.raw, sp.name)
+			FROM specializations sp, q
+			WHERE sp.deleted_at IS NULL AND (sp.search_vector @@ q.query OR q.raw <% sp.name)
+		),
+		top AS (
+			SELECT * FROM hits
+			WHERE type = ANY($3)
+			ORDER BY rank DESC, type, id
+			LIMIT $4
+		)
+		SELECT top.type, top.id,
+		       ts_headline('russian', translate(top.title, $5, ''), q.query, '` + searchHeadlineOptions + `, HighlightAll=true'),
+		       COALESCE(ts_headline('russian', translate(top.body, $5, ''), q.query, '` + searchHeadlineOptions + `, MinWords=15, MaxWords=35'), ''),
+		       top.rank::float8
+		FROM top, q
+		ORDER BY top.rank DESC, top.type, top.id
+	`
+
+	rows, err := q.Query(ctx, sql, prefixTSQuery(query.Terms), strings.Join(query.Terms, " "), query.Types, query.Limit, HighlightStart+HighlightStop)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search: %w", err)
+	}
+	defer rows.Close()
+
+	results := []entity.SearchResult{}
+	for rows.Next() {
+		var result entity.SearchResult
+		if err := rows.Scan(&result.Type, &result.ID, &result.Title, &result.Snippet, &result.Rank); err != nil {
+			return nil, fmt.Errorf("failed to scan search result: %w", err)
+		}
+		results = append(results, result)
+	}
+
+	return results, rows.Err()
+}
+
+// tsqueryEscaper экранирует слово для записи в кавычках внутри tsquery
+var tsqueryEscaper = strings.NewReplacer(`\`, `\\`, `'`, `''`)
+
+// prefixTSQuery собирает tsquery, где каждое слово - префикс и все слова обязательны.
+// Сервис оставляет в словах только буквы и цифры, но слова всё равно берутся в кавычки:
+// операторы tsquery внутри них остаются текстом.
+func prefixTSQuery(terms []string) string {
+	prefixes := make([]string, len(terms))
+	for i, term := range terms {
+		prefixes[i] = `'` + tsqueryEscaper.Replace(term) + `':*`
+	}
+	return strings.Join(prefixes, " & ")
+}
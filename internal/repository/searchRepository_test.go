@@ -0,0 +1,21 @@
+package repository
+
+import "testing"
+
+func TestPrefixTSQuery(t *testing.T) {
+	tests := []struct {
+		terms []string
+		want  string
+	}{
+		{[]string{"кардио"}, `'кардио':*`},
+		{[]string{"узи", "сердца"}, `'узи':* & 'сердца':*`},
+		{[]string{"o'brien"}, `'o''brien':*`},
+		{[]string{`a\b`}, `'a\\b':*`},
+		{[]string{"<b>", "x|y", "!z"}, `'<b>':* & 'x|y':* & '!z':*`},
+	}
+	for _, tt := range tests {
+		if got := prefixTSQuery(tt.terms); got != tt.want {
+			t.Errorf("prefixTSQuery(%q) = %s, want %s", tt.terms, got, tt.want)
+		}
+	}
+}
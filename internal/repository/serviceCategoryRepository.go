@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -12,12 +13,14 @@ import (
 
 type ServiceCategoryRepositoryInterface interface {
 	Create(ctx context.Context, category *entity.ServiceCategory) (*entity.ServiceCategory, error)
-	GetAll(ctx context.Context) ([]entity.ServiceCategory, error)
+	GetAll(ctx context.Context, includeDeleted bool) ([]entity.ServiceCategory, error)
 	GetByID(ctx context.Context, id int) (*entity.ServiceCategory, error)
 	GetFavorites(ctx context.Context) ([]entity.ServiceCategory, error)
 	Update(ctx context.Context, id int, category *entity.ServiceCategory) (*entity.ServiceCategory, error)
-	Delete(ctx context.Context, id int) error
-	SetFavorite(ctx context.Context, id int, favorite bool) error
+	Delete(ctx context.Context, id int, version int) error
+	Restore(ctx context.Context, id int) error
+	Purge(ctx context.Context, before time.Time) (int64, error)
+	SetFavorite(ctx context.Context, id int, favorite bool, version int) error
 }
 
 type ServiceCategoryRepository struct {
@@ -30,16 +33,17 @@ func NewServiceCategoryRepository(db *pgxpool.Pool) ServiceCategoryRepositoryInt
 
 func (r *ServiceCategoryRepository) Create(ctx context.Context, category *entity.ServiceCategory) (*entity.ServiceCategory, error) {
 	query := `
-		INSERT INTO service_categories (name, description, category_photo, favorite, specialization_id)
-		VALUES ($1, $2, $3, $4, $5)
-		RETURNING id, name, description, category_photo, favorite, specialization_id, created_at, updated_at
+		INSERT INTO service_categories (name, description, category_photo, category_photo_id, favorite, specialization_id)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, name, description, category_photo, category_photo_id, favorite, specialization_id, created_at, updated_at, version
 	`
 
 	var created entity.ServiceCategory
-	err := r.db.QueryRow(ctx, query,
+	err := getQuerier(ctx, r.db).QueryRow(ctx, query,
 		category.Name,
 		category.Description,
 		category.CategoryPhoto,
+		category.CategoryPhotoID,
 		category.Favorite,
 		category.SpecializationID,
 	).Scan(
@@ -47,10 +51,12 @@ func (r *ServiceCategoryRepository) Create(ctx context.Context, category *entity
 		&created.Name,
 		&created.Description,
 		&created.CategoryPhoto,
+		&created.CategoryPhotoID,
 		&created.Favorite,
 		&created.SpecializationID,
 		&created.CreatedAt,
 		&created.UpdatedAt,
+		&created.Version,
 	)
 
 	if err != nil {
@@ -60,14 +66,15 @@ func (r *ServiceCategoryRepository) Create(ctx context.Context, category *entity
 	return &created, nil
 }
 
-func (r *ServiceCategoryRepository) GetAll(ctx context.Context) ([]entity.ServiceCategory, error) {
+func (r *ServiceCategoryRepository) GetAll(ctx context.Context, includeDeleted bool) ([]entity.ServiceCategory, error) {
 	query := `
-		SELECT id, name, description, category_photo, favorite, specialization_id, created_at, updated_at
+		SELECT id, name, description, category_photo, category_photo_id, favorite, specialization_id, created_at, updated_at, version, deleted_at
 		FROM service_categories
+		WHERE $1 OR deleted_at IS NULL
 		ORDER BY id
 	`
 
-	rows, err := r.db.Query(ctx, query)
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query, includeDeleted)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query service categories: %w", err)
 	}
@@ -81,10 +88,13 @@ func (r *ServiceCategoryRepository) GetAll(ctx context.Context) ([]entity.Servic
 			&cat.Name,
 			&cat.Description,
 			&cat.CategoryPhoto,
+			&cat.CategoryPhotoID,
 			&cat.Favorite,
 			&cat.SpecializationID,
 			&cat.CreatedAt,
 			&cat.UpdatedAt,
+			&cat.Version,
+			&cat.DeletedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan service category: %w", err)
@@ -97,21 +107,23 @@ func (r *ServiceCategoryRepository) GetAll(ctx context.Context) ([]entity.Servic
 
 func (r *ServiceCategoryRepository) GetByID(ctx context.Context, id int) (*entity.ServiceCategory, error) {
 	query := `
-		SELECT id, name, description, category_photo, favorite, specialization_id, created_at, updated_at
+		SELECT id, name, description, category_photo, category_photo_id, favorite, specialization_id, created_at, updated_at, version
 		FROM service_categories
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 
 	var cat entity.ServiceCategory
-	err := r.db.QueryRow(ctx, query, id).Scan(
+	err := getQuerier(ctx, r.db).QueryRow(ctx, query, id).Scan(
 		&cat.ID,
 		&cat.Name,
 		&cat.Description,
 		&cat.CategoryPhoto,
+		&cat.CategoryPhotoID,
 		&cat.Favorite,
 		&cat.SpecializationID,
 		&cat.CreatedAt,
 		&cat.UpdatedAt,
+		&cat.Version,
 	)
 
 	if err != nil {
@@ -126,13 +138,13 @@ func (r *ServiceCategoryRepository) GetByID(ctx context.Context, id int) (*entit
 
 func (r *ServiceCategoryRepository) GetFavorites(ctx context.Context) ([]entity.ServiceCategory, error) {
 	query := `
-		SELECT id, name, description, category_photo, favorite, specialization_id, created_at, updated_at
+		SELECT id, name, description, category_photo, category_photo_id, favorite, specialization_id, created_at, updated_at, version
 		FROM service_categories
-		WHERE favorite = true
+		WHERE favorite = true AND deleted_at IS NULL
 		ORDER BY id
 	`
 
-	rows, err := r.db.Query(ctx, query)
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query favorite categories: %w", err)
 	}
@@ -146,10 +158,12 @@ func (r *ServiceCategoryRepository) GetFavorites(ctx context.Context) ([]entity.
 			&cat.Name,
 			&cat.Description,
 			&cat.CategoryPhoto,
+			&cat.CategoryPhotoID,
 			&cat.Favorite,
 			&cat.SpecializationID,
 			&cat.CreatedAt,
 			&cat.UpdatedAt,
+			&cat.Version,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan service category: %w", err)
@@ -163,56 +177,100 @@ func (r *ServiceCategoryRepository) GetFavorites(ctx context.Context) ([]entity.
 func (r *ServiceCategoryRepository) Update(ctx context.Context, id int, category *entity.ServiceCategory) (*entity.ServiceCategory, error) {
 	query := `
 		UPDATE service_categories
-		SET name = $1, description = $2, category_photo = $3, favorite = $4, 
-		    specialization_id = $5, updated_at = CURRENT_TIMESTAMP
-		WHERE id = $6
-		RETURNING id, name, description, category_photo, favorite, specialization_id, created_at, updated_at
+		SET name = $1, description = $2, category_photo = $3, category_photo_id = $4, favorite = $5,
+		    specialization_id = $6, updated_at = CURRENT_TIMESTAMP, version = version + 1
+		WHERE id = $7 AND deleted_at IS NULL AND $8 IN (0, version)
+		RETURNING id, name, description, category_photo, category_photo_id, favorite, specialization_id, created_at, updated_at, version
 	`
 
 	var updated entity.ServiceCategory
-	err := r.db.QueryRow(ctx, query,
+	err := getQuerier(ctx, r.db).QueryRow(ctx, query,
 		category.Name,
 		category.Description,
 		category.CategoryPhoto,
+		category.CategoryPhotoID,
 		category.Favorite,
 		category.SpecializationID,
 		id,
+		category.Version,
 	).Scan(
 		&updated.ID,
 		&updated.Name,
 		&updated.Description,
 		&updated.CategoryPhoto,
+		&updated.CategoryPhotoID,
 		&updated.Favorite,
 		&updated.SpecializationID,
 		&updated.CreatedAt,
 		&updated.UpdatedAt,
+		&updated.Version,
 	)
 
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, resolveNoRows(ctx, getQuerier(ctx, r.db), "service_categories", id, errors.New("service category not found"))
+		}
 		return nil, fmt.Errorf("failed to update service category: %w", err)
 	}
 
 	return &updated, nil
 }
 
-func (r *ServiceCategoryRepository) Delete(ctx context.Context, id int) error {
-	query := `DELETE FROM service_categories WHERE id = $1`
-	_, err := r.db.Exec(ctx, query, id)
+func (r *ServiceCategoryRepository) Delete(ctx context.Context, id int, version int) error {
+	query := `
+		UPDATE service_categories
+		SET deleted_at = CURRENT_TIMESTAMP, version = version + 1
+		WHERE id = $1 AND deleted_at IS NULL AND $2 IN (0, version)
+	`
+	tag, err := getQuerier(ctx, r.db).Exec(ctx, query, id, version)
 	if err != nil {
 		return fmt.Errorf("failed to delete service category: %w", err)
 	}
+	if tag.RowsAffected() == 0 {
+		return resolveNoRows(ctx, getQuerier(ctx, r.db), "service_categories", id, errors.New("service category not found"))
+	}
 	return nil
 }
 
-func (r *ServiceCategoryRepository) SetFavorite(ctx context.Context, id int, favorite bool) error {
+func (r *ServiceCategoryRepository) SetFavorite(ctx context.Context, id int, favorite bool, version int) error {
 	query := `
 		UPDATE service_categories 
-		SET favorite = $1, updated_at = CURRENT_TIMESTAMP 
-		WHERE id = $2
+		SET favorite = $1, updated_at = CURRENT_TIMESTAMP, version = version + 1
+		WHERE id = $2 AND deleted_at IS NULL AND $3 IN (0, version)
 	`
-	_, err := r.db.Exec(ctx, query, favorite, id)
+	tag, err := getQuerier(ctx, r.db).Exec(ctx, query, favorite, id, version)
 	if err != nil {
 		return fmt.Errorf("failed to set favorite: %w", err)
 	}
+	if tag.RowsAffected() == 0 {
+		return resolveNoRows(ctx, getQuerier(ctx, r.db), "service_categories", id, errors.New("service category not found"))
+	}
 	return nil
 }
+
+func (r *ServiceCategoryRepository) Restore(ctx context.Context, id int) error {
+	query := `UPDATE service_categories SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL`
+	tag, err := getQuerier(ctx, r.db).Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to restore service category: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.New("deleted service category not found")
+	}
+	return nil
+}
+
+// Purge окончательно удаляет записи, помеченные удалёнными раньше before.
+// Категории, в которых ещё есть услуги, остаются.
+func (r *ServiceCategoryRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	query := `
+		DELETE FROM service_categories
+		WHERE deleted_at < $1
+		  AND NOT EXISTS (SELECT 1 FROM services s WHERE s.service_category_id = service_categories.id)
+	`
+	tag, err := getQuerier(ctx, r.db).Exec(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge service categories: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -12,12 +13,14 @@ import (
 
 type ServiceRepositoryInterface interface {
 	Create(ctx context.Context, service *entity.Service) (*entity.Service, error)
-	GetAll(ctx context.Context) ([]entity.Service, error)
+	GetAll(ctx context.Context, includeDeleted bool) ([]entity.Service, error)
 	GetByID(ctx context.Context, id int) (*entity.Service, error)
 	GetByCategory(ctx context.Context, categoryID int) ([]entity.Service, error)
 	GetBySpecialization(ctx context.Context, specializationID int) ([]entity.Service, error)
 	Update(ctx context.Context, id int, service *entity.Service) (*entity.Service, error)
-	Delete(ctx context.Context, id int) error
+	Delete(ctx context.Context, id int, version int) error
+	Restore(ctx context.Context, id int) error
+	Purge(ctx context.Context, before time.Time) (int64, error)
 }
 
 type ServiceRepository struct {
@@ -30,16 +33,17 @@ func NewServiceRepository(db *pgxpool.Pool) ServiceRepositoryInterface {
 
 func (r *ServiceRepository) Create(ctx context.Context, service *entity.Service) (*entity.Service, error) {
 	query := `
-		INSERT INTO services (name, description, specific_photo, price, service_category_id, specialization_id)
-		VALUES ($1, $2, $3, $4, $5, $6)
-		RETURNING id, name, description, specific_photo, price, service_category_id, specialization_id, created_at, updated_at
+		INSERT INTO services (name, description, specific_photo, specific_photo_id, price, service_category_id, specialization_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, name, description, specific_photo, specific_photo_id, price, service_category_id, specialization_id, created_at, updated_at, version
 	`
 
 	var created entity.Service
-	err := r.db.QueryRow(ctx, query,
+	err := getQuerier(ctx, r.db).QueryRow(ctx, query,
 		service.Name,
 		service.Description,
 		service.SpecificPhoto,
+		service.SpecificPhotoID,
 		service.Price,
 		service.ServiceCategoryID,
 		service.SpecializationID,
@@ -48,11 +52,13 @@ func (r *ServiceRepository) Create(ctx context.Context, service *entity.Service)
 		&created.Name,
 		&created.Description,
 		&created.SpecificPhoto,
+		&created.SpecificPhotoID,
 		&created.Price,
 		&created.ServiceCategoryID,
 		&created.SpecializationID,
 		&created.CreatedAt,
 		&created.UpdatedAt,
+		&created.Version,
 	)
 
 	if err != nil {
@@ -62,14 +68,15 @@ func (r *ServiceRepository) Create(ctx context.Context, service *entity.Service)
 	return &created, nil
 }
 
-func (r *ServiceRepository) GetAll(ctx context.Context) ([]entity.Service, error) {
+func (r *ServiceRepository) GetAll(ctx context.Context, includeDeleted bool) ([]entity.Service, error) {
 	query := `
-		SELECT id, name, description, specific_photo, price, service_category_id, specialization_id, created_at, updated_at
+		SELECT id, name, description, specific_photo, specific_photo_id, price, service_category_id, specialization_id, created_at, updated_at, version, deleted_at
 		FROM services
+		WHERE $1 OR deleted_at IS NULL
 		ORDER BY id
 	`
 
-	rows, err := r.db.Query(ctx, query)
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query, includeDeleted)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query services: %w", err)
 	}
@@ -83,11 +90,14 @@ func (r *ServiceRepository) GetAll(ctx context.Context) ([]entity.Service, error
 			&service.Name,
 			&service.Description,
 			&service.SpecificPhoto,
+			&service.SpecificPhotoID,
 			&service.Price,
 			&service.ServiceCategoryID,
 			&service.SpecializationID,
 			&service.CreatedAt,
 			&service.UpdatedAt,
+			&service.Version,
+			&service.DeletedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan service: %w", err)
@@ -100,22 +110,24 @@ func (r *ServiceRepository) GetAll(ctx context.Context) ([]entity.Service, error
 
 func (r *ServiceRepository) GetByID(ctx context.Context, id int) (*entity.Service, error) {
 	query := `
-		SELECT id, name, description, specific_photo, price, service_category_id, specialization_id, created_at, updated_at
+		SELECT id, name, description, specific_photo, specific_photo_id, price, service_category_id, specialization_id, created_at, updated_at, version
 		FROM services
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 
 	var service entity.Service
-	err := r.db.QueryRow(ctx, query, id).Scan(
+	err := getQuerier(ctx, r.db).QueryRow(ctx, query, id).Scan(
 		&service.ID,
 		&service.Name,
 		&service.Description,
 		&service.SpecificPhoto,
+		&service.SpecificPhotoID,
 		&service.Price,
 		&service.ServiceCategoryID,
 		&service.SpecializationID,
 		&service.CreatedAt,
 		&service.UpdatedAt,
+		&service.Version,
 	)
 
 	if err != nil {
@@ -130,13 +142,13 @@ func (r *ServiceRepository) GetByID(ctx context.Context, id int) (*entity.Servic
 
 func (r *ServiceRepository) GetByCategory(ctx context.Context, categoryID int) ([]entity.Service, error) {
 	query := `
-		SELECT id, name, description, specific_photo, price, service_category_id, specialization_id, created_at, updated_at
+		SELECT id, name, description, specific_photo, specific_photo_id, price, service_category_id, specialization_id, created_at, updated_at, version
 		FROM services
-		WHERE service_category_id = $1
+		WHERE service_category_id = $1 AND deleted_at IS NULL
 		ORDER BY id
 	`
 
-	rows, err := r.db.Query(ctx, query, categoryID)
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query, categoryID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query services by category: %w", err)
 	}
@@ -150,11 +162,13 @@ func (r *ServiceRepository) GetByCategory(ctx context.Context, categoryID int) (
 			&service.Name,
 			&service.Description,
 			&service.SpecificPhoto,
+			&service.SpecificPhotoID,
 			&service.Price,
 			&service.ServiceCategoryID,
 			&service.SpecializationID,
 			&service.CreatedAt,
 			&service.UpdatedAt,
+			&service.Version,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan service: %w", err)
@@ -167,13 +181,13 @@ func (r *ServiceRepository) GetByCategory(ctx context.Context, categoryID int) (
 
 func (r *ServiceRepository) GetBySpecialization(ctx context.Context, specializationID int) ([]entity.Service, error) {
 	query := `
-		SELECT id, name, description, specific_photo, price, service_category_id, specialization_id, created_at, updated_at
+		SELECT id, name, description, specific_photo, specific_photo_id, price, service_category_id, specialization_id, created_at, updated_at, version
 		FROM services
-		WHERE specialization_id = $1
+		WHERE specialization_id = $1 AND deleted_at IS NULL
 		ORDER BY id
 	`
 
-	rows, err := r.db.Query(ctx, query, specializationID)
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query, specializationID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query services by specialization: %w", err)
 	}
@@ -187,11 +201,13 @@ func (r *ServiceRepository) GetBySpecialization(ctx context.Context, specializat
 			&service.Name,
 			&service.Description,
 			&service.SpecificPhoto,
+			&service.SpecificPhotoID,
 			&service.Price,
 			&service.ServiceCategoryID,
 			&service.SpecializationID,
 			&service.CreatedAt,
 			&service.UpdatedAt,
+			&service.Version,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan service: %w", err)
@@ -205,45 +221,82 @@ func (r *ServiceRepository) GetBySpecialization(ctx context.Context, specializat
 func (r *ServiceRepository) Update(ctx context.Context, id int, service *entity.Service) (*entity.Service, error) {
 	query := `
 		UPDATE services
-		SET name = $1, description = $2, specific_photo = $3, price = $4,
-		    service_category_id = $5, specialization_id = $6, updated_at = CURRENT_TIMESTAMP
-		WHERE id = $7
-		RETURNING id, name, description, specific_photo, price, service_category_id, specialization_id, created_at, updated_at
+		SET name = $1, description = $2, specific_photo = $3, specific_photo_id = $4, price = $5,
+		    service_category_id = $6, specialization_id = $7,
+		    updated_at = CURRENT_TIMESTAMP, version = version + 1
+		WHERE id = $8 AND deleted_at IS NULL AND $9 IN (0, version)
+		RETURNING id, name, description, specific_photo, specific_photo_id, price, service_category_id, specialization_id, created_at, updated_at, version
 	`
 
 	var updated entity.Service
-	err := r.db.QueryRow(ctx, query,
+	err := getQuerier(ctx, r.db).QueryRow(ctx, query,
 		service.Name,
 		service.Description,
 		service.SpecificPhoto,
+		service.SpecificPhotoID,
 		service.Price,
 		service.ServiceCategoryID,
 		service.SpecializationID,
 		id,
+		service.Version,
 	).Scan(
 		&updated.ID,
 		&updated.Name,
 		&updated.Description,
 		&updated.SpecificPhoto,
+		&updated.SpecificPhotoID,
 		&updated.Price,
 		&updated.ServiceCategoryID,
 		&updated.SpecializationID,
 		&updated.CreatedAt,
 		&updated.UpdatedAt,
+		&updated.Version,
 	)
 
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, resolveNoRows(ctx, getQuerier(ctx, r.db), "services", id, errors.New("service not found"))
+		}
 		return nil, fmt.Errorf("failed to update service: %w", err)
 	}
 
 	return &updated, nil
 }
 
-func (r *ServiceRepository) Delete(ctx context.Context, id int) error {
-	query := `DELETE FROM services WHERE id = $1`
-	_, err := r.db.Exec(ctx, query, id)
+func (r *ServiceRepository) Delete(ctx context.Context, id int, version int) error {
+	query := `
+		UPDATE services
+		SET deleted_at = CURRENT_TIMESTAMP, version = version + 1
+		WHERE id = $1 AND deleted_at IS NULL AND $2 IN (0, version)
+	`
+	tag, err := getQuerier(ctx, r.db).Exec(ctx, query, id, version)
 	if err != nil {
 		return fmt.Errorf("failed to delete service: %w", err)
 	}
+	if tag.RowsAffected() == 0 {
+		return resolveNoRows(ctx, getQuerier(ctx, r.db), "services", id, errors.New("service not found"))
+	}
+	return nil
+}
+
+func (r *ServiceRepository) Restore(ctx context.Context, id int) error {
+	query := `UPDATE services SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL`
+	tag, err := getQuerier(ctx, r.db).Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to restore service: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.New("deleted service not found")
+	}
 	return nil
 }
+
+// Purge окончательно удаляет записи, помеченные удалёнными раньше before
+func (r *ServiceRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	query := `DELETE FROM services WHERE deleted_at < $1`
+	tag, err := getQuerier(ctx, r.db).Exec(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge services: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
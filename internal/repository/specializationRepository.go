@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -12,10 +13,12 @@ import (
 
 type SpecializationRepositoryInterface interface {
 	Create(ctx context.Context, spec *entity.Specialization) (*entity.Specialization, error)
-	GetAll(ctx context.Context) ([]entity.Specialization, error)
+	GetAll(ctx context.Context, includeDeleted bool) ([]entity.Specialization, error)
 	GetByID(ctx context.Context, id int) (*entity.Specialization, error)
 	Update(ctx context.Context, id int, spec *entity.Specialization) (*entity.Specialization, error)
-	Delete(ctx context.Context, id int) error
+	Delete(ctx context.Context, id int, version int) error
+	Restore(ctx context.Context, id int) error
+	Purge(ctx context.Context, before time.Time) (int64, error)
 }
 
 type SpecializationRepository struct {
@@ -30,12 +33,12 @@ func (r *SpecializationRepository) Create(ctx context.Context, spec *entity.Spec
 	query := `
 		INSERT INTO specializations (name)
 		VALUES ($1)
-		RETURNING id, name, created_at, updated_at
+		RETURNING id, name, created_at, updated_at, version
 	`
 
 	var created entity.Specialization
-	err := r.db.QueryRow(ctx, query, spec.Name).Scan(
-		&created.ID, &created.Name, &created.CreatedAt, &created.UpdatedAt,
+	err := getQuerier(ctx, r.db).QueryRow(ctx, query, spec.Name).Scan(
+		&created.ID, &created.Name, &created.CreatedAt, &created.UpdatedAt, &created.Version,
 	)
 
 	if err != nil {
@@ -45,10 +48,10 @@ func (r *SpecializationRepository) Create(ctx context.Context, spec *entity.Spec
 	return &created, nil
 }
 
-func (r *SpecializationRepository) GetAll(ctx context.Context) ([]entity.Specialization, error) {
-	query := `SELECT id, name, created_at, updated_at FROM specializations ORDER BY id`
+func (r *SpecializationRepository) GetAll(ctx context.Context, includeDeleted bool) ([]entity.Specialization, error) {
+	query := `SELECT id, name, created_at, updated_at, version, deleted_at FROM specializations WHERE $1 OR deleted_at IS NULL ORDER BY id`
 
-	rows, err := r.db.Query(ctx, query)
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query, includeDeleted)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query specializations: %w", err)
 	}
@@ -57,7 +60,7 @@ func (r *SpecializationRepository) GetAll(ctx context.Context) ([]entity.Special
 	var specializations []entity.Specialization
 	for rows.Next() {
 		var spec entity.Specialization
-		err := rows.Scan(&spec.ID, &spec.Name, &spec.CreatedAt, &spec.UpdatedAt)
+		err := rows.Scan(&spec.ID, &spec.Name, &spec.CreatedAt, &spec.UpdatedAt, &spec.Version, &spec.DeletedAt)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan specialization: %w", err)
 		}
@@ -68,11 +71,11 @@ func (r *SpecializationRepository) GetAll(ctx context.Context) ([]entity.Special
 }
 
 func (r *SpecializationRepository) GetByID(ctx context.Context, id int) (*entity.Specialization, error) {
-	query := `SELECT id, name, created_at, updated_at FROM specializations WHERE id = $1`
+	query := `SELECT id, name, created_at, updated_at, version FROM specializations WHERE id = $1 AND deleted_at IS NULL`
 
 	var spec entity.Specialization
-	err := r.db.QueryRow(ctx, query, id).Scan(
-		&spec.ID, &spec.Name, &spec.CreatedAt, &spec.UpdatedAt,
+	err := getQuerier(ctx, r.db).QueryRow(ctx, query, id).Scan(
+		&spec.ID, &spec.Name, &spec.CreatedAt, &spec.UpdatedAt, &spec.Version,
 	)
 
 	if err != nil {
@@ -88,28 +91,72 @@ func (r *SpecializationRepository) GetByID(ctx context.Context, id int) (*entity
 func (r *SpecializationRepository) Update(ctx context.Context, id int, spec *entity.Specialization) (*entity.Specialization, error) {
 	query := `
 		UPDATE specializations
-		SET name = $1, updated_at = CURRENT_TIMESTAMP
-		WHERE id = $2
-		RETURNING id, name, created_at, updated_at
+		SET name = $1, updated_at = CURRENT_TIMESTAMP, version = version + 1
+		WHERE id = $2 AND deleted_at IS NULL AND $3 IN (0, version)
+		RETURNING id, name, created_at, updated_at, version
 	`
 
 	var updated entity.Specialization
-	err := r.db.QueryRow(ctx, query, spec.Name, id).Scan(
-		&updated.ID, &updated.Name, &updated.CreatedAt, &updated.UpdatedAt,
+	err := getQuerier(ctx, r.db).QueryRow(ctx, query, spec.Name, id, spec.Version).Scan(
+		&updated.ID, &updated.Name, &updated.CreatedAt, &updated.UpdatedAt, &updated.Version,
 	)
 
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, resolveNoRows(ctx, getQuerier(ctx, r.db), "specializations", id, errors.New("specialization not found"))
+		}
 		return nil, fmt.Errorf("failed to update specialization: %w", err)
 	}
 
 	return &updated, nil
 }
 
-func (r *SpecializationRepository) Delete(ctx context.Context, id int) error {
-	query := `DELETE FROM specializations WHERE id = $1`
-	_, err := r.db.Exec(ctx, query, id)
+func (r *SpecializationRepository) Delete(ctx context.Context, id int, version int) error {
+	query := `
+		UPDATE specializations
+		SET deleted_at = CURRENT_TIMESTAMP, version = version + 1
+		WHERE id = $1 AND deleted_at IS NULL AND $2 IN (0, version)
+	`
+	tag, err := getQuerier(ctx, r.db).Exec(ctx, query, id, version)
 	if err != nil {
 		return fmt.Errorf("failed to delete specialization: %w", err)
 	}
+	if tag.RowsAffected() == 0 {
+		return resolveNoRows(ctx, getQuerier(ctx, r.db), "specializations", id, errors.New("specialization not found"))
+	}
+	return nil
+}
+
+func (r *SpecializationRepository) Restore(ctx context.Context, id int) error {
+	query := `UPDATE specializations SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL`
+	tag, err := getQuerier(ctx, r.db).Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to restore specialization: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.New("deleted specialization not found")
+	}
 	return nil
 }
+
+// Purge окончательно удаляет записи, помеченные удалёнными раньше before. Специализации,
+// на которые ссылаются услуги, категории или действующие врачи, остаются: первые не дали бы
+// удалить ключи, а связи с врачами удалились бы каскадом.
+func (r *SpecializationRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	query := `
+		DELETE FROM specializations
+		WHERE deleted_at < $1
+		  AND NOT EXISTS (SELECT 1 FROM services s WHERE s.specialization_id = specializations.id)
+		  AND NOT EXISTS (SELECT 1 FROM service_categories c WHERE c.specialization_id = specializations.id)
+		  AND NOT EXISTS (
+			SELECT 1 FROM doctor_specializations ds
+			JOIN doctors d ON d.id = ds.doctor_id
+			WHERE ds.specialization_id = specializations.id AND d.deleted_at IS NULL
+		  )
+	`
+	tag, err := getQuerier(ctx, r.db).Exec(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge specializations: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Querier - общий набор методов *pgxpool.Pool и pgx.Tx, через который работают репозитории
+type Querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+type txKey struct{}
+
+// getQuerier возвращает транзакцию из контекста, если она открыта, иначе пул.
+// Запросы через него попадают в метрику длительности с именем вызвавшего метода репозитория.
+func getQuerier(ctx context.Context, db *pgxpool.Pool) Querier {
+	var q Querier = db
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		q = tx
+	}
+
+	pc, _, _, _ := runtime.Caller(1)
+	return instrumentedQuerier{q: q, labels: labelsForCaller(pc)}
+}
+
+type TransactionManagerInterface interface {
+	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type TransactionManager struct {
+	db *pgxpool.Pool
+}
+
+func NewTransactionManager(db *pgxpool.Pool) TransactionManagerInterface {
+	return &TransactionManager{db: db}
+}
+
+// WithTx выполняет fn в одной транзакции. Все вызовы репозиториев с переданным
+// в fn контекстом идут через эту транзакцию. Вложенный вызов переиспользует
+// уже открытую транзакцию.
+func (m *TransactionManager) WithTx(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if _, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := m.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback(ctx)
+			panic(p)
+		}
+		if err != nil {
+			if rbErr := tx.Rollback(ctx); rbErr != nil && !errors.Is(rbErr, pgx.ErrTxClosed) {
+				err = errors.Join(err, fmt.Errorf("failed to rollback transaction: %w", rbErr))
+			}
+		}
+	}()
+
+	if err = fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"Clinic_backend/internal/entity"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	// ErrTreatingDoctorNotFound - врач не назначен этому пациенту
+	ErrTreatingDoctorNotFound = errors.New("treating doctor assignment not found")
+	// ErrNotADoctor - пользователь не привязан к действующему профилю врача
+	ErrNotADoctor = errors.New("user is not a doctor")
+)
+
+const treatingDoctorColumns = `id, patient_id, doctor_user_id, assigned_by, created_at`
+
+type TreatingDoctorRepositoryInterface interface {
+	Assign(ctx context.Context, link *entity.TreatingDoctor) (*entity.TreatingDoctor, error)
+	ListByPatient(ctx context.Context, patientID int) ([]entity.TreatingDoctor, error)
+	Get(ctx context.Context, patientID, doctorUserID int) (*entity.TreatingDoctor, error)
+	Delete(ctx context.Context, patientID, doctorUserID int) error
+	IsTreating(ctx context.Context, doctorUserID, patientID int) (bool, error)
+}
+
+type TreatingDoctorRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewTreatingDoctorRepository(db *pgxpool.Pool) TreatingDoctorRepositoryInterface {
+	return &TreatingDoctorRepository{db: db}
+}
+
+// Assign назначает врача пациенту. Повторное назначение возвращает существующую связь:
+// пустой DO UPDATE нужен, чтобы RETURNING вернул строку и при конфликте
+func (r *TreatingDoctorRepository) Assign(ctx context.Context, link *entity.TreatingDoctor) (*entity.TreatingDoctor, error) {
+	query := `
+		INSERT INTO patient_doctors (patient_id, doctor_user_id, assigned_by)
+		SELECT $1, d.user_id, $3
+		FROM doctors d
+		WHERE d.user_id = $2 AND d.deleted_at IS NULL
+		ON CONFLICT (patient_id, doctor_user_id) DO UPDATE SET patient_id = EXCLUDED.patient_id
+		RETURNING ` + treatingDoctorColumns
+
+	assigned, err := scanTreatingDoctor(getQuerier(ctx, r.db).QueryRow(ctx, query, link.PatientID, link.DoctorUserID, link.AssignedBy))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotADoctor
+		}
+		if isConstraintViolation(err, "patient_doctors_patient_id_fkey") {
+			return nil, ErrPatientNotFound
+		}
+		return nil, fmt.Errorf("failed to assign treating doctor: %w", err)
+	}
+
+	return assigned, nil
+}
+
+func (r *TreatingDoctorRepository) ListByPatient(ctx context.Context, patientID int) ([]entity.TreatingDoctor, error) {
+	query := `
+		SELECT ` + treatingDoctorColumns + `
+		FROM patient_doctors
+		WHERE patient_id = $1
+		ORDER BY created_at, id
+	`
+
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query, patientID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query treating doctors: %w", err)
+	}
+	defer rows.Close()
+
+	links := []entity.TreatingDoctor{}
+	for rows.Next() {
+		link, err := scanTreatingDoctor(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan treating doctor: %w", err)
+		}
+		links = append(links, *link)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return links, nil
+}
+
+func (r *TreatingDoctorRepository) Get(ctx context.Context, patientID, doctorUserID int) (*entity.TreatingDoctor, error) {
+	query := `SELECT ` + treatingDoctorColumns + ` FROM patient_doctors WHERE patient_id = $1 AND doctor_user_id = $2`
+
+	link, err := scanTreatingDoctor(getQuerier(ctx, r.db).QueryRow(ctx, query, patientID, doctorUserID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrTreatingDoctorNotFound
+		}
+		return nil, fmt.Errorf("failed to get treating doctor: %w", err)
+	}
+
+	return link, nil
+}
+
+func (r *TreatingDoctorRepository) Delete(ctx context.Context, patientID, doctorUserID int) error {
+	query := `DELETE FROM patient_doctors WHERE patient_id = $1 AND doctor_user_id = $2`
+
+	result, err := getQuerier(ctx, r.db).Exec(ctx, query, patientID, doctorUserID)
+	if err != nil {
+		return fmt.Errorf("failed to delete treating doctor: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrTreatingDoctorNotFound
+	}
+
+	return nil
+}
+
+// IsTreating сообщает, лечит ли врач пациента: назначен ему или уже вёл его приём
+func (r *TreatingDoctorRepository) IsTreating(ctx context.Context, doctorUserID, patientID int) (bool, error) {
+	query := `
+		SELECT EXISTS(SELECT 1 FROM patient_doctors WHERE patient_id = $1 AND doctor_user_id = $2)
+			OR EXISTS(SELECT 1 FROM encounters WHERE patient_id = $1 AND doctor_user_id = $2)
+	`
+
+	var treating bool
+	if err := getQuerier(ctx, r.db).QueryRow(ctx, query, patientID, doctorUserID).Scan(&treating); err != nil {
+		return false, fmt.Errorf("failed to check treating doctor: %w", err)
+	}
+
+	return treating, nil
+}
+
+func scanTreatingDoctor(row pgx.Row) (*entity.TreatingDoctor, error) {
+	var link entity.TreatingDoctor
+	err := row.Scan(
+		&link.ID,
+		&link.PatientID,
+		&link.DoctorUserID,
+		&link.AssignedBy,
+		&link.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
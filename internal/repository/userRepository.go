@@ -10,13 +10,16 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// ErrUserNotFound - пользователя нет или его учётная запись уже обезличена
+var ErrUserNotFound = errors.New("user not found")
+
 type UserRepositoryInterface interface {
 	Create(ctx context.Context, user *entity.User) (*entity.User, error)
 	GetByEmail(ctx context.Context, email string) (*entity.User, error)
 	GetByID(ctx context.Context, id int) (*entity.User, error)
 	GetAll(ctx context.Context) ([]entity.User, error)
 	Update(ctx context.Context, id int, user *entity.User) (*entity.User, error)
-	Delete(ctx context.Context, id int) error
+	Erase(ctx context.Context, id int) (*entity.ErasureSummary, error)
 }
 
 type UserRepository struct {
@@ -35,7 +38,7 @@ func (r *UserRepository) Create(ctx context.Context, user *entity.User) (*entity
 	`
 
 	var createdUser entity.User
-	err := r.db.QueryRow(ctx, query, user.Username, user.Email, user.Password).Scan(
+	err := getQuerier(ctx, r.db).QueryRow(ctx, query, user.Username, user.Email, user.Password).Scan(
 		&createdUser.ID,
 		&createdUser.Username,
 		&createdUser.Email,
@@ -53,7 +56,7 @@ func (r *UserRepository) Create(ctx context.Context, user *entity.User) (*entity
 	// Get role name
 	if createdUser.RoleID != nil {
 		roleQuery := `SELECT name FROM roles WHERE id = $1`
-		err = r.db.QueryRow(ctx, roleQuery, *createdUser.RoleID).Scan(&createdUser.RoleName)
+		err = getQuerier(ctx, r.db).QueryRow(ctx, roleQuery, *createdUser.RoleID).Scan(&createdUser.RoleName)
 		if err != nil {
 			createdUser.RoleName = "user"
 		}
@@ -72,7 +75,7 @@ func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*entity.
 	`
 
 	var user entity.User
-	err := r.db.QueryRow(ctx, query, email).Scan(
+	err := getQuerier(ctx, r.db).QueryRow(ctx, query, email).Scan(
 		&user.ID,
 		&user.Username,
 		&user.Email,
@@ -105,7 +108,7 @@ func (r *UserRepository) GetByID(ctx context.Context, id int) (*entity.User, err
 	`
 
 	var user entity.User
-	err := r.db.QueryRow(ctx, query, id).Scan(
+	err := getQuerier(ctx, r.db).QueryRow(ctx, query, id).Scan(
 		&user.ID,
 		&user.Username,
 		&user.Email,
@@ -137,7 +140,7 @@ func (r *UserRepository) GetAll(ctx context.Context) ([]entity.User, error) {
 		ORDER BY u.id
 	`
 
-	rows, err := r.db.Query(ctx, query)
+	rows, err := getQuerier(ctx, r.db).Query(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query users: %w", err)
 	}
@@ -179,7 +182,7 @@ func (r *UserRepository) Update(ctx context.Context, id int, user *entity.User)
 	`
 
 	var updatedUser entity.User
-	err := r.db.QueryRow(ctx, query, user.Username, user.Email, id).Scan(
+	err := getQuerier(ctx, r.db).QueryRow(ctx, query, user.Username, user.Email, id).Scan(
 		&updatedUser.ID,
 		&updatedUser.Username,
 		&updatedUser.Email,
@@ -197,11 +200,99 @@ func (r *UserRepository) Update(ctx context.Context, id int, user *entity.User)
 	return &updatedUser, nil
 }
 
-func (r *UserRepository) Delete(ctx context.Context, id int) error {
-	query := `DELETE FROM users WHERE id = $1`
-	_, err := r.db.Exec(ctx, query, id)
+// Erase обезличивает учётную запись вместо удаления: на пользователя ссылаются медицинские
+// документы и журнал аудита. Профиль пациента, по которому есть приёмы, рецепты или
+// направления, отвязывается от учётной записи и теряет контактные данные - ФИО, дата
+// рождения и полис остаются как обязательные реквизиты медицинской документации.
+// Профиль без документов удаляется. Вызывается внутри транзакции.
+func (r *UserRepository) Erase(ctx context.Context, id int) (*entity.ErasureSummary, error) {
+	q := getQuerier(ctx, r.db)
+	summary := &entity.ErasureSummary{UserID: id}
+
+	var patientID int
+	err := q.QueryRow(ctx, `SELECT id FROM patient_profiles WHERE user_id = $1 FOR UPDATE`, id).Scan(&patientID)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+	case err != nil:
+		return nil, fmt.Errorf("failed to lock patient profile: %w", err)
+	default:
+		summary.PatientID = &patientID
+
+		var hasRecords bool
+		recordsQuery := `
+			SELECT EXISTS (SELECT 1 FROM encounters WHERE patient_id = $1)
+			    OR EXISTS (SELECT 1 FROM prescriptions WHERE patient_id = $1)
+			    OR EXISTS (SELECT 1 FROM lab_orders WHERE patient_id = $1)
+		`
+		if err := q.QueryRow(ctx, recordsQuery, patientID).Scan(&hasRecords); err != nil {
+			return nil, fmt.Errorf("failed to check medical records: %w", err)
+		}
+
+		if hasRecords {
+			retainQuery := `
+				UPDATE patient_profiles
+				SET user_id = NULL, phone = NULL, address = NULL, updated_at = CURRENT_TIMESTAMP, version = version + 1
+				WHERE id = $1
+			`
+			if _, err := q.Exec(ctx, retainQuery, patientID); err != nil {
+				return nil, fmt.Errorf("failed to anonymise patient profile: %w", err)
+			}
+			summary.ProfileRetained = true
+		} else {
+			if _, err := q.Exec(ctx, `DELETE FROM patient_profiles WHERE id = $1`, patientID); err != nil {
+				return nil, fmt.Errorf("failed to delete patient profile: %w", err)
+			}
+			summary.ProfileDeleted = true
+		}
+	}
+
+	result, err := q.Exec(ctx, `DELETE FROM patient_guardians WHERE guardian_user_id = $1`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to remove guardianships: %w", err)
+	}
+	summary.GuardianshipsRemoved = int(result.RowsAffected())
+
+	result, err = q.Exec(ctx, `UPDATE doctors SET user_id = NULL WHERE user_id = $1`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unlink doctor: %w", err)
+	}
+	summary.DoctorUnlinked = result.RowsAffected() > 0
+
+	// Без учётной записи врач больше не ведёт назначенных ему пациентов
+	if _, err := q.Exec(ctx, `DELETE FROM patient_doctors WHERE doctor_user_id = $1`, id); err != nil {
+		return nil, fmt.Errorf("failed to remove treating doctor assignments: %w", err)
+	}
+
+	// Отзывы - текст автора, они удаляются, рейтинг врачей пересчитывается
+	reviewsQuery := `
+		WITH removed AS (DELETE FROM doctor_reviews WHERE user_id = $1 RETURNING doctor_id)
+		SELECT COALESCE(array_agg(doctor_id), '{}') FROM removed
+	`
+	var reviewedDoctors []int
+	if err := q.QueryRow(ctx, reviewsQuery, id).Scan(&reviewedDoctors); err != nil {
+		return nil, fmt.Errorf("failed to remove reviews: %w", err)
+	}
+	if len(reviewedDoctors) > 0 {
+		if _, err := q.Exec(ctx, refreshDoctorRatingsQuery, reviewedDoctors); err != nil {
+			return nil, fmt.Errorf("failed to refresh doctor ratings: %w", err)
+		}
+	}
+	summary.ReviewsRemoved = len(reviewedDoctors)
+
+	userQuery := `
+		UPDATE users
+		SET username = 'deleted-' || id, email = 'deleted-' || id || '@erased.invalid', provider = NULL,
+		    password = '', reset_password_token = NULL, confirmation_token = NULL, blocked = TRUE,
+		    erased_at = now(), updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND erased_at IS NULL
+	`
+	result, err = q.Exec(ctx, userQuery, id)
 	if err != nil {
-		return fmt.Errorf("failed to delete user: %w", err)
+		return nil, fmt.Errorf("failed to anonymise user: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return nil, ErrUserNotFound
 	}
-	return nil
+
+	return summary, nil
 }
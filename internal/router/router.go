@@ -3,45 +3,101 @@ package router
 import (
 	"Clinic_backend/config"
 	"Clinic_backend/internal/handler"
+	"Clinic_backend/internal/icd10"
+	"Clinic_backend/internal/media"
+	"Clinic_backend/internal/metrics"
 	"Clinic_backend/internal/middleware"
+	"Clinic_backend/internal/pdf"
 	"Clinic_backend/internal/repository"
 	"Clinic_backend/internal/service"
+	"fmt"
 
-	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-func SetupRouter(cfg *config.Config, db *pgxpool.Pool) *gin.Engine {
-	if cfg.Env.Environment == "production" {
+// SetupRouter собирает маршруты. Параметры, доступные для перезагрузки
+// (CORS, лимиты, заголовки, JWT), middleware читают из cfg на каждый запрос.
+func SetupRouter(cfg *config.Holder, db *pgxpool.Pool, blobStore media.BlobStore, healthService service.HealthServiceInterface) (*gin.Engine, error) {
+	static := cfg.Get()
+	if static.IsProduction() {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
 	r := gin.Default()
 
+	// X-Forwarded-For учитывается только от доверенных прокси, иначе клиент может подменить IP
+	if err := r.SetTrustedProxies(static.Security.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("invalid trusted proxies: %w", err)
+	}
+
+	rateLimits, err := middleware.NewRateLimitStore(cfg)
+	if err != nil {
+		return nil, err
+	}
+	rateLimit := func(group string) gin.HandlerFunc {
+		return middleware.RateLimitMiddleware(cfg, rateLimits, group)
+	}
+
+	// Tracing: корневой спан запроса, должен стоять первым
+	r.Use(otelgin.Middleware(static.Tracing.ServiceName))
+
+	// Request ID и логгер запроса
+	r.Use(middleware.RequestIDMiddleware())
+
 	// CORS configuration
-	corsConfig := cors.DefaultConfig()
-	corsConfig.AllowOrigins = []string{"*"}
-	corsConfig.AllowMethods = []string{"GET", "POST", "PUT", "DELETE", "PATCH", "OPTIONS"}
-	corsConfig.AllowHeaders = []string{"Origin", "Content-Type", "Authorization"}
-	corsConfig.AllowCredentials = true
-	r.Use(cors.New(corsConfig))
+	r.Use(middleware.CORSMiddleware(cfg))
+
+	// Security headers
+	r.Use(middleware.SecurityHeadersMiddleware(cfg))
 
 	// Logger middleware
 	r.Use(middleware.LoggerMiddleware())
 
+	// Prometheus metrics
+	if static.Features.Metrics {
+		r.Use(middleware.MetricsMiddleware())
+		metrics.RegisterPoolStats(db)
+		r.GET("/metrics", middleware.MetricsAuthMiddleware(cfg), gin.WrapH(metrics.Handler()))
+	}
+
 	// Swagger
-	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	if static.Features.Swagger {
+		r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	}
 
 	// Health check
-	r.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{"status": "ok"})
-	})
+	healthHandler := handler.NewHealthHandler(healthService)
+	r.GET("/health", healthHandler.Live)
+	r.GET("/health/live", healthHandler.Live)
+	r.GET("/health/ready", healthHandler.Ready)
+
+	// ICD-10 catalog for medical records
+	icdTable := icd10.Default()
+	if static.EMR.ICD10File != "" {
+		table, err := icd10.LoadFile(static.EMR.ICD10File)
+		if err != nil {
+			return nil, err
+		}
+		icdTable = table
+	}
+
+	// Cyrillic font for printable documents; Helvetica with transliteration when not set
+	var pdfFont *pdf.TrueTypeFont
+	if static.Clinic.PDFFontFile != "" {
+		font, err := pdf.LoadTrueType(static.Clinic.PDFFontFile)
+		if err != nil {
+			return nil, err
+		}
+		pdfFont = font
+	}
 
 	// Init Repos
+	txManager := repository.NewTransactionManager(db)
 	userRepo := repository.NewUserRepository(db)
 	doctorRepo := repository.NewDoctorRepository(db)
 	serviceRepo := repository.NewServiceRepository(db)
@@ -50,20 +106,44 @@ func SetupRouter(cfg *config.Config, db *pgxpool.Pool) *gin.Engine {
 	scheduleRepo := repository.NewScheduleRepository(db)
 	licenseRepo := repository.NewLicenseRepository(db)
 	carouselRepo := repository.NewCarouselRepository(db)
+	auditRepo := repository.NewAuditRepository(db)
+	mediaRepo := repository.NewMediaRepository(db)
+	searchRepo := repository.NewSearchRepository(db)
+	patientRepo := repository.NewPatientRepository(db)
+	guardianRepo := repository.NewGuardianRepository(db)
+	treatingDoctorRepo := repository.NewTreatingDoctorRepository(db)
+	encounterRepo := repository.NewEncounterRepository(db)
+	prescriptionRepo := repository.NewPrescriptionRepository(db)
+	labRepo := repository.NewLabRepository(db)
+	dataRequestRepo := repository.NewDataRequestRepository(db)
+	consentRepo := repository.NewConsentRepository(db)
+	reviewRepo := repository.NewReviewRepository(db)
 
 	// Init Services
-	authService := service.NewAuthService(cfg, userRepo)
-	doctorService := service.NewDoctorService(doctorRepo, specRepo, scheduleRepo)
-	serviceService := service.NewServiceService(serviceRepo, serviceCategoryRepo, specRepo)
-	serviceCategoryService := service.NewCategoryService(serviceCategoryRepo, specRepo)
-	specializationService := service.NewSpecializationService(specRepo)
-	scheduleService := service.NewScheduleService(scheduleRepo)
-	licenseService := service.NewLicenseService(licenseRepo)
-	carouselService := service.NewCarouselService(carouselRepo)
+	auditService := service.NewAuditService(txManager, auditRepo)
+	mediaService := service.NewMediaService(cfg, blobStore, mediaRepo)
+	consentService := service.NewConsentService(txManager, auditService, consentRepo)
+	authService := service.NewAuthService(cfg, txManager, userRepo, consentService)
+	userService := service.NewUserService(txManager, auditService, userRepo)
+	doctorService := service.NewDoctorService(txManager, auditService, mediaService, doctorRepo, specRepo, scheduleRepo)
+	serviceService := service.NewServiceService(txManager, auditService, mediaService, serviceRepo, serviceCategoryRepo, specRepo)
+	serviceCategoryService := service.NewCategoryService(txManager, auditService, mediaService, serviceCategoryRepo, specRepo)
+	specializationService := service.NewSpecializationService(txManager, auditService, specRepo)
+	scheduleService := service.NewScheduleService(txManager, auditService, scheduleRepo)
+	licenseService := service.NewLicenseService(txManager, auditService, mediaService, licenseRepo)
+	carouselService := service.NewCarouselService(txManager, auditService, mediaService, carouselRepo)
+	searchService := service.NewSearchService(txManager, searchRepo)
+	patientService := service.NewPatientService(txManager, auditService, patientRepo, guardianRepo, treatingDoctorRepo)
+	encounterService := service.NewEncounterService(txManager, auditService, patientService, icdTable, encounterRepo)
+	prescriptionService := service.NewPrescriptionService(cfg, pdfFont, txManager, auditService, patientService, doctorRepo, encounterRepo, prescriptionRepo)
+	labService := service.NewLabService(cfg, blobStore, txManager, auditService, patientService, doctorRepo, encounterRepo, labRepo)
+	privacyService := service.NewPrivacyService(txManager, auditService, patientService, encounterService, prescriptionService, labService, userRepo, dataRequestRepo, consentRepo, reviewRepo)
+	reviewService := service.NewReviewService(txManager, auditService, doctorRepo, reviewRepo)
+	fhirService := service.NewFHIRService(auditService, doctorService, serviceService, serviceCategoryService, specializationService, patientService)
 
 	// Init handlers
 	authHandler := handler.NewAuthHandler(authService)
-	userHandler := handler.NewUserHandler(userRepo)
+	userHandler := handler.NewUserHandler(userService)
 	doctorHandler := handler.NewDoctorHandler(doctorService)
 	serviceHandler := handler.NewServiceHandler(serviceService)
 	serviceCategoryHandler := handler.NewCategoryHandler(serviceCategoryService)
@@ -71,11 +151,36 @@ func SetupRouter(cfg *config.Config, db *pgxpool.Pool) *gin.Engine {
 	scheduleHandler := handler.NewScheduleHandler(scheduleService)
 	licenseHandler := handler.NewLicenseHandler(licenseService)
 	carouselHandler := handler.NewCarouselHandler(carouselService)
+	auditHandler := handler.NewAuditHandler(auditService)
+	configHandler := handler.NewConfigHandler(cfg)
+	mediaHandler := handler.NewMediaHandler(mediaService, cfg)
+	searchHandler := handler.NewSearchHandler(searchService)
+	patientHandler := handler.NewPatientHandler(patientService)
+	encounterHandler := handler.NewEncounterHandler(encounterService)
+	prescriptionHandler := handler.NewPrescriptionHandler(prescriptionService)
+	labHandler := handler.NewLabHandler(labService, cfg)
+	fhirHandler := handler.NewFHIRHandler(fhirService)
+	privacyHandler := handler.NewPrivacyHandler(privacyService)
+	consentHandler := handler.NewConsentHandler(consentService)
+	reviewHandler := handler.NewReviewHandler(reviewService)
+
+	// Изображения отдаются вне /api/v1: ответы кэшируются навсегда и не буферизуются
+	mediaGroup := r.Group("/media")
+	mediaGroup.Use(rateLimit("media"))
+	{
+		mediaGroup.GET("/:id", mediaHandler.Serve)
+		mediaGroup.HEAD("/:id", mediaHandler.Serve)
+	}
+
+	// If-None-Match поддерживается только для публичного каталога: ответы с персональными
+	// данными и файлы не кэшируются, и буферизовать их целиком незачем
+	conditionalGet := middleware.ConditionalGetMiddleware()
 
 	api := r.Group("/api/v1")
 	{
 		// Auth routes (public)
 		auth := api.Group("/auth")
+		auth.Use(rateLimit("auth"))
 		{
 			auth.POST("/register", authHandler.Register)
 			auth.POST("/login", authHandler.Login)
@@ -84,9 +189,33 @@ func SetupRouter(cfg *config.Config, db *pgxpool.Pool) *gin.Engine {
 		// User routes
 		users := api.Group("/users")
 		users.Use(middleware.AuthMiddleware(cfg))
+		users.Use(rateLimit("users"))
 		{
 			users.GET("/me", userHandler.GetMe)
 			users.PUT("/me", userHandler.UpdateMe)
+			users.GET("/me/profile", patientHandler.GetMyProfile)
+			users.PUT("/me/profile", patientHandler.SaveMyProfile)
+			users.GET("/me/dependents", patientHandler.ListDependents)
+			users.POST("/me/dependents", patientHandler.AddDependent)
+			users.PUT("/me/dependents/:patient_id", patientHandler.UpdateDependent)
+			users.DELETE("/me/dependents/:patient_id", patientHandler.RemoveDependent)
+			users.GET("/me/encounters", encounterHandler.ListMine)
+			users.GET("/me/encounters/:id", encounterHandler.GetMine)
+			users.GET("/me/prescriptions", prescriptionHandler.ListMine)
+			users.GET("/me/prescriptions/:id", prescriptionHandler.GetMine)
+			users.GET("/me/prescriptions/:id/pdf", prescriptionHandler.GetMinePDF)
+			users.GET("/me/lab-orders", labHandler.ListMine)
+			users.GET("/me/lab-orders/:id", labHandler.GetMine)
+			users.GET("/me/lab-orders/:id/attachments/:attachment_id", labHandler.DownloadMineAttachment)
+			users.GET("/me/data-export", privacyHandler.Export)
+			users.GET("/me/data-requests", privacyHandler.ListMine)
+			users.POST("/me/data-requests/erasure", privacyHandler.RequestErasure)
+			users.GET("/me/consents", consentHandler.Status)
+			users.POST("/me/consents", consentHandler.Accept)
+			users.DELETE("/me/consents/:type", consentHandler.Withdraw)
+			users.GET("/me/reviews", reviewHandler.ListMine)
+			users.PUT("/me/reviews/:id", reviewHandler.UpdateMine)
+			users.DELETE("/me/reviews/:id", reviewHandler.DeleteMine)
 
 			// Admin only
 			admin := users.Group("")
@@ -99,15 +228,47 @@ func SetupRouter(cfg *config.Config, db *pgxpool.Pool) *gin.Engine {
 			}
 		}
 
+		// Consent documents routes
+		consents := api.Group("/consents")
+		consents.Use(middleware.OptionalAuthMiddleware(cfg))
+		consents.Use(rateLimit("consents"))
+		{
+			// Public routes
+			consents.GET("/current", consentHandler.Current)
+			consents.GET("/documents", consentHandler.ListDocuments)
+			consents.GET("/documents/:id", consentHandler.GetDocument)
+
+			// Admin only
+			consentsAdmin := consents.Group("")
+			consentsAdmin.Use(middleware.AuthMiddleware(cfg))
+			consentsAdmin.Use(middleware.RoleMiddleware("admin"))
+			{
+				consentsAdmin.POST("/documents", consentHandler.CreateDocument)
+				consentsAdmin.PUT("/documents/:id", consentHandler.UpdateDocument)
+				consentsAdmin.POST("/documents/:id/publish", consentHandler.PublishDocument)
+			}
+		}
+
 		// Doctors routes
 		doctors := api.Group("/doctors")
+		doctors.Use(middleware.OptionalAuthMiddleware(cfg))
+		doctors.Use(rateLimit("doctors"))
+		doctors.Use(conditionalGet)
 		{
 			// Public routes
 			doctors.GET("/specialization/:id", doctorHandler.GetBySpecialization)
 			doctors.GET("/:id/schedule", doctorHandler.GetDoctorSchedule)
+			doctors.GET("/:id/reviews", reviewHandler.ListForDoctor)
 			doctors.GET("/:id", doctorHandler.GetDoctorByID)
 			doctors.GET("", doctorHandler.GetAllDoctors)
 
+			// Any authenticated user
+			doctorsAuth := doctors.Group("")
+			doctorsAuth.Use(middleware.AuthMiddleware(cfg))
+			{
+				doctorsAuth.POST("/:id/reviews", reviewHandler.Create)
+			}
+
 			// Admin only
 			doctorsAdmin := doctors.Group("")
 			doctorsAdmin.Use(middleware.AuthMiddleware(cfg))
@@ -116,11 +277,15 @@ func SetupRouter(cfg *config.Config, db *pgxpool.Pool) *gin.Engine {
 				doctorsAdmin.POST("", doctorHandler.CreateDoctor)
 				doctorsAdmin.PUT("/:id", doctorHandler.UpdateDoctor)
 				doctorsAdmin.DELETE("/:id", doctorHandler.DeleteDoctor)
+				doctorsAdmin.POST("/:id/restore", doctorHandler.RestoreDoctor)
 			}
 		}
 
 		// Services routes
 		services := api.Group("/services")
+		services.Use(middleware.OptionalAuthMiddleware(cfg))
+		services.Use(rateLimit("services"))
+		services.Use(conditionalGet)
 		{
 			// Public routes
 			services.GET("/category/:id", serviceHandler.GetByCategory)
@@ -136,11 +301,15 @@ func SetupRouter(cfg *config.Config, db *pgxpool.Pool) *gin.Engine {
 				servicesAdmin.POST("", serviceHandler.CreateService)
 				servicesAdmin.PUT("/:id", serviceHandler.UpdateService)
 				servicesAdmin.DELETE("/:id", serviceHandler.DeleteService)
+				servicesAdmin.POST("/:id/restore", serviceHandler.RestoreService)
 			}
 		}
 
 		// Service Categories routes
 		categories := api.Group("/service-categories")
+		categories.Use(middleware.OptionalAuthMiddleware(cfg))
+		categories.Use(rateLimit("service-categories"))
+		categories.Use(conditionalGet)
 		{
 			// Public routes
 			categories.GET("", serviceCategoryHandler.GetAllCategories)
@@ -156,11 +325,15 @@ func SetupRouter(cfg *config.Config, db *pgxpool.Pool) *gin.Engine {
 				categoriesAdmin.PUT("/:id", serviceCategoryHandler.UpdateCategory)
 				categoriesAdmin.PATCH("/:id/favorite", serviceCategoryHandler.ToggleFavorite)
 				categoriesAdmin.DELETE("/:id", serviceCategoryHandler.DeleteCategory)
+				categoriesAdmin.POST("/:id/restore", serviceCategoryHandler.RestoreCategory)
 			}
 		}
 
 		// Specializations routes
 		specializations := api.Group("/specializations")
+		specializations.Use(middleware.OptionalAuthMiddleware(cfg))
+		specializations.Use(rateLimit("specializations"))
+		specializations.Use(conditionalGet)
 		{
 			// Public routes
 			specializations.GET("", specializationHandler.GetAllSpecializations)
@@ -174,11 +347,15 @@ func SetupRouter(cfg *config.Config, db *pgxpool.Pool) *gin.Engine {
 				specializationsAdmin.POST("", specializationHandler.CreateSpecialization)
 				specializationsAdmin.PUT("/:id", specializationHandler.UpdateSpecialization)
 				specializationsAdmin.DELETE("/:id", specializationHandler.DeleteSpecialization)
+				specializationsAdmin.POST("/:id/restore", specializationHandler.RestoreSpecialization)
 			}
 		}
 
 		// Schedules routes
 		schedules := api.Group("/schedules")
+		schedules.Use(middleware.OptionalAuthMiddleware(cfg))
+		schedules.Use(rateLimit("schedules"))
+		schedules.Use(conditionalGet)
 		{
 			// Public routes
 			schedules.GET("/day/:day", scheduleHandler.GetByDay)
@@ -198,6 +375,9 @@ func SetupRouter(cfg *config.Config, db *pgxpool.Pool) *gin.Engine {
 
 		// Licenses routes
 		licenses := api.Group("/licenses")
+		licenses.Use(middleware.OptionalAuthMiddleware(cfg))
+		licenses.Use(rateLimit("licenses"))
+		licenses.Use(conditionalGet)
 		{
 			// Public routes
 			licenses.GET("", licenseHandler.GetAllLicenses)
@@ -211,11 +391,15 @@ func SetupRouter(cfg *config.Config, db *pgxpool.Pool) *gin.Engine {
 				licensesAdmin.POST("", licenseHandler.CreateLicense)
 				licensesAdmin.PUT("/:id", licenseHandler.UpdateLicense)
 				licensesAdmin.DELETE("/:id", licenseHandler.DeleteLicense)
+				licensesAdmin.POST("/:id/restore", licenseHandler.RestoreLicense)
 			}
 		}
 
 		// Carousel routes
 		carousel := api.Group("/carousel")
+		carousel.Use(middleware.OptionalAuthMiddleware(cfg))
+		carousel.Use(rateLimit("carousel"))
+		carousel.Use(conditionalGet)
 		{
 			// Public routes
 			carousel.GET("", carouselHandler.GetAllSlides)
@@ -229,9 +413,176 @@ func SetupRouter(cfg *config.Config, db *pgxpool.Pool) *gin.Engine {
 				carouselAdmin.POST("", carouselHandler.CreateSlide)
 				carouselAdmin.PUT("/:id", carouselHandler.UpdateSlide)
 				carouselAdmin.DELETE("/:id", carouselHandler.DeleteSlide)
+				carouselAdmin.POST("/:id/restore", carouselHandler.RestoreSlide)
+			}
+		}
+
+		// Patient profiles (admin only)
+		patients := api.Group("/patients")
+		patients.Use(middleware.AuthMiddleware(cfg))
+		patients.Use(rateLimit("patients"))
+		patients.Use(middleware.RoleMiddleware("admin"))
+		{
+			patients.GET("", patientHandler.Search)
+			patients.GET("/:id", patientHandler.GetByID)
+			patients.GET("/:id/doctors", patientHandler.ListTreatingDoctors)
+			patients.POST("/:id/doctors", patientHandler.AssignTreatingDoctor)
+			patients.DELETE("/:id/doctors/:doctor_user_id", patientHandler.RemoveTreatingDoctor)
+		}
+
+		// Medical records: doctors and admins read, only the treating doctor writes
+		encounters := api.Group("/encounters")
+		encounters.Use(middleware.AuthMiddleware(cfg))
+		encounters.Use(rateLimit("emr"))
+		encounters.Use(middleware.RoleMiddleware("doctor", "admin"))
+		{
+			encounters.GET("", encounterHandler.List)
+			encounters.GET("/:id", encounterHandler.GetByID)
+			encounters.GET("/:id/revisions", encounterHandler.ListRevisions)
+
+			encountersDoctor := encounters.Group("")
+			encountersDoctor.Use(middleware.RoleMiddleware("doctor"))
+			{
+				encountersDoctor.POST("", encounterHandler.Create)
+				encountersDoctor.PUT("/:id", encounterHandler.UpdateDraft)
+				encountersDoctor.POST("/:id/sign", encounterHandler.Sign)
+				encountersDoctor.POST("/:id/amendments", encounterHandler.Amend)
+			}
+		}
+
+		prescriptions := api.Group("/prescriptions")
+		prescriptions.Use(middleware.AuthMiddleware(cfg))
+		prescriptions.Use(rateLimit("emr"))
+		prescriptions.Use(middleware.RoleMiddleware("doctor", "admin"))
+		{
+			prescriptions.GET("", prescriptionHandler.List)
+			prescriptions.GET("/:id", prescriptionHandler.GetByID)
+			prescriptions.GET("/:id/pdf", prescriptionHandler.GetPDF)
+
+			prescriptionsDoctor := prescriptions.Group("")
+			prescriptionsDoctor.Use(middleware.RoleMiddleware("doctor"))
+			{
+				prescriptionsDoctor.POST("", prescriptionHandler.Create)
+				prescriptionsDoctor.PUT("/:id", prescriptionHandler.Update)
+				prescriptionsDoctor.DELETE("/:id", prescriptionHandler.Delete)
+			}
+		}
+
+		labOrders := api.Group("/lab-orders")
+		labOrders.Use(middleware.AuthMiddleware(cfg))
+		labOrders.Use(rateLimit("emr"))
+		labOrders.Use(middleware.RoleMiddleware("doctor", "admin"))
+		{
+			labOrders.GET("", labHandler.List)
+			labOrders.GET("/:id", labHandler.GetByID)
+			labOrders.POST("/:id/status", labHandler.UpdateStatus)
+			labOrders.POST("/:id/results", labHandler.AddResults)
+			labOrders.POST("/:id/attachments", labHandler.UploadAttachment)
+			labOrders.GET("/:id/attachments/:attachment_id", labHandler.DownloadAttachment)
+
+			labOrdersDoctor := labOrders.Group("")
+			labOrdersDoctor.Use(middleware.RoleMiddleware("doctor"))
+			{
+				labOrdersDoctor.POST("", labHandler.Create)
+				labOrdersDoctor.DELETE("/:id", labHandler.Cancel)
+			}
+		}
+
+		// Laboratory import, authenticated with X-Lab-Token instead of a user token
+		labImport := api.Group("/lab")
+		labImport.Use(rateLimit("lab"))
+		labImport.Use(middleware.LabTokenMiddleware(cfg))
+		{
+			labImport.POST("/results", labHandler.Import)
+			labImport.POST("/orders/:id/attachments", labHandler.UploadAttachment)
+		}
+
+		// Personal data requests: admins review erasure requests
+		dataRequests := api.Group("/data-requests")
+		dataRequests.Use(middleware.AuthMiddleware(cfg))
+		dataRequests.Use(rateLimit("admin"))
+		dataRequests.Use(middleware.RoleMiddleware("admin"))
+		{
+			dataRequests.GET("", privacyHandler.List)
+			dataRequests.GET("/:id", privacyHandler.GetByID)
+			dataRequests.POST("/:id/approve", privacyHandler.Approve)
+			dataRequests.POST("/:id/reject", privacyHandler.Reject)
+		}
+
+		// Doctor reviews moderation (admin only)
+		reviews := api.Group("/reviews")
+		reviews.Use(middleware.AuthMiddleware(cfg))
+		reviews.Use(rateLimit("admin"))
+		reviews.Use(middleware.RoleMiddleware("admin"))
+		{
+			reviews.GET("", reviewHandler.List)
+			reviews.GET("/:id", reviewHandler.GetByID)
+			reviews.POST("/:id/approve", reviewHandler.Approve)
+			reviews.POST("/:id/reject", reviewHandler.Reject)
+		}
+
+		// Read-only FHIR R4 facade: the catalog is public, patients need a doctor or admin token
+		fhirAPI := api.Group("/fhir")
+		fhirAPI.Use(rateLimit("fhir"))
+		{
+			fhirAPI.GET("/metadata", fhirHandler.Metadata)
+			for _, resourceType := range []string{"Practitioner", "PractitionerRole", "HealthcareService", "Schedule", "Slot"} {
+				fhirAPI.GET("/"+resourceType, fhirHandler.Search(resourceType))
+				fhirAPI.GET("/"+resourceType+"/:id", fhirHandler.Read(resourceType))
+			}
+
+			fhirPatients := fhirAPI.Group("/Patient")
+			fhirPatients.Use(middleware.AuthMiddleware(cfg))
+			fhirPatients.Use(middleware.RoleMiddleware("doctor", "admin"))
+			{
+				fhirPatients.GET("", fhirHandler.Search("Patient"))
+				fhirPatients.GET("/:id", fhirHandler.Read("Patient"))
 			}
 		}
+
+		icd := api.Group("/icd10")
+		icd.Use(middleware.AuthMiddleware(cfg))
+		icd.Use(rateLimit("emr"))
+		{
+			icd.GET("", encounterHandler.SearchICD10)
+		}
+
+		// Search (public)
+		search := api.Group("/search")
+		search.Use(rateLimit("search"))
+		{
+			search.GET("", searchHandler.Search)
+		}
+
+		// Audit log routes (admin only)
+		audit := api.Group("/audit-log")
+		audit.Use(middleware.AuthMiddleware(cfg))
+		audit.Use(rateLimit("audit-log"))
+		audit.Use(middleware.RoleMiddleware("admin"))
+		{
+			audit.GET("", auditHandler.List)
+			audit.GET("/export", auditHandler.Export)
+			audit.GET("/verify", auditHandler.Verify)
+		}
+
+		// Media upload (admin only)
+		mediaAdmin := api.Group("/media")
+		mediaAdmin.Use(middleware.AuthMiddleware(cfg))
+		mediaAdmin.Use(rateLimit("media"))
+		mediaAdmin.Use(middleware.RoleMiddleware("admin"))
+		{
+			mediaAdmin.POST("", mediaHandler.Upload)
+		}
+
+		// Administration (admin only)
+		admin := api.Group("/admin")
+		admin.Use(middleware.AuthMiddleware(cfg))
+		admin.Use(rateLimit("admin"))
+		admin.Use(middleware.RoleMiddleware("admin"))
+		{
+			admin.GET("/config", configHandler.Get)
+		}
 	}
 
-	return r
+	return r, nil
 }
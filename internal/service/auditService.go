@@ -0,0 +1,250 @@
+package service
+
+import (
+	"Clinic_backend/internal/entity"
+	"Clinic_backend/internal/repository"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// Поля, изменение которых не несёт смысла для аудита
+var auditIgnoredFields = map[string]bool{
+	"updated_at": true,
+	"version":    true,
+}
+
+type AuditServiceInterface interface {
+	Record(ctx context.Context, entityType string, entityID int, action string, before, after any) error
+	List(ctx context.Context, filter *entity.AuditLogFilter) ([]entity.AuditLog, int, error)
+	Export(ctx context.Context, filter *entity.AuditLogFilter) ([]entity.AuditLog, error)
+	Verify(ctx context.Context) (*entity.AuditVerifyResult, error)
+}
+
+type AuditService struct {
+	txManager repository.TransactionManagerInterface
+	auditRepo repository.AuditRepositoryInterface
+}
+
+func NewAuditService(txManager repository.TransactionManagerInterface, auditRepo repository.AuditRepositoryInterface) AuditServiceInterface {
+	return &AuditService{
+		txManager: txManager,
+		auditRepo: auditRepo,
+	}
+}
+
+// Record добавляет запись в журнал. Вызывается внутри транзакции изменения,
+// поэтому запись журнала и само изменение фиксируются или откатываются вместе.
+func (s *AuditService) Record(ctx context.Context, entityType string, entityID int, action string, before, after any) error {
+	beforeJSON, afterJSON, err := auditDiff(before, after)
+	if err != nil {
+		return err
+	}
+
+	actor := entity.ActorFromContext(ctx)
+	entry := &entity.AuditLog{
+		ActorID:    actor.UserID,
+		IP:         actor.IP,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Action:     action,
+		Before:     beforeJSON,
+		After:      afterJSON,
+		RequestID:  actor.RequestID,
+		CreatedAt:  time.Now().UTC().Truncate(time.Microsecond),
+	}
+
+	return s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.auditRepo.LockChain(ctx); err != nil {
+			return err
+		}
+
+		prevHash, err := s.auditRepo.GetLastHash(ctx)
+		if err != nil {
+			return err
+		}
+
+		entry.PrevHash = prevHash
+		entry.Hash = auditHash(entry)
+
+		return s.auditRepo.Create(ctx, entry)
+	})
+}
+
+func (s *AuditService) List(ctx context.Context, filter *entity.AuditLogFilter) ([]entity.AuditLog, int, error) {
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.Limit < 1 || filter.Limit > 100 {
+		filter.Limit = 50
+	}
+
+	return s.auditRepo.List(ctx, filter)
+}
+
+func (s *AuditService) Export(ctx context.Context, filter *entity.AuditLogFilter) ([]entity.AuditLog, error) {
+	filter.Page = 1
+	filter.Limit = 0
+
+	entries, _, err := s.auditRepo.List(ctx, filter)
+	return entries, err
+}
+
+// Verify пересчитывает цепочку хэшей и возвращает первую запись, на которой она нарушена
+func (s *AuditService) Verify(ctx context.Context) (*entity.AuditVerifyResult, error) {
+	result := &entity.AuditVerifyResult{Valid: true}
+	prevHash := ""
+
+	err := s.auditRepo.Iterate(ctx, func(entry *entity.AuditLog) error {
+		result.Checked++
+		if entry.PrevHash != prevHash || auditHash(entry) != entry.Hash {
+			result.Valid = false
+			id := entry.ID
+			result.BrokenAt = &id
+			return errStopIteration
+		}
+		prevHash = entry.Hash
+		return nil
+	})
+	if err != nil && !errors.Is(err, errStopIteration) {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+var errStopIteration = errors.New("stop iteration")
+
+// auditHash - SHA-256 от хэша предыдущей записи и всех значимых полей текущей
+func auditHash(entry *entity.AuditLog) string {
+	actorID := ""
+	if entry.ActorID != nil {
+		actorID = strconv.Itoa(*entry.ActorID)
+	}
+
+	h := sha256.New()
+	for _, part := range []string{
+		entry.PrevHash,
+		actorID,
+		entry.IP,
+		entry.EntityType,
+		strconv.Itoa(entry.EntityID),
+		entry.Action,
+		string(entry.Before),
+		string(entry.After),
+		entry.RequestID,
+		entry.CreatedAt.UTC().Format(time.RFC3339Nano),
+	} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// auditDiff сериализует состояния до и после изменения. Для обновления
+// в журнал попадают только изменившиеся поля.
+func auditDiff(before, after any) (json.RawMessage, json.RawMessage, error) {
+	beforeMap, err := toAuditMap(before)
+	if err != nil {
+		return nil, nil, err
+	}
+	afterMap, err := toAuditMap(after)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if beforeMap != nil && afterMap != nil {
+		for key := range beforeMap {
+			if auditIgnoredFields[key] || reflect.DeepEqual(beforeMap[key], afterMap[key]) {
+				delete(beforeMap, key)
+				delete(afterMap, key)
+			}
+		}
+		for key := range afterMap {
+			if _, ok := beforeMap[key]; !ok && (auditIgnoredFields[key] || afterMap[key] == nil) {
+				delete(afterMap, key)
+			}
+		}
+	}
+
+	beforeJSON, err := marshalAuditMap(beforeMap)
+	if err != nil {
+		return nil, nil, err
+	}
+	afterJSON, err := marshalAuditMap(afterMap)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return beforeJSON, afterJSON, nil
+}
+
+// changedFields возвращает отсортированные имена полей, различающихся в JSON-представлениях.
+// Используется там, где значения полей нельзя писать в журнал - для медицинских данных.
+func changedFields(before, after any) ([]string, error) {
+	beforeMap, err := toAuditMap(before)
+	if err != nil {
+		return nil, err
+	}
+	afterMap, err := toAuditMap(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := []string{}
+	for key, value := range afterMap {
+		if !auditIgnoredFields[key] && !reflect.DeepEqual(beforeMap[key], value) {
+			changed = append(changed, key)
+		}
+	}
+	for key := range beforeMap {
+		if _, ok := afterMap[key]; !ok && !auditIgnoredFields[key] {
+			changed = append(changed, key)
+		}
+	}
+	slices.Sort(changed)
+
+	return changed, nil
+}
+
+func toAuditMap(value any) (map[string]any, error) {
+	if value == nil || (reflect.ValueOf(value).Kind() == reflect.Ptr && reflect.ValueOf(value).IsNil()) {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal audit state: %w", err)
+	}
+
+	var result map[string]any
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode audit state: %w", err)
+	}
+
+	return result, nil
+}
+
+func marshalAuditMap(value map[string]any) (json.RawMessage, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal audit diff: %w", err)
+	}
+
+	return data, nil
+}
@@ -3,6 +3,8 @@ package service
 import (
 	"Clinic_backend/config"
 	"Clinic_backend/internal/entity"
+	"Clinic_backend/internal/logging"
+	"Clinic_backend/internal/metrics"
 	"Clinic_backend/internal/repository"
 	"context"
 	"errors"
@@ -19,14 +21,18 @@ type AuthServiceInterface interface {
 }
 
 type AuthService struct {
-	cfg      *config.Config
-	userRepo repository.UserRepositoryInterface
+	cfg            *config.Holder
+	txManager      repository.TransactionManagerInterface
+	userRepo       repository.UserRepositoryInterface
+	consentService ConsentServiceInterface
 }
 
-func NewAuthService(cfg *config.Config, userRepo repository.UserRepositoryInterface) AuthServiceInterface {
+func NewAuthService(cfg *config.Holder, txManager repository.TransactionManagerInterface, userRepo repository.UserRepositoryInterface, consentService ConsentServiceInterface) AuthServiceInterface {
 	return &AuthService{
-		cfg:      cfg,
-		userRepo: userRepo,
+		cfg:            cfg,
+		txManager:      txManager,
+		userRepo:       userRepo,
+		consentService: consentService,
 	}
 }
 
@@ -50,10 +56,20 @@ func (s *AuthService) Register(ctx context.Context, req *entity.UserRegisterRequ
 		Password: string(hashedPassword),
 	}
 
-	createdUser, err := s.userRepo.Create(ctx, user)
+	// Учётная запись появляется только вместе с согласиями на действующие документы
+	var createdUser *entity.User
+	err = s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		createdUser, err = s.userRepo.Create(ctx, user)
+		if err != nil {
+			return err
+		}
+		return s.consentService.AcceptAtRegistration(ctx, createdUser.ID, req.ConsentDocumentIDs)
+	})
 	if err != nil {
 		return nil, err
 	}
+	metrics.RegistrationsTotal.Inc()
+	logging.FromContext(ctx).Info("User registered", "registered_user_id", createdUser.ID, "email", createdUser.Email)
 
 	// Генерируем токены
 	token, refreshToken, err := s.generateTokens(createdUser)
@@ -71,17 +87,30 @@ func (s *AuthService) Register(ctx context.Context, req *entity.UserRegisterRequ
 func (s *AuthService) Login(ctx context.Context, req *entity.UserLoginRequest) (*entity.AuthResponse, error) {
 	user, err := s.userRepo.GetByEmail(ctx, req.Email)
 	if err != nil {
+		metrics.LoginsTotal.WithLabelValues("failure").Inc()
+		logging.FromContext(ctx).Warn("Login failed: unknown user", "email", req.Email)
 		return nil, errors.New("invalid credentials, can't get user by email: " + err.Error())
 	}
 
 	if user.Blocked {
+		metrics.LoginsTotal.WithLabelValues("blocked").Inc()
+		logging.FromContext(ctx).Warn("Login rejected: user is blocked", "login_user_id", user.ID)
 		return nil, errors.New("user is blocked")
 	}
 
 	// Проверяем пароль
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
+		metrics.LoginsTotal.WithLabelValues("failure").Inc()
+		logging.FromContext(ctx).Warn("Login failed: password mismatch", "login_user_id", user.ID)
 		return nil, errors.New("invalid credentials, password mismatch")
 	}
+	metrics.LoginsTotal.WithLabelValues("success").Inc()
+
+	// Вышли новые версии документов - клиент попросит согласиться с ними заново
+	pending, err := s.consentService.Pending(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
 
 	// Генерируем токены
 	token, refreshToken, err := s.generateTokens(user)
@@ -90,22 +119,26 @@ func (s *AuthService) Login(ctx context.Context, req *entity.UserLoginRequest) (
 	}
 
 	return &entity.AuthResponse{
-		Token:        token,
-		RefreshToken: refreshToken,
-		User:         user.ToResponse(),
+		Token:           token,
+		RefreshToken:    refreshToken,
+		User:            user.ToResponse(),
+		PendingConsents: pending,
 	}, nil
 }
 
 func (s *AuthService) generateTokens(user *entity.User) (string, string, error) {
+	// Оба токена подписываются одним снимком конфигурации
+	auth := s.cfg.Get().Auth
+
 	// Access token
 	accessClaims := jwt.MapClaims{
 		"user_id": user.ID,
 		"email":   user.Email,
 		"role":    user.RoleName,
-		"exp":     time.Now().Add(time.Hour * time.Duration(s.cfg.Env.JWTExpireHours)).Unix(),
+		"exp":     time.Now().Add(time.Hour * time.Duration(auth.JWTExpireHours)).Unix(),
 	}
 	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims)
-	token, err := accessToken.SignedString([]byte(s.cfg.Env.JWTSecret))
+	token, err := accessToken.SignedString([]byte(auth.JWTSecret))
 	if err != nil {
 		return "", "", err
 	}
@@ -113,10 +146,10 @@ func (s *AuthService) generateTokens(user *entity.User) (string, string, error)
 	// Refresh token
 	refreshClaims := jwt.MapClaims{
 		"user_id": user.ID,
-		"exp":     time.Now().Add(time.Hour * time.Duration(s.cfg.Env.JWTRefreshExpireHours)).Unix(),
+		"exp":     time.Now().Add(time.Hour * time.Duration(auth.JWTRefreshExpireHours)).Unix(),
 	}
 	refreshTokenObj := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshClaims)
-	refreshToken, err := refreshTokenObj.SignedString([]byte(s.cfg.Env.JWTSecret))
+	refreshToken, err := refreshTokenObj.SignedString([]byte(auth.JWTSecret))
 	if err != nil {
 		return "", "", err
 	}
@@ -8,38 +8,107 @@ import (
 
 type CarouselServiceInterface interface {
 	CreateSlide(ctx context.Context, carousel *entity.Carousel) (*entity.Carousel, error)
-	GetAllSlides(ctx context.Context) ([]entity.Carousel, error)
+	GetAllSlides(ctx context.Context, includeDeleted bool) ([]entity.Carousel, error)
 	GetSlideByID(ctx context.Context, id int) (*entity.Carousel, error)
-	UpdateSlide(ctx context.Context, id int, carousel *entity.Carousel) (*entity.Carousel, error)
-	DeleteSlide(ctx context.Context, id int) error
+	UpdateSlide(ctx context.Context, id int, version int, carousel *entity.Carousel) (*entity.Carousel, error)
+	DeleteSlide(ctx context.Context, id int, version int) error
+	RestoreSlide(ctx context.Context, id int) error
 }
 
 type CarouselService struct {
+	txManager    repository.TransactionManagerInterface
+	auditService AuditServiceInterface
+	mediaService MediaServiceInterface
 	carouselRepo repository.CarouselRepositoryInterface
 }
 
-func NewCarouselService(carouselRepo repository.CarouselRepositoryInterface) CarouselServiceInterface {
+func NewCarouselService(txManager repository.TransactionManagerInterface, auditService AuditServiceInterface, mediaService MediaServiceInterface, carouselRepo repository.CarouselRepositoryInterface) CarouselServiceInterface {
 	return &CarouselService{
+		txManager:    txManager,
+		auditService: auditService,
+		mediaService: mediaService,
 		carouselRepo: carouselRepo,
 	}
 }
 
 func (s *CarouselService) CreateSlide(ctx context.Context, carousel *entity.Carousel) (*entity.Carousel, error) {
-	return s.carouselRepo.Create(ctx, carousel)
+	if err := attachMedia(ctx, s.mediaService, "image_id", carousel.ImageID, &carousel.Image); err != nil {
+		return nil, err
+	}
+
+	var created *entity.Carousel
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		created, err = s.carouselRepo.Create(ctx, carousel)
+		if err != nil {
+			return err
+		}
+		return s.auditService.Record(ctx, entity.AuditEntityCarousel, created.ID, entity.AuditActionCreate, nil, created)
+	})
+	return withSrcset(ctx, s.mediaService, created, err, carouselImage)
 }
 
-func (s *CarouselService) GetAllSlides(ctx context.Context) ([]entity.Carousel, error) {
-	return s.carouselRepo.GetAll(ctx)
+func (s *CarouselService) GetAllSlides(ctx context.Context, includeDeleted bool) ([]entity.Carousel, error) {
+	slides, err := s.carouselRepo.GetAll(ctx, includeDeleted)
+	return withSrcsets(ctx, s.mediaService, slides, err, carouselImage)
 }
 
 func (s *CarouselService) GetSlideByID(ctx context.Context, id int) (*entity.Carousel, error) {
-	return s.carouselRepo.GetByID(ctx, id)
+	slide, err := s.carouselRepo.GetByID(ctx, id)
+	return withSrcset(ctx, s.mediaService, slide, err, carouselImage)
+}
+
+func (s *CarouselService) UpdateSlide(ctx context.Context, id int, version int, carousel *entity.Carousel) (*entity.Carousel, error) {
+	carousel.Version = version
+
+	if err := attachMedia(ctx, s.mediaService, "image_id", carousel.ImageID, &carousel.Image); err != nil {
+		return nil, err
+	}
+
+	var updated *entity.Carousel
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		before, err := s.carouselRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		updated, err = s.carouselRepo.Update(ctx, id, carousel)
+		if err != nil {
+			return err
+		}
+		return s.auditService.Record(ctx, entity.AuditEntityCarousel, id, entity.AuditActionUpdate, before, updated)
+	})
+	return withSrcset(ctx, s.mediaService, updated, err, carouselImage)
+}
+
+func (s *CarouselService) DeleteSlide(ctx context.Context, id int, version int) error {
+	return s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		before, err := s.carouselRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := s.carouselRepo.Delete(ctx, id, version); err != nil {
+			return err
+		}
+		return s.auditService.Record(ctx, entity.AuditEntityCarousel, id, entity.AuditActionDelete, before, nil)
+	})
 }
 
-func (s *CarouselService) UpdateSlide(ctx context.Context, id int, carousel *entity.Carousel) (*entity.Carousel, error) {
-	return s.carouselRepo.Update(ctx, id, carousel)
+func (s *CarouselService) RestoreSlide(ctx context.Context, id int) error {
+	return s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.carouselRepo.Restore(ctx, id); err != nil {
+			return err
+		}
+
+		after, err := s.carouselRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		return s.auditService.Record(ctx, entity.AuditEntityCarousel, id, entity.AuditActionRestore, nil, after)
+	})
 }
 
-func (s *CarouselService) DeleteSlide(ctx context.Context, id int) error {
-	return s.carouselRepo.Delete(ctx, id)
+func carouselImage(slide *entity.Carousel) (*string, *entity.Srcset) {
+	return slide.ImageID, &slide.ImageSrcset
 }
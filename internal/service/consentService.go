@@ -0,0 +1,305 @@
+package service
+
+import (
+	"Clinic_backend/internal/entity"
+	"Clinic_backend/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrMandatoryConsentMissing    = errors.New("consent to the current mandatory documents is required")
+	ErrConsentDocumentNotCurrent  = errors.New("consent document is not the current published version")
+	ErrConsentWithdrawalForbidden = errors.New("mandatory consent cannot be withdrawn; request erasure of personal data instead")
+)
+
+// ConsentServiceInterface - версии документов (политика конфиденциальности, согласие на
+// обработку персональных данных, рассылки) и согласия пользователей с ними
+type ConsentServiceInterface interface {
+	ListDocuments(ctx context.Context, filter *entity.ConsentDocumentFilter, publishedOnly bool) ([]entity.ConsentDocument, error)
+	GetDocument(ctx context.Context, id int, publishedOnly bool) (*entity.ConsentDocument, error)
+	Current(ctx context.Context) ([]entity.ConsentDocument, error)
+	CreateDocument(ctx context.Context, req *entity.ConsentDocumentRequest) (*entity.ConsentDocument, error)
+	UpdateDocument(ctx context.Context, id int, req *entity.ConsentDocumentUpdateRequest) (*entity.ConsentDocument, error)
+	PublishDocument(ctx context.Context, id int) (*entity.ConsentDocument, error)
+	Status(ctx context.Context, userID int) ([]entity.ConsentStatus, error)
+	Pending(ctx context.Context, userID int) ([]entity.ConsentDocument, error)
+	AcceptAtRegistration(ctx context.Context, userID int, documentIDs []int) error
+	Accept(ctx context.Context, userID int, documentIDs []int) ([]entity.ConsentStatus, error)
+	Withdraw(ctx context.Context, userID int, consentType string) (*entity.UserConsent, error)
+	History(ctx context.Context, userID int) ([]entity.UserConsent, error)
+}
+
+type ConsentService struct {
+	txManager    repository.TransactionManagerInterface
+	auditService AuditServiceInterface
+	consentRepo  repository.ConsentRepositoryInterface
+}
+
+func NewConsentService(txManager repository.TransactionManagerInterface, auditService AuditServiceInterface, consentRepo repository.ConsentRepositoryInterface) ConsentServiceInterface {
+	return &ConsentService{
+		txManager:    txManager,
+		auditService: auditService,
+		consentRepo:  consentRepo,
+	}
+}
+
+func (s *ConsentService) ListDocuments(ctx context.Context, filter *entity.ConsentDocumentFilter, publishedOnly bool) ([]entity.ConsentDocument, error) {
+	ctx, span := tracer.Start(ctx, "ConsentService.ListDocuments")
+	defer span.End()
+
+	return s.consentRepo.ListDocuments(ctx, filter, publishedOnly)
+}
+
+// GetDocument возвращает версию документа. Черновики видны только администратору
+func (s *ConsentService) GetDocument(ctx context.Context, id int, publishedOnly bool) (*entity.ConsentDocument, error) {
+	ctx, span := tracer.Start(ctx, "ConsentService.GetDocument")
+	defer span.End()
+
+	document, err := s.consentRepo.GetDocument(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if publishedOnly && document.PublishedAt == nil {
+		return nil, repository.ErrConsentDocumentNotFound
+	}
+	return document, nil
+}
+
+// Current возвращает действующие версии документов - их показывают при регистрации
+func (s *ConsentService) Current(ctx context.Context) ([]entity.ConsentDocument, error) {
+	ctx, span := tracer.Start(ctx, "ConsentService.Current")
+	defer span.End()
+
+	return s.consentRepo.Current(ctx)
+}
+
+// CreateDocument создаёт черновик следующей версии; пользователи увидят его после публикации
+func (s *ConsentService) CreateDocument(ctx context.Context, req *entity.ConsentDocumentRequest) (*entity.ConsentDocument, error) {
+	ctx, span := tracer.Start(ctx, "ConsentService.CreateDocument")
+	defer span.End()
+
+	var created *entity.ConsentDocument
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		created, err = s.consentRepo.CreateDocument(ctx, req)
+		if err != nil {
+			return err
+		}
+		return s.auditService.Record(ctx, entity.AuditEntityConsentDocument, created.ID, entity.AuditActionCreate, nil, map[string]any{
+			"type":    created.Type,
+			"version": created.Version,
+			"title":   created.Title,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// UpdateDocument правит черновик; опубликованная версия неизменна
+func (s *ConsentService) UpdateDocument(ctx context.Context, id int, req *entity.ConsentDocumentUpdateRequest) (*entity.ConsentDocument, error) {
+	ctx, span := tracer.Start(ctx, "ConsentService.UpdateDocument")
+	defer span.End()
+
+	var updated *entity.ConsentDocument
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		current, err := s.consentRepo.GetDocument(ctx, id)
+		if err != nil {
+			return err
+		}
+		updated, err = s.consentRepo.UpdateDraft(ctx, id, req)
+		if err != nil {
+			return err
+		}
+		return s.auditService.Record(ctx, entity.AuditEntityConsentDocument, id, entity.AuditActionUpdate,
+			map[string]any{"title": current.Title, "content": current.Content},
+			map[string]any{"title": updated.Title, "content": updated.Content},
+		)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+// PublishDocument делает черновик действующей версией. Пользователи, согласившиеся с
+// прежней версией, получат запрос на повторное согласие при следующем входе
+func (s *ConsentService) PublishDocument(ctx context.Context, id int) (*entity.ConsentDocument, error) {
+	ctx, span := tracer.Start(ctx, "ConsentService.PublishDocument")
+	defer span.End()
+
+	var published *entity.ConsentDocument
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		published, err = s.consentRepo.Publish(ctx, id)
+		if err != nil {
+			return err
+		}
+		return s.auditService.Record(ctx, entity.AuditEntityConsentDocument, id, entity.AuditActionPublish, nil, map[string]any{
+			"type":         published.Type,
+			"version":      published.Version,
+			"published_at": published.PublishedAt,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return published, nil
+}
+
+// Status сопоставляет действующие версии документов с согласиями пользователя
+func (s *ConsentService) Status(ctx context.Context, userID int) ([]entity.ConsentStatus, error) {
+	ctx, span := tracer.Start(ctx, "ConsentService.Status")
+	defer span.End()
+
+	current, err := s.consentRepo.Current(ctx)
+	if err != nil {
+		return nil, err
+	}
+	active, err := s.consentRepo.ListActive(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	documents := make(map[string]entity.ConsentDocument, len(current))
+	for _, document := range current {
+		document.Content = ""
+		documents[document.Type] = document
+	}
+	accepted := make(map[string]entity.UserConsent, len(active))
+	for _, consent := range active {
+		accepted[consent.Type] = consent
+	}
+
+	statuses := make([]entity.ConsentStatus, 0, len(entity.ConsentTypes))
+	for _, consentType := range entity.ConsentTypes {
+		status := entity.ConsentStatus{
+			Type:      consentType,
+			Mandatory: entity.ConsentMandatory(consentType),
+		}
+		if document, ok := documents[consentType]; ok {
+			status.Current = &document
+		}
+		if consent, ok := accepted[consentType]; ok {
+			status.Accepted = &consent
+		}
+		// Пока документ не опубликован, соглашаться не с чем
+		status.UpToDate = status.Current == nil ||
+			(status.Accepted != nil && status.Accepted.DocumentID == status.Current.ID)
+		status.Reconsent = !status.UpToDate && (status.Mandatory || status.Accepted != nil)
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// Pending возвращает действующие версии, с которыми пользователю нужно согласиться заново
+func (s *ConsentService) Pending(ctx context.Context, userID int) ([]entity.ConsentDocument, error) {
+	ctx, span := tracer.Start(ctx, "ConsentService.Pending")
+	defer span.End()
+
+	statuses, err := s.Status(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []entity.ConsentDocument
+	for _, status := range statuses {
+		if status.Reconsent {
+			pending = append(pending, *status.Current)
+		}
+	}
+	return pending, nil
+}
+
+// AcceptAtRegistration сохраняет согласия нового пользователя. Среди них должны быть все
+// действующие обязательные документы; вызывается в транзакции создания учётной записи
+func (s *ConsentService) AcceptAtRegistration(ctx context.Context, userID int, documentIDs []int) error {
+	ctx, span := tracer.Start(ctx, "ConsentService.AcceptAtRegistration")
+	defer span.End()
+
+	current, err := s.consentRepo.Current(ctx)
+	if err != nil {
+		return err
+	}
+	if err := checkCurrentDocuments(current, documentIDs); err != nil {
+		return err
+	}
+
+	given := make(map[int]bool, len(documentIDs))
+	for _, id := range documentIDs {
+		given[id] = true
+	}
+	for _, document := range current {
+		if document.Mandatory && !given[document.ID] {
+			return fmt.Errorf("%w: %s version %d", ErrMandatoryConsentMissing, document.Type, document.Version)
+		}
+	}
+
+	if len(documentIDs) == 0 {
+		return nil
+	}
+	return s.consentRepo.Accept(ctx, userID, documentIDs, entity.ActorFromContext(ctx).IP)
+}
+
+// Accept сохраняет согласия пользователя с действующими версиями документов
+func (s *ConsentService) Accept(ctx context.Context, userID int, documentIDs []int) ([]entity.ConsentStatus, error) {
+	ctx, span := tracer.Start(ctx, "ConsentService.Accept")
+	defer span.End()
+
+	current, err := s.consentRepo.Current(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkCurrentDocuments(current, documentIDs); err != nil {
+		return nil, err
+	}
+
+	if err := s.consentRepo.Accept(ctx, userID, documentIDs, entity.ActorFromContext(ctx).IP); err != nil {
+		return nil, err
+	}
+
+	return s.Status(ctx, userID)
+}
+
+// Withdraw отзывает согласие с необязательным документом. Отказ от обязательных
+// согласий означает прекращение обработки данных - это запрос на удаление
+func (s *ConsentService) Withdraw(ctx context.Context, userID int, consentType string) (*entity.UserConsent, error) {
+	ctx, span := tracer.Start(ctx, "ConsentService.Withdraw")
+	defer span.End()
+
+	if entity.ConsentMandatory(consentType) {
+		return nil, ErrConsentWithdrawalForbidden
+	}
+
+	return s.consentRepo.Withdraw(ctx, userID, consentType, entity.ActorFromContext(ctx).IP)
+}
+
+// History возвращает все согласия пользователя, включая отозванные
+func (s *ConsentService) History(ctx context.Context, userID int) ([]entity.UserConsent, error) {
+	ctx, span := tracer.Start(ctx, "ConsentService.History")
+	defer span.End()
+
+	return s.consentRepo.History(ctx, userID)
+}
+
+// checkCurrentDocuments проверяет, что согласие дают с действующими версиями, а не
+// с черновиками или устаревшими редакциями
+func checkCurrentDocuments(current []entity.ConsentDocument, documentIDs []int) error {
+	ids := make(map[int]bool, len(current))
+	for _, document := range current {
+		ids[document.ID] = true
+	}
+	for _, id := range documentIDs {
+		if !ids[id] {
+			return fmt.Errorf("%w: %d", ErrConsentDocumentNotCurrent, id)
+		}
+	}
+	return nil
+}
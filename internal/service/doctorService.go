@@ -2,6 +2,7 @@ package service
 
 import (
 	"Clinic_backend/internal/entity"
+	"Clinic_backend/internal/logging"
 	"Clinic_backend/internal/repository"
 	"context"
 	"errors"
@@ -9,22 +10,29 @@ import (
 
 type DoctorServiceInterface interface {
 	CreateDoctor(ctx context.Context, req *entity.DoctorCreateRequest) (*entity.Doctor, error)
-	GetAllDoctors(ctx context.Context) ([]entity.Doctor, error)
+	GetAllDoctors(ctx context.Context, includeDeleted bool, sort string) ([]entity.Doctor, error)
 	GetDoctorByID(ctx context.Context, id int) (*entity.Doctor, error)
 	GetDoctorsBySpecialization(ctx context.Context, specID int) ([]entity.Doctor, error)
-	UpdateDoctor(ctx context.Context, id int, req *entity.DoctorUpdateRequest) (*entity.Doctor, error)
-	DeleteDoctor(ctx context.Context, id int) error
+	UpdateDoctor(ctx context.Context, id int, version int, req *entity.DoctorUpdateRequest) (*entity.Doctor, error)
+	DeleteDoctor(ctx context.Context, id int, version int) error
+	RestoreDoctor(ctx context.Context, id int) error
 	GetDoctorSchedule(ctx context.Context, doctorID int) (*entity.Schedule, error)
 }
 
 type DoctorService struct {
+	txManager    repository.TransactionManagerInterface
+	auditService AuditServiceInterface
+	mediaService MediaServiceInterface
 	doctorRepo   repository.DoctorRepositoryInterface
 	specRepo     repository.SpecializationRepositoryInterface
 	scheduleRepo repository.ScheduleRepositoryInterface
 }
 
-func NewDoctorService(doctorRepo repository.DoctorRepositoryInterface, specRepo repository.SpecializationRepositoryInterface, scheduleRepo repository.ScheduleRepositoryInterface) DoctorServiceInterface {
+func NewDoctorService(txManager repository.TransactionManagerInterface, auditService AuditServiceInterface, mediaService MediaServiceInterface, doctorRepo repository.DoctorRepositoryInterface, specRepo repository.SpecializationRepositoryInterface, scheduleRepo repository.ScheduleRepositoryInterface) DoctorServiceInterface {
 	return &DoctorService{
+		txManager:    txManager,
+		auditService: auditService,
+		mediaService: mediaService,
 		doctorRepo:   doctorRepo,
 		specRepo:     specRepo,
 		scheduleRepo: scheduleRepo,
@@ -32,6 +40,9 @@ func NewDoctorService(doctorRepo repository.DoctorRepositoryInterface, specRepo
 }
 
 func (s *DoctorService) CreateDoctor(ctx context.Context, req *entity.DoctorCreateRequest) (*entity.Doctor, error) {
+	ctx, span := tracer.Start(ctx, "DoctorService.CreateDoctor")
+	defer span.End()
+
 	// Валидация schedule_id если указан
 	if req.ScheduleID != nil {
 		_, err := s.scheduleRepo.GetByID(ctx, *req.ScheduleID)
@@ -41,78 +52,88 @@ func (s *DoctorService) CreateDoctor(ctx context.Context, req *entity.DoctorCrea
 	}
 
 	doctor := &entity.Doctor{
-		Fullname:    req.Fullname,
-		Description: req.Description,
-		DoctorPhoto: req.DoctorPhoto,
-		ScheduleID:  req.ScheduleID,
+		Fullname:      req.Fullname,
+		Description:   req.Description,
+		DoctorPhoto:   req.DoctorPhoto,
+		DoctorPhotoID: req.DoctorPhotoID,
+		ScheduleID:    req.ScheduleID,
+		UserID:        req.UserID,
 	}
-
-	created, err := s.doctorRepo.Create(ctx, doctor)
-	if err != nil {
+	if err := attachMedia(ctx, s.mediaService, "doctor_photo_id", doctor.DoctorPhotoID, &doctor.DoctorPhoto); err != nil {
 		return nil, err
 	}
 
-	// Добавляем специализации
-	for _, specID := range req.SpecializationIDs {
-		if err := s.doctorRepo.AddSpecialization(ctx, created.ID, specID); err != nil {
-			return nil, err
+	// Врач и его специализации создаются атомарно
+	var created *entity.Doctor
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		created, err = s.doctorRepo.Create(ctx, doctor)
+		if err != nil {
+			return err
 		}
-	}
 
-	// Загружаем специализации
-	specializations, err := s.doctorRepo.GetSpecializations(ctx, created.ID)
-	if err == nil {
-		created.Specializations = specializations
-	}
-
-	// Загружаем расписание если есть
-	if created.ScheduleID != nil {
-		schedule, err := s.scheduleRepo.GetByID(ctx, *created.ScheduleID)
-		if err == nil {
-			created.Schedule = schedule
+		// Добавляем специализации
+		for _, specID := range req.SpecializationIDs {
+			if err := s.doctorRepo.AddSpecialization(ctx, created.ID, specID); err != nil {
+				return err
+			}
 		}
-	}
 
-	return created, nil
+		// Загружаем специализации и расписание
+		created, err = s.getDoctor(ctx, created.ID)
+		if err != nil {
+			return err
+		}
+		return s.auditService.Record(ctx, entity.AuditEntityDoctor, created.ID, entity.AuditActionCreate, nil, created)
+	})
+	return withSrcset(ctx, s.mediaService, created, err, doctorPhoto)
 }
 
-func (s *DoctorService) GetAllDoctors(ctx context.Context) ([]entity.Doctor, error) {
-	doctors, err := s.doctorRepo.GetAll(ctx)
+func (s *DoctorService) GetAllDoctors(ctx context.Context, includeDeleted bool, sort string) ([]entity.Doctor, error) {
+	ctx, span := tracer.Start(ctx, "DoctorService.GetAllDoctors")
+	defer span.End()
+
+	doctors, err := s.doctorRepo.GetAll(ctx, includeDeleted, sort)
 	if err != nil {
 		return nil, err
 	}
 
-	// Загружаем специализации для каждого врача
-	for i := range doctors {
-		specializations, _ := s.doctorRepo.GetSpecializations(ctx, doctors[i].ID)
-		doctors[i].Specializations = specializations
-
-		// Загружаем расписание
-		if doctors[i].ScheduleID != nil {
-			schedule, err := s.scheduleRepo.GetByID(ctx, *doctors[i].ScheduleID)
-			if err == nil {
-				doctors[i].Schedule = schedule
-			}
-		}
+	if err := s.loadRelations(ctx, doctors); err != nil {
+		return nil, err
 	}
 
 	return doctors, nil
 }
 
 func (s *DoctorService) GetDoctorByID(ctx context.Context, id int) (*entity.Doctor, error) {
+	ctx, span := tracer.Start(ctx, "DoctorService.GetDoctorByID")
+	defer span.End()
+
+	doctor, err := s.getDoctor(ctx, id)
+	return withSrcset(ctx, s.mediaService, doctor, err, doctorPhoto)
+}
+
+// getDoctor загружает врача со специализациями и расписанием. Адреса копий фото
+// не заполняются: результат попадает в журнал аудита, а они вычисляются из настроек.
+func (s *DoctorService) getDoctor(ctx context.Context, id int) (*entity.Doctor, error) {
 	doctor, err := s.doctorRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
 	// Загружаем специализации
-	specializations, _ := s.doctorRepo.GetSpecializations(ctx, doctor.ID)
+	specializations, err := s.doctorRepo.GetSpecializations(ctx, doctor.ID)
+	if err != nil {
+		logging.FromContext(ctx).Warn("Failed to load doctor specializations", "doctor_id", doctor.ID, "error", err.Error())
+	}
 	doctor.Specializations = specializations
 
 	// Загружаем расписание
 	if doctor.ScheduleID != nil {
 		schedule, err := s.scheduleRepo.GetByID(ctx, *doctor.ScheduleID)
-		if err == nil {
+		if err != nil {
+			logging.FromContext(ctx).Warn("Failed to load doctor schedule", "doctor_id", doctor.ID, "schedule_id", *doctor.ScheduleID, "error", err.Error())
+		} else {
 			doctor.Schedule = schedule
 		}
 	}
@@ -121,26 +142,37 @@ func (s *DoctorService) GetDoctorByID(ctx context.Context, id int) (*entity.Doct
 }
 
 func (s *DoctorService) GetDoctorsBySpecialization(ctx context.Context, specID int) ([]entity.Doctor, error) {
+	ctx, span := tracer.Start(ctx, "DoctorService.GetDoctorsBySpecialization")
+	defer span.End()
+
 	doctors, err := s.doctorRepo.GetBySpecialization(ctx, specID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Загружаем специализации для каждого врача
-	for i := range doctors {
-		specializations, _ := s.doctorRepo.GetSpecializations(ctx, doctors[i].ID)
-		doctors[i].Specializations = specializations
+	if err := s.loadRelations(ctx, doctors); err != nil {
+		return nil, err
 	}
 
 	return doctors, nil
 }
 
-func (s *DoctorService) UpdateDoctor(ctx context.Context, id int, req *entity.DoctorUpdateRequest) (*entity.Doctor, error) {
-	existing, err := s.doctorRepo.GetByID(ctx, id)
+func (s *DoctorService) UpdateDoctor(ctx context.Context, id int, version int, req *entity.DoctorUpdateRequest) (*entity.Doctor, error) {
+	ctx, span := tracer.Start(ctx, "DoctorService.UpdateDoctor")
+	defer span.End()
+
+	existing, err := s.getDoctor(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
+	// Клиент редактировал устаревшую версию
+	if version != repository.AnyVersion && existing.Version != version {
+		return nil, repository.ErrVersionConflict
+	}
+
+	before := *existing
+
 	// Обновляем только переданные поля
 	if req.Fullname != nil {
 		existing.Fullname = *req.Fullname
@@ -148,49 +180,106 @@ func (s *DoctorService) UpdateDoctor(ctx context.Context, id int, req *entity.Do
 	if req.Description != nil {
 		existing.Description = req.Description
 	}
+	// Внешняя ссылка заменяет загруженное фото, загруженное фото - внешнюю ссылку
 	if req.DoctorPhoto != nil {
 		existing.DoctorPhoto = req.DoctorPhoto
+		existing.DoctorPhotoID = nil
+	}
+	if req.DoctorPhotoID != nil {
+		existing.DoctorPhotoID = req.DoctorPhotoID
+		if err := attachMedia(ctx, s.mediaService, "doctor_photo_id", existing.DoctorPhotoID, &existing.DoctorPhoto); err != nil {
+			return nil, err
+		}
 	}
 	if req.ScheduleID != nil {
 		existing.ScheduleID = req.ScheduleID
 	}
-
-	_, err = s.doctorRepo.Update(ctx, id, existing)
-	if err != nil {
-		return nil, err
+	if req.UserID != nil {
+		existing.UserID = req.UserID
+		if *req.UserID == 0 {
+			existing.UserID = nil
+		}
 	}
 
-	// Обновляем специализации если переданы
-	if len(req.SpecializationIDs) > 0 {
-		// Получаем текущие специализации
-		currentSpecs, _ := s.doctorRepo.GetSpecializations(ctx, id)
+	// Данные врача и специализации обновляются атомарно
+	var updated *entity.Doctor
+	err = s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		_, err := s.doctorRepo.Update(ctx, id, existing)
+		if err != nil {
+			return err
+		}
 
-		// Удаляем старые
-		for _, spec := range currentSpecs {
-			err := s.doctorRepo.RemoveSpecialization(ctx, id, spec.ID)
+		// Обновляем специализации если переданы
+		if len(req.SpecializationIDs) > 0 {
+			// Получаем текущие специализации
+			currentSpecs, err := s.doctorRepo.GetSpecializations(ctx, id)
 			if err != nil {
-				return nil, err
+				return err
 			}
-		}
 
-		// Добавляем новые
-		for _, specID := range req.SpecializationIDs {
-			err := s.doctorRepo.AddSpecialization(ctx, id, specID)
-			if err != nil {
-				return nil, err
+			// Удаляем старые
+			for _, spec := range currentSpecs {
+				if err := s.doctorRepo.RemoveSpecialization(ctx, id, spec.ID); err != nil {
+					return err
+				}
+			}
+
+			// Добавляем новые
+			for _, specID := range req.SpecializationIDs {
+				if err := s.doctorRepo.AddSpecialization(ctx, id, specID); err != nil {
+					return err
+				}
 			}
 		}
-	}
 
-	// Загружаем обновленные данные
-	return s.GetDoctorByID(ctx, id)
+		// Загружаем обновленные данные
+		updated, err = s.getDoctor(ctx, id)
+		if err != nil {
+			return err
+		}
+		return s.auditService.Record(ctx, entity.AuditEntityDoctor, id, entity.AuditActionUpdate, &before, updated)
+	})
+	return withSrcset(ctx, s.mediaService, updated, err, doctorPhoto)
 }
 
-func (s *DoctorService) DeleteDoctor(ctx context.Context, id int) error {
-	return s.doctorRepo.Delete(ctx, id)
+func (s *DoctorService) DeleteDoctor(ctx context.Context, id int, version int) error {
+	ctx, span := tracer.Start(ctx, "DoctorService.DeleteDoctor")
+	defer span.End()
+
+	return s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		before, err := s.getDoctor(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := s.doctorRepo.Delete(ctx, id, version); err != nil {
+			return err
+		}
+		return s.auditService.Record(ctx, entity.AuditEntityDoctor, id, entity.AuditActionDelete, before, nil)
+	})
+}
+
+func (s *DoctorService) RestoreDoctor(ctx context.Context, id int) error {
+	ctx, span := tracer.Start(ctx, "DoctorService.RestoreDoctor")
+	defer span.End()
+
+	return s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.doctorRepo.Restore(ctx, id); err != nil {
+			return err
+		}
+
+		after, err := s.getDoctor(ctx, id)
+		if err != nil {
+			return err
+		}
+		return s.auditService.Record(ctx, entity.AuditEntityDoctor, id, entity.AuditActionRestore, nil, after)
+	})
 }
 
 func (s *DoctorService) GetDoctorSchedule(ctx context.Context, doctorID int) (*entity.Schedule, error) {
+	ctx, span := tracer.Start(ctx, "DoctorService.GetDoctorSchedule")
+	defer span.End()
+
 	doctor, err := s.doctorRepo.GetByID(ctx, doctorID)
 	if err != nil {
 		return nil, err
@@ -202,3 +291,47 @@ func (s *DoctorService) GetDoctorSchedule(ctx context.Context, doctorID int) (*e
 
 	return s.scheduleRepo.GetByID(ctx, *doctor.ScheduleID)
 }
+
+// loadRelations подгружает специализации, расписания и адреса копий фото для списка врачей
+// фиксированным числом запросов, независимо от количества врачей
+func (s *DoctorService) loadRelations(ctx context.Context, doctors []entity.Doctor) error {
+	ctx, span := tracer.Start(ctx, "DoctorService.loadRelations")
+	defer span.End()
+
+	if len(doctors) == 0 {
+		return nil
+	}
+
+	doctorIDs := make([]int, 0, len(doctors))
+	scheduleIDs := make([]int, 0, len(doctors))
+	for _, doctor := range doctors {
+		doctorIDs = append(doctorIDs, doctor.ID)
+		if doctor.ScheduleID != nil {
+			scheduleIDs = append(scheduleIDs, *doctor.ScheduleID)
+		}
+	}
+
+	specializations, err := s.doctorRepo.GetSpecializationsForDoctors(ctx, doctorIDs)
+	if err != nil {
+		return err
+	}
+
+	schedules, err := s.scheduleRepo.GetByIDs(ctx, scheduleIDs)
+	if err != nil {
+		return err
+	}
+
+	for i := range doctors {
+		doctors[i].Specializations = specializations[doctors[i].ID]
+		if doctors[i].ScheduleID != nil {
+			doctors[i].Schedule = schedules[*doctors[i].ScheduleID]
+		}
+	}
+
+	_, err = withSrcsets(ctx, s.mediaService, doctors, nil, doctorPhoto)
+	return err
+}
+
+func doctorPhoto(doctor *entity.Doctor) (*string, *entity.Srcset) {
+	return doctor.DoctorPhotoID, &doctor.DoctorPhotoSrcset
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"Clinic_backend/internal/entity"
+	"Clinic_backend/internal/repository"
+	"context"
+	"fmt"
+	"testing"
+)
+
+// Каждый вызов пакетных методов репозиториев - ровно один запрос к базе
+// (см. repository.BenchmarkGetSpecializationsForDoctors), поэтому здесь считаются вызовы
+
+type countingDoctorRepo struct {
+	repository.DoctorRepositoryInterface
+	calls int
+}
+
+func (r *countingDoctorRepo) GetSpecializationsForDoctors(ctx context.Context, doctorIDs []int) (map[int][]entity.Specialization, error) {
+	r.calls++
+	result := make(map[int][]entity.Specialization, len(doctorIDs))
+	for _, id := range doctorIDs {
+		result[id] = []entity.Specialization{{ID: 1, Name: "spec"}}
+	}
+	return result, nil
+}
+
+type countingScheduleRepo struct {
+	repository.ScheduleRepositoryInterface
+	calls int
+}
+
+func (r *countingScheduleRepo) GetByIDs(ctx context.Context, ids []int) (map[int]*entity.Schedule, error) {
+	r.calls++
+	result := make(map[int]*entity.Schedule, len(ids))
+	for _, id := range ids {
+		result[id] = &entity.Schedule{ID: id, Day: id%7 + 1}
+	}
+	return result, nil
+}
+
+type countingMediaService struct {
+	MediaServiceInterface
+	calls int
+}
+
+func (s *countingMediaService) Srcsets(ctx context.Context, ids []string) (map[string]entity.Srcset, error) {
+	s.calls++
+	return make(map[string]entity.Srcset, len(ids)), nil
+}
+
+func testDoctors(n int) []entity.Doctor {
+	doctors := make([]entity.Doctor, n)
+	for i := range doctors {
+		scheduleID := i + 1
+		photoID := fmt.Sprintf("photo-%d", i+1)
+		doctors[i] = entity.Doctor{ID: i + 1, ScheduleID: &scheduleID, DoctorPhotoID: &photoID}
+	}
+	return doctors
+}
+
+func BenchmarkLoadRelations(b *testing.B) {
+	for _, n := range []int{1, 10, 100, 1000} {
+		b.Run(fmt.Sprintf("doctors=%d", n), func(b *testing.B) {
+			doctorRepo := &countingDoctorRepo{}
+			scheduleRepo := &countingScheduleRepo{}
+			mediaService := &countingMediaService{}
+			s := &DoctorService{doctorRepo: doctorRepo, scheduleRepo: scheduleRepo, mediaService: mediaService}
+			doctors := testDoctors(n)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := s.loadRelations(context.Background(), doctors); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.StopTimer()
+
+			queries := doctorRepo.calls + scheduleRepo.calls + mediaService.calls
+			if queries != 3*b.N {
+				b.Fatalf("%d queries for %d calls, want 3 per call", queries, b.N)
+			}
+			b.ReportMetric(float64(queries)/float64(b.N), "queries/op")
+		})
+	}
+}
+
+func TestLoadRelationsQueryCount(t *testing.T) {
+	for _, n := range []int{0, 1, 50} {
+		doctorRepo := &countingDoctorRepo{}
+		scheduleRepo := &countingScheduleRepo{}
+		mediaService := &countingMediaService{}
+		s := &DoctorService{doctorRepo: doctorRepo, scheduleRepo: scheduleRepo, mediaService: mediaService}
+		doctors := testDoctors(n)
+
+		if err := s.loadRelations(context.Background(), doctors); err != nil {
+			t.Fatal(err)
+		}
+
+		want := 1
+		if n == 0 {
+			want = 0
+		}
+		if doctorRepo.calls != want || scheduleRepo.calls != want || mediaService.calls != want {
+			t.Errorf("%d doctors: specializations %d, schedules %d, srcsets %d queries, want %d each",
+				n, doctorRepo.calls, scheduleRepo.calls, mediaService.calls, want)
+		}
+		for _, doctor := range doctors {
+			if len(doctor.Specializations) != 1 || doctor.Schedule == nil || doctor.Schedule.ID != *doctor.ScheduleID {
+				t.Errorf("doctor %d: relations not attached", doctor.ID)
+			}
+		}
+	}
+}
@@ -0,0 +1,416 @@
+package service
+
+import (
+	"Clinic_backend/internal/entity"
+	"Clinic_backend/internal/icd10"
+	"Clinic_backend/internal/repository"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidEncounter - содержание записи не прошло проверку
+	ErrInvalidEncounter = errors.New("invalid encounter")
+	// ErrEncounterForbidden - создавать и менять записи может только лечащий врач пациента
+	ErrEncounterForbidden = errors.New("only the treating doctor can write this encounter")
+	// ErrEncounterLocked - подписанная запись меняется только дополнением
+	ErrEncounterLocked = errors.New("encounter is signed; add an amendment instead")
+	// ErrEncounterNotSigned - дополнение возможно только к подписанной записи
+	ErrEncounterNotSigned = errors.New("encounter is not signed; edit the draft instead")
+)
+
+const icd10SearchLimit = 50
+
+type EncounterServiceInterface interface {
+	SearchICD10(query string, limit int) []icd10.Code
+	Create(ctx context.Context, doctorUserID int, req *entity.EncounterCreateRequest) (*entity.Encounter, error)
+	GetByID(ctx context.Context, viewerUserID, id int) (*entity.Encounter, error)
+	List(ctx context.Context, viewerUserID int, filter *entity.EncounterFilter) ([]entity.Encounter, int, error)
+	ListRevisions(ctx context.Context, viewerUserID, id int) ([]entity.EncounterRevision, error)
+	UpdateDraft(ctx context.Context, doctorUserID, id, version int, req *entity.EncounterContentRequest) (*entity.Encounter, error)
+	Sign(ctx context.Context, doctorUserID, id, version int) (*entity.Encounter, error)
+	Amend(ctx context.Context, doctorUserID, id, version int, req *entity.EncounterAmendmentRequest) (*entity.Encounter, error)
+	ListForPatient(ctx context.Context, userID int, patientID *int, filter *entity.EncounterFilter) ([]entity.Encounter, int, error)
+	GetForPatient(ctx context.Context, userID int, patientID *int, id int) (*entity.Encounter, error)
+}
+
+type EncounterService struct {
+	txManager      repository.TransactionManagerInterface
+	auditService   AuditServiceInterface
+	patientService PatientServiceInterface
+	icd            *icd10.Table
+	encounterRepo  repository.EncounterRepositoryInterface
+}
+
+func NewEncounterService(txManager repository.TransactionManagerInterface, auditService AuditServiceInterface, patientService PatientServiceInterface, icd *icd10.Table, encounterRepo repository.EncounterRepositoryInterface) EncounterServiceInterface {
+	return &EncounterService{
+		txManager:      txManager,
+		auditService:   auditService,
+		patientService: patientService,
+		icd:            icd,
+		encounterRepo:  encounterRepo,
+	}
+}
+
+func (s *EncounterService) SearchICD10(query string, limit int) []icd10.Code {
+	if limit < 1 || limit > icd10SearchLimit {
+		limit = 20
+	}
+	return s.icd.Search(query, limit)
+}
+
+// Create создаёт черновик записи о приёме от имени лечащего врача
+func (s *EncounterService) Create(ctx context.Context, doctorUserID int, req *entity.EncounterCreateRequest) (*entity.Encounter, error) {
+	ctx, span := tracer.Start(ctx, "EncounterService.Create")
+	defer span.End()
+
+	content, err := s.encounterContent(&req.EncounterContentRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	var created *entity.Encounter
+	err = s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := s.patientService.GetByID(ctx, req.PatientID); err != nil {
+			return err
+		}
+
+		// Карту ведёт только лечащий врач пациента
+		treating, err := s.patientService.IsTreatingDoctor(ctx, doctorUserID, req.PatientID)
+		if err != nil {
+			return err
+		}
+		if !treating {
+			return ErrEncounterForbidden
+		}
+
+		created, err = s.encounterRepo.Create(ctx, &entity.Encounter{
+			PatientID:        req.PatientID,
+			DoctorUserID:     doctorUserID,
+			EncounterContent: *content,
+		})
+		if err != nil {
+			return err
+		}
+		return s.recordAudit(ctx, entity.AuditActionCreate, nil, created)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// GetByID возвращает запись врачу или администратору. Черновик виден только его автору
+func (s *EncounterService) GetByID(ctx context.Context, viewerUserID, id int) (*entity.Encounter, error) {
+	encounter, err := s.encounterRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if encounter.Status == entity.EncounterStatusDraft && encounter.DoctorUserID != viewerUserID {
+		return nil, repository.ErrEncounterNotFound
+	}
+	return encounter, nil
+}
+
+func (s *EncounterService) List(ctx context.Context, viewerUserID int, filter *entity.EncounterFilter) ([]entity.Encounter, int, error) {
+	ctx, span := tracer.Start(ctx, "EncounterService.List")
+	defer span.End()
+
+	normalizePage(&filter.Page, &filter.Limit)
+	filter.ViewerUserID = viewerUserID
+	return s.encounterRepo.List(ctx, filter)
+}
+
+func (s *EncounterService) ListRevisions(ctx context.Context, viewerUserID, id int) ([]entity.EncounterRevision, error) {
+	if _, err := s.GetByID(ctx, viewerUserID, id); err != nil {
+		return nil, err
+	}
+	return s.encounterRepo.ListRevisions(ctx, id)
+}
+
+// UpdateDraft заменяет содержание черновика
+func (s *EncounterService) UpdateDraft(ctx context.Context, doctorUserID, id, version int, req *entity.EncounterContentRequest) (*entity.Encounter, error) {
+	ctx, span := tracer.Start(ctx, "EncounterService.UpdateDraft")
+	defer span.End()
+
+	content, err := s.encounterContent(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var updated *entity.Encounter
+	err = s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		before, err := s.editable(ctx, doctorUserID, id, version)
+		if err != nil {
+			return err
+		}
+		if before.Status != entity.EncounterStatusDraft {
+			return ErrEncounterLocked
+		}
+
+		updated, err = s.encounterRepo.UpdateDraft(ctx, id, version, content)
+		if err != nil {
+			return err
+		}
+		return s.recordAudit(ctx, entity.AuditActionUpdate, &before.EncounterContent, updated)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+// Sign подписывает черновик. Подписанная запись становится первой редакцией
+// и дальше меняется только дополнениями
+func (s *EncounterService) Sign(ctx context.Context, doctorUserID, id, version int) (*entity.Encounter, error) {
+	ctx, span := tracer.Start(ctx, "EncounterService.Sign")
+	defer span.End()
+
+	var signed *entity.Encounter
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		before, err := s.editable(ctx, doctorUserID, id, version)
+		if err != nil {
+			return err
+		}
+		if before.Status != entity.EncounterStatusDraft {
+			return ErrEncounterLocked
+		}
+		if err := checkSignable(&before.EncounterContent); err != nil {
+			return err
+		}
+
+		hash, err := encounterHash(before, 1, &before.EncounterContent)
+		if err != nil {
+			return err
+		}
+
+		signed, err = s.encounterRepo.Sign(ctx, id, version, hash)
+		if err != nil {
+			return err
+		}
+		if err := s.encounterRepo.CreateRevision(ctx, &entity.EncounterRevision{
+			EncounterID:      id,
+			Revision:         1,
+			AuthorUserID:     doctorUserID,
+			EncounterContent: before.EncounterContent,
+			ContentHash:      hash,
+		}); err != nil {
+			return err
+		}
+		return s.recordAudit(ctx, entity.AuditActionSign, &before.EncounterContent, signed)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return signed, nil
+}
+
+// Amend добавляет к подписанной записи новую редакцию. Предыдущие редакции остаются в истории
+func (s *EncounterService) Amend(ctx context.Context, doctorUserID, id, version int, req *entity.EncounterAmendmentRequest) (*entity.Encounter, error) {
+	ctx, span := tracer.Start(ctx, "EncounterService.Amend")
+	defer span.End()
+
+	content, err := s.encounterContent(&req.EncounterContentRequest)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSignable(content); err != nil {
+		return nil, err
+	}
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, fmt.Errorf("%w: reason is required", ErrInvalidEncounter)
+	}
+
+	var amended *entity.Encounter
+	err = s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		before, err := s.editable(ctx, doctorUserID, id, version)
+		if err != nil {
+			return err
+		}
+		if before.Status != entity.EncounterStatusSigned {
+			return ErrEncounterNotSigned
+		}
+
+		revision := before.Revision + 1
+		hash, err := encounterHash(before, revision, content)
+		if err != nil {
+			return err
+		}
+
+		amended, err = s.encounterRepo.Amend(ctx, id, version, revision, content, hash)
+		if err != nil {
+			return err
+		}
+		if err := s.encounterRepo.CreateRevision(ctx, &entity.EncounterRevision{
+			EncounterID:      id,
+			Revision:         revision,
+			AuthorUserID:     doctorUserID,
+			Reason:           &reason,
+			EncounterContent: *content,
+			ContentHash:      hash,
+		}); err != nil {
+			return err
+		}
+		return s.recordAudit(ctx, entity.AuditActionAmend, &before.EncounterContent, amended)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return amended, nil
+}
+
+// ListForPatient возвращает подписанные записи пациента; черновики пациенту не показываются
+func (s *EncounterService) ListForPatient(ctx context.Context, userID int, patientID *int, filter *entity.EncounterFilter) ([]entity.Encounter, int, error) {
+	ctx, span := tracer.Start(ctx, "EncounterService.ListForPatient")
+	defer span.End()
+
+	patient, err := s.patientService.ResolvePatient(ctx, userID, patientID, entity.PatientAccessView)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	normalizePage(&filter.Page, &filter.Limit)
+	filter.PatientID = &patient.ID
+	filter.Status = entity.EncounterStatusSigned
+	filter.ViewerUserID = 0
+	return s.encounterRepo.List(ctx, filter)
+}
+
+func (s *EncounterService) GetForPatient(ctx context.Context, userID int, patientID *int, id int) (*entity.Encounter, error) {
+	patient, err := s.patientService.ResolvePatient(ctx, userID, patientID, entity.PatientAccessView)
+	if err != nil {
+		return nil, err
+	}
+
+	encounter, err := s.encounterRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if encounter.PatientID != patient.ID || encounter.Status != entity.EncounterStatusSigned {
+		return nil, repository.ErrEncounterNotFound
+	}
+	return encounter, nil
+}
+
+// editable загружает запись для изменения её лечащим врачом
+func (s *EncounterService) editable(ctx context.Context, doctorUserID, id, version int) (*entity.Encounter, error) {
+	encounter, err := s.GetByID(ctx, doctorUserID, id)
+	if err != nil {
+		return nil, err
+	}
+	if encounter.DoctorUserID != doctorUserID {
+		return nil, ErrEncounterForbidden
+	}
+	// Клиент редактировал устаревшую версию
+	if version != repository.AnyVersion && encounter.Version != version {
+		return nil, repository.ErrVersionConflict
+	}
+	return encounter, nil
+}
+
+// encounterContent проверяет содержание записи и подставляет названия диагнозов из справочника
+func (s *EncounterService) encounterContent(req *entity.EncounterContentRequest) (*entity.EncounterContent, error) {
+	encounterDate, err := time.Parse(time.DateOnly, strings.TrimSpace(req.EncounterDate))
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid encounter_date format (expected YYYY-MM-DD)", ErrInvalidEncounter)
+	}
+	if encounterDate.After(time.Now()) {
+		return nil, fmt.Errorf("%w: encounter_date is in the future", ErrInvalidEncounter)
+	}
+
+	content := &entity.EncounterContent{
+		EncounterDate:   encounterDate.Format(time.DateOnly),
+		Complaints:      trimmedOrNil(req.Complaints),
+		Anamnesis:       trimmedOrNil(req.Anamnesis),
+		Examination:     trimmedOrNil(req.Examination),
+		Diagnoses:       make([]entity.Diagnosis, 0, len(req.Diagnoses)),
+		Recommendations: trimmedOrNil(req.Recommendations),
+	}
+
+	seen := make(map[string]bool, len(req.Diagnoses))
+	primary := 0
+	for _, diagnosis := range req.Diagnoses {
+		code, ok := s.icd.Lookup(diagnosis.Code)
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown ICD-10 code %q", ErrInvalidEncounter, diagnosis.Code)
+		}
+		if seen[code.Code] {
+			return nil, fmt.Errorf("%w: duplicate diagnosis %s", ErrInvalidEncounter, code.Code)
+		}
+		seen[code.Code] = true
+		if diagnosis.Kind == entity.DiagnosisPrimary {
+			primary++
+		}
+		content.Diagnoses = append(content.Diagnoses, entity.Diagnosis{Code: code.Code, Title: code.Title, Kind: diagnosis.Kind})
+	}
+	if primary > 1 {
+		return nil, fmt.Errorf("%w: only one primary diagnosis is allowed", ErrInvalidEncounter)
+	}
+
+	return content, nil
+}
+
+// checkSignable - подписать можно запись с основным диагнозом
+func checkSignable(content *entity.EncounterContent) error {
+	for _, diagnosis := range content.Diagnoses {
+		if diagnosis.Kind == entity.DiagnosisPrimary {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: a primary diagnosis is required to sign", ErrInvalidEncounter)
+}
+
+// encounterHash - SHA-256 редакции: запись, пациент, врач, номер редакции и содержание.
+// Сохраняется вместе с редакцией и позволяет обнаружить её подмену в базе.
+func encounterHash(encounter *entity.Encounter, revision int, content *entity.EncounterContent) (string, error) {
+	data, err := json.Marshal(struct {
+		EncounterID  int                      `json:"encounter_id"`
+		PatientID    int                      `json:"patient_id"`
+		DoctorUserID int                      `json:"doctor_user_id"`
+		Revision     int                      `json:"revision"`
+		Content      *entity.EncounterContent `json:"content"`
+	}{encounter.ID, encounter.PatientID, encounter.DoctorUserID, revision, content})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal encounter revision: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// recordAudit пишет в журнал только метаданные записи и имена изменённых полей:
+// журнал неизменяем, а клинические данные должны удаляться вместе с картой
+func (s *EncounterService) recordAudit(ctx context.Context, action string, before *entity.EncounterContent, after *entity.Encounter) error {
+	changed, err := changedFields(before, &after.EncounterContent)
+	if err != nil {
+		return err
+	}
+
+	return s.auditService.Record(ctx, entity.AuditEntityEncounter, after.ID, action, nil, map[string]any{
+		"patient_id":     after.PatientID,
+		"status":         after.Status,
+		"revision":       after.Revision,
+		"changed_fields": changed,
+	})
+}
+
+// normalizePage подставляет первую страницу и размер по умолчанию
+func normalizePage(page, limit *int) {
+	if *page < 1 {
+		*page = 1
+	}
+	if *limit < 1 || *limit > 100 {
+		*limit = 50
+	}
+}
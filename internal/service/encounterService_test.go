@@ -0,0 +1,70 @@
+package service
+
+import (
+	"Clinic_backend/internal/entity"
+	"Clinic_backend/internal/repository"
+	"context"
+	"errors"
+	"testing"
+)
+
+// treatingPatients - пациенты и назначенные им врачи: patientID -> doctorUserID
+type treatingPatients struct {
+	PatientServiceInterface
+	doctors map[int]int
+}
+
+func (p *treatingPatients) GetByID(ctx context.Context, id int) (*entity.PatientProfile, error) {
+	if _, ok := p.doctors[id]; !ok {
+		return nil, repository.ErrPatientNotFound
+	}
+	return &entity.PatientProfile{ID: id}, nil
+}
+
+func (p *treatingPatients) IsTreatingDoctor(ctx context.Context, doctorUserID, patientID int) (bool, error) {
+	return p.doctors[patientID] == doctorUserID, nil
+}
+
+type recordingEncounterRepo struct {
+	repository.EncounterRepositoryInterface
+	created []entity.Encounter
+}
+
+func (r *recordingEncounterRepo) Create(ctx context.Context, encounter *entity.Encounter) (*entity.Encounter, error) {
+	encounter.ID = len(r.created) + 1
+	r.created = append(r.created, *encounter)
+	return encounter, nil
+}
+
+func TestEncounterCreateRequiresTreatingDoctor(t *testing.T) {
+	const doctorUserID = 7
+
+	tests := []struct {
+		name      string
+		patientID int
+		err       error
+	}{
+		{name: "assigned doctor", patientID: 1},
+		{name: "other doctor's patient", patientID: 2, err: ErrEncounterForbidden},
+		{name: "unknown patient", patientID: 3, err: repository.ErrPatientNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encounters := &recordingEncounterRepo{}
+			patients := &treatingPatients{doctors: map[int]int{1: doctorUserID, 2: doctorUserID + 1}}
+			s := NewEncounterService(noTx{}, nopAudit{}, patients, nil, encounters)
+
+			req := &entity.EncounterCreateRequest{PatientID: tt.patientID}
+			req.EncounterDate = "2026-01-15"
+			_, err := s.Create(context.Background(), doctorUserID, req)
+
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Create: error %v, want %v", err, tt.err)
+			}
+			if created := len(encounters.created) == 1; created != (tt.err == nil) {
+				t.Errorf("encounter created = %v, want %v", created, tt.err == nil)
+			}
+		})
+	}
+}
@@ -0,0 +1,325 @@
+package service
+
+import (
+	"Clinic_backend/internal/entity"
+	"Clinic_backend/internal/fhir"
+	"Clinic_backend/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Горизонт, на который расписание разворачивается в интервалы Slot
+const fhirPlanningHorizon = 28 * 24 * time.Hour
+
+var (
+	ErrFHIRUnknownResource = errors.New("unsupported FHIR resource type")
+	ErrFHIRNotFound        = errors.New("FHIR resource not found")
+	ErrFHIRInvalidSearch   = errors.New("invalid FHIR search parameter")
+)
+
+// FHIRQuery - поддерживаемые параметры поиска. IDs объединяются по ИЛИ, остальные условия - по И
+type FHIRQuery struct {
+	IDs []string
+	// Начало любого слова имени
+	Name string
+	// Ссылка practitioner, actor или schedule: "Practitioner/5" или просто "5"
+	Reference string
+	Count     int
+	Page      int
+	// Unrestricted разрешает поиск пациентов без _id и name - только администратору
+	Unrestricted bool
+}
+
+// FHIRServiceInterface - фасад FHIR R4 только для чтения поверх каталога врачей и услуг
+// и профилей пациентов. base - адрес REST API клиники, от него строятся системы кодов
+type FHIRServiceInterface interface {
+	Read(ctx context.Context, base, resourceType, id string) (fhir.Resource, error)
+	Search(ctx context.Context, base, resourceType string, query *FHIRQuery) ([]fhir.Resource, int, error)
+}
+
+type FHIRService struct {
+	auditService    AuditServiceInterface
+	doctorService   DoctorServiceInterface
+	serviceService  ServiceServiceInterface
+	categoryService CategoryServiceInterface
+	specService     SpecializationServiceInterface
+	patientService  PatientServiceInterface
+}
+
+func NewFHIRService(auditService AuditServiceInterface, doctorService DoctorServiceInterface, serviceService ServiceServiceInterface, categoryService CategoryServiceInterface, specService SpecializationServiceInterface, patientService PatientServiceInterface) FHIRServiceInterface {
+	return &FHIRService{
+		auditService:    auditService,
+		doctorService:   doctorService,
+		serviceService:  serviceService,
+		categoryService: categoryService,
+		specService:     specService,
+		patientService:  patientService,
+	}
+}
+
+func (s *FHIRService) Read(ctx context.Context, base, resourceType, id string) (fhir.Resource, error) {
+	ctx, span := tracer.Start(ctx, "FHIRService.Read")
+	defer span.End()
+
+	if resourceType == "Slot" {
+		return s.readSlot(ctx, base, id)
+	}
+
+	resources, _, err := s.Search(ctx, base, resourceType, &FHIRQuery{IDs: []string{id}, Count: 1})
+	if err != nil {
+		return nil, err
+	}
+	if len(resources) == 0 {
+		return nil, ErrFHIRNotFound
+	}
+	return resources[0], nil
+}
+
+func (s *FHIRService) Search(ctx context.Context, base, resourceType string, query *FHIRQuery) ([]fhir.Resource, int, error) {
+	ctx, span := tracer.Start(ctx, "FHIRService.Search")
+	defer span.End()
+
+	normalizePage(&query.Page, &query.Count)
+	query.Name = strings.TrimSpace(query.Name)
+	converter := fhir.Converter{APIBase: base, Location: time.Local}
+
+	switch resourceType {
+	case "Practitioner", "PractitionerRole", "Schedule", "Slot":
+		return s.searchDoctors(ctx, converter, resourceType, query)
+	case "HealthcareService":
+		return s.searchServices(ctx, converter, query)
+	case "Patient":
+		return s.searchPatients(ctx, converter, query)
+	default:
+		return nil, 0, fmt.Errorf("%w: %s", ErrFHIRUnknownResource, resourceType)
+	}
+}
+
+// searchDoctors отдаёт ресурсы, построенные из врачей. Каталог небольшой, поэтому
+// фильтрация и постраничный вывод выполняются в памяти
+func (s *FHIRService) searchDoctors(ctx context.Context, converter fhir.Converter, resourceType string, query *FHIRQuery) ([]fhir.Resource, int, error) {
+	doctors, err := s.doctorService.GetAllDoctors(ctx, false, "")
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to load doctors: %w", err)
+	}
+
+	referenced := 0
+	if query.Reference != "" {
+		if referenced, err = referenceID(query.Reference); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	now := time.Now().In(converter.Location)
+	horizon := fhir.Period{
+		Start: now.Format(time.RFC3339),
+		End:   now.Add(fhirPlanningHorizon).Format(time.RFC3339),
+	}
+
+	var resources []fhir.Resource
+	for _, doctor := range doctors {
+		if referenced != 0 && doctor.ID != referenced {
+			continue
+		}
+		if resourceType == "Practitioner" && query.Name != "" && !matchesName(doctor.Fullname, query.Name) {
+			continue
+		}
+
+		switch resourceType {
+		case "Practitioner":
+			if matchesID(query.IDs, strconv.Itoa(doctor.ID)) {
+				resources = append(resources, converter.Practitioner(doctor))
+			}
+		case "PractitionerRole":
+			if matchesID(query.IDs, strconv.Itoa(doctor.ID)) {
+				resources = append(resources, converter.PractitionerRole(doctor))
+			}
+		case "Schedule":
+			if doctor.Schedule != nil && matchesID(query.IDs, strconv.Itoa(doctor.ID)) {
+				resources = append(resources, converter.Schedule(doctor, horizon))
+			}
+		case "Slot":
+			for _, slot := range converter.Slots(doctor, now, now.Add(fhirPlanningHorizon)) {
+				if matchesID(query.IDs, slot.ID) {
+					resources = append(resources, slot)
+				}
+			}
+		}
+	}
+	return paginate(resources, query), len(resources), nil
+}
+
+// readSlot восстанавливает интервал по ID, в том числе за пределами горизонта поиска
+func (s *FHIRService) readSlot(ctx context.Context, base, id string) (fhir.Resource, error) {
+	doctorID, _, ok := fhir.ParseSlotID(id)
+	if !ok {
+		return nil, ErrFHIRNotFound
+	}
+
+	doctors, err := s.doctorService.GetAllDoctors(ctx, false, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load doctors: %w", err)
+	}
+	converter := fhir.Converter{APIBase: base, Location: time.Local}
+	for _, doctor := range doctors {
+		if doctor.ID != doctorID {
+			continue
+		}
+		if slot, ok := converter.Slot(doctor, id); ok {
+			return slot, nil
+		}
+	}
+	return nil, ErrFHIRNotFound
+}
+
+func (s *FHIRService) searchServices(ctx context.Context, converter fhir.Converter, query *FHIRQuery) ([]fhir.Resource, int, error) {
+	services, err := s.serviceService.GetAllServices(ctx, false)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to load services: %w", err)
+	}
+	categories, err := s.categoryService.GetAllCategories(ctx, false)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to load service categories: %w", err)
+	}
+	specializations, err := s.specService.GetAllSpecializations(ctx, false)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to load specializations: %w", err)
+	}
+
+	categoryNames := make(map[int]string, len(categories))
+	for _, category := range categories {
+		categoryNames[category.ID] = category.Name
+	}
+	specNames := make(map[int]string, len(specializations))
+	for _, spec := range specializations {
+		specNames[spec.ID] = spec.Name
+	}
+
+	var resources []fhir.Resource
+	for _, service := range services {
+		if !matchesID(query.IDs, strconv.Itoa(service.ID)) {
+			continue
+		}
+		if query.Name != "" && !matchesName(service.Name, query.Name) {
+			continue
+		}
+		resources = append(resources, converter.HealthcareService(service, categoryNames, specNames))
+	}
+	return paginate(resources, query), len(resources), nil
+}
+
+// searchPatients ищет профили пациентов: по ID - точечно, иначе через поиск профилей по ФИО.
+// Врачу нужен _id или name, весь список доступен только администратору. Каждый отданный
+// профиль фиксируется в журнале аудита как чтение
+func (s *FHIRService) searchPatients(ctx context.Context, converter fhir.Converter, query *FHIRQuery) ([]fhir.Resource, int, error) {
+	if len(query.IDs) == 0 && query.Name == "" && !query.Unrestricted {
+		return nil, 0, fmt.Errorf("%w: Patient search requires _id or name", ErrFHIRInvalidSearch)
+	}
+
+	resources, total, err := s.findPatients(ctx, converter, query)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, resource := range resources {
+		id, err := referenceID(resource.Reference())
+		if err != nil {
+			return nil, 0, err
+		}
+		if err := s.auditService.Record(ctx, entity.AuditEntityPatientProfile, id, entity.AuditActionRead, nil, map[string]any{"via": "fhir"}); err != nil {
+			return nil, 0, err
+		}
+	}
+	return resources, total, nil
+}
+
+func (s *FHIRService) findPatients(ctx context.Context, converter fhir.Converter, query *FHIRQuery) ([]fhir.Resource, int, error) {
+	if len(query.IDs) > 0 {
+		var resources []fhir.Resource
+		for _, raw := range query.IDs {
+			id, err := strconv.Atoi(raw)
+			if err != nil {
+				continue
+			}
+			profile, err := s.patientService.GetByID(ctx, id)
+			if errors.Is(err, repository.ErrPatientNotFound) {
+				continue
+			}
+			if err != nil {
+				return nil, 0, err
+			}
+			if query.Name != "" && !matchesName(profile.FullName, query.Name) {
+				continue
+			}
+			resources = append(resources, converter.Patient(*profile))
+		}
+		return paginate(resources, query), len(resources), nil
+	}
+
+	profiles, total, err := s.patientService.Search(ctx, &entity.PatientFilter{
+		Name:  query.Name,
+		Page:  query.Page,
+		Limit: query.Count,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	resources := make([]fhir.Resource, 0, len(profiles))
+	for _, profile := range profiles {
+		resources = append(resources, converter.Patient(profile))
+	}
+	return resources, total, nil
+}
+
+// referenceID извлекает ID из ссылки "Тип/ID" или голого ID
+func referenceID(reference string) (int, error) {
+	if i := strings.LastIndex(reference, "/"); i >= 0 {
+		reference = reference[i+1:]
+	}
+	id, err := strconv.Atoi(reference)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("%w: reference %q", ErrFHIRInvalidSearch, reference)
+	}
+	return id, nil
+}
+
+func matchesID(ids []string, id string) bool {
+	if len(ids) == 0 {
+		return true
+	}
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesName - каждое слово запроса является началом какого-либо слова имени (без учёта регистра)
+func matchesName(name, query string) bool {
+	words := strings.Fields(strings.ToLower(name))
+	for _, term := range strings.Fields(strings.ToLower(query)) {
+		found := false
+		for _, word := range words {
+			if strings.HasPrefix(word, term) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func paginate(resources []fhir.Resource, query *FHIRQuery) []fhir.Resource {
+	from := (query.Page - 1) * query.Count
+	if from >= len(resources) {
+		return []fhir.Resource{}
+	}
+	return resources[from:min(from+query.Count, len(resources))]
+}
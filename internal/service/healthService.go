@@ -0,0 +1,96 @@
+package service
+
+import (
+	"Clinic_backend/internal/entity"
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const healthCheckTimeout = 2 * time.Second
+
+// HealthCheck - проверка одной зависимости. Сбой необязательной зависимости
+// (почта, кэш) переводит статус в degraded, но не снимает готовность.
+type HealthCheck struct {
+	Name     string
+	Optional bool
+	Check    func(ctx context.Context) error
+}
+
+type HealthServiceInterface interface {
+	Ready(ctx context.Context) *entity.HealthReport
+	SetShuttingDown()
+	IsShuttingDown() bool
+}
+
+type HealthService struct {
+	checks       []HealthCheck
+	shuttingDown atomic.Bool
+}
+
+func NewHealthService(checks ...HealthCheck) HealthServiceInterface {
+	return &HealthService{checks: checks}
+}
+
+// Ready выполняет все проверки параллельно, каждую со своим таймаутом
+func (s *HealthService) Ready(ctx context.Context) *entity.HealthReport {
+	if s.shuttingDown.Load() {
+		return &entity.HealthReport{Status: entity.HealthStatusShuttingDown}
+	}
+
+	report := &entity.HealthReport{
+		Status:     entity.HealthStatusOK,
+		Components: make(map[string]entity.ComponentHealth, len(s.checks)),
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, check := range s.checks {
+		wg.Add(1)
+		go func(check HealthCheck) {
+			defer wg.Done()
+
+			checkCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+			defer cancel()
+
+			start := time.Now()
+			err := check.Check(checkCtx)
+			component := entity.ComponentHealth{
+				Status:    entity.HealthStatusOK,
+				Optional:  check.Optional,
+				LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+			}
+			if err != nil {
+				component.Status = entity.HealthStatusFailing
+				component.Error = err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Components[check.Name] = component
+			switch {
+			case err == nil:
+			case !check.Optional:
+				report.Status = entity.HealthStatusFailing
+			case report.Status == entity.HealthStatusOK:
+				report.Status = entity.HealthStatusDegraded
+			}
+		}(check)
+	}
+	wg.Wait()
+
+	return report
+}
+
+// SetShuttingDown переводит готовность в failing, чтобы балансировщик
+// перестал направлять запросы до остановки сервера
+func (s *HealthService) SetShuttingDown() {
+	s.shuttingDown.Store(true)
+}
+
+func (s *HealthService) IsShuttingDown() bool {
+	return s.shuttingDown.Load()
+}
@@ -0,0 +1,680 @@
+package service
+
+import (
+	"Clinic_backend/config"
+	"Clinic_backend/internal/entity"
+	"Clinic_backend/internal/lab"
+	"Clinic_backend/internal/logging"
+	"Clinic_backend/internal/media"
+	"Clinic_backend/internal/repository"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Форматы файлов импорта результатов
+const (
+	LabFormatCSV = "csv"
+	LabFormatHL7 = "hl7"
+)
+
+var (
+	// ErrInvalidLabOrder - направление или результат не прошли проверку
+	ErrInvalidLabOrder = errors.New("invalid lab order")
+	// ErrLabOrderForbidden - отменить направление может только выписавший его врач
+	ErrLabOrderForbidden = errors.New("only the ordering doctor can cancel this lab order")
+	// ErrLabStatusTransition - статус направления меняется только вперёд
+	ErrLabStatusTransition = errors.New("lab order status cannot move backwards")
+	// ErrLabOrderNotCancellable - материал уже взят, направление нельзя отменить
+	ErrLabOrderNotCancellable = errors.New("only lab orders awaiting sampling can be cancelled")
+	// ErrLabResultsMissing - готовым можно отметить только направление с результатами или бланком
+	ErrLabResultsMissing = errors.New("lab order has no results or attachments")
+	// ErrLabAttachmentTooLarge - бланк больше lab.max_attachment_size
+	ErrLabAttachmentTooLarge = errors.New("attachment is too large")
+	// ErrLabAttachmentNotPDF - бланк результата должен быть PDF
+	ErrLabAttachmentNotPDF = errors.New("attachment must be a PDF document")
+)
+
+// Порядок статусов направления; переход возможен только на более поздний
+var labOrderStatuses = []string{
+	entity.LabOrderStatusOrdered,
+	entity.LabOrderStatusSampled,
+	entity.LabOrderStatusInProgress,
+	entity.LabOrderStatusReady,
+}
+
+// Ограничения колонок lab_results
+const (
+	maxLabTestCode = 32
+	maxLabValue    = 200
+)
+
+type LabServiceInterface interface {
+	CreateOrder(ctx context.Context, doctorUserID int, req *entity.LabOrderCreateRequest) (*entity.LabOrder, error)
+	GetOrder(ctx context.Context, id int) (*entity.LabOrder, error)
+	ListOrders(ctx context.Context, filter *entity.LabOrderFilter) ([]entity.LabOrder, int, error)
+	UpdateStatus(ctx context.Context, id, version int, status string) (*entity.LabOrder, error)
+	CancelOrder(ctx context.Context, doctorUserID, id, version int) error
+	AddResults(ctx context.Context, id int, req *entity.LabResultsRequest) (*entity.LabOrder, error)
+	AddAttachment(ctx context.Context, orderID int, filename string, r io.Reader) (*entity.LabAttachment, error)
+	OpenAttachment(ctx context.Context, orderID, id int) (*entity.LabAttachment, io.ReadCloser, error)
+	Import(ctx context.Context, format string, data []byte) (*entity.LabImportSummary, error)
+	ListForPatient(ctx context.Context, userID int, patientID *int, filter *entity.LabOrderFilter) ([]entity.LabOrder, int, error)
+	GetForPatient(ctx context.Context, userID int, patientID *int, id int) (*entity.LabOrder, error)
+	OpenAttachmentForPatient(ctx context.Context, userID int, patientID *int, orderID, id int) (*entity.LabAttachment, io.ReadCloser, error)
+}
+
+type LabService struct {
+	cfg            *config.Holder
+	store          media.BlobStore
+	txManager      repository.TransactionManagerInterface
+	auditService   AuditServiceInterface
+	patientService PatientServiceInterface
+	doctorRepo     repository.DoctorRepositoryInterface
+	encounterRepo  repository.EncounterRepositoryInterface
+	labRepo        repository.LabRepositoryInterface
+}
+
+func NewLabService(cfg *config.Holder, store media.BlobStore, txManager repository.TransactionManagerInterface, auditService AuditServiceInterface, patientService PatientServiceInterface, doctorRepo repository.DoctorRepositoryInterface, encounterRepo repository.EncounterRepositoryInterface, labRepo repository.LabRepositoryInterface) LabServiceInterface {
+	return &LabService{
+		cfg:            cfg,
+		store:          store,
+		txManager:      txManager,
+		auditService:   auditService,
+		patientService: patientService,
+		doctorRepo:     doctorRepo,
+		encounterRepo:  encounterRepo,
+		labRepo:        labRepo,
+	}
+}
+
+// CreateOrder выписывает направление от имени врача, привязанного к учётной записи
+func (s *LabService) CreateOrder(ctx context.Context, doctorUserID int, req *entity.LabOrderCreateRequest) (*entity.LabOrder, error) {
+	ctx, span := tracer.Start(ctx, "LabService.CreateOrder")
+	defer span.End()
+
+	tests, err := labTests(req.Tests)
+	if err != nil {
+		return nil, err
+	}
+
+	var created *entity.LabOrder
+	err = s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		doctor, err := doctorForUser(ctx, s.doctorRepo, doctorUserID)
+		if err != nil {
+			return err
+		}
+		if _, err := s.patientService.GetByID(ctx, req.PatientID); err != nil {
+			return err
+		}
+		if err := checkEncounter(ctx, s.encounterRepo, req.PatientID, req.EncounterID, ErrInvalidLabOrder); err != nil {
+			return err
+		}
+
+		created, err = s.labRepo.CreateOrder(ctx, &entity.LabOrder{
+			PatientID:   req.PatientID,
+			DoctorID:    doctor.ID,
+			EncounterID: req.EncounterID,
+			Tests:       tests,
+			Comment:     trimmedOrNil(req.Comment),
+		})
+		if err != nil {
+			return err
+		}
+		return s.auditService.Record(ctx, entity.AuditEntityLabOrder, created.ID, entity.AuditActionCreate, nil, map[string]any{
+			"patient_id": created.PatientID,
+			"doctor_id":  created.DoctorID,
+			"tests":      len(created.Tests),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// GetOrder возвращает направление вместе с результатами, в том числе предварительными, и бланками
+func (s *LabService) GetOrder(ctx context.Context, id int) (*entity.LabOrder, error) {
+	order, err := s.labRepo.GetOrder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.loadDetails(ctx, order); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+func (s *LabService) ListOrders(ctx context.Context, filter *entity.LabOrderFilter) ([]entity.LabOrder, int, error) {
+	ctx, span := tracer.Start(ctx, "LabService.ListOrders")
+	defer span.End()
+
+	normalizePage(&filter.Page, &filter.Limit)
+	return s.labRepo.ListOrders(ctx, filter)
+}
+
+// UpdateStatus отмечает этапы выполнения направления: взятие материала, начало исследования, готовность
+func (s *LabService) UpdateStatus(ctx context.Context, id, version int, status string) (*entity.LabOrder, error) {
+	ctx, span := tracer.Start(ctx, "LabService.UpdateStatus")
+	defer span.End()
+
+	var updated *entity.LabOrder
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		order, err := s.labRepo.LockOrder(ctx, id)
+		if err != nil {
+			return err
+		}
+		// Клиент видел устаревшую версию
+		if version != repository.AnyVersion && order.Version != version {
+			return repository.ErrVersionConflict
+		}
+		if slices.Index(labOrderStatuses, status) <= slices.Index(labOrderStatuses, order.Status) {
+			return fmt.Errorf("%w: %s -> %s", ErrLabStatusTransition, order.Status, status)
+		}
+
+		if status == entity.LabOrderStatusReady {
+			if err := s.loadDetails(ctx, order); err != nil {
+				return err
+			}
+			if len(order.Results) == 0 && len(order.Attachments) == 0 {
+				return ErrLabResultsMissing
+			}
+		}
+
+		updated, err = s.labRepo.UpdateStatus(ctx, id, version, status)
+		if err != nil {
+			return err
+		}
+		return s.auditService.Record(ctx, entity.AuditEntityLabOrder, id, entity.AuditActionUpdate,
+			map[string]any{"status": order.Status},
+			map[string]any{"status": updated.Status, "patient_id": order.PatientID},
+		)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetOrder(ctx, updated.ID)
+}
+
+// CancelOrder отменяет направление, по которому ещё не взят материал
+func (s *LabService) CancelOrder(ctx context.Context, doctorUserID, id, version int) error {
+	ctx, span := tracer.Start(ctx, "LabService.CancelOrder")
+	defer span.End()
+
+	return s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		doctor, err := doctorForUser(ctx, s.doctorRepo, doctorUserID)
+		if err != nil {
+			return err
+		}
+
+		order, err := s.labRepo.LockOrder(ctx, id)
+		if err != nil {
+			return err
+		}
+		if order.DoctorID != doctor.ID {
+			return ErrLabOrderForbidden
+		}
+		if version != repository.AnyVersion && order.Version != version {
+			return repository.ErrVersionConflict
+		}
+		if order.Status != entity.LabOrderStatusOrdered {
+			return ErrLabOrderNotCancellable
+		}
+
+		if err := s.labRepo.DeleteOrder(ctx, id, version); err != nil {
+			return err
+		}
+		return s.auditService.Record(ctx, entity.AuditEntityLabOrder, id, entity.AuditActionDelete, nil, map[string]any{
+			"patient_id": order.PatientID,
+			"doctor_id":  order.DoctorID,
+		})
+	})
+}
+
+// AddResults вносит результаты вручную, например из собственной лаборатории клиники
+func (s *LabService) AddResults(ctx context.Context, id int, req *entity.LabResultsRequest) (*entity.LabOrder, error) {
+	ctx, span := tracer.Start(ctx, "LabService.AddResults")
+	defer span.End()
+
+	observations := make([]lab.Observation, 0, len(req.Results))
+	for _, r := range req.Results {
+		observation := lab.Observation{
+			OrderID:    id,
+			TestCode:   strings.TrimSpace(r.TestCode),
+			TestName:   strings.TrimSpace(r.TestName),
+			Value:      strings.TrimSpace(r.Value),
+			Final:      r.Final == nil || *r.Final,
+			ObservedAt: r.ObservedAt,
+		}
+		if r.Unit != nil {
+			observation.Unit = strings.TrimSpace(*r.Unit)
+		}
+		if r.ReferenceRange != nil {
+			observation.ReferenceRange = strings.TrimSpace(*r.ReferenceRange)
+		}
+		if r.Flag != nil {
+			observation.Flag = strings.TrimSpace(*r.Flag)
+		}
+		observations = append(observations, observation)
+	}
+
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		order, err := s.labRepo.LockOrder(ctx, id)
+		if err != nil {
+			return err
+		}
+		_, err = s.apply(ctx, order, observations, nil, ErrInvalidLabOrder)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetOrder(ctx, id)
+}
+
+// AddAttachment прикладывает PDF-бланк результата к направлению
+func (s *LabService) AddAttachment(ctx context.Context, orderID int, filename string, r io.Reader) (*entity.LabAttachment, error) {
+	ctx, span := tracer.Start(ctx, "LabService.AddAttachment")
+	defer span.End()
+
+	maxSize := s.cfg.Get().Lab.MaxAttachmentSize
+	data, err := io.ReadAll(io.LimitReader(r, maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read upload: %w", err)
+	}
+	if err := s.checkAttachment(data); err != nil {
+		return nil, err
+	}
+
+	var created []entity.LabAttachment
+	err = s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		order, err := s.labRepo.LockOrder(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		created, err = s.apply(ctx, order, nil, []lab.Attachment{{OrderID: orderID, Filename: filename, Data: data}}, ErrInvalidLabOrder)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &created[0], nil
+}
+
+// OpenAttachment возвращает бланк и его содержимое; вызывающий закрывает reader
+func (s *LabService) OpenAttachment(ctx context.Context, orderID, id int) (*entity.LabAttachment, io.ReadCloser, error) {
+	if _, err := s.labRepo.GetOrder(ctx, orderID); err != nil {
+		return nil, nil, err
+	}
+
+	attachment, err := s.labRepo.GetAttachment(ctx, orderID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	body, err := s.store.Get(ctx, attachment.BlobKey)
+	if errors.Is(err, media.ErrNotFound) {
+		return nil, nil, repository.ErrLabAttachmentNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return attachment, body, nil
+}
+
+// Import принимает файл результатов от лаборатории. Файл применяется целиком или не
+// применяется вовсе: ошибка в любой строке или неизвестное направление отклоняют весь импорт
+func (s *LabService) Import(ctx context.Context, format string, data []byte) (*entity.LabImportSummary, error) {
+	ctx, span := tracer.Start(ctx, "LabService.Import")
+	defer span.End()
+
+	var batch *lab.Batch
+	var err error
+	switch format {
+	case LabFormatCSV:
+		batch, err = lab.ParseCSV(bytes.NewReader(data))
+	case LabFormatHL7:
+		batch, err = lab.ParseHL7(data)
+	default:
+		return nil, fmt.Errorf("%w: unsupported format %q", lab.ErrInvalidImport, format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, attachment := range batch.Attachments {
+		if err := s.checkAttachment(attachment.Data); err != nil {
+			return nil, fmt.Errorf("%w: order %d: %w", lab.ErrInvalidImport, attachment.OrderID, err)
+		}
+	}
+
+	// Направления обрабатываются в порядке появления в файле
+	var orderIDs []int
+	for _, observation := range batch.Observations {
+		if !slices.Contains(orderIDs, observation.OrderID) {
+			orderIDs = append(orderIDs, observation.OrderID)
+		}
+	}
+	for _, attachment := range batch.Attachments {
+		if !slices.Contains(orderIDs, attachment.OrderID) {
+			orderIDs = append(orderIDs, attachment.OrderID)
+		}
+	}
+
+	summary := &entity.LabImportSummary{Orders: orderIDs}
+	err = s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		for _, orderID := range orderIDs {
+			order, err := s.labRepo.LockOrder(ctx, orderID)
+			if errors.Is(err, repository.ErrLabOrderNotFound) {
+				return fmt.Errorf("%w: lab order %d not found", lab.ErrInvalidImport, orderID)
+			}
+			if err != nil {
+				return err
+			}
+
+			var observations []lab.Observation
+			for _, observation := range batch.Observations {
+				if observation.OrderID == orderID {
+					observations = append(observations, observation)
+				}
+			}
+			var attachments []lab.Attachment
+			for _, attachment := range batch.Attachments {
+				if attachment.OrderID == orderID {
+					attachments = append(attachments, attachment)
+				}
+			}
+
+			if _, err := s.apply(ctx, order, observations, attachments, lab.ErrInvalidImport); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	summary.Results = len(batch.Observations)
+	summary.Attachments = len(batch.Attachments)
+	logging.FromContext(ctx).Info("Lab results imported",
+		"format", format, "orders", len(orderIDs), "results", summary.Results, "attachments", summary.Attachments)
+	return summary, nil
+}
+
+func (s *LabService) ListForPatient(ctx context.Context, userID int, patientID *int, filter *entity.LabOrderFilter) ([]entity.LabOrder, int, error) {
+	ctx, span := tracer.Start(ctx, "LabService.ListForPatient")
+	defer span.End()
+
+	patient, err := s.patientService.ResolvePatient(ctx, userID, patientID, entity.PatientAccessView)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	filter.PatientID = &patient.ID
+	filter.DoctorID = nil
+	return s.ListOrders(ctx, filter)
+}
+
+// GetForPatient показывает пациенту направление; результаты и бланки - только после готовности,
+// чтобы предварительные значения не попадали к пациенту без проверки лабораторией
+func (s *LabService) GetForPatient(ctx context.Context, userID int, patientID *int, id int) (*entity.LabOrder, error) {
+	order, err := s.patientOrder(ctx, userID, patientID, id)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status == entity.LabOrderStatusReady {
+		if err := s.loadDetails(ctx, order); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+func (s *LabService) OpenAttachmentForPatient(ctx context.Context, userID int, patientID *int, orderID, id int) (*entity.LabAttachment, io.ReadCloser, error) {
+	order, err := s.patientOrder(ctx, userID, patientID, orderID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if order.Status != entity.LabOrderStatusReady {
+		return nil, nil, repository.ErrLabAttachmentNotFound
+	}
+	return s.OpenAttachment(ctx, orderID, id)
+}
+
+// patientOrder загружает направление пациента, доступного пользователю
+func (s *LabService) patientOrder(ctx context.Context, userID int, patientID *int, id int) (*entity.LabOrder, error) {
+	patient, err := s.patientService.ResolvePatient(ctx, userID, patientID, entity.PatientAccessView)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := s.labRepo.GetOrder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if order.PatientID != patient.ID {
+		return nil, repository.ErrLabOrderNotFound
+	}
+	return order, nil
+}
+
+// apply сохраняет результаты и бланки заблокированного направления и продвигает его статус:
+// ready, когда по каждому назначенному исследованию есть окончательный результат, иначе
+// in_progress. Ошибки проверки оборачиваются в invalid
+func (s *LabService) apply(ctx context.Context, order *entity.LabOrder, observations []lab.Observation, attachments []lab.Attachment, invalid error) ([]entity.LabAttachment, error) {
+	for _, observation := range observations {
+		result, err := labResult(order, observation)
+		if err != nil {
+			return nil, fmt.Errorf("%w: order %d: %v", invalid, order.ID, err)
+		}
+		if err := s.labRepo.UpsertResult(ctx, result); err != nil {
+			return nil, err
+		}
+	}
+
+	var created []entity.LabAttachment
+	for _, attachment := range attachments {
+		stored, err := s.storeAttachment(ctx, order.ID, attachment)
+		if err != nil {
+			return nil, err
+		}
+		created = append(created, *stored)
+	}
+
+	results, err := s.labRepo.ListResults(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+	status := entity.LabOrderStatusInProgress
+	if allTestsFinal(order.Tests, results) {
+		status = entity.LabOrderStatusReady
+	}
+
+	// Исправленные результаты по готовому направлению статус не откатывают
+	after := order.Status
+	if slices.Index(labOrderStatuses, status) > slices.Index(labOrderStatuses, order.Status) {
+		if _, err := s.labRepo.UpdateStatus(ctx, order.ID, order.Version, status); err != nil {
+			return nil, err
+		}
+		after = status
+	}
+
+	// В журнал попадают только количества: состав исследований - медицинские данные
+	err = s.auditService.Record(ctx, entity.AuditEntityLabOrder, order.ID, entity.AuditActionUpdate,
+		map[string]any{"status": order.Status},
+		map[string]any{
+			"status":      after,
+			"patient_id":  order.PatientID,
+			"results":     len(observations),
+			"attachments": len(attachments),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// storeAttachment сохраняет бланк под хэшем содержимого; повторная загрузка того же файла
+// не дублирует объект в хранилище
+func (s *LabService) storeAttachment(ctx context.Context, orderID int, attachment lab.Attachment) (*entity.LabAttachment, error) {
+	sum := sha256.Sum256(attachment.Data)
+	key := media.PrivatePrefix + hex.EncodeToString(sum[:])
+
+	exists, err := s.store.Exists(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		if err := s.store.Put(ctx, key, attachment.Data, "application/pdf"); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.labRepo.CreateAttachment(ctx, &entity.LabAttachment{
+		OrderID:    orderID,
+		BlobKey:    key,
+		Filename:   attachmentFilename(attachment.Filename),
+		Size:       int64(len(attachment.Data)),
+		UploadedBy: entity.ActorFromContext(ctx).UserID,
+	})
+}
+
+func (s *LabService) checkAttachment(data []byte) error {
+	if int64(len(data)) > s.cfg.Get().Lab.MaxAttachmentSize {
+		return ErrLabAttachmentTooLarge
+	}
+	if !bytes.HasPrefix(data, []byte("%PDF-")) {
+		return ErrLabAttachmentNotPDF
+	}
+	return nil
+}
+
+func (s *LabService) loadDetails(ctx context.Context, order *entity.LabOrder) error {
+	results, err := s.labRepo.ListResults(ctx, order.ID)
+	if err != nil {
+		return err
+	}
+	attachments, err := s.labRepo.ListAttachments(ctx, order.ID)
+	if err != nil {
+		return err
+	}
+
+	order.Results = results
+	order.Attachments = attachments
+	return nil
+}
+
+// labTests проверяет список исследований: коды без повторов
+func labTests(tests []entity.LabTest) ([]entity.LabTest, error) {
+	normalized := make([]entity.LabTest, 0, len(tests))
+	var codes []string
+	for _, test := range tests {
+		test.Code = strings.TrimSpace(test.Code)
+		test.Name = strings.TrimSpace(test.Name)
+		if test.Code == "" || test.Name == "" {
+			return nil, fmt.Errorf("%w: test code and name are required", ErrInvalidLabOrder)
+		}
+		if slices.Contains(codes, test.Code) {
+			return nil, fmt.Errorf("%w: duplicate test %s", ErrInvalidLabOrder, test.Code)
+		}
+		codes = append(codes, test.Code)
+		normalized = append(normalized, test)
+	}
+	return normalized, nil
+}
+
+// labResult переводит результат лаборатории в запись показателя. Название по умолчанию
+// берётся из направления, флаг - от лаборатории или по референсному интервалу
+func labResult(order *entity.LabOrder, observation lab.Observation) (*entity.LabResult, error) {
+	code := observation.TestCode
+	switch {
+	case code == "":
+		return nil, errors.New("test code is required")
+	case len(code) > maxLabTestCode:
+		return nil, fmt.Errorf("test code %q is longer than %d characters", code, maxLabTestCode)
+	case observation.Value == "":
+		return nil, fmt.Errorf("value is required for %s", code)
+	case len([]rune(observation.Value)) > maxLabValue:
+		return nil, fmt.Errorf("value for %s is longer than %d characters", code, maxLabValue)
+	}
+
+	name := observation.TestName
+	if name == "" {
+		name = code
+		for _, test := range order.Tests {
+			if test.Code == code {
+				name = test.Name
+			}
+		}
+	}
+
+	flag, outOfRange := lab.Evaluate(observation.Value, observation.ReferenceRange, observation.Flag)
+	return &entity.LabResult{
+		OrderID:        order.ID,
+		TestCode:       code,
+		TestName:       name,
+		Value:          observation.Value,
+		Unit:           stringOrNil(observation.Unit),
+		ReferenceRange: stringOrNil(observation.ReferenceRange),
+		Flag:           stringOrNil(flag),
+		OutOfRange:     outOfRange,
+		Final:          observation.Final,
+		ObservedAt:     observation.ObservedAt,
+	}, nil
+}
+
+// allTestsFinal сообщает, есть ли окончательный результат по каждому назначенному исследованию
+func allTestsFinal(tests []entity.LabTest, results []entity.LabResult) bool {
+	for _, test := range tests {
+		found := slices.ContainsFunc(results, func(result entity.LabResult) bool {
+			return result.TestCode == test.Code && result.Final
+		})
+		if !found {
+			return false
+		}
+	}
+	return len(tests) > 0
+}
+
+// attachmentFilename оставляет от имени файла только безопасную для Content-Disposition часть
+func attachmentFilename(filename string) string {
+	name := strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == '"' || r == '\\' {
+			return -1
+		}
+		return r
+	}, filepath.Base(strings.ReplaceAll(filename, "\\", "/")))
+	name = strings.TrimSpace(name)
+
+	if name == "" || name == "." || name == "/" {
+		name = "result"
+	}
+	if runes := []rune(name); len(runes) > 100 {
+		name = string(runes[:100])
+	}
+	if !strings.HasSuffix(strings.ToLower(name), ".pdf") {
+		name += ".pdf"
+	}
+	return name
+}
+
+func stringOrNil(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
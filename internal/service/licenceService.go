@@ -8,38 +8,107 @@ import (
 
 type LicenseServiceInterface interface {
 	CreateLicense(ctx context.Context, license *entity.License) (*entity.License, error)
-	GetAllLicenses(ctx context.Context) ([]entity.License, error)
+	GetAllLicenses(ctx context.Context, includeDeleted bool) ([]entity.License, error)
 	GetLicenseByID(ctx context.Context, id int) (*entity.License, error)
-	UpdateLicense(ctx context.Context, id int, license *entity.License) (*entity.License, error)
-	DeleteLicense(ctx context.Context, id int) error
+	UpdateLicense(ctx context.Context, id int, version int, license *entity.License) (*entity.License, error)
+	DeleteLicense(ctx context.Context, id int, version int) error
+	RestoreLicense(ctx context.Context, id int) error
 }
 
 type LicenseService struct {
-	licenseRepo repository.LicenseRepositoryInterface
+	txManager    repository.TransactionManagerInterface
+	auditService AuditServiceInterface
+	mediaService MediaServiceInterface
+	licenseRepo  repository.LicenseRepositoryInterface
 }
 
-func NewLicenseService(licenseRepo repository.LicenseRepositoryInterface) LicenseServiceInterface {
+func NewLicenseService(txManager repository.TransactionManagerInterface, auditService AuditServiceInterface, mediaService MediaServiceInterface, licenseRepo repository.LicenseRepositoryInterface) LicenseServiceInterface {
 	return &LicenseService{
-		licenseRepo: licenseRepo,
+		txManager:    txManager,
+		auditService: auditService,
+		mediaService: mediaService,
+		licenseRepo:  licenseRepo,
 	}
 }
 
 func (s *LicenseService) CreateLicense(ctx context.Context, license *entity.License) (*entity.License, error) {
-	return s.licenseRepo.Create(ctx, license)
+	if err := attachMedia(ctx, s.mediaService, "photo_id", license.PhotoID, &license.Photo); err != nil {
+		return nil, err
+	}
+
+	var created *entity.License
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		created, err = s.licenseRepo.Create(ctx, license)
+		if err != nil {
+			return err
+		}
+		return s.auditService.Record(ctx, entity.AuditEntityLicense, created.ID, entity.AuditActionCreate, nil, created)
+	})
+	return withSrcset(ctx, s.mediaService, created, err, licensePhoto)
 }
 
-func (s *LicenseService) GetAllLicenses(ctx context.Context) ([]entity.License, error) {
-	return s.licenseRepo.GetAll(ctx)
+func (s *LicenseService) GetAllLicenses(ctx context.Context, includeDeleted bool) ([]entity.License, error) {
+	licenses, err := s.licenseRepo.GetAll(ctx, includeDeleted)
+	return withSrcsets(ctx, s.mediaService, licenses, err, licensePhoto)
 }
 
 func (s *LicenseService) GetLicenseByID(ctx context.Context, id int) (*entity.License, error) {
-	return s.licenseRepo.GetByID(ctx, id)
+	license, err := s.licenseRepo.GetByID(ctx, id)
+	return withSrcset(ctx, s.mediaService, license, err, licensePhoto)
+}
+
+func (s *LicenseService) UpdateLicense(ctx context.Context, id int, version int, license *entity.License) (*entity.License, error) {
+	license.Version = version
+
+	if err := attachMedia(ctx, s.mediaService, "photo_id", license.PhotoID, &license.Photo); err != nil {
+		return nil, err
+	}
+
+	var updated *entity.License
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		before, err := s.licenseRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		updated, err = s.licenseRepo.Update(ctx, id, license)
+		if err != nil {
+			return err
+		}
+		return s.auditService.Record(ctx, entity.AuditEntityLicense, id, entity.AuditActionUpdate, before, updated)
+	})
+	return withSrcset(ctx, s.mediaService, updated, err, licensePhoto)
+}
+
+func (s *LicenseService) DeleteLicense(ctx context.Context, id int, version int) error {
+	return s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		before, err := s.licenseRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := s.licenseRepo.Delete(ctx, id, version); err != nil {
+			return err
+		}
+		return s.auditService.Record(ctx, entity.AuditEntityLicense, id, entity.AuditActionDelete, before, nil)
+	})
 }
 
-func (s *LicenseService) UpdateLicense(ctx context.Context, id int, license *entity.License) (*entity.License, error) {
-	return s.licenseRepo.Update(ctx, id, license)
+func (s *LicenseService) RestoreLicense(ctx context.Context, id int) error {
+	return s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.licenseRepo.Restore(ctx, id); err != nil {
+			return err
+		}
+
+		after, err := s.licenseRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		return s.auditService.Record(ctx, entity.AuditEntityLicense, id, entity.AuditActionRestore, nil, after)
+	})
 }
 
-func (s *LicenseService) DeleteLicense(ctx context.Context, id int) error {
-	return s.licenseRepo.Delete(ctx, id)
+func licensePhoto(license *entity.License) (*string, *entity.Srcset) {
+	return license.PhotoID, &license.PhotoSrcset
 }
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"Clinic_backend/config"
+	"Clinic_backend/internal/entity"
+	"Clinic_backend/internal/media"
+	"Clinic_backend/internal/repository"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+type MediaBackfillServiceInterface interface {
+	Backfill(ctx context.Context, baseURL string) (*entity.MediaBackfillResult, error)
+}
+
+// MediaBackfillService переносит изображения, заданные строкой (внешний URL, data URI),
+// в хранилище и связывает их с записями каталога, чтобы для них создавались копии
+type MediaBackfillService struct {
+	cfg          *config.Holder
+	txManager    repository.TransactionManagerInterface
+	auditService AuditServiceInterface
+	mediaService MediaServiceInterface
+	mediaRepo    repository.MediaRepositoryInterface
+	client       *http.Client
+}
+
+func NewMediaBackfillService(cfg *config.Holder, txManager repository.TransactionManagerInterface, auditService AuditServiceInterface, mediaService MediaServiceInterface, mediaRepo repository.MediaRepositoryInterface) MediaBackfillServiceInterface {
+	return &MediaBackfillService{
+		cfg:          cfg,
+		txManager:    txManager,
+		auditService: auditService,
+		mediaService: mediaService,
+		mediaRepo:    mediaRepo,
+		client:       &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Backfill загружает изображения всех несвязанных записей. Относительные URL
+// разрешаются от baseURL. Изображение, которое не удалось получить, пропускается;
+// ошибка БД прерывает перенос. Также возвращает в очередь изображения без копий
+// текущих media.variant_widths. Повторный запуск безопасен.
+func (s *MediaBackfillService) Backfill(ctx context.Context, baseURL string) (*entity.MediaBackfillResult, error) {
+	var base *url.URL
+	if baseURL != "" {
+		var err error
+		base, err = url.Parse(baseURL)
+		if err != nil || (base.Scheme != "http" && base.Scheme != "https") {
+			return nil, fmt.Errorf("invalid base URL %q", baseURL)
+		}
+	}
+
+	refs, err := s.mediaRepo.ListUnlinkedPhotos(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &entity.MediaBackfillResult{}
+	for i := range refs {
+		ref := &refs[i]
+		logger := slog.With("entity_type", ref.EntityType, "entity_id", ref.EntityID)
+
+		item, err := s.importPhoto(ctx, ref.Value, base)
+		if err != nil {
+			logger.Warn("Failed to import photo", "error", err.Error())
+			result.Failed++
+			continue
+		}
+
+		linked, err := s.link(ctx, ref, item)
+		if err != nil {
+			return result, err
+		}
+		if !linked {
+			// Запись изменили во время переноса
+			result.Skipped++
+			continue
+		}
+
+		logger.Info("Photo linked to media", "media_id", item.ID)
+		result.Linked++
+	}
+
+	result.Requeued, err = s.mediaRepo.RequeueVariants(ctx, s.cfg.Get().Media.VariantWidths)
+	if err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+func (s *MediaBackfillService) importPhoto(ctx context.Context, value string, base *url.URL) (*entity.Media, error) {
+	if media.IsDataURI(value) {
+		return s.mediaService.UploadBase64(ctx, "", value)
+	}
+
+	u, err := url.Parse(strings.TrimSpace(value))
+	if err != nil {
+		return nil, fmt.Errorf("invalid photo URL: %w", err)
+	}
+
+	// Адрес уже загруженного изображения, указанный без ID
+	if u.Host == "" && strings.HasPrefix(u.Path, entity.MediaURL("")) {
+		return s.mediaService.GetByID(ctx, strings.TrimPrefix(u.Path, entity.MediaURL("")))
+	}
+
+	if !u.IsAbs() {
+		if base == nil {
+			return nil, fmt.Errorf("relative photo URL %q requires a base URL", value)
+		}
+		u = base.ResolveReference(u)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported photo URL scheme %q", u.Scheme)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download photo: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download photo: status %d", resp.StatusCode)
+	}
+
+	return s.mediaService.Upload(ctx, path.Base(u.Path), resp.Body)
+}
+
+// link связывает запись с изображением и пишет изменение в журнал аудита
+func (s *MediaBackfillService) link(ctx context.Context, ref *entity.MediaPhotoRef, item *entity.Media) (bool, error) {
+	var linked bool
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		linked, err = s.mediaRepo.LinkPhoto(ctx, ref, item)
+		if err != nil || !linked {
+			return err
+		}
+
+		before := map[string]any{ref.URLField: ref.Value, ref.IDField: nil}
+		after := map[string]any{ref.URLField: item.URL, ref.IDField: item.ID}
+		return s.auditService.Record(ctx, ref.EntityType, ref.EntityID, entity.AuditActionUpdate, before, after)
+	})
+	return linked, err
+}
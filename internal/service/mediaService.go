@@ -0,0 +1,342 @@
+package service
+
+import (
+	"Clinic_backend/config"
+	"Clinic_backend/internal/entity"
+	"Clinic_backend/internal/logging"
+	"Clinic_backend/internal/media"
+	"Clinic_backend/internal/repository"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+)
+
+var (
+	// ErrMediaTooLarge - файл больше media.max_upload_size
+	ErrMediaTooLarge = errors.New("file is too large")
+	// ErrMediaWidthNotAllowed - запрошена ширина, которой нет в media.variant_widths
+	ErrMediaWidthNotAllowed = errors.New("unsupported image width")
+)
+
+var mediaIDPattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+type MediaServiceInterface interface {
+	Upload(ctx context.Context, filename string, r io.Reader) (*entity.Media, error)
+	UploadBase64(ctx context.Context, filename string, value string) (*entity.Media, error)
+	GetByID(ctx context.Context, id string) (*entity.Media, error)
+	Open(ctx context.Context, id string) (io.ReadCloser, error)
+	GetVariant(ctx context.Context, id string, width int) (*entity.MediaVariant, error)
+	OpenVariant(ctx context.Context, id string, width int) (io.ReadCloser, error)
+	Srcsets(ctx context.Context, ids []string) (map[string]entity.Srcset, error)
+	GeneratePendingVariants(ctx context.Context, limit int) (int, error)
+}
+
+type MediaService struct {
+	cfg       *config.Holder
+	store     media.BlobStore
+	mediaRepo repository.MediaRepositoryInterface
+}
+
+func NewMediaService(cfg *config.Holder, store media.BlobStore, mediaRepo repository.MediaRepositoryInterface) MediaServiceInterface {
+	return &MediaService{
+		cfg:       cfg,
+		store:     store,
+		mediaRepo: mediaRepo,
+	}
+}
+
+// Upload проверяет изображение, удаляет метаданные и сохраняет его под хэшем содержимого.
+// Повторная загрузка того же изображения возвращает существующую запись.
+func (s *MediaService) Upload(ctx context.Context, filename string, r io.Reader) (*entity.Media, error) {
+	maxSize := s.cfg.Get().Media.MaxUploadSize
+
+	data, err := io.ReadAll(io.LimitReader(r, maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read upload: %w", err)
+	}
+	if int64(len(data)) > maxSize {
+		return nil, ErrMediaTooLarge
+	}
+
+	img, err := media.Process(data)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(img.Data)
+	id := hex.EncodeToString(sum[:])
+
+	// Содержимое записывается до строки в БД, чтобы запись никогда не ссылалась на отсутствующий объект
+	exists, err := s.store.Exists(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		if err := s.store.Put(ctx, id, img.Data, img.ContentType); err != nil {
+			return nil, err
+		}
+	}
+
+	created, err := s.mediaRepo.Create(ctx, &entity.Media{
+		ID:           id,
+		ContentType:  img.ContentType,
+		Size:         int64(len(img.Data)),
+		Width:        img.Width,
+		Height:       img.Height,
+		OriginalName: filepath.Base(filename),
+		UploadedBy:   entity.ActorFromContext(ctx).UserID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logging.FromContext(ctx).Info("Media uploaded",
+		"media_id", created.ID, "content_type", created.ContentType, "size", created.Size, "deduplicated", exists)
+
+	created.Srcset = srcset(created, s.cfg.Get().Media.VariantWidths)
+	return created, nil
+}
+
+// UploadBase64 загружает изображение, переданное data URI или base64-строкой
+func (s *MediaService) UploadBase64(ctx context.Context, filename string, value string) (*entity.Media, error) {
+	// base64 длиннее содержимого на треть - проверяем размер до декодирования
+	if int64(len(value)) > s.cfg.Get().Media.MaxUploadSize/3*4+1024 {
+		return nil, ErrMediaTooLarge
+	}
+
+	data, err := media.DecodeBase64(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Upload(ctx, filename, bytes.NewReader(data))
+}
+
+func (s *MediaService) GetByID(ctx context.Context, id string) (*entity.Media, error) {
+	if !mediaIDPattern.MatchString(id) {
+		return nil, repository.ErrMediaNotFound
+	}
+	return s.mediaRepo.GetByID(ctx, id)
+}
+
+// Open возвращает содержимое изображения; вызывающий закрывает reader
+func (s *MediaService) Open(ctx context.Context, id string) (io.ReadCloser, error) {
+	if !mediaIDPattern.MatchString(id) {
+		return nil, repository.ErrMediaNotFound
+	}
+
+	body, err := s.store.Get(ctx, id)
+	if errors.Is(err, media.ErrNotFound) {
+		return nil, repository.ErrMediaNotFound
+	}
+	return body, err
+}
+
+// GetVariant возвращает копию изображения заданной ширины, если она уже создана
+func (s *MediaService) GetVariant(ctx context.Context, id string, width int) (*entity.MediaVariant, error) {
+	if !slices.Contains(s.cfg.Get().Media.VariantWidths, width) {
+		return nil, ErrMediaWidthNotAllowed
+	}
+	if !mediaIDPattern.MatchString(id) {
+		return nil, repository.ErrMediaNotFound
+	}
+	return s.mediaRepo.GetVariant(ctx, id, width)
+}
+
+func (s *MediaService) OpenVariant(ctx context.Context, id string, width int) (io.ReadCloser, error) {
+	if !mediaIDPattern.MatchString(id) {
+		return nil, repository.ErrMediaNotFound
+	}
+
+	body, err := s.store.Get(ctx, variantKey(id, width))
+	if errors.Is(err, media.ErrNotFound) {
+		return nil, repository.ErrMediaVariantNotFound
+	}
+	return body, err
+}
+
+// Srcsets возвращает наборы адресов для srcset по ID изображений одним запросом.
+// Набор зависит только от изображения и настроек, а не от готовности копий:
+// пока копия не создана, по её адресу отдаётся оригинал.
+func (s *MediaService) Srcsets(ctx context.Context, ids []string) (map[string]entity.Srcset, error) {
+	items, err := s.mediaRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	widths := s.cfg.Get().Media.VariantWidths
+	result := make(map[string]entity.Srcset, len(items))
+	for id, item := range items {
+		result[id] = srcset(&item, widths)
+	}
+	return result, nil
+}
+
+// GeneratePendingVariants создаёт уменьшенные копии для очередной порции изображений
+// и возвращает число обработанных. Ошибка хранилища или БД прерывает порцию - оставшиеся
+// изображения будут обработаны при следующем запуске. Повторная обработка безопасна,
+// поэтому несколько экземпляров приложения могут работать одновременно.
+func (s *MediaService) GeneratePendingVariants(ctx context.Context, limit int) (int, error) {
+	pending, err := s.mediaRepo.ListPendingVariants(ctx, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range pending {
+		if err := s.generateVariants(ctx, &pending[i]); err != nil {
+			return i, err
+		}
+	}
+
+	return len(pending), nil
+}
+
+func (s *MediaService) generateVariants(ctx context.Context, item *entity.Media) error {
+	logger := logging.FromContext(ctx).With("media_id", item.ID)
+
+	if !media.CanResize(item.ContentType) {
+		return s.mediaRepo.SetVariantsStatus(ctx, item.ID, entity.MediaVariantsUnsupported)
+	}
+
+	body, err := s.store.Get(ctx, item.ID)
+	if errors.Is(err, media.ErrNotFound) {
+		logger.Error("Media blob is missing, variants skipped")
+		return s.mediaRepo.SetVariantsStatus(ctx, item.ID, entity.MediaVariantsFailed)
+	}
+	if err != nil {
+		return err
+	}
+	data, err := io.ReadAll(body)
+	body.Close()
+	if err != nil {
+		return fmt.Errorf("failed to read media blob: %w", err)
+	}
+
+	cfg := s.cfg.Get().Media
+	variants, err := media.Resize(data, cfg.VariantWidths, cfg.VariantQuality)
+	if err != nil {
+		// Ошибки декодирования и кодирования не исчезнут при повторе
+		logger.Error("Failed to generate media variants", "error", err.Error())
+		return s.mediaRepo.SetVariantsStatus(ctx, item.ID, entity.MediaVariantsFailed)
+	}
+
+	saved := make([]entity.MediaVariant, 0, len(variants))
+	for _, variant := range variants {
+		if err := s.store.Put(ctx, variantKey(item.ID, variant.Width), variant.Data, variant.ContentType); err != nil {
+			return err
+		}
+		saved = append(saved, entity.MediaVariant{
+			MediaID:     item.ID,
+			Width:       variant.Width,
+			Height:      variant.Height,
+			ContentType: variant.ContentType,
+			Size:        int64(len(variant.Data)),
+		})
+	}
+
+	if err := s.mediaRepo.SaveVariants(ctx, item.ID, saved); err != nil {
+		return err
+	}
+
+	logger.Info("Media variants generated", "count", len(saved))
+	return nil
+}
+
+// variantKey - ключ копии в хранилище рядом с оригиналом
+func variantKey(id string, width int) string {
+	return id + "-w" + strconv.Itoa(width)
+}
+
+// srcset - оригинал и копии всех настроенных ширин меньше исходной
+func srcset(item *entity.Media, widths []int) entity.Srcset {
+	set := entity.Srcset{strconv.Itoa(item.Width) + "w": item.URL}
+	if !media.CanResize(item.ContentType) {
+		return set
+	}
+
+	for _, width := range widths {
+		if width < item.Width {
+			set[strconv.Itoa(width)+"w"] = entity.MediaVariantURL(item.ID, width)
+		}
+	}
+	return set
+}
+
+// srcsetField возвращает ID изображения записи и поле для набора адресов
+type srcsetField[T any] func(item *T) (*string, *entity.Srcset)
+
+// withSrcsets заполняет srcset у списка записей одним запросом.
+// err - ошибка загрузки самих записей, чтобы вызов можно было оборачивать в return.
+func withSrcsets[T any](ctx context.Context, mediaService MediaServiceInterface, items []T, err error, field srcsetField[T]) ([]T, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	ptrs := make([]*T, len(items))
+	for i := range items {
+		ptrs[i] = &items[i]
+	}
+	if err := fillSrcsets(ctx, mediaService, ptrs, field); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func withSrcset[T any](ctx context.Context, mediaService MediaServiceInterface, item *T, err error, field srcsetField[T]) (*T, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	if err := fillSrcsets(ctx, mediaService, []*T{item}, field); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+func fillSrcsets[T any](ctx context.Context, mediaService MediaServiceInterface, items []*T, field srcsetField[T]) error {
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		if id, _ := field(item); id != nil {
+			ids = append(ids, *id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	srcsets, err := mediaService.Srcsets(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		if id, set := field(item); id != nil {
+			*set = srcsets[*id]
+		}
+	}
+	return nil
+}
+
+// attachMedia проверяет ссылку на загруженное изображение и записывает его адрес
+// в устаревшее текстовое поле, чтобы клиенты, читающие URL, продолжали работать
+func attachMedia(ctx context.Context, mediaService MediaServiceInterface, field string, id *string, url **string) error {
+	if id == nil {
+		return nil
+	}
+
+	m, err := mediaService.GetByID(ctx, *id)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", field, err)
+	}
+
+	*url = &m.URL
+	return nil
+}
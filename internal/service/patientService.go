@@ -0,0 +1,584 @@
+package service
+
+import (
+	"Clinic_backend/internal/entity"
+	"Clinic_backend/internal/repository"
+	"Clinic_backend/internal/utils"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidPatientProfile - данные профиля не прошли проверку
+	ErrInvalidPatientProfile = errors.New("invalid patient profile")
+	// ErrPatientAccessDenied - пользователь не может действовать от имени этого пациента.
+	// Возвращается и для несуществующего профиля, чтобы не раскрывать его наличие.
+	ErrPatientAccessDenied = errors.New("access to patient denied")
+	// ErrConsentRequired - опекун не подтвердил согласие подопечного от 15 лет
+	ErrConsentRequired = errors.New("dependent's consent must be confirmed")
+	// ErrDependentBirthDateLocked - опекун пытается изменить дату рождения подопечного
+	ErrDependentBirthDateLocked = errors.New("dependent's birth date cannot be changed by a guardian")
+)
+
+const (
+	// consentAge - возраст, с которого пациент сам даёт согласие на медицинское
+	// вмешательство (ст. 54 323-ФЗ)
+	consentAge = 15
+	adultAge   = 18
+)
+
+type PatientServiceInterface interface {
+	ResolvePatient(ctx context.Context, userID int, patientID *int, access string) (*entity.PatientProfile, error)
+	GetMyProfile(ctx context.Context, userID int, patientID *int) (*entity.PatientProfile, error)
+	SaveMyProfile(ctx context.Context, userID int, patientID *int, req *entity.PatientProfileRequest) (*entity.PatientProfile, error)
+	ListDependents(ctx context.Context, userID int) ([]entity.Dependent, error)
+	AddDependent(ctx context.Context, userID int, req *entity.DependentCreateRequest) (*entity.Dependent, error)
+	UpdateDependent(ctx context.Context, userID, patientID int, req *entity.GuardianshipUpdateRequest) (*entity.Dependent, error)
+	RemoveDependent(ctx context.Context, userID, patientID int) error
+	GetByID(ctx context.Context, id int) (*entity.PatientProfile, error)
+	Search(ctx context.Context, filter *entity.PatientFilter) ([]entity.PatientProfile, int, error)
+	ListTreatingDoctors(ctx context.Context, patientID int) ([]entity.TreatingDoctor, error)
+	AssignTreatingDoctor(ctx context.Context, adminUserID, patientID, doctorUserID int) (*entity.TreatingDoctor, error)
+	RemoveTreatingDoctor(ctx context.Context, patientID, doctorUserID int) error
+	IsTreatingDoctor(ctx context.Context, doctorUserID, patientID int) (bool, error)
+}
+
+type PatientService struct {
+	txManager    repository.TransactionManagerInterface
+	auditService AuditServiceInterface
+	patientRepo  repository.PatientRepositoryInterface
+	guardianRepo repository.GuardianRepositoryInterface
+	treatingRepo repository.TreatingDoctorRepositoryInterface
+}
+
+func NewPatientService(txManager repository.TransactionManagerInterface, auditService AuditServiceInterface, patientRepo repository.PatientRepositoryInterface, guardianRepo repository.GuardianRepositoryInterface, treatingRepo repository.TreatingDoctorRepositoryInterface) PatientServiceInterface {
+	return &PatientService{
+		txManager:    txManager,
+		auditService: auditService,
+		patientRepo:  patientRepo,
+		guardianRepo: guardianRepo,
+		treatingRepo: treatingRepo,
+	}
+}
+
+// ResolvePatient возвращает профиль, от имени которого действует пользователь: свой,
+// если patientID не передан, иначе профиль подопечного при наличии права access
+func (s *PatientService) ResolvePatient(ctx context.Context, userID int, patientID *int, access string) (*entity.PatientProfile, error) {
+	if patientID == nil {
+		return s.patientRepo.GetByUserID(ctx, userID)
+	}
+
+	profile, err := s.patientRepo.GetByID(ctx, *patientID)
+	if err != nil {
+		if errors.Is(err, repository.ErrPatientNotFound) {
+			return nil, ErrPatientAccessDenied
+		}
+		return nil, err
+	}
+	if profile.UserID != nil && *profile.UserID == userID {
+		return profile, nil
+	}
+
+	guardian, err := s.guardianRepo.Get(ctx, userID, profile.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrGuardianshipNotFound) {
+			return nil, ErrPatientAccessDenied
+		}
+		return nil, err
+	}
+	if !slices.Contains(guardianAccess(guardian, profile, time.Now()), access) {
+		return nil, ErrPatientAccessDenied
+	}
+
+	return profile, nil
+}
+
+func (s *PatientService) GetMyProfile(ctx context.Context, userID int, patientID *int) (*entity.PatientProfile, error) {
+	return s.ResolvePatient(ctx, userID, patientID, entity.PatientAccessManage)
+}
+
+// SaveMyProfile создаёт или заменяет профиль текущего пользователя, а с patientID -
+// профиль подопечного
+func (s *PatientService) SaveMyProfile(ctx context.Context, userID int, patientID *int, req *entity.PatientProfileRequest) (*entity.PatientProfile, error) {
+	ctx, span := tracer.Start(ctx, "PatientService.SaveMyProfile")
+	defer span.End()
+
+	profile, err := patientFromRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	if patientID != nil {
+		return s.saveDependentProfile(ctx, userID, *patientID, profile)
+	}
+	profile.UserID = &userID
+
+	var saved *entity.PatientProfile
+	err = s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		before, err := s.patientRepo.GetByUserID(ctx, userID)
+		if err != nil && !errors.Is(err, repository.ErrPatientNotFound) {
+			return err
+		}
+
+		saved, err = s.patientRepo.UpsertByUserID(ctx, profile)
+		if err != nil {
+			return err
+		}
+
+		action := entity.AuditActionUpdate
+		if before == nil {
+			action = entity.AuditActionCreate
+		}
+		return s.auditService.Record(ctx, entity.AuditEntityPatientProfile, saved.ID, action, nil, patientAuditState(before, saved))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return saved, nil
+}
+
+// saveDependentProfile заменяет профиль подопечного. Дата рождения задаётся при добавлении
+// подопечного и опекуном не меняется: от неё зависят права самого опекуна, и, «омолодив»
+// подопечного, он вернул бы себе права, требующие его согласия.
+func (s *PatientService) saveDependentProfile(ctx context.Context, userID, patientID int, profile *entity.PatientProfile) (*entity.PatientProfile, error) {
+	var saved *entity.PatientProfile
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		before, err := s.ResolvePatient(ctx, userID, &patientID, entity.PatientAccessManage)
+		if err != nil {
+			return err
+		}
+
+		if profile.BirthDate != nil && !reflect.DeepEqual(profile.BirthDate, before.BirthDate) {
+			return ErrDependentBirthDateLocked
+		}
+		profile.BirthDate = before.BirthDate
+
+		saved, err = s.patientRepo.Update(ctx, patientID, profile)
+		if err != nil {
+			return err
+		}
+		return s.auditService.Record(ctx, entity.AuditEntityPatientProfile, patientID, entity.AuditActionUpdate, nil, patientAuditState(before, saved))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return saved, nil
+}
+
+func (s *PatientService) ListDependents(ctx context.Context, userID int) ([]entity.Dependent, error) {
+	ctx, span := tracer.Start(ctx, "PatientService.ListDependents")
+	defer span.End()
+
+	guardians, err := s.guardianRepo.ListByGuardian(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	patientIDs := make([]int, 0, len(guardians))
+	for _, guardian := range guardians {
+		patientIDs = append(patientIDs, guardian.PatientID)
+	}
+
+	profiles, err := s.patientRepo.GetByIDs(ctx, patientIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	dependents := make([]entity.Dependent, 0, len(guardians))
+	for i := range guardians {
+		profile := profiles[guardians[i].PatientID]
+		dependents = append(dependents, entity.Dependent{
+			Profile:      profile,
+			Guardianship: guardians[i],
+			Access:       guardianAccess(&guardians[i], &profile, now),
+		})
+	}
+
+	return dependents, nil
+}
+
+// AddDependent создаёт профиль подопечного без учётной записи и делает пользователя его опекуном
+func (s *PatientService) AddDependent(ctx context.Context, userID int, req *entity.DependentCreateRequest) (*entity.Dependent, error) {
+	ctx, span := tracer.Start(ctx, "PatientService.AddDependent")
+	defer span.End()
+
+	profile, err := patientFromRequest(&req.PatientProfileRequest)
+	if err != nil {
+		return nil, err
+	}
+	if profile.BirthDate == nil {
+		return nil, fmt.Errorf("%w: birth_date is required for a dependent", ErrInvalidPatientProfile)
+	}
+
+	now := time.Now()
+	age := patientAge(profile, now)
+	// Совершеннолетний может доверить свои дела только сам
+	if age >= adultAge && !req.ConsentConfirmed {
+		return nil, fmt.Errorf("%w: an adult dependent must consent to guardianship", ErrConsentRequired)
+	}
+	if age >= consentAge && (req.CanBook || req.CanViewRecords) && !req.ConsentConfirmed {
+		return nil, ErrConsentRequired
+	}
+
+	guardian := &entity.PatientGuardian{
+		GuardianUserID: userID,
+		Relationship:   req.Relationship,
+		CanBook:        req.CanBook,
+		CanViewRecords: req.CanViewRecords,
+	}
+	if req.ConsentConfirmed {
+		guardian.ConsentConfirmedAt = &now
+	}
+
+	var dependent *entity.Dependent
+	err = s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		created, err := s.patientRepo.Create(ctx, profile)
+		if err != nil {
+			return err
+		}
+		if err := s.auditService.Record(ctx, entity.AuditEntityPatientProfile, created.ID, entity.AuditActionCreate, nil, patientAuditState(nil, created)); err != nil {
+			return err
+		}
+
+		guardian.PatientID = created.ID
+		guardian, err = s.guardianRepo.Create(ctx, guardian)
+		if err != nil {
+			return err
+		}
+		if err := s.auditService.Record(ctx, entity.AuditEntityPatientGuardian, guardian.ID, entity.AuditActionCreate, nil, guardian); err != nil {
+			return err
+		}
+
+		dependent = &entity.Dependent{
+			Profile:      *created,
+			Guardianship: *guardian,
+			Access:       guardianAccess(guardian, created, now),
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return dependent, nil
+}
+
+// UpdateDependent меняет степень родства и флаги согласия подопечного
+func (s *PatientService) UpdateDependent(ctx context.Context, userID, patientID int, req *entity.GuardianshipUpdateRequest) (*entity.Dependent, error) {
+	ctx, span := tracer.Start(ctx, "PatientService.UpdateDependent")
+	defer span.End()
+
+	var dependent *entity.Dependent
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		before, err := s.guardianRepo.Get(ctx, userID, patientID)
+		if err != nil {
+			return err
+		}
+		profile, err := s.patientRepo.GetByID(ctx, patientID)
+		if err != nil {
+			return err
+		}
+
+		guardian := *before
+		if req.Relationship != nil {
+			guardian.Relationship = *req.Relationship
+		}
+		if req.CanBook != nil {
+			guardian.CanBook = *req.CanBook
+		}
+		if req.CanViewRecords != nil {
+			guardian.CanViewRecords = *req.CanViewRecords
+		}
+
+		// Отзыв согласия подтверждения не требует, новое согласие - требует
+		now := time.Now()
+		granted := (guardian.CanBook && !before.CanBook) || (guardian.CanViewRecords && !before.CanViewRecords)
+		if granted && patientAge(profile, now) >= consentAge && !req.ConsentConfirmed {
+			return ErrConsentRequired
+		}
+		if req.ConsentConfirmed {
+			guardian.ConsentConfirmedAt = &now
+		}
+
+		updated, err := s.guardianRepo.Update(ctx, &guardian)
+		if err != nil {
+			return err
+		}
+		if err := s.auditService.Record(ctx, entity.AuditEntityPatientGuardian, updated.ID, entity.AuditActionUpdate, before, updated); err != nil {
+			return err
+		}
+
+		dependent = &entity.Dependent{
+			Profile:      *profile,
+			Guardianship: *updated,
+			Access:       guardianAccess(updated, profile, now),
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return dependent, nil
+}
+
+// RemoveDependent снимает опекунство. Профиль подопечного остаётся:
+// медицинские данные хранятся независимо от того, кто ими управляет.
+func (s *PatientService) RemoveDependent(ctx context.Context, userID, patientID int) error {
+	ctx, span := tracer.Start(ctx, "PatientService.RemoveDependent")
+	defer span.End()
+
+	return s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		before, err := s.guardianRepo.Get(ctx, userID, patientID)
+		if err != nil {
+			return err
+		}
+
+		if err := s.guardianRepo.Delete(ctx, userID, patientID); err != nil {
+			return err
+		}
+		return s.auditService.Record(ctx, entity.AuditEntityPatientGuardian, before.ID, entity.AuditActionDelete, before, nil)
+	})
+}
+
+func (s *PatientService) GetByID(ctx context.Context, id int) (*entity.PatientProfile, error) {
+	return s.patientRepo.GetByID(ctx, id)
+}
+
+func (s *PatientService) Search(ctx context.Context, filter *entity.PatientFilter) ([]entity.PatientProfile, int, error) {
+	ctx, span := tracer.Start(ctx, "PatientService.Search")
+	defer span.End()
+
+	normalizePage(&filter.Page, &filter.Limit)
+
+	// Телефон и полис ищутся по цифрам, в каком бы виде их ни ввели
+	filter.Name = strings.TrimSpace(filter.Name)
+	filter.Phone = digitsOnly(filter.Phone)
+	filter.Policy = digitsOnly(filter.Policy)
+
+	return s.patientRepo.Search(ctx, filter)
+}
+
+func (s *PatientService) ListTreatingDoctors(ctx context.Context, patientID int) ([]entity.TreatingDoctor, error) {
+	if _, err := s.patientRepo.GetByID(ctx, patientID); err != nil {
+		return nil, err
+	}
+	return s.treatingRepo.ListByPatient(ctx, patientID)
+}
+
+// AssignTreatingDoctor назначает пациенту лечащего врача - пользователя с профилем врача
+func (s *PatientService) AssignTreatingDoctor(ctx context.Context, adminUserID, patientID, doctorUserID int) (*entity.TreatingDoctor, error) {
+	ctx, span := tracer.Start(ctx, "PatientService.AssignTreatingDoctor")
+	defer span.End()
+
+	var assigned *entity.TreatingDoctor
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := s.patientRepo.GetByID(ctx, patientID); err != nil {
+			return err
+		}
+
+		var err error
+		assigned, err = s.treatingRepo.Assign(ctx, &entity.TreatingDoctor{
+			PatientID:    patientID,
+			DoctorUserID: doctorUserID,
+			AssignedBy:   &adminUserID,
+		})
+		if err != nil {
+			return err
+		}
+		return s.auditService.Record(ctx, entity.AuditEntityTreatingDoctor, assigned.ID, entity.AuditActionCreate, nil, assigned)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return assigned, nil
+}
+
+func (s *PatientService) RemoveTreatingDoctor(ctx context.Context, patientID, doctorUserID int) error {
+	ctx, span := tracer.Start(ctx, "PatientService.RemoveTreatingDoctor")
+	defer span.End()
+
+	return s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		before, err := s.treatingRepo.Get(ctx, patientID, doctorUserID)
+		if err != nil {
+			return err
+		}
+
+		if err := s.treatingRepo.Delete(ctx, patientID, doctorUserID); err != nil {
+			return err
+		}
+		return s.auditService.Record(ctx, entity.AuditEntityTreatingDoctor, before.ID, entity.AuditActionDelete, before, nil)
+	})
+}
+
+// IsTreatingDoctor сообщает, может ли врач вести записи о приёмах пациента
+func (s *PatientService) IsTreatingDoctor(ctx context.Context, doctorUserID, patientID int) (bool, error) {
+	return s.treatingRepo.IsTreating(ctx, doctorUserID, patientID)
+}
+
+// patientFromRequest проверяет и нормализует данные профиля. Пустые строки
+// в необязательных полях означают отсутствие значения.
+func patientFromRequest(req *entity.PatientProfileRequest) (*entity.PatientProfile, error) {
+	profile := &entity.PatientProfile{
+		LastName:        strings.TrimSpace(req.LastName),
+		FirstName:       strings.TrimSpace(req.FirstName),
+		MiddleName:      trimmedOrNil(req.MiddleName),
+		BirthDate:       trimmedOrNil(req.BirthDate),
+		Sex:             trimmedOrNil(req.Sex),
+		Phone:           trimmedOrNil(req.Phone),
+		Address:         trimmedOrNil(req.Address),
+		InsurancePolicy: trimmedOrNil(req.InsurancePolicy),
+		SNILS:           trimmedOrNil(req.SNILS),
+	}
+
+	if profile.LastName == "" || profile.FirstName == "" {
+		return nil, fmt.Errorf("%w: last_name and first_name are required", ErrInvalidPatientProfile)
+	}
+	if profile.BirthDate != nil {
+		if err := utils.ValidateBirthDate(*profile.BirthDate); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPatientProfile, err)
+		}
+	}
+	if profile.Sex != nil && *profile.Sex != entity.SexMale && *profile.Sex != entity.SexFemale {
+		return nil, fmt.Errorf("%w: sex must be male or female", ErrInvalidPatientProfile)
+	}
+	if profile.Phone != nil {
+		*profile.Phone = utils.NormalizePhone(*profile.Phone)
+		if err := utils.ValidatePhone(*profile.Phone); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPatientProfile, err)
+		}
+	}
+	if profile.InsurancePolicy != nil {
+		*profile.InsurancePolicy = strings.ReplaceAll(*profile.InsurancePolicy, " ", "")
+		if err := utils.ValidatePolicyNumber(*profile.InsurancePolicy); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPatientProfile, err)
+		}
+	}
+	if profile.SNILS != nil {
+		*profile.SNILS = utils.NormalizeSNILS(*profile.SNILS)
+		if err := utils.ValidateSNILS(*profile.SNILS); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPatientProfile, err)
+		}
+	}
+
+	return profile, nil
+}
+
+// patientAuditState - запись журнала аудита об изменении профиля. Журнал неизменяем,
+// поэтому персональные данные в него не пишутся - только имена изменённых полей.
+// При создании профиля перечисляются заполненные поля.
+func patientAuditState(before, after *entity.PatientProfile) map[string]any {
+	if before == nil {
+		before = &entity.PatientProfile{}
+	}
+
+	fields := []struct {
+		name    string
+		changed bool
+	}{
+		{"last_name", before.LastName != after.LastName},
+		{"first_name", before.FirstName != after.FirstName},
+		{"middle_name", !reflect.DeepEqual(before.MiddleName, after.MiddleName)},
+		{"birth_date", !reflect.DeepEqual(before.BirthDate, after.BirthDate)},
+		{"sex", !reflect.DeepEqual(before.Sex, after.Sex)},
+		{"phone", !reflect.DeepEqual(before.Phone, after.Phone)},
+		{"address", !reflect.DeepEqual(before.Address, after.Address)},
+		{"insurance_policy", !reflect.DeepEqual(before.InsurancePolicy, after.InsurancePolicy)},
+		{"snils", !reflect.DeepEqual(before.SNILS, after.SNILS)},
+	}
+
+	changed := []string{}
+	for _, field := range fields {
+		if field.changed {
+			changed = append(changed, field.name)
+		}
+	}
+
+	return map[string]any{
+		"user_id":        after.UserID,
+		"changed_fields": changed,
+	}
+}
+
+// guardianAccess возвращает действующие права опекуна. До 15 лет опекун как законный
+// представитель видит медицинские данные и записывает к врачу без согласия подопечного,
+// с 15 лет для просмотра данных, а с 18 и для записи нужен флаг и согласие подопечного,
+// подтверждённое не раньше, чем он достиг этого возраста. Флаги, выставленные до того,
+// сами по себе прав не дают. Профилем с собственной учётной записью пациент управляет сам.
+func guardianAccess(guardian *entity.PatientGuardian, profile *entity.PatientProfile, now time.Time) []string {
+	age := patientAge(profile, now)
+
+	access := []string{}
+	if age < consentAge || (guardian.CanViewRecords && consentedSince(guardian, profile, consentAge)) {
+		access = append(access, entity.PatientAccessView)
+	}
+	if age < adultAge || (guardian.CanBook && consentedSince(guardian, profile, adultAge)) {
+		access = append(access, entity.PatientAccessBook)
+	}
+	if profile.UserID == nil {
+		access = append(access, entity.PatientAccessManage)
+	}
+	return access
+}
+
+// consentedSince сообщает, подтверждено ли согласие подопечного после того, как ему
+// исполнилось age лет. Без даты рождения достаточно самого факта подтверждения.
+func consentedSince(guardian *entity.PatientGuardian, profile *entity.PatientProfile, age int) bool {
+	if guardian.ConsentConfirmedAt == nil {
+		return false
+	}
+	if profile.BirthDate == nil {
+		return true
+	}
+	birthDate, err := time.Parse(time.DateOnly, *profile.BirthDate)
+	if err != nil {
+		return true
+	}
+	return !guardian.ConsentConfirmedAt.Before(birthDate.AddDate(age, 0, 0))
+}
+
+// patientAge возвращает число полных лет. Пациент без даты рождения считается
+// совершеннолетним - для него действуют самые строгие правила.
+func patientAge(profile *entity.PatientProfile, now time.Time) int {
+	if profile.BirthDate == nil {
+		return adultAge
+	}
+	birthDate, err := time.Parse(time.DateOnly, *profile.BirthDate)
+	if err != nil {
+		return adultAge
+	}
+
+	age := now.Year() - birthDate.Year()
+	if now.Month() < birthDate.Month() || (now.Month() == birthDate.Month() && now.Day() < birthDate.Day()) {
+		age--
+	}
+	return age
+}
+
+func trimmedOrNil(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*value)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
+
+func digitsOnly(value string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, value)
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"Clinic_backend/internal/entity"
+	"Clinic_backend/internal/repository"
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+type fakePatientRepo struct {
+	repository.PatientRepositoryInterface
+	profiles map[int]*entity.PatientProfile
+}
+
+func (r *fakePatientRepo) GetByID(ctx context.Context, id int) (*entity.PatientProfile, error) {
+	profile, ok := r.profiles[id]
+	if !ok {
+		return nil, repository.ErrPatientNotFound
+	}
+	copied := *profile
+	return &copied, nil
+}
+
+func (r *fakePatientRepo) Update(ctx context.Context, id int, profile *entity.PatientProfile) (*entity.PatientProfile, error) {
+	saved := *profile
+	saved.ID = id
+	r.profiles[id] = &saved
+	return &saved, nil
+}
+
+type fakeGuardianRepo struct {
+	repository.GuardianRepositoryInterface
+	guardian *entity.PatientGuardian
+}
+
+func (r *fakeGuardianRepo) Get(ctx context.Context, guardianUserID, patientID int) (*entity.PatientGuardian, error) {
+	if r.guardian.GuardianUserID != guardianUserID || r.guardian.PatientID != patientID {
+		return nil, repository.ErrGuardianshipNotFound
+	}
+	return r.guardian, nil
+}
+
+type noTx struct{}
+
+func (noTx) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type nopAudit struct {
+	AuditServiceInterface
+}
+
+func (nopAudit) Record(ctx context.Context, entityType string, entityID int, action string, before, after any) error {
+	return nil
+}
+
+func TestGuardianCannotRejuvenateDependent(t *testing.T) {
+	const guardianID, patientID = 7, 42
+	now := time.Now()
+	// Подопечному 16: просмотр карты требует согласия, которого нет
+	birthDate := now.AddDate(-16, 0, -1).Format(time.DateOnly)
+	patients := &fakePatientRepo{profiles: map[int]*entity.PatientProfile{
+		patientID: {ID: patientID, LastName: "Иванов", FirstName: "Пётр", BirthDate: &birthDate},
+	}}
+	guardians := &fakeGuardianRepo{guardian: &entity.PatientGuardian{GuardianUserID: guardianID, PatientID: patientID}}
+	s := NewPatientService(noTx{}, nopAudit{}, patients, guardians, nil)
+
+	accessBefore := guardianAccess(guardians.guardian, patients.profiles[patientID], now)
+	if slices.Contains(accessBefore, entity.PatientAccessView) {
+		t.Fatalf("guardian of a 16-year-old without consent has %v", accessBefore)
+	}
+
+	younger := now.AddDate(-10, 0, 0).Format(time.DateOnly)
+	id := patientID
+	_, err := s.SaveMyProfile(context.Background(), guardianID, &id, &entity.PatientProfileRequest{
+		LastName: "Иванов", FirstName: "Пётр", BirthDate: &younger,
+	})
+	if !errors.Is(err, ErrDependentBirthDateLocked) {
+		t.Fatalf("SaveMyProfile() with another birth date: error %v, want ErrDependentBirthDateLocked", err)
+	}
+
+	// Без даты рождения профиль сохраняется, но дата остаётся прежней
+	saved, err := s.SaveMyProfile(context.Background(), guardianID, &id, &entity.PatientProfileRequest{
+		LastName: "Иванов", FirstName: "Павел",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.BirthDate == nil || *saved.BirthDate != birthDate {
+		t.Fatalf("birth date after save = %v, want %s", saved.BirthDate, birthDate)
+	}
+
+	accessAfter := guardianAccess(guardians.guardian, patients.profiles[patientID], now)
+	if !slices.Equal(accessAfter, accessBefore) {
+		t.Errorf("guardian access after the edit = %v, want %v", accessAfter, accessBefore)
+	}
+}
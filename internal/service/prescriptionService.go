@@ -0,0 +1,426 @@
+package service
+
+import (
+	"Clinic_backend/config"
+	"Clinic_backend/internal/entity"
+	"Clinic_backend/internal/pdf"
+	"Clinic_backend/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidPrescription - назначение не прошло проверку
+	ErrInvalidPrescription = errors.New("invalid prescription")
+	// ErrDoctorProfileRequired - учётная запись не привязана к врачу клиники
+	ErrDoctorProfileRequired = errors.New("no doctor profile is linked to this account")
+	// ErrPrescriptionForbidden - менять рецепт может только выписавший его врач
+	ErrPrescriptionForbidden = errors.New("only the issuing doctor can change this prescription")
+)
+
+type PrescriptionServiceInterface interface {
+	Create(ctx context.Context, doctorUserID int, req *entity.PrescriptionCreateRequest) (*entity.Prescription, error)
+	GetByID(ctx context.Context, id int) (*entity.Prescription, error)
+	List(ctx context.Context, filter *entity.PrescriptionFilter) ([]entity.Prescription, int, error)
+	Update(ctx context.Context, doctorUserID, id, version int, req *entity.PrescriptionRequest) (*entity.Prescription, error)
+	Delete(ctx context.Context, doctorUserID, id, version int) error
+	RenderPDF(ctx context.Context, id int) ([]byte, error)
+	ListForPatient(ctx context.Context, userID int, patientID *int, filter *entity.PrescriptionFilter) ([]entity.Prescription, int, error)
+	GetForPatient(ctx context.Context, userID int, patientID *int, id int) (*entity.Prescription, error)
+	RenderPDFForPatient(ctx context.Context, userID int, patientID *int, id int) ([]byte, error)
+}
+
+type PrescriptionService struct {
+	cfg              *config.Holder
+	font             *pdf.TrueTypeFont
+	txManager        repository.TransactionManagerInterface
+	auditService     AuditServiceInterface
+	patientService   PatientServiceInterface
+	doctorRepo       repository.DoctorRepositoryInterface
+	encounterRepo    repository.EncounterRepositoryInterface
+	prescriptionRepo repository.PrescriptionRepositoryInterface
+}
+
+func NewPrescriptionService(cfg *config.Holder, font *pdf.TrueTypeFont, txManager repository.TransactionManagerInterface, auditService AuditServiceInterface, patientService PatientServiceInterface, doctorRepo repository.DoctorRepositoryInterface, encounterRepo repository.EncounterRepositoryInterface, prescriptionRepo repository.PrescriptionRepositoryInterface) PrescriptionServiceInterface {
+	return &PrescriptionService{
+		cfg:              cfg,
+		font:             font,
+		txManager:        txManager,
+		auditService:     auditService,
+		patientService:   patientService,
+		doctorRepo:       doctorRepo,
+		encounterRepo:    encounterRepo,
+		prescriptionRepo: prescriptionRepo,
+	}
+}
+
+// Create выписывает рецепт от имени врача, привязанного к учётной записи
+func (s *PrescriptionService) Create(ctx context.Context, doctorUserID int, req *entity.PrescriptionCreateRequest) (*entity.Prescription, error) {
+	ctx, span := tracer.Start(ctx, "PrescriptionService.Create")
+	defer span.End()
+
+	content, err := prescriptionContent(&req.PrescriptionRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	var created *entity.Prescription
+	err = s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		doctor, err := doctorForUser(ctx, s.doctorRepo, doctorUserID)
+		if err != nil {
+			return err
+		}
+		if _, err := s.patientService.GetByID(ctx, req.PatientID); err != nil {
+			return err
+		}
+		if err := checkEncounter(ctx, s.encounterRepo, req.PatientID, req.EncounterID, ErrInvalidPrescription); err != nil {
+			return err
+		}
+
+		created, err = s.prescriptionRepo.Create(ctx, &entity.Prescription{
+			PatientID:           req.PatientID,
+			DoctorID:            doctor.ID,
+			EncounterID:         req.EncounterID,
+			PrescriptionContent: *content,
+		})
+		if err != nil {
+			return err
+		}
+		return s.recordAudit(ctx, entity.AuditActionCreate, nil, created)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.withSpecializations(ctx, created)
+}
+
+func (s *PrescriptionService) GetByID(ctx context.Context, id int) (*entity.Prescription, error) {
+	prescription, err := s.prescriptionRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.withSpecializations(ctx, prescription)
+}
+
+func (s *PrescriptionService) List(ctx context.Context, filter *entity.PrescriptionFilter) ([]entity.Prescription, int, error) {
+	ctx, span := tracer.Start(ctx, "PrescriptionService.List")
+	defer span.End()
+
+	normalizePage(&filter.Page, &filter.Limit)
+	prescriptions, total, err := s.prescriptionRepo.List(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := s.loadSpecializations(ctx, prescriptions); err != nil {
+		return nil, 0, err
+	}
+	return prescriptions, total, nil
+}
+
+// Update заменяет назначение целиком
+func (s *PrescriptionService) Update(ctx context.Context, doctorUserID, id, version int, req *entity.PrescriptionRequest) (*entity.Prescription, error) {
+	ctx, span := tracer.Start(ctx, "PrescriptionService.Update")
+	defer span.End()
+
+	content, err := prescriptionContent(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var updated *entity.Prescription
+	err = s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		before, err := s.editable(ctx, doctorUserID, id, version)
+		if err != nil {
+			return err
+		}
+		if err := checkEncounter(ctx, s.encounterRepo, before.PatientID, req.EncounterID, ErrInvalidPrescription); err != nil {
+			return err
+		}
+
+		updated, err = s.prescriptionRepo.Update(ctx, id, version, req.EncounterID, content)
+		if err != nil {
+			return err
+		}
+		return s.recordAudit(ctx, entity.AuditActionUpdate, before, updated)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.withSpecializations(ctx, updated)
+}
+
+// Delete отменяет рецепт; пациенту он больше не показывается
+func (s *PrescriptionService) Delete(ctx context.Context, doctorUserID, id, version int) error {
+	ctx, span := tracer.Start(ctx, "PrescriptionService.Delete")
+	defer span.End()
+
+	return s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		before, err := s.editable(ctx, doctorUserID, id, version)
+		if err != nil {
+			return err
+		}
+		if err := s.prescriptionRepo.Delete(ctx, id, version); err != nil {
+			return err
+		}
+		return s.auditService.Record(ctx, entity.AuditEntityPrescription, id, entity.AuditActionDelete, nil, map[string]any{
+			"patient_id": before.PatientID,
+			"doctor_id":  before.DoctorID,
+		})
+	})
+}
+
+func (s *PrescriptionService) RenderPDF(ctx context.Context, id int) ([]byte, error) {
+	prescription, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.render(ctx, prescription)
+}
+
+func (s *PrescriptionService) ListForPatient(ctx context.Context, userID int, patientID *int, filter *entity.PrescriptionFilter) ([]entity.Prescription, int, error) {
+	ctx, span := tracer.Start(ctx, "PrescriptionService.ListForPatient")
+	defer span.End()
+
+	patient, err := s.patientService.ResolvePatient(ctx, userID, patientID, entity.PatientAccessView)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	filter.PatientID = &patient.ID
+	return s.List(ctx, filter)
+}
+
+func (s *PrescriptionService) GetForPatient(ctx context.Context, userID int, patientID *int, id int) (*entity.Prescription, error) {
+	patient, err := s.patientService.ResolvePatient(ctx, userID, patientID, entity.PatientAccessView)
+	if err != nil {
+		return nil, err
+	}
+
+	prescription, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if prescription.PatientID != patient.ID {
+		return nil, repository.ErrPrescriptionNotFound
+	}
+	return prescription, nil
+}
+
+func (s *PrescriptionService) RenderPDFForPatient(ctx context.Context, userID int, patientID *int, id int) ([]byte, error) {
+	prescription, err := s.GetForPatient(ctx, userID, patientID, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.render(ctx, prescription)
+}
+
+// editable загружает рецепт для изменения выписавшим его врачом
+func (s *PrescriptionService) editable(ctx context.Context, doctorUserID, id, version int) (*entity.Prescription, error) {
+	doctor, err := doctorForUser(ctx, s.doctorRepo, doctorUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	prescription, err := s.prescriptionRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if prescription.DoctorID != doctor.ID {
+		return nil, ErrPrescriptionForbidden
+	}
+	// Клиент редактировал устаревшую версию
+	if version != repository.AnyVersion && prescription.Version != version {
+		return nil, repository.ErrVersionConflict
+	}
+	return prescription, nil
+}
+
+// doctorForUser - врач, привязанный к учётной записи
+func doctorForUser(ctx context.Context, doctorRepo repository.DoctorRepositoryInterface, userID int) (*entity.Doctor, error) {
+	doctor, err := doctorRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, ErrDoctorProfileRequired
+	}
+	return doctor, nil
+}
+
+// checkEncounter - документ можно связать только с приёмом того же пациента;
+// иначе возвращается invalid с пояснением
+func checkEncounter(ctx context.Context, encounterRepo repository.EncounterRepositoryInterface, patientID int, encounterID *int, invalid error) error {
+	if encounterID == nil {
+		return nil
+	}
+
+	encounter, err := encounterRepo.GetByID(ctx, *encounterID)
+	if errors.Is(err, repository.ErrEncounterNotFound) || (err == nil && encounter.PatientID != patientID) {
+		return fmt.Errorf("%w: encounter %d does not belong to the patient", invalid, *encounterID)
+	}
+	return err
+}
+
+func (s *PrescriptionService) withSpecializations(ctx context.Context, prescription *entity.Prescription) (*entity.Prescription, error) {
+	prescriptions := []entity.Prescription{*prescription}
+	if err := s.loadSpecializations(ctx, prescriptions); err != nil {
+		return nil, err
+	}
+	return &prescriptions[0], nil
+}
+
+// loadSpecializations подгружает специализации врачей одним запросом
+func (s *PrescriptionService) loadSpecializations(ctx context.Context, prescriptions []entity.Prescription) error {
+	if len(prescriptions) == 0 {
+		return nil
+	}
+
+	doctorIDs := make([]int, 0, len(prescriptions))
+	for _, prescription := range prescriptions {
+		doctorIDs = append(doctorIDs, prescription.DoctorID)
+	}
+
+	specializations, err := s.doctorRepo.GetSpecializationsForDoctors(ctx, doctorIDs)
+	if err != nil {
+		return err
+	}
+	for i := range prescriptions {
+		prescriptions[i].DoctorSpecializations = specializations[prescriptions[i].DoctorID]
+	}
+	return nil
+}
+
+// recordAudit пишет в журнал метаданные рецепта и имена изменённых полей, без самого назначения
+func (s *PrescriptionService) recordAudit(ctx context.Context, action string, before, after *entity.Prescription) error {
+	var beforeContent *entity.PrescriptionContent
+	var beforeEncounter *int
+	if before != nil {
+		beforeContent = &before.PrescriptionContent
+		beforeEncounter = before.EncounterID
+	}
+
+	changed, err := changedFields(beforeContent, &after.PrescriptionContent)
+	if err != nil {
+		return err
+	}
+	if before != nil && !equalIntPtr(beforeEncounter, after.EncounterID) {
+		changed = append(changed, "encounter_id")
+	}
+
+	return s.auditService.Record(ctx, entity.AuditEntityPrescription, after.ID, action, nil, map[string]any{
+		"patient_id":     after.PatientID,
+		"doctor_id":      after.DoctorID,
+		"changed_fields": changed,
+	})
+}
+
+// render формирует печатную форму рецепта с реквизитами клиники из текущей конфигурации
+func (s *PrescriptionService) render(ctx context.Context, prescription *entity.Prescription) ([]byte, error) {
+	_, span := tracer.Start(ctx, "PrescriptionService.render")
+	defer span.End()
+
+	clinic := s.cfg.Get().Clinic
+	doc := pdf.New(s.font)
+	doc.SetTitle("Рецепт № " + strconv.Itoa(prescription.ID))
+
+	doc.Text(clinic.Name, pdf.Style{Size: 16, Bold: true, Align: pdf.AlignCenter})
+	var contacts []string
+	for _, value := range []string{clinic.Address, clinic.Phone} {
+		if value != "" {
+			contacts = append(contacts, value)
+		}
+	}
+	if len(contacts) > 0 {
+		doc.Text(strings.Join(contacts, ", "), pdf.Style{Size: 9, Align: pdf.AlignCenter})
+	}
+	doc.Rule()
+	doc.Space(12)
+
+	doc.Text("РЕЦЕПТ № "+strconv.Itoa(prescription.ID), pdf.Style{Size: 14, Bold: true, Align: pdf.AlignCenter})
+	doc.Text("от "+displayDate(prescription.IssuedOn), pdf.Style{Size: 10, Align: pdf.AlignCenter})
+	doc.Space(12)
+
+	doc.Text("Пациент: "+prescription.PatientName, pdf.Style{})
+	if patient, err := s.patientService.GetByID(ctx, prescription.PatientID); err == nil && patient.BirthDate != nil {
+		doc.Text("Дата рождения: "+displayDate(*patient.BirthDate), pdf.Style{})
+	}
+	doc.Space(12)
+
+	doc.Text("Rp.: "+prescription.DrugName, pdf.Style{Size: 12, Bold: true})
+	doc.Text("Дозировка: "+prescription.Dosage, pdf.Style{})
+	doc.Text("Кратность приёма: "+prescription.Frequency, pdf.Style{})
+	doc.Text("Продолжительность: "+prescription.Duration, pdf.Style{})
+	if prescription.Instructions != nil {
+		doc.Text("Указания: "+*prescription.Instructions, pdf.Style{})
+	}
+	doc.Space(24)
+
+	doc.Rule()
+	doc.Text("Врач: "+prescription.DoctorName, pdf.Style{})
+	if len(prescription.DoctorSpecializations) > 0 {
+		names := make([]string, 0, len(prescription.DoctorSpecializations))
+		for _, spec := range prescription.DoctorSpecializations {
+			names = append(names, spec.Name)
+		}
+		doc.Text("Специализация: "+strings.Join(names, ", "), pdf.Style{})
+	}
+	doc.Space(18)
+	doc.Text("Подпись врача ____________________          М.П.", pdf.Style{})
+
+	return doc.Bytes()
+}
+
+// prescriptionContent проверяет назначение и подставляет дату выписки
+func prescriptionContent(req *entity.PrescriptionRequest) (*entity.PrescriptionContent, error) {
+	content := &entity.PrescriptionContent{
+		DrugName:     strings.TrimSpace(req.DrugName),
+		Dosage:       strings.TrimSpace(req.Dosage),
+		Frequency:    strings.TrimSpace(req.Frequency),
+		Duration:     strings.TrimSpace(req.Duration),
+		Instructions: trimmedOrNil(req.Instructions),
+		IssuedOn:     time.Now().Format(time.DateOnly),
+	}
+	required := []struct{ field, value string }{
+		{"drug_name", content.DrugName},
+		{"dosage", content.Dosage},
+		{"frequency", content.Frequency},
+		{"duration", content.Duration},
+	}
+	for _, r := range required {
+		if r.value == "" {
+			return nil, fmt.Errorf("%w: %s is required", ErrInvalidPrescription, r.field)
+		}
+	}
+
+	if req.IssuedOn != nil {
+		issuedOn, err := time.Parse(time.DateOnly, strings.TrimSpace(*req.IssuedOn))
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid issued_on format (expected YYYY-MM-DD)", ErrInvalidPrescription)
+		}
+		if issuedOn.After(time.Now()) {
+			return nil, fmt.Errorf("%w: issued_on is in the future", ErrInvalidPrescription)
+		}
+		content.IssuedOn = issuedOn.Format(time.DateOnly)
+	}
+
+	return content, nil
+}
+
+// displayDate переводит дату YYYY-MM-DD в привычный формат ДД.ММ.ГГГГ
+func displayDate(date string) string {
+	parsed, err := time.Parse(time.DateOnly, date)
+	if err != nil {
+		return date
+	}
+	return parsed.Format("02.01.2006")
+}
+
+func equalIntPtr(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
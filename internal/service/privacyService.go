@@ -0,0 +1,390 @@
+package service
+
+import (
+	"Clinic_backend/internal/entity"
+	"Clinic_backend/internal/repository"
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Размер страницы при сборе записей для выгрузки
+const exportPageSize = 100
+
+var (
+	ErrDataRequestResolved = errors.New("data request has already been resolved")
+	ErrResolutionRequired  = errors.New("resolution is required to reject a request")
+)
+
+// PrivacyServiceInterface - права субъекта персональных данных по 152-ФЗ: выгрузка своих
+// данных без участия администратора и удаление, которое выполняется после его одобрения
+type PrivacyServiceInterface interface {
+	Export(ctx context.Context, userID int) ([]byte, error)
+	RequestErasure(ctx context.Context, userID int, req *entity.ErasureRequest) (*entity.DataRequest, error)
+	ListMine(ctx context.Context, userID int, filter *entity.DataRequestFilter) ([]entity.DataRequest, int, error)
+	List(ctx context.Context, filter *entity.DataRequestFilter) ([]entity.DataRequest, int, error)
+	GetByID(ctx context.Context, id int) (*entity.DataRequest, error)
+	Approve(ctx context.Context, reviewerID, id, version int, req *entity.DataRequestDecision) (*entity.DataRequest, error)
+	Reject(ctx context.Context, reviewerID, id, version int, req *entity.DataRequestDecision) (*entity.DataRequest, error)
+}
+
+type PrivacyService struct {
+	txManager           repository.TransactionManagerInterface
+	auditService        AuditServiceInterface
+	patientService      PatientServiceInterface
+	encounterService    EncounterServiceInterface
+	prescriptionService PrescriptionServiceInterface
+	labService          LabServiceInterface
+	userRepo            repository.UserRepositoryInterface
+	dataRequestRepo     repository.DataRequestRepositoryInterface
+	consentRepo         repository.ConsentRepositoryInterface
+	reviewRepo          repository.ReviewRepositoryInterface
+}
+
+func NewPrivacyService(txManager repository.TransactionManagerInterface, auditService AuditServiceInterface, patientService PatientServiceInterface, encounterService EncounterServiceInterface, prescriptionService PrescriptionServiceInterface, labService LabServiceInterface, userRepo repository.UserRepositoryInterface, dataRequestRepo repository.DataRequestRepositoryInterface, consentRepo repository.ConsentRepositoryInterface, reviewRepo repository.ReviewRepositoryInterface) PrivacyServiceInterface {
+	return &PrivacyService{
+		txManager:           txManager,
+		auditService:        auditService,
+		patientService:      patientService,
+		encounterService:    encounterService,
+		prescriptionService: prescriptionService,
+		labService:          labService,
+		userRepo:            userRepo,
+		dataRequestRepo:     dataRequestRepo,
+		consentRepo:         consentRepo,
+		reviewRepo:          reviewRepo,
+	}
+}
+
+// Export собирает ZIP с данными пользователя: учётная запись, профиль пациента, подопечные,
+// медицинские документы в том виде, в каком их видит пациент, бланки анализов, обращения,
+// согласия, отзывы о врачах и действия пользователя из журнала аудита. Каждая выгрузка фиксируется как обращение
+func (s *PrivacyService) Export(ctx context.Context, userID int) ([]byte, error) {
+	ctx, span := tracer.Start(ctx, "PrivacyService.Export")
+	defer span.End()
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	user.Password = ""
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	files := []string{}
+	add := func(name string, value any) error {
+		files = append(files, name)
+		return writeZipJSON(archive, name, value)
+	}
+
+	if err := add("account.json", user); err != nil {
+		return nil, err
+	}
+
+	dependents, err := s.patientService.ListDependents(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load dependents: %w", err)
+	}
+	if err := add("dependents.json", dependents); err != nil {
+		return nil, err
+	}
+
+	profile, err := s.patientService.GetMyProfile(ctx, userID, nil)
+	switch {
+	case errors.Is(err, repository.ErrPatientNotFound):
+	case err != nil:
+		return nil, fmt.Errorf("failed to load patient profile: %w", err)
+	default:
+		if err := add("profile.json", profile); err != nil {
+			return nil, err
+		}
+		medical, err := s.exportMedical(ctx, archive, userID)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, medical...)
+	}
+
+	requests, err := collectPages(func(page int) ([]entity.DataRequest, int, error) {
+		return s.dataRequestRepo.List(ctx, &entity.DataRequestFilter{UserID: &userID, Page: page, Limit: exportPageSize})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load data requests: %w", err)
+	}
+	if err := add("data_requests.json", requests); err != nil {
+		return nil, err
+	}
+
+	consents, err := s.consentRepo.History(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load consents: %w", err)
+	}
+	if err := add("consents.json", consents); err != nil {
+		return nil, err
+	}
+
+	reviews, err := collectPages(func(page int) ([]entity.DoctorReview, int, error) {
+		return s.reviewRepo.List(ctx, &entity.DoctorReviewFilter{UserID: &userID, Page: page, Limit: exportPageSize})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load reviews: %w", err)
+	}
+	if err := add("reviews.json", reviews); err != nil {
+		return nil, err
+	}
+
+	activity, err := s.auditService.Export(ctx, &entity.AuditLogFilter{ActorID: &userID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load activity: %w", err)
+	}
+	if err := add("activity.json", activity); err != nil {
+		return nil, err
+	}
+
+	manifest := map[string]any{
+		"user_id":      userID,
+		"generated_at": time.Now().UTC().Format(time.RFC3339),
+		"files":        files,
+	}
+	if err := writeZipJSON(archive, "manifest.json", manifest); err != nil {
+		return nil, err
+	}
+	if err := archive.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finish archive: %w", err)
+	}
+
+	err = s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		request, err := s.dataRequestRepo.Create(ctx, &entity.DataRequest{
+			UserID: userID,
+			Type:   entity.DataRequestExport,
+			Status: entity.DataRequestStatusCompleted,
+		})
+		if err != nil {
+			return err
+		}
+		return s.auditService.Record(ctx, entity.AuditEntityDataRequest, request.ID, entity.AuditActionCreate, nil, dataRequestAudit(request, map[string]any{"files": len(files)}))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// exportMedical добавляет в архив документы собственного профиля пациента и
+// возвращает имена добавленных файлов
+func (s *PrivacyService) exportMedical(ctx context.Context, archive *zip.Writer, userID int) ([]string, error) {
+	encounters, err := collectPages(func(page int) ([]entity.Encounter, int, error) {
+		return s.encounterService.ListForPatient(ctx, userID, nil, &entity.EncounterFilter{Page: page, Limit: exportPageSize})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load encounters: %w", err)
+	}
+
+	prescriptions, err := collectPages(func(page int) ([]entity.Prescription, int, error) {
+		return s.prescriptionService.ListForPatient(ctx, userID, nil, &entity.PrescriptionFilter{Page: page, Limit: exportPageSize})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load prescriptions: %w", err)
+	}
+
+	summaries, err := collectPages(func(page int) ([]entity.LabOrder, int, error) {
+		return s.labService.ListForPatient(ctx, userID, nil, &entity.LabOrderFilter{Page: page, Limit: exportPageSize})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load lab orders: %w", err)
+	}
+
+	files := []string{"encounters.json", "prescriptions.json", "lab_orders.json"}
+	orders := make([]entity.LabOrder, 0, len(summaries))
+	for _, summary := range summaries {
+		// Результаты и бланки загружаются только для готовых направлений, как и в личном кабинете
+		order, err := s.labService.GetForPatient(ctx, userID, nil, summary.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load lab order %d: %w", summary.ID, err)
+		}
+		orders = append(orders, *order)
+
+		for _, attachment := range order.Attachments {
+			name := fmt.Sprintf("attachments/lab/%d/%d-%s", order.ID, attachment.ID, attachmentFilename(attachment.Filename))
+			if err := s.copyLabAttachment(ctx, archive, userID, order.ID, attachment.ID, name); err != nil {
+				return nil, err
+			}
+			files = append(files, name)
+		}
+	}
+
+	for i, value := range []any{encounters, prescriptions, orders} {
+		if err := writeZipJSON(archive, files[i], value); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+func (s *PrivacyService) copyLabAttachment(ctx context.Context, archive *zip.Writer, userID, orderID, id int, name string) error {
+	_, body, err := s.labService.OpenAttachmentForPatient(ctx, userID, nil, orderID, id)
+	if err != nil {
+		return fmt.Errorf("failed to open lab attachment %d: %w", id, err)
+	}
+	defer body.Close()
+
+	w, err := archive.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to add %s: %w", name, err)
+	}
+	if _, err := io.Copy(w, body); err != nil {
+		return fmt.Errorf("failed to add %s: %w", name, err)
+	}
+	return nil
+}
+
+// RequestErasure регистрирует запрос на удаление данных. Пока он не рассмотрен, новый не принимается
+func (s *PrivacyService) RequestErasure(ctx context.Context, userID int, req *entity.ErasureRequest) (*entity.DataRequest, error) {
+	ctx, span := tracer.Start(ctx, "PrivacyService.RequestErasure")
+	defer span.End()
+
+	var created *entity.DataRequest
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		created, err = s.dataRequestRepo.Create(ctx, &entity.DataRequest{
+			UserID: userID,
+			Type:   entity.DataRequestErasure,
+			Status: entity.DataRequestStatusPending,
+			Reason: trimmedOrNil(req.Reason),
+		})
+		if err != nil {
+			return err
+		}
+		return s.auditService.Record(ctx, entity.AuditEntityDataRequest, created.ID, entity.AuditActionCreate, nil, dataRequestAudit(created, nil))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+func (s *PrivacyService) ListMine(ctx context.Context, userID int, filter *entity.DataRequestFilter) ([]entity.DataRequest, int, error) {
+	filter.UserID = &userID
+	return s.List(ctx, filter)
+}
+
+func (s *PrivacyService) List(ctx context.Context, filter *entity.DataRequestFilter) ([]entity.DataRequest, int, error) {
+	normalizePage(&filter.Page, &filter.Limit)
+	return s.dataRequestRepo.List(ctx, filter)
+}
+
+func (s *PrivacyService) GetByID(ctx context.Context, id int) (*entity.DataRequest, error) {
+	return s.dataRequestRepo.GetByID(ctx, id)
+}
+
+// Approve выполняет запрос на удаление: учётная запись обезличивается в той же транзакции,
+// в которой обращение помечается выполненным
+func (s *PrivacyService) Approve(ctx context.Context, reviewerID, id, version int, req *entity.DataRequestDecision) (*entity.DataRequest, error) {
+	ctx, span := tracer.Start(ctx, "PrivacyService.Approve")
+	defer span.End()
+
+	return s.resolve(ctx, reviewerID, id, version, entity.DataRequestStatusCompleted, trimmedOrNil(req.Resolution))
+}
+
+func (s *PrivacyService) Reject(ctx context.Context, reviewerID, id, version int, req *entity.DataRequestDecision) (*entity.DataRequest, error) {
+	ctx, span := tracer.Start(ctx, "PrivacyService.Reject")
+	defer span.End()
+
+	resolution := trimmedOrNil(req.Resolution)
+	if resolution == nil {
+		return nil, ErrResolutionRequired
+	}
+	return s.resolve(ctx, reviewerID, id, version, entity.DataRequestStatusRejected, resolution)
+}
+
+func (s *PrivacyService) resolve(ctx context.Context, reviewerID, id, version int, status string, resolution *string) (*entity.DataRequest, error) {
+	var resolved *entity.DataRequest
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		request, err := s.dataRequestRepo.Lock(ctx, id)
+		if err != nil {
+			return err
+		}
+		if version != repository.AnyVersion && request.Version != version {
+			return repository.ErrVersionConflict
+		}
+		if request.Status != entity.DataRequestStatusPending {
+			return ErrDataRequestResolved
+		}
+
+		var summary *entity.ErasureSummary
+		if status == entity.DataRequestStatusCompleted {
+			summary, err = s.userRepo.Erase(ctx, request.UserID)
+			if err != nil {
+				return err
+			}
+		}
+
+		resolved, err = s.dataRequestRepo.Resolve(ctx, id, status, resolution, &reviewerID)
+		if err != nil {
+			return err
+		}
+		if err := s.auditService.Record(ctx, entity.AuditEntityDataRequest, id, entity.AuditActionUpdate,
+			dataRequestAudit(request, nil), dataRequestAudit(resolved, nil)); err != nil {
+			return err
+		}
+		if summary != nil {
+			return s.auditService.Record(ctx, entity.AuditEntityUser, request.UserID, entity.AuditActionErase, nil, summary)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resolved, nil
+}
+
+// dataRequestAudit - запись обращения для журнала. Комментарии пользователя и администратора
+// в журнал не попадают: они могут содержать персональные данные, а журнал не редактируется
+func dataRequestAudit(request *entity.DataRequest, extra map[string]any) map[string]any {
+	entry := map[string]any{
+		"user_id":     request.UserID,
+		"type":        request.Type,
+		"status":      request.Status,
+		"reviewed_by": request.ReviewedBy,
+	}
+	for key, value := range extra {
+		entry[key] = value
+	}
+	return entry
+}
+
+// collectPages загружает все страницы списка
+func collectPages[T any](fetch func(page int) ([]T, int, error)) ([]T, error) {
+	all := []T{}
+	for page := 1; ; page++ {
+		items, total, err := fetch(page)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, items...)
+		if len(items) == 0 || len(all) >= total {
+			return all, nil
+		}
+	}
+}
+
+func writeZipJSON(archive *zip.Writer, name string, value any) error {
+	w, err := archive.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to add %s: %w", name, err)
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		return fmt.Errorf("failed to encode %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,244 @@
+package service
+
+import (
+	"Clinic_backend/internal/entity"
+	"Clinic_backend/internal/repository"
+	"context"
+	"errors"
+	"strings"
+)
+
+var (
+	ErrReviewDoctorNotFound  = errors.New("doctor not found")
+	ErrReviewEmpty           = errors.New("review text is required")
+	ErrReviewOwnProfile      = errors.New("doctors cannot review themselves")
+	ErrReviewStatusUnchanged = errors.New("review already has this status")
+)
+
+// ReviewServiceInterface - отзывы пользователей о врачах. Отзыв виден всем и учитывается
+// в рейтинге врача только после одобрения модератором
+type ReviewServiceInterface interface {
+	Create(ctx context.Context, userID, doctorID int, req *entity.DoctorReviewRequest) (*entity.DoctorReview, error)
+	ListForDoctor(ctx context.Context, doctorID int, filter *entity.DoctorReviewFilter) ([]entity.DoctorReview, int, error)
+	ListMine(ctx context.Context, userID int, filter *entity.DoctorReviewFilter) ([]entity.DoctorReview, int, error)
+	UpdateMine(ctx context.Context, userID, id, version int, req *entity.DoctorReviewRequest) (*entity.DoctorReview, error)
+	DeleteMine(ctx context.Context, userID, id, version int) error
+	List(ctx context.Context, filter *entity.DoctorReviewFilter) ([]entity.DoctorReview, int, error)
+	GetByID(ctx context.Context, id int) (*entity.DoctorReview, error)
+	Approve(ctx context.Context, moderatorID, id, version int, req *entity.ReviewModerationRequest) (*entity.DoctorReview, error)
+	Reject(ctx context.Context, moderatorID, id, version int, req *entity.ReviewModerationRequest) (*entity.DoctorReview, error)
+}
+
+type ReviewService struct {
+	txManager    repository.TransactionManagerInterface
+	auditService AuditServiceInterface
+	doctorRepo   repository.DoctorRepositoryInterface
+	reviewRepo   repository.ReviewRepositoryInterface
+}
+
+func NewReviewService(txManager repository.TransactionManagerInterface, auditService AuditServiceInterface, doctorRepo repository.DoctorRepositoryInterface, reviewRepo repository.ReviewRepositoryInterface) ReviewServiceInterface {
+	return &ReviewService{
+		txManager:    txManager,
+		auditService: auditService,
+		doctorRepo:   doctorRepo,
+		reviewRepo:   reviewRepo,
+	}
+}
+
+// Create сохраняет отзыв на модерацию. Один пользователь - один отзыв о враче
+func (s *ReviewService) Create(ctx context.Context, userID, doctorID int, req *entity.DoctorReviewRequest) (*entity.DoctorReview, error) {
+	ctx, span := tracer.Start(ctx, "ReviewService.Create")
+	defer span.End()
+
+	text := strings.TrimSpace(req.Text)
+	if text == "" {
+		return nil, ErrReviewEmpty
+	}
+
+	doctor, err := s.doctorRepo.GetByID(ctx, doctorID)
+	if err != nil {
+		return nil, ErrReviewDoctorNotFound
+	}
+	if doctor.UserID != nil && *doctor.UserID == userID {
+		return nil, ErrReviewOwnProfile
+	}
+
+	return s.reviewRepo.Create(ctx, &entity.DoctorReview{
+		DoctorID: doctorID,
+		UserID:   userID,
+		Rating:   req.Rating,
+		Text:     text,
+	})
+}
+
+// ListForDoctor возвращает одобренные отзывы о враче без идентификаторов авторов
+func (s *ReviewService) ListForDoctor(ctx context.Context, doctorID int, filter *entity.DoctorReviewFilter) ([]entity.DoctorReview, int, error) {
+	ctx, span := tracer.Start(ctx, "ReviewService.ListForDoctor")
+	defer span.End()
+
+	if _, err := s.doctorRepo.GetByID(ctx, doctorID); err != nil {
+		return nil, 0, ErrReviewDoctorNotFound
+	}
+
+	filter.DoctorID = &doctorID
+	filter.UserID = nil
+	filter.Status = entity.ReviewStatusApproved
+	normalizePage(&filter.Page, &filter.Limit)
+
+	reviews, total, err := s.reviewRepo.List(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range reviews {
+		reviews[i].UserID = 0
+		reviews[i].ModerationComment = nil
+		reviews[i].ModeratedBy = nil
+	}
+	return reviews, total, nil
+}
+
+func (s *ReviewService) ListMine(ctx context.Context, userID int, filter *entity.DoctorReviewFilter) ([]entity.DoctorReview, int, error) {
+	ctx, span := tracer.Start(ctx, "ReviewService.ListMine")
+	defer span.End()
+
+	filter.UserID = &userID
+	normalizePage(&filter.Page, &filter.Limit)
+
+	return s.reviewRepo.List(ctx, filter)
+}
+
+// UpdateMine исправляет свой отзыв; исправленный отзыв снимается с публикации до повторной модерации
+func (s *ReviewService) UpdateMine(ctx context.Context, userID, id, version int, req *entity.DoctorReviewRequest) (*entity.DoctorReview, error) {
+	ctx, span := tracer.Start(ctx, "ReviewService.UpdateMine")
+	defer span.End()
+
+	req.Text = strings.TrimSpace(req.Text)
+	if req.Text == "" {
+		return nil, ErrReviewEmpty
+	}
+
+	var updated *entity.DoctorReview
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		review, err := s.lockOwn(ctx, userID, id, version)
+		if err != nil {
+			return err
+		}
+
+		updated, err = s.reviewRepo.Update(ctx, id, req)
+		if err != nil {
+			return err
+		}
+		if review.Status == entity.ReviewStatusApproved {
+			return s.reviewRepo.RefreshDoctorRating(ctx, review.DoctorID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+func (s *ReviewService) DeleteMine(ctx context.Context, userID, id, version int) error {
+	ctx, span := tracer.Start(ctx, "ReviewService.DeleteMine")
+	defer span.End()
+
+	return s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		review, err := s.lockOwn(ctx, userID, id, version)
+		if err != nil {
+			return err
+		}
+
+		if err := s.reviewRepo.Delete(ctx, id); err != nil {
+			return err
+		}
+		if review.Status == entity.ReviewStatusApproved {
+			return s.reviewRepo.RefreshDoctorRating(ctx, review.DoctorID)
+		}
+		return nil
+	})
+}
+
+// lockOwn блокирует отзыв пользователя; чужой отзыв для него не существует
+func (s *ReviewService) lockOwn(ctx context.Context, userID, id, version int) (*entity.DoctorReview, error) {
+	review, err := s.reviewRepo.Lock(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if review.UserID != userID {
+		return nil, repository.ErrReviewNotFound
+	}
+	if version != repository.AnyVersion && review.Version != version {
+		return nil, repository.ErrVersionConflict
+	}
+	return review, nil
+}
+
+// List - очередь модерации и все отзывы для администратора
+func (s *ReviewService) List(ctx context.Context, filter *entity.DoctorReviewFilter) ([]entity.DoctorReview, int, error) {
+	ctx, span := tracer.Start(ctx, "ReviewService.List")
+	defer span.End()
+
+	normalizePage(&filter.Page, &filter.Limit)
+
+	return s.reviewRepo.List(ctx, filter)
+}
+
+func (s *ReviewService) GetByID(ctx context.Context, id int) (*entity.DoctorReview, error) {
+	ctx, span := tracer.Start(ctx, "ReviewService.GetByID")
+	defer span.End()
+
+	return s.reviewRepo.GetByID(ctx, id)
+}
+
+// Approve публикует отзыв и пересчитывает рейтинг врача
+func (s *ReviewService) Approve(ctx context.Context, moderatorID, id, version int, req *entity.ReviewModerationRequest) (*entity.DoctorReview, error) {
+	ctx, span := tracer.Start(ctx, "ReviewService.Approve")
+	defer span.End()
+
+	return s.moderate(ctx, moderatorID, id, version, entity.ReviewStatusApproved, trimmedOrNil(req.Comment))
+}
+
+// Reject скрывает отзыв. Одобренный ранее отзыв тоже можно отклонить - например, по жалобе
+func (s *ReviewService) Reject(ctx context.Context, moderatorID, id, version int, req *entity.ReviewModerationRequest) (*entity.DoctorReview, error) {
+	ctx, span := tracer.Start(ctx, "ReviewService.Reject")
+	defer span.End()
+
+	return s.moderate(ctx, moderatorID, id, version, entity.ReviewStatusRejected, trimmedOrNil(req.Comment))
+}
+
+func (s *ReviewService) moderate(ctx context.Context, moderatorID, id, version int, status string, comment *string) (*entity.DoctorReview, error) {
+	var moderated *entity.DoctorReview
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		review, err := s.reviewRepo.Lock(ctx, id)
+		if err != nil {
+			return err
+		}
+		if version != repository.AnyVersion && review.Version != version {
+			return repository.ErrVersionConflict
+		}
+		if review.Status == status {
+			return ErrReviewStatusUnchanged
+		}
+
+		moderated, err = s.reviewRepo.Moderate(ctx, id, status, comment, moderatorID)
+		if err != nil {
+			return err
+		}
+		if review.Status == entity.ReviewStatusApproved || status == entity.ReviewStatusApproved {
+			if err := s.reviewRepo.RefreshDoctorRating(ctx, review.DoctorID); err != nil {
+				return err
+			}
+		}
+		return s.auditService.Record(ctx, entity.AuditEntityDoctorReview, id, entity.AuditActionUpdate,
+			map[string]any{"status": review.Status, "moderation_comment": review.ModerationComment},
+			map[string]any{"status": moderated.Status, "moderation_comment": moderated.ModerationComment, "doctor_id": review.DoctorID},
+		)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return moderated, nil
+}
@@ -17,11 +17,15 @@ type ScheduleServiceInterface interface {
 }
 
 type ScheduleService struct {
+	txManager    repository.TransactionManagerInterface
+	auditService AuditServiceInterface
 	scheduleRepo repository.ScheduleRepositoryInterface
 }
 
-func NewScheduleService(scheduleRepo repository.ScheduleRepositoryInterface) ScheduleServiceInterface {
+func NewScheduleService(txManager repository.TransactionManagerInterface, auditService AuditServiceInterface, scheduleRepo repository.ScheduleRepositoryInterface) ScheduleServiceInterface {
 	return &ScheduleService{
+		txManager:    txManager,
+		auditService: auditService,
 		scheduleRepo: scheduleRepo,
 	}
 }
@@ -37,7 +41,20 @@ func (s *ScheduleService) CreateSchedule(ctx context.Context, schedule *entity.S
 		return nil, err
 	}
 
-	return s.scheduleRepo.Create(ctx, schedule)
+	var created *entity.Schedule
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		created, err = s.scheduleRepo.Create(ctx, schedule)
+		if err != nil {
+			return err
+		}
+		return s.auditService.Record(ctx, entity.AuditEntitySchedule, created.ID, entity.AuditActionCreate, nil, created)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
 }
 
 func (s *ScheduleService) GetAllSchedules(ctx context.Context) ([]entity.Schedule, error) {
@@ -67,9 +84,36 @@ func (s *ScheduleService) UpdateSchedule(ctx context.Context, id int, schedule *
 		return nil, err
 	}
 
-	return s.scheduleRepo.Update(ctx, id, schedule)
+	var updated *entity.Schedule
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		before, err := s.scheduleRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		updated, err = s.scheduleRepo.Update(ctx, id, schedule)
+		if err != nil {
+			return err
+		}
+		return s.auditService.Record(ctx, entity.AuditEntitySchedule, id, entity.AuditActionUpdate, before, updated)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updated, nil
 }
 
 func (s *ScheduleService) DeleteSchedule(ctx context.Context, id int) error {
-	return s.scheduleRepo.Delete(ctx, id)
+	return s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		before, err := s.scheduleRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := s.scheduleRepo.Delete(ctx, id); err != nil {
+			return err
+		}
+		return s.auditService.Record(ctx, entity.AuditEntitySchedule, id, entity.AuditActionDelete, before, nil)
+	})
 }
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"Clinic_backend/internal/entity"
+	"Clinic_backend/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+const (
+	searchDefaultLimit = 20
+	searchMaxLimit     = 50
+	searchMaxTerms     = 8
+	searchMaxTermRunes = 64
+)
+
+var (
+	ErrInvalidSearchQuery = errors.New("search query must contain letters or digits")
+	ErrInvalidSearchType  = errors.New("invalid search type")
+)
+
+type SearchServiceInterface interface {
+	Search(ctx context.Context, req *entity.SearchRequest) (*entity.SearchResponse, error)
+}
+
+type SearchService struct {
+	txManager  repository.TransactionManagerInterface
+	searchRepo repository.SearchRepositoryInterface
+}
+
+func NewSearchService(txManager repository.TransactionManagerInterface, searchRepo repository.SearchRepositoryInterface) SearchServiceInterface {
+	return &SearchService{
+		txManager:  txManager,
+		searchRepo: searchRepo,
+	}
+}
+
+func (s *SearchService) Search(ctx context.Context, req *entity.SearchRequest) (*entity.SearchResponse, error) {
+	query := &entity.SearchQuery{
+		Terms: searchTerms(req.Q),
+		Types: entity.SearchTypes,
+		Limit: req.Limit,
+	}
+	if len(query.Terms) == 0 {
+		return nil, ErrInvalidSearchQuery
+	}
+
+	if req.Type != "" {
+		query.Types = strings.Split(req.Type, ",")
+		for _, t := range query.Types {
+			if !slices.Contains(entity.SearchTypes, t) {
+				return nil, fmt.Errorf("%w %q, expected one of %s", ErrInvalidSearchType, t, strings.Join(entity.SearchTypes, ", "))
+			}
+		}
+	}
+
+	if query.Limit < 1 {
+		query.Limit = searchDefaultLimit
+	}
+	query.Limit = min(query.Limit, searchMaxLimit)
+
+	var results []entity.SearchResult
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		results, err = s.searchRepo.Search(ctx, query)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range results {
+		results[i].Title = escapeHighlight(results[i].Title)
+		results[i].Snippet = escapeHighlight(results[i].Snippet)
+	}
+
+	return &entity.SearchResponse{Query: strings.Join(query.Terms, " "), Results: results}, nil
+}
+
+// searchTerms разбивает запрос на слова из букв и цифр в нижнем регистре.
+// Всё остальное (знаки препинания, операторы tsquery) считается разделителем.
+func searchTerms(q string) []string {
+	words := strings.FieldsFunc(strings.ToLower(q), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	terms := make([]string, 0, min(len(words), searchMaxTerms))
+	for _, word := range words {
+		if len(terms) == searchMaxTerms {
+			break
+		}
+		if runes := []rune(word); len(runes) > searchMaxTermRunes {
+			word = string(runes[:searchMaxTermRunes])
+		}
+		terms = append(terms, word)
+	}
+	return terms
+}
+
+// highlightTags заменяет маркеры выделения из репозитория тегами <mark>
+var highlightTags = strings.NewReplacer(repository.HighlightStart, "<mark>", repository.HighlightStop, "</mark>")
+
+// escapeHighlight экранирует HTML в тексте от ts_headline. Разметкой остаются только
+// теги <mark> на месте маркеров выделения
+func escapeHighlight(text string) string {
+	return highlightTags.Replace(html.EscapeString(text))
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"Clinic_backend/internal/repository"
+	"slices"
+	"testing"
+)
+
+func TestEscapeHighlight(t *testing.T) {
+	const start, stop = repository.HighlightStart, repository.HighlightStop
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"marked word", "Врач " + start + "кардиолог" + stop, "Врач <mark>кардиолог</mark>"},
+		{"html in text", `<b>УЗИ</b> & "ЭКГ"`, `&lt;b&gt;УЗИ&lt;/b&gt; &amp; &#34;ЭКГ&#34;`},
+		{"markup in marked word", start + "<b>'x'</b>" + stop, "<mark>&lt;b&gt;&#39;x&#39;&lt;/b&gt;</mark>"},
+		{"literal mark tag is text", "<mark>не выделение</mark>", "&lt;mark&gt;не выделение&lt;/mark&gt;"},
+		{"script", "<script>alert(1)</script>", "&lt;script&gt;alert(1)&lt;/script&gt;"},
+	}
+	for _, tt := range tests {
+		if got := escapeHighlight(tt.text); got != tt.want {
+			t.Errorf("%s: escapeHighlight(%q) = %q, want %q", tt.name, tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestSearchTerms(t *testing.T) {
+	tests := []struct {
+		q    string
+		want []string
+	}{
+		{"Кардиолог  УЗИ", []string{"кардиолог", "узи"}},
+		{`<b>o'brien</b> & "x" | !y:*`, []string{"b", "o", "brien", "b", "x", "y"}},
+		{"!&|()", []string{}},
+	}
+	for _, tt := range tests {
+		if got := searchTerms(tt.q); !slices.Equal(got, tt.want) {
+			t.Errorf("searchTerms(%q) = %q, want %q", tt.q, got, tt.want)
+		}
+	}
+}
@@ -8,55 +8,135 @@ import (
 
 type CategoryServiceInterface interface {
 	CreateCategory(ctx context.Context, category *entity.ServiceCategory) (*entity.ServiceCategory, error)
-	GetAllCategories(ctx context.Context) ([]entity.ServiceCategory, error)
+	GetAllCategories(ctx context.Context, includeDeleted bool) ([]entity.ServiceCategory, error)
 	GetCategoryByID(ctx context.Context, id int) (*entity.ServiceCategory, error)
 	GetFavoriteCategories(ctx context.Context) ([]entity.ServiceCategory, error)
-	UpdateCategory(ctx context.Context, id int, category *entity.ServiceCategory) (*entity.ServiceCategory, error)
-	DeleteCategory(ctx context.Context, id int) error
-	ToggleFavorite(ctx context.Context, id int) error
+	UpdateCategory(ctx context.Context, id int, version int, category *entity.ServiceCategory) (*entity.ServiceCategory, error)
+	DeleteCategory(ctx context.Context, id int, version int) error
+	RestoreCategory(ctx context.Context, id int) error
+	ToggleFavorite(ctx context.Context, id int, version int) error
 }
 
 type CategoryService struct {
+	txManager    repository.TransactionManagerInterface
+	auditService AuditServiceInterface
+	mediaService MediaServiceInterface
 	categoryRepo repository.ServiceCategoryRepositoryInterface
 	specRepo     repository.SpecializationRepositoryInterface
 }
 
-func NewCategoryService(categoryRepo repository.ServiceCategoryRepositoryInterface, specRepo repository.SpecializationRepositoryInterface) CategoryServiceInterface {
+func NewCategoryService(txManager repository.TransactionManagerInterface, auditService AuditServiceInterface, mediaService MediaServiceInterface, categoryRepo repository.ServiceCategoryRepositoryInterface, specRepo repository.SpecializationRepositoryInterface) CategoryServiceInterface {
 	return &CategoryService{
+		txManager:    txManager,
+		auditService: auditService,
+		mediaService: mediaService,
 		categoryRepo: categoryRepo,
 		specRepo:     specRepo,
 	}
 }
 
 func (s *CategoryService) CreateCategory(ctx context.Context, category *entity.ServiceCategory) (*entity.ServiceCategory, error) {
-	return s.categoryRepo.Create(ctx, category)
+	if err := attachMedia(ctx, s.mediaService, "category_photo_id", category.CategoryPhotoID, &category.CategoryPhoto); err != nil {
+		return nil, err
+	}
+
+	var created *entity.ServiceCategory
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		created, err = s.categoryRepo.Create(ctx, category)
+		if err != nil {
+			return err
+		}
+		return s.auditService.Record(ctx, entity.AuditEntityServiceCategory, created.ID, entity.AuditActionCreate, nil, created)
+	})
+	return withSrcset(ctx, s.mediaService, created, err, categoryPhoto)
 }
 
-func (s *CategoryService) GetAllCategories(ctx context.Context) ([]entity.ServiceCategory, error) {
-	return s.categoryRepo.GetAll(ctx)
+func (s *CategoryService) GetAllCategories(ctx context.Context, includeDeleted bool) ([]entity.ServiceCategory, error) {
+	categories, err := s.categoryRepo.GetAll(ctx, includeDeleted)
+	return withSrcsets(ctx, s.mediaService, categories, err, categoryPhoto)
 }
 
 func (s *CategoryService) GetCategoryByID(ctx context.Context, id int) (*entity.ServiceCategory, error) {
-	return s.categoryRepo.GetByID(ctx, id)
+	category, err := s.categoryRepo.GetByID(ctx, id)
+	return withSrcset(ctx, s.mediaService, category, err, categoryPhoto)
 }
 
 func (s *CategoryService) GetFavoriteCategories(ctx context.Context) ([]entity.ServiceCategory, error) {
-	return s.categoryRepo.GetFavorites(ctx)
+	categories, err := s.categoryRepo.GetFavorites(ctx)
+	return withSrcsets(ctx, s.mediaService, categories, err, categoryPhoto)
+}
+
+func (s *CategoryService) UpdateCategory(ctx context.Context, id int, version int, category *entity.ServiceCategory) (*entity.ServiceCategory, error) {
+	category.Version = version
+
+	if err := attachMedia(ctx, s.mediaService, "category_photo_id", category.CategoryPhotoID, &category.CategoryPhoto); err != nil {
+		return nil, err
+	}
+
+	var updated *entity.ServiceCategory
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		before, err := s.categoryRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		updated, err = s.categoryRepo.Update(ctx, id, category)
+		if err != nil {
+			return err
+		}
+		return s.auditService.Record(ctx, entity.AuditEntityServiceCategory, id, entity.AuditActionUpdate, before, updated)
+	})
+	return withSrcset(ctx, s.mediaService, updated, err, categoryPhoto)
 }
 
-func (s *CategoryService) UpdateCategory(ctx context.Context, id int, category *entity.ServiceCategory) (*entity.ServiceCategory, error) {
-	return s.categoryRepo.Update(ctx, id, category)
+func (s *CategoryService) DeleteCategory(ctx context.Context, id int, version int) error {
+	return s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		before, err := s.categoryRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := s.categoryRepo.Delete(ctx, id, version); err != nil {
+			return err
+		}
+		return s.auditService.Record(ctx, entity.AuditEntityServiceCategory, id, entity.AuditActionDelete, before, nil)
+	})
 }
 
-func (s *CategoryService) DeleteCategory(ctx context.Context, id int) error {
-	return s.categoryRepo.Delete(ctx, id)
+func (s *CategoryService) RestoreCategory(ctx context.Context, id int) error {
+	return s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.categoryRepo.Restore(ctx, id); err != nil {
+			return err
+		}
+
+		after, err := s.categoryRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		return s.auditService.Record(ctx, entity.AuditEntityServiceCategory, id, entity.AuditActionRestore, nil, after)
+	})
 }
 
-func (s *CategoryService) ToggleFavorite(ctx context.Context, id int) error {
-	category, err := s.categoryRepo.GetByID(ctx, id)
-	if err != nil {
-		return err
-	}
+func (s *CategoryService) ToggleFavorite(ctx context.Context, id int, version int) error {
+	return s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		before, err := s.categoryRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := s.categoryRepo.SetFavorite(ctx, id, !before.Favorite, version); err != nil {
+			return err
+		}
+
+		after, err := s.categoryRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		return s.auditService.Record(ctx, entity.AuditEntityServiceCategory, id, entity.AuditActionUpdate, before, after)
+	})
+}
 
-	return s.categoryRepo.SetFavorite(ctx, id, !category.Favorite)
+func categoryPhoto(category *entity.ServiceCategory) (*string, *entity.Srcset) {
+	return category.CategoryPhotoID, &category.CategoryPhotoSrcset
 }
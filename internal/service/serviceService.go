@@ -9,22 +9,29 @@ import (
 
 type ServiceServiceInterface interface {
 	CreateService(ctx context.Context, req *entity.ServiceCreateRequest) (*entity.Service, error)
-	GetAllServices(ctx context.Context) ([]entity.Service, error)
+	GetAllServices(ctx context.Context, includeDeleted bool) ([]entity.Service, error)
 	GetServiceByID(ctx context.Context, id int) (*entity.Service, error)
 	GetServicesByCategory(ctx context.Context, categoryID int) ([]entity.Service, error)
 	GetServicesBySpecialization(ctx context.Context, specID int) ([]entity.Service, error)
-	UpdateService(ctx context.Context, id int, req *entity.ServiceCreateRequest) (*entity.Service, error)
-	DeleteService(ctx context.Context, id int) error
+	UpdateService(ctx context.Context, id int, version int, req *entity.ServiceCreateRequest) (*entity.Service, error)
+	DeleteService(ctx context.Context, id int, version int) error
+	RestoreService(ctx context.Context, id int) error
 }
 
 type ServiceService struct {
+	txManager    repository.TransactionManagerInterface
+	auditService AuditServiceInterface
+	mediaService MediaServiceInterface
 	serviceRepo  repository.ServiceRepositoryInterface
 	categoryRepo repository.ServiceCategoryRepositoryInterface
 	specRepo     repository.SpecializationRepositoryInterface
 }
 
-func NewServiceService(serviceRepo repository.ServiceRepositoryInterface, categoryRepo repository.ServiceCategoryRepositoryInterface, specRepo repository.SpecializationRepositoryInterface) ServiceServiceInterface {
+func NewServiceService(txManager repository.TransactionManagerInterface, auditService AuditServiceInterface, mediaService MediaServiceInterface, serviceRepo repository.ServiceRepositoryInterface, categoryRepo repository.ServiceCategoryRepositoryInterface, specRepo repository.SpecializationRepositoryInterface) ServiceServiceInterface {
 	return &ServiceService{
+		txManager:    txManager,
+		auditService: auditService,
+		mediaService: mediaService,
 		serviceRepo:  serviceRepo,
 		categoryRepo: categoryRepo,
 		specRepo:     specRepo,
@@ -57,20 +64,35 @@ func (s *ServiceService) CreateService(ctx context.Context, req *entity.ServiceC
 		Name:              req.Name,
 		Description:       req.Description,
 		SpecificPhoto:     req.SpecificPhoto,
+		SpecificPhotoID:   req.SpecificPhotoID,
 		Price:             req.Price,
 		ServiceCategoryID: req.ServiceCategoryID,
 		SpecializationID:  req.SpecializationID,
 	}
+	if err := attachMedia(ctx, s.mediaService, "specific_photo_id", service.SpecificPhotoID, &service.SpecificPhoto); err != nil {
+		return nil, err
+	}
 
-	return s.serviceRepo.Create(ctx, service)
+	var created *entity.Service
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		created, err = s.serviceRepo.Create(ctx, service)
+		if err != nil {
+			return err
+		}
+		return s.auditService.Record(ctx, entity.AuditEntityService, created.ID, entity.AuditActionCreate, nil, created)
+	})
+	return withSrcset(ctx, s.mediaService, created, err, servicePhoto)
 }
 
-func (s *ServiceService) GetAllServices(ctx context.Context) ([]entity.Service, error) {
-	return s.serviceRepo.GetAll(ctx)
+func (s *ServiceService) GetAllServices(ctx context.Context, includeDeleted bool) ([]entity.Service, error) {
+	services, err := s.serviceRepo.GetAll(ctx, includeDeleted)
+	return withSrcsets(ctx, s.mediaService, services, err, servicePhoto)
 }
 
 func (s *ServiceService) GetServiceByID(ctx context.Context, id int) (*entity.Service, error) {
-	return s.serviceRepo.GetByID(ctx, id)
+	service, err := s.serviceRepo.GetByID(ctx, id)
+	return withSrcset(ctx, s.mediaService, service, err, servicePhoto)
 }
 
 func (s *ServiceService) GetServicesByCategory(ctx context.Context, categoryID int) ([]entity.Service, error) {
@@ -80,7 +102,8 @@ func (s *ServiceService) GetServicesByCategory(ctx context.Context, categoryID i
 		return nil, errors.New("category not found")
 	}
 
-	return s.serviceRepo.GetByCategory(ctx, categoryID)
+	services, err := s.serviceRepo.GetByCategory(ctx, categoryID)
+	return withSrcsets(ctx, s.mediaService, services, err, servicePhoto)
 }
 
 func (s *ServiceService) GetServicesBySpecialization(ctx context.Context, specID int) ([]entity.Service, error) {
@@ -90,26 +113,75 @@ func (s *ServiceService) GetServicesBySpecialization(ctx context.Context, specID
 		return nil, errors.New("specialization not found")
 	}
 
-	return s.serviceRepo.GetBySpecialization(ctx, specID)
+	services, err := s.serviceRepo.GetBySpecialization(ctx, specID)
+	return withSrcsets(ctx, s.mediaService, services, err, servicePhoto)
 }
 
-func (s *ServiceService) UpdateService(ctx context.Context, id int, req *entity.ServiceCreateRequest) (*entity.Service, error) {
+func (s *ServiceService) UpdateService(ctx context.Context, id int, version int, req *entity.ServiceCreateRequest) (*entity.Service, error) {
 	existing, err := s.serviceRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
+	// Клиент редактировал устаревшую версию
+	if version != repository.AnyVersion && existing.Version != version {
+		return nil, repository.ErrVersionConflict
+	}
+
+	before := *existing
+
 	// Обновляем поля
 	existing.Name = req.Name
 	existing.Description = req.Description
 	existing.SpecificPhoto = req.SpecificPhoto
+	existing.SpecificPhotoID = req.SpecificPhotoID
 	existing.Price = req.Price
 	existing.ServiceCategoryID = req.ServiceCategoryID
 	existing.SpecializationID = req.SpecializationID
+	if err := attachMedia(ctx, s.mediaService, "specific_photo_id", existing.SpecificPhotoID, &existing.SpecificPhoto); err != nil {
+		return nil, err
+	}
 
-	return s.serviceRepo.Update(ctx, id, existing)
+	var updated *entity.Service
+	err = s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		updated, err = s.serviceRepo.Update(ctx, id, existing)
+		if err != nil {
+			return err
+		}
+		return s.auditService.Record(ctx, entity.AuditEntityService, id, entity.AuditActionUpdate, &before, updated)
+	})
+	return withSrcset(ctx, s.mediaService, updated, err, servicePhoto)
+}
+
+func (s *ServiceService) DeleteService(ctx context.Context, id int, version int) error {
+	return s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		before, err := s.serviceRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := s.serviceRepo.Delete(ctx, id, version); err != nil {
+			return err
+		}
+		return s.auditService.Record(ctx, entity.AuditEntityService, id, entity.AuditActionDelete, before, nil)
+	})
+}
+
+func (s *ServiceService) RestoreService(ctx context.Context, id int) error {
+	return s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.serviceRepo.Restore(ctx, id); err != nil {
+			return err
+		}
+
+		after, err := s.serviceRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		return s.auditService.Record(ctx, entity.AuditEntityService, id, entity.AuditActionRestore, nil, after)
+	})
 }
 
-func (s *ServiceService) DeleteService(ctx context.Context, id int) error {
-	return s.serviceRepo.Delete(ctx, id)
+func servicePhoto(service *entity.Service) (*string, *entity.Srcset) {
+	return service.SpecificPhotoID, &service.SpecificPhotoSrcset
 }
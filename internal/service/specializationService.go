@@ -8,38 +8,99 @@ import (
 
 type SpecializationServiceInterface interface {
 	CreateSpecialization(ctx context.Context, spec *entity.Specialization) (*entity.Specialization, error)
-	GetAllSpecializations(ctx context.Context) ([]entity.Specialization, error)
+	GetAllSpecializations(ctx context.Context, includeDeleted bool) ([]entity.Specialization, error)
 	GetSpecializationByID(ctx context.Context, id int) (*entity.Specialization, error)
-	UpdateSpecialization(ctx context.Context, id int, spec *entity.Specialization) (*entity.Specialization, error)
-	DeleteSpecialization(ctx context.Context, id int) error
+	UpdateSpecialization(ctx context.Context, id int, version int, spec *entity.Specialization) (*entity.Specialization, error)
+	DeleteSpecialization(ctx context.Context, id int, version int) error
+	RestoreSpecialization(ctx context.Context, id int) error
 }
 
 type SpecializationService struct {
-	specRepo repository.SpecializationRepositoryInterface
+	txManager    repository.TransactionManagerInterface
+	auditService AuditServiceInterface
+	specRepo     repository.SpecializationRepositoryInterface
 }
 
-func NewSpecializationService(specRepo repository.SpecializationRepositoryInterface) SpecializationServiceInterface {
+func NewSpecializationService(txManager repository.TransactionManagerInterface, auditService AuditServiceInterface, specRepo repository.SpecializationRepositoryInterface) SpecializationServiceInterface {
 	return &SpecializationService{
-		specRepo: specRepo,
+		txManager:    txManager,
+		auditService: auditService,
+		specRepo:     specRepo,
 	}
 }
 
 func (s *SpecializationService) CreateSpecialization(ctx context.Context, spec *entity.Specialization) (*entity.Specialization, error) {
-	return s.specRepo.Create(ctx, spec)
+	var created *entity.Specialization
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		created, err = s.specRepo.Create(ctx, spec)
+		if err != nil {
+			return err
+		}
+		return s.auditService.Record(ctx, entity.AuditEntitySpecialization, created.ID, entity.AuditActionCreate, nil, created)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
 }
 
-func (s *SpecializationService) GetAllSpecializations(ctx context.Context) ([]entity.Specialization, error) {
-	return s.specRepo.GetAll(ctx)
+func (s *SpecializationService) GetAllSpecializations(ctx context.Context, includeDeleted bool) ([]entity.Specialization, error) {
+	return s.specRepo.GetAll(ctx, includeDeleted)
 }
 
 func (s *SpecializationService) GetSpecializationByID(ctx context.Context, id int) (*entity.Specialization, error) {
 	return s.specRepo.GetByID(ctx, id)
 }
 
-func (s *SpecializationService) UpdateSpecialization(ctx context.Context, id int, spec *entity.Specialization) (*entity.Specialization, error) {
-	return s.specRepo.Update(ctx, id, spec)
+func (s *SpecializationService) UpdateSpecialization(ctx context.Context, id int, version int, spec *entity.Specialization) (*entity.Specialization, error) {
+	spec.Version = version
+
+	var updated *entity.Specialization
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		before, err := s.specRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		updated, err = s.specRepo.Update(ctx, id, spec)
+		if err != nil {
+			return err
+		}
+		return s.auditService.Record(ctx, entity.AuditEntitySpecialization, id, entity.AuditActionUpdate, before, updated)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+func (s *SpecializationService) DeleteSpecialization(ctx context.Context, id int, version int) error {
+	return s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		before, err := s.specRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := s.specRepo.Delete(ctx, id, version); err != nil {
+			return err
+		}
+		return s.auditService.Record(ctx, entity.AuditEntitySpecialization, id, entity.AuditActionDelete, before, nil)
+	})
 }
 
-func (s *SpecializationService) DeleteSpecialization(ctx context.Context, id int) error {
-	return s.specRepo.Delete(ctx, id)
+func (s *SpecializationService) RestoreSpecialization(ctx context.Context, id int) error {
+	return s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.specRepo.Restore(ctx, id); err != nil {
+			return err
+		}
+
+		after, err := s.specRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		return s.auditService.Record(ctx, entity.AuditEntitySpecialization, id, entity.AuditActionRestore, nil, after)
+	})
 }
@@ -0,0 +1,6 @@
+package service
+
+import "go.opentelemetry.io/otel"
+
+// tracer открывает спаны бизнес-операций; провайдер задаёт tracing.Setup
+var tracer = otel.Tracer("Clinic_backend/internal/service")
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"Clinic_backend/internal/entity"
+	"Clinic_backend/internal/repository"
+	"context"
+)
+
+type UserServiceInterface interface {
+	GetByID(ctx context.Context, id int) (*entity.User, error)
+	GetAll(ctx context.Context) ([]entity.User, error)
+	Update(ctx context.Context, id int, user *entity.User) (*entity.User, error)
+	Delete(ctx context.Context, id int) error
+}
+
+type UserService struct {
+	txManager    repository.TransactionManagerInterface
+	auditService AuditServiceInterface
+	userRepo     repository.UserRepositoryInterface
+}
+
+func NewUserService(txManager repository.TransactionManagerInterface, auditService AuditServiceInterface, userRepo repository.UserRepositoryInterface) UserServiceInterface {
+	return &UserService{
+		txManager:    txManager,
+		auditService: auditService,
+		userRepo:     userRepo,
+	}
+}
+
+func (s *UserService) GetByID(ctx context.Context, id int) (*entity.User, error) {
+	return s.userRepo.GetByID(ctx, id)
+}
+
+func (s *UserService) GetAll(ctx context.Context) ([]entity.User, error) {
+	return s.userRepo.GetAll(ctx)
+}
+
+// Update обновляет пользователя. В журнал аудита пишутся только имена изменённых полей:
+// журнал неизменяем и связан хэшами, поэтому имя и email из него уже не удалить при
+// обезличивании. Записи, сделанные до этого изменения, содержат UserResponse целиком и
+// остаются в журнале как есть - их нельзя исправить, не разорвав цепочку хэшей
+func (s *UserService) Update(ctx context.Context, id int, user *entity.User) (*entity.User, error) {
+	var updated *entity.User
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		before, err := s.userRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		updated, err = s.userRepo.Update(ctx, id, user)
+		if err != nil {
+			return err
+		}
+		return s.auditService.Record(ctx, entity.AuditEntityUser, id, entity.AuditActionUpdate, nil, userAuditState(before, updated))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+// userAuditState - запись журнала аудита об изменении учётной записи без персональных данных
+func userAuditState(before, after *entity.User) map[string]any {
+	changed := []string{}
+	if before.Username != after.Username {
+		changed = append(changed, "username")
+	}
+	if before.Email != after.Email {
+		changed = append(changed, "email")
+	}
+	return map[string]any{"changed_fields": changed}
+}
+
+// Delete обезличивает учётную запись (см. UserRepository.Erase): строка пользователя
+// остаётся, потому что на неё ссылаются медицинские документы. В журнал пишется только
+// итог без персональных данных
+func (s *UserService) Delete(ctx context.Context, id int) error {
+	return s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		summary, err := s.userRepo.Erase(ctx, id)
+		if err != nil {
+			return err
+		}
+		return s.auditService.Record(ctx, entity.AuditEntityUser, id, entity.AuditActionErase, nil, summary)
+	})
+}
@@ -2,7 +2,9 @@ package storage
 
 import (
 	"context"
+	"crypto/sha256"
 	_ "embed"
+	"encoding/hex"
 	"fmt"
 
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -11,12 +13,36 @@ import (
 //go:embed init.sql
 var initSQL string
 
+// SchemaChecksum - контрольная сумма встроенного init.sql, т.е. версия схемы, которую ожидает код
+func SchemaChecksum() string {
+	sum := sha256.Sum256([]byte(initSQL))
+	return hex.EncodeToString(sum[:])
+}
+
+// PendingMigrations сообщает, что текущая версия init.sql ещё не применена к базе
+func PendingMigrations(ctx context.Context, db *pgxpool.Pool) (bool, error) {
+	var applied bool
+	err := db.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM schema_migrations WHERE checksum = $1)", SchemaChecksum()).Scan(&applied)
+	if err != nil {
+		return false, fmt.Errorf("failed to check schema migrations: %w", err)
+	}
+	return !applied, nil
+}
+
 func CheckAndMigrate(db *pgxpool.Pool) error {
 	_, err := db.Exec(context.Background(), initSQL)
 	if err != nil {
 		return fmt.Errorf("ошибка выполнения init.sql: %w", err)
 	}
 
+	_, err = db.Exec(context.Background(),
+		"INSERT INTO schema_migrations (checksum) VALUES ($1) ON CONFLICT (checksum) DO NOTHING",
+		SchemaChecksum(),
+	)
+	if err != nil {
+		return fmt.Errorf("ошибка записи версии схемы: %w", err)
+	}
+
 	var count int
 	err = db.QueryRow(context.Background(), "select count(*) from users").Scan(&count)
 	if err != nil {
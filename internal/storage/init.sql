@@ -105,6 +105,411 @@ CREATE TABLE IF NOT EXISTS doctor_specializations (
 );
 
 
+-- Мягкое удаление справочных сущностей
+ALTER TABLE doctors ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+ALTER TABLE services ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+ALTER TABLE service_categories ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+ALTER TABLE specializations ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+ALTER TABLE licenses ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+ALTER TABLE main_carusel ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+
+-- Версия строки для оптимистичной блокировки (ETag / If-Match)
+ALTER TABLE doctors ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE services ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE service_categories ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE specializations ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE licenses ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE main_carusel ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+
+
+-- Журнал административных изменений. Только добавление, записи связаны цепочкой хэшей.
+-- before/after хранятся как JSON (а не JSONB), чтобы сохранить исходный текст для проверки хэша.
+CREATE TABLE IF NOT EXISTS audit_log (
+  id BIGSERIAL PRIMARY KEY,
+  actor_id INT,
+  ip TEXT NOT NULL DEFAULT '',
+  entity_type TEXT NOT NULL,
+  entity_id INT NOT NULL,
+  action TEXT NOT NULL,
+  before JSON,
+  after JSON,
+  request_id TEXT NOT NULL DEFAULT '',
+  prev_hash TEXT NOT NULL,
+  hash TEXT NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE OR REPLACE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_log_append_only ON audit_log;
+CREATE TRIGGER audit_log_append_only
+  BEFORE UPDATE OR DELETE ON audit_log
+  FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
+
+DROP TRIGGER IF EXISTS audit_log_no_truncate ON audit_log;
+CREATE TRIGGER audit_log_no_truncate
+  BEFORE TRUNCATE ON audit_log
+  FOR EACH STATEMENT EXECUTE FUNCTION audit_log_append_only();
+
+-- Загруженные изображения. id - SHA-256 содержимого после удаления метаданных,
+-- он же ключ объекта в хранилище, поэтому одинаковые файлы хранятся один раз
+CREATE TABLE IF NOT EXISTS media (
+  id VARCHAR(64) PRIMARY KEY,
+  content_type TEXT NOT NULL,
+  size BIGINT NOT NULL,
+  width INTEGER NOT NULL,
+  height INTEGER NOT NULL,
+  original_name TEXT NOT NULL DEFAULT '',
+  uploaded_by INT REFERENCES users(id) ON DELETE SET NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Ссылки на загруженные изображения. Текстовые поля с URL остаются для совместимости
+-- и при указании изображения заполняются его адресом /media/{id}
+ALTER TABLE doctors ADD COLUMN IF NOT EXISTS doctor_photo_id VARCHAR(64) REFERENCES media(id);
+ALTER TABLE service_categories ADD COLUMN IF NOT EXISTS category_photo_id VARCHAR(64) REFERENCES media(id);
+ALTER TABLE services ADD COLUMN IF NOT EXISTS specific_photo_id VARCHAR(64) REFERENCES media(id);
+ALTER TABLE licenses ADD COLUMN IF NOT EXISTS photo_id VARCHAR(64) REFERENCES media(id);
+ALTER TABLE main_carusel ADD COLUMN IF NOT EXISTS image_id VARCHAR(64) REFERENCES media(id);
+
+-- Уменьшенные копии изображений создаются фоновым воркером. Копия хранится
+-- в хранилище под ключом {media_id}-w{width}
+ALTER TABLE media ADD COLUMN IF NOT EXISTS variants_status VARCHAR(16) NOT NULL DEFAULT 'pending';
+CREATE INDEX IF NOT EXISTS idx_media_variants_pending ON media(created_at) WHERE variants_status = 'pending';
+
+CREATE TABLE IF NOT EXISTS media_variants (
+  media_id VARCHAR(64) NOT NULL REFERENCES media(id) ON DELETE CASCADE,
+  width INTEGER NOT NULL,
+  height INTEGER NOT NULL,
+  content_type TEXT NOT NULL,
+  size BIGINT NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (media_id, width)
+);
+
+-- Полнотекстовый поиск по каталогу. search_vector поддерживается триггерами:
+-- название с весом A, описание с весом B. Русская конфигурация приводит слова
+-- к основе, английская оставляет русские слова как есть - это помогает поиску по началу слова.
+-- Опечатки в названиях ищутся по триграммам (pg_trgm).
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE OR REPLACE FUNCTION catalog_search_vector(title TEXT, body TEXT) RETURNS tsvector AS $$
+  SELECT setweight(to_tsvector('russian', coalesce(title, '')), 'A') ||
+         setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+         setweight(to_tsvector('russian', coalesce(body, '')), 'B') ||
+         setweight(to_tsvector('english', coalesce(body, '')), 'B')
+$$ LANGUAGE sql IMMUTABLE;
+
+-- Аргументы триггера - имена колонок с названием и описанием
+CREATE OR REPLACE FUNCTION catalog_search_vector_update() RETURNS trigger AS $$
+BEGIN
+  NEW.search_vector := catalog_search_vector(to_jsonb(NEW) ->> TG_ARGV[0], to_jsonb(NEW) ->> TG_ARGV[1]);
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE doctors ADD COLUMN IF NOT EXISTS search_vector tsvector;
+ALTER TABLE services ADD COLUMN IF NOT EXISTS search_vector tsvector;
+ALTER TABLE service_categories ADD COLUMN IF NOT EXISTS search_vector tsvector;
+ALTER TABLE specializations ADD COLUMN IF NOT EXISTS search_vector tsvector;
+
+DROP TRIGGER IF EXISTS doctors_search_vector ON doctors;
+CREATE TRIGGER doctors_search_vector
+  BEFORE INSERT OR UPDATE OF fullname, description ON doctors
+  FOR EACH ROW EXECUTE FUNCTION catalog_search_vector_update('fullname', 'description');
+
+DROP TRIGGER IF EXISTS services_search_vector ON services;
+CREATE TRIGGER services_search_vector
+  BEFORE INSERT OR UPDATE OF name, description ON services
+  FOR EACH ROW EXECUTE FUNCTION catalog_search_vector_update('name', 'description');
+
+DROP TRIGGER IF EXISTS service_categories_search_vector ON service_categories;
+CREATE TRIGGER service_categories_search_vector
+  BEFORE INSERT OR UPDATE OF name, description ON service_categories
+  FOR EACH ROW EXECUTE FUNCTION catalog_search_vector_update('name', 'description');
+
+DROP TRIGGER IF EXISTS specializations_search_vector ON specializations;
+CREATE TRIGGER specializations_search_vector
+  BEFORE INSERT OR UPDATE OF name ON specializations
+  FOR EACH ROW EXECUTE FUNCTION catalog_search_vector_update('name');
+
+-- Записи, созданные до появления триггеров
+UPDATE doctors SET search_vector = catalog_search_vector(fullname, description) WHERE search_vector IS NULL;
+UPDATE services SET search_vector = catalog_search_vector(name, description) WHERE search_vector IS NULL;
+UPDATE service_categories SET search_vector = catalog_search_vector(name, description) WHERE search_vector IS NULL;
+UPDATE specializations SET search_vector = catalog_search_vector(name, NULL) WHERE search_vector IS NULL;
+
+-- Профиль пациента: персональные данные и идентификаторы для медицинских документов.
+-- Телефон хранится в формате E.164, полис ОМС (16 цифр) и СНИЛС (11 цифр) - без разделителей
+CREATE TABLE IF NOT EXISTS patient_profiles (
+  id SERIAL PRIMARY KEY,
+  user_id INT UNIQUE REFERENCES users(id) ON DELETE CASCADE,
+  last_name VARCHAR(100) NOT NULL,
+  first_name VARCHAR(100) NOT NULL,
+  middle_name VARCHAR(100),
+  birth_date DATE,
+  sex VARCHAR(8) CHECK (sex IN ('male', 'female')),
+  phone VARCHAR(16),
+  address TEXT,
+  insurance_policy VARCHAR(16) UNIQUE,
+  snils VARCHAR(11) UNIQUE,
+  version INTEGER NOT NULL DEFAULT 1,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Опекунство: пользователь ведёт профили подопечных без своей учётной записи - детей,
+-- пожилых родственников. Флаги - согласие подопечного на запись к врачу и просмотр
+-- медицинских данных опекуном; учитываются с 18 и 15 лет соответственно
+CREATE TABLE IF NOT EXISTS patient_guardians (
+  id SERIAL PRIMARY KEY,
+  guardian_user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  patient_id INT NOT NULL REFERENCES patient_profiles(id) ON DELETE CASCADE,
+  relationship VARCHAR(32) NOT NULL,
+  can_book BOOLEAN NOT NULL DEFAULT FALSE,
+  can_view_records BOOLEAN NOT NULL DEFAULT FALSE,
+  consent_confirmed_at TIMESTAMP,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE (guardian_user_id, patient_id)
+);
+
+-- Лечащие врачи пациента, назначенные администратором. Запись о приёме создаёт
+-- назначенный врач или врач, у которого пациент уже был на приёме
+CREATE TABLE IF NOT EXISTS patient_doctors (
+  id SERIAL PRIMARY KEY,
+  patient_id INT NOT NULL REFERENCES patient_profiles(id) ON DELETE CASCADE,
+  doctor_user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  assigned_by INT REFERENCES users(id) ON DELETE SET NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE (patient_id, doctor_user_id)
+);
+
+-- Электронная медицинская карта: записи о приёмах. Черновик правит только лечащий врач;
+-- после подписи запись заблокирована, исправления оформляются дополнениями - новыми
+-- редакциями. Каждая подписанная редакция сохраняется в encounter_revisions без изменений.
+-- Пациент удаляется только вместе с картой, поэтому ссылка без каскада
+CREATE TABLE IF NOT EXISTS encounters (
+  id SERIAL PRIMARY KEY,
+  patient_id INT NOT NULL REFERENCES patient_profiles(id),
+  doctor_user_id INT NOT NULL REFERENCES users(id),
+  encounter_date DATE NOT NULL,
+  complaints TEXT,
+  anamnesis TEXT,
+  examination TEXT,
+  diagnoses JSONB NOT NULL DEFAULT '[]',
+  recommendations TEXT,
+  status VARCHAR(16) NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'signed')),
+  revision INTEGER NOT NULL DEFAULT 0,
+  content_hash VARCHAR(64),
+  signed_at TIMESTAMPTZ,
+  version INTEGER NOT NULL DEFAULT 1,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS encounter_revisions (
+  encounter_id INT NOT NULL REFERENCES encounters(id),
+  revision INTEGER NOT NULL,
+  author_user_id INT NOT NULL REFERENCES users(id),
+  -- Причина дополнения; у первой подписанной редакции отсутствует
+  reason TEXT,
+  encounter_date DATE NOT NULL,
+  complaints TEXT,
+  anamnesis TEXT,
+  examination TEXT,
+  diagnoses JSONB NOT NULL,
+  recommendations TEXT,
+  content_hash VARCHAR(64) NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (encounter_id, revision)
+);
+
+-- Подписанную запись меняет только дополнение, увеличивающее номер редакции
+CREATE OR REPLACE FUNCTION encounters_lock_signed() RETURNS trigger AS $$
+BEGIN
+  IF TG_OP = 'DELETE' THEN
+    IF OLD.status = 'signed' THEN
+      RAISE EXCEPTION 'encounter % is signed and cannot be deleted', OLD.id;
+    END IF;
+    RETURN OLD;
+  END IF;
+  IF OLD.status = 'signed' AND NEW.revision <= OLD.revision THEN
+    RAISE EXCEPTION 'encounter % is signed; changes require an amendment', OLD.id;
+  END IF;
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS encounters_lock_signed ON encounters;
+CREATE TRIGGER encounters_lock_signed
+  BEFORE UPDATE OR DELETE ON encounters
+  FOR EACH ROW EXECUTE FUNCTION encounters_lock_signed();
+
+CREATE OR REPLACE FUNCTION encounter_revisions_append_only() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION 'encounter_revisions is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS encounter_revisions_append_only ON encounter_revisions;
+CREATE TRIGGER encounter_revisions_append_only
+  BEFORE UPDATE OR DELETE ON encounter_revisions
+  FOR EACH ROW EXECUTE FUNCTION encounter_revisions_append_only();
+
+-- Учётная запись врача: через неё врач выписывает рецепты от своего имени
+ALTER TABLE doctors ADD COLUMN IF NOT EXISTS user_id INT UNIQUE REFERENCES users(id) ON DELETE SET NULL;
+
+-- Рецепты. Ссылки на пациента и врача без каскада: рецепт - медицинский документ,
+-- отменённый рецепт помечается deleted_at и остаётся в базе
+CREATE TABLE IF NOT EXISTS prescriptions (
+  id SERIAL PRIMARY KEY,
+  patient_id INT NOT NULL REFERENCES patient_profiles(id),
+  doctor_id INT NOT NULL REFERENCES doctors(id),
+  encounter_id INT REFERENCES encounters(id),
+  drug_name TEXT NOT NULL,
+  dosage TEXT NOT NULL,
+  frequency TEXT NOT NULL,
+  duration TEXT NOT NULL,
+  instructions TEXT,
+  issued_on DATE NOT NULL DEFAULT CURRENT_DATE,
+  version INTEGER NOT NULL DEFAULT 1,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  deleted_at TIMESTAMP
+);
+
+-- Направления на анализы. Статус меняется только вперёд: ordered -> sampled -> in_progress -> ready.
+-- Отменить можно только направление, по которому ещё не взят материал
+CREATE TABLE IF NOT EXISTS lab_orders (
+  id SERIAL PRIMARY KEY,
+  patient_id INT NOT NULL REFERENCES patient_profiles(id),
+  doctor_id INT NOT NULL REFERENCES doctors(id),
+  encounter_id INT REFERENCES encounters(id),
+  -- Назначенные исследования: [{"code": "...", "name": "..."}]
+  tests JSONB NOT NULL,
+  comment TEXT,
+  status VARCHAR(16) NOT NULL DEFAULT 'ordered'
+    CHECK (status IN ('ordered', 'sampled', 'in_progress', 'ready')),
+  sampled_at TIMESTAMPTZ,
+  ready_at TIMESTAMPTZ,
+  version INTEGER NOT NULL DEFAULT 1,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  deleted_at TIMESTAMP
+);
+
+-- Результаты по показателям. Повторный импорт того же показателя заменяет значение
+CREATE TABLE IF NOT EXISTS lab_results (
+  id SERIAL PRIMARY KEY,
+  order_id INT NOT NULL REFERENCES lab_orders(id) ON DELETE CASCADE,
+  test_code VARCHAR(32) NOT NULL,
+  test_name TEXT NOT NULL,
+  value TEXT NOT NULL,
+  unit TEXT,
+  reference_range TEXT,
+  flag VARCHAR(16) CHECK (flag IN ('normal', 'low', 'high', 'abnormal')),
+  out_of_range BOOLEAN NOT NULL DEFAULT false,
+  final BOOLEAN NOT NULL DEFAULT true,
+  observed_at TIMESTAMPTZ,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE (order_id, test_code)
+);
+
+-- PDF-бланки результатов; содержимое хранится в хранилище файлов под ключом lab/{sha256}
+CREATE TABLE IF NOT EXISTS lab_attachments (
+  id SERIAL PRIMARY KEY,
+  order_id INT NOT NULL REFERENCES lab_orders(id) ON DELETE CASCADE,
+  blob_key VARCHAR(128) NOT NULL,
+  filename TEXT NOT NULL,
+  size BIGINT NOT NULL,
+  uploaded_by INT REFERENCES users(id) ON DELETE SET NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Обращения субъектов персональных данных (152-ФЗ): выгрузка выполняется сразу и
+-- фиксируется как выполненная, удаление ждёт решения администратора. Учётная запись
+-- при удалении обезличивается, а не удаляется, поэтому ссылка без каскада
+CREATE TABLE IF NOT EXISTS data_requests (
+  id SERIAL PRIMARY KEY,
+  user_id INT NOT NULL REFERENCES users(id),
+  type VARCHAR(16) NOT NULL CHECK (type IN ('export', 'erasure')),
+  status VARCHAR(16) NOT NULL DEFAULT 'pending'
+    CHECK (status IN ('pending', 'completed', 'rejected')),
+  reason TEXT,
+  resolution TEXT,
+  reviewed_by INT REFERENCES users(id) ON DELETE SET NULL,
+  reviewed_at TIMESTAMPTZ,
+  completed_at TIMESTAMPTZ,
+  version INTEGER NOT NULL DEFAULT 1,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Момент обезличивания учётной записи по запросу на удаление
+ALTER TABLE users ADD COLUMN IF NOT EXISTS erased_at TIMESTAMPTZ;
+
+-- Документы для согласий: политика конфиденциальности, согласие на обработку персональных
+-- данных и на рекламные рассылки. Версии нумеруются по типу; опубликованная версия не
+-- меняется, действующей считается последняя опубликованная
+CREATE TABLE IF NOT EXISTS consent_documents (
+  id SERIAL PRIMARY KEY,
+  type VARCHAR(32) NOT NULL CHECK (type IN ('privacy_policy', 'personal_data', 'marketing')),
+  version INTEGER NOT NULL,
+  title TEXT NOT NULL,
+  content TEXT NOT NULL,
+  published_at TIMESTAMPTZ,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE (type, version)
+);
+
+-- Согласия пользователей. Строки не удаляются: отзыв отмечается withdrawn_at, повторное
+-- согласие добавляет новую строку, поэтому история сохраняется целиком
+CREATE TABLE IF NOT EXISTS user_consents (
+  id SERIAL PRIMARY KEY,
+  user_id INT NOT NULL REFERENCES users(id),
+  document_id INT NOT NULL REFERENCES consent_documents(id),
+  accepted_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  ip TEXT NOT NULL DEFAULT '',
+  withdrawn_at TIMESTAMPTZ,
+  withdrawn_ip TEXT
+);
+
+-- Отзывы о врачах: один от пользователя на врача. Публикуются после модерации,
+-- исправленный автором отзыв снова попадает в очередь
+CREATE TABLE IF NOT EXISTS doctor_reviews (
+  id SERIAL PRIMARY KEY,
+  doctor_id INT NOT NULL REFERENCES doctors(id) ON DELETE CASCADE,
+  user_id INT NOT NULL REFERENCES users(id),
+  rating SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+  text TEXT NOT NULL,
+  status VARCHAR(16) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+  moderation_comment TEXT,
+  moderated_by INT REFERENCES users(id),
+  moderated_at TIMESTAMPTZ,
+  version INTEGER NOT NULL DEFAULT 1,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE (doctor_id, user_id)
+);
+
+-- Рейтинг врача по одобренным отзывам; пересчитывается при модерации
+ALTER TABLE doctors ADD COLUMN IF NOT EXISTS rating_avg NUMERIC(3, 2);
+ALTER TABLE doctors ADD COLUMN IF NOT EXISTS rating_count INTEGER NOT NULL DEFAULT 0;
+
+-- Применённые версии схемы: контрольная сумма init.sql на момент запуска
+CREATE TABLE IF NOT EXISTS schema_migrations (
+  checksum VARCHAR(64) PRIMARY KEY,
+  applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+
 CREATE INDEX IF NOT EXISTS idx_users_email ON users(email);
 CREATE INDEX IF NOT EXISTS idx_users_role_id ON users(role_id);
 CREATE INDEX IF NOT EXISTS idx_services_category_id ON services(service_category_id);
@@ -113,6 +518,45 @@ CREATE INDEX IF NOT EXISTS idx_service_categories_specialization_id ON service_c
 CREATE INDEX IF NOT EXISTS idx_schedules_day ON schedules(day);
 CREATE INDEX IF NOT EXISTS idx_doctor_specializations_doctor_id ON doctor_specializations(doctor_id);
 CREATE INDEX IF NOT EXISTS idx_doctor_specializations_specialization_id ON doctor_specializations(specialization_id);
+CREATE INDEX IF NOT EXISTS idx_doctors_deleted_at ON doctors(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_services_deleted_at ON services(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_service_categories_deleted_at ON service_categories(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_specializations_deleted_at ON specializations(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_licenses_deleted_at ON licenses(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_main_carusel_deleted_at ON main_carusel(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_audit_log_entity ON audit_log(entity_type, entity_id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor_id ON audit_log(actor_id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log(created_at);
+CREATE INDEX IF NOT EXISTS idx_doctors_search ON doctors USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_services_search ON services USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_service_categories_search ON service_categories USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_specializations_search ON specializations USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_doctors_fullname_trgm ON doctors USING GIN (fullname gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_services_name_trgm ON services USING GIN (name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_service_categories_name_trgm ON service_categories USING GIN (name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_specializations_name_trgm ON specializations USING GIN (name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_patient_profiles_full_name_trgm ON patient_profiles USING GIN ((last_name || ' ' || first_name || coalesce(' ' || middle_name, '')) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_patient_profiles_phone_trgm ON patient_profiles USING GIN (phone gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_patient_guardians_patient_id ON patient_guardians(patient_id);
+CREATE INDEX IF NOT EXISTS idx_patient_doctors_doctor_user_id ON patient_doctors(doctor_user_id);
+CREATE INDEX IF NOT EXISTS idx_encounters_patient_id ON encounters(patient_id, encounter_date DESC);
+CREATE INDEX IF NOT EXISTS idx_encounters_doctor_user_id ON encounters(doctor_user_id, encounter_date DESC);
+CREATE INDEX IF NOT EXISTS idx_prescriptions_patient_id ON prescriptions(patient_id, issued_on DESC);
+CREATE INDEX IF NOT EXISTS idx_prescriptions_doctor_id ON prescriptions(doctor_id, issued_on DESC);
+CREATE INDEX IF NOT EXISTS idx_prescriptions_encounter_id ON prescriptions(encounter_id) WHERE encounter_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_lab_orders_patient_id ON lab_orders(patient_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_lab_orders_doctor_id ON lab_orders(doctor_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_lab_orders_status ON lab_orders(status) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_lab_attachments_order_id ON lab_attachments(order_id);
+CREATE INDEX IF NOT EXISTS idx_data_requests_user_id ON data_requests(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_data_requests_status ON data_requests(status, created_at);
+-- Не больше одного нерассмотренного запроса на удаление от пользователя
+CREATE UNIQUE INDEX IF NOT EXISTS idx_data_requests_pending_erasure ON data_requests(user_id) WHERE type = 'erasure' AND status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_user_consents_user_id ON user_consents(user_id, accepted_at DESC);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_consents_active ON user_consents(user_id, document_id) WHERE withdrawn_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_doctor_reviews_doctor_id ON doctor_reviews(doctor_id, status, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_doctor_reviews_user_id ON doctor_reviews(user_id);
+CREATE INDEX IF NOT EXISTS idx_doctor_reviews_status ON doctor_reviews(status, created_at);
 
 -- Insert default roles
 -- INSERT INTO roles (name) VALUES 
@@ -3,49 +3,42 @@ package storage
 import (
 	"context"
 	"fmt"
-	"log"
 	"log/slog"
-	"time"
 
 	"Clinic_backend/config"
+	"Clinic_backend/internal/tracing"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-func NewConnection(ctx context.Context, cfg *config.Config) *pgxpool.Pool {
-	env := cfg.Env
+func NewConnection(ctx context.Context, cfg *config.Config) (*pgxpool.Pool, error) {
+	db := cfg.Database
 
-	dsn := fmt.Sprintf("postgresql://%s:%s@%s:%d/%s",
-		env.DbUsername,
-		env.DbPassword,
-		env.DbHost,
-		env.DbPort,
-		env.DbName,
-	)
-
-	config, err := pgxpool.ParseConfig(dsn)
+	config, err := pgxpool.ParseConfig(db.DSN())
 	if err != nil {
-		log.Fatal("Unable to parse config", err)
+		return nil, fmt.Errorf("unable to parse database config: %w", err)
 	}
 
 	// Настройки пула
-	config.MaxConns = 25
-	config.MinConns = 5
-	config.MaxConnLifetime = 30 * time.Minute
-	config.MaxConnIdleTime = 5 * time.Minute
+	config.MaxConns = db.MaxConns
+	config.MinConns = db.MinConns
+	config.MaxConnLifetime = db.MaxConnLifetime
+	config.MaxConnIdleTime = db.MaxConnIdleTime
+
+	// Спан на каждый запрос к БД
+	config.ConnConfig.Tracer = tracing.NewQueryTracer()
 
-	conn, err := pgxpool.NewWithConfig(context.Background(), config)
+	conn, err := pgxpool.NewWithConfig(ctx, config)
 	if err != nil {
-		slog.Error("ошибка при подключении к БД", slog.Any("error", err))
-		panic(err)
+		return nil, fmt.Errorf("ошибка при подключении к БД: %w", err)
 	}
 
 	if err = CheckAndMigrate(conn); err != nil {
-		slog.Error("ошибка при миграции базы данных", slog.Any("error", err))
-		panic(err)
+		conn.Close()
+		return nil, fmt.Errorf("ошибка при миграции базы данных: %w", err)
 	}
 
 	slog.Info("DB connected")
 
-	return conn
+	return conn, nil
 }
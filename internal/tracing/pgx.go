@@ -0,0 +1,109 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"github.com/exaring/otelpgx"
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type operationKey struct{}
+
+// WithOperation задаёт имя спана для следующего запроса, например "DoctorRepository.GetByID"
+func WithOperation(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, operationKey{}, name)
+}
+
+// QueryTracer - трассировка pgx на otelpgx. Подключается через ConnConfig.Tracer.
+// Текст запроса попадает в спан только без литералов (см. SanitizeSQL).
+type QueryTracer struct {
+	*otelpgx.Tracer
+}
+
+func NewQueryTracer() *QueryTracer {
+	return &QueryTracer{Tracer: otelpgx.NewTracer(
+		otelpgx.WithTrimSQLInSpanName(),
+		otelpgx.WithDisableQuerySpanNamePrefix(),
+		otelpgx.WithSpanNameCtxFunc(spanName),
+		otelpgx.WithDisableSQLStatementInAttributes(),
+	)}
+}
+
+func (t *QueryTracer) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	ctx = t.Tracer.TraceQueryStart(ctx, conn, data)
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("db.query.text", SanitizeSQL(data.SQL)))
+	return ctx
+}
+
+// spanName - метод репозитория из WithOperation, иначе ключевое слово запроса
+func spanName(ctx context.Context, sql string) string {
+	if name, _ := ctx.Value(operationKey{}).(string); name != "" {
+		return name
+	}
+	return sqlOperation(sql)
+}
+
+// sqlOperation - первое ключевое слово запроса (SELECT, INSERT, ...)
+func sqlOperation(sql string) string {
+	fields := strings.Fields(sql)
+	if len(fields) == 0 {
+		return "query"
+	}
+	return strings.ToUpper(fields[0])
+}
+
+// SanitizeSQL заменяет строковые и числовые литералы на "?" и схлопывает пробелы,
+// чтобы в трассы не попадали персональные данные. Плейсхолдеры $N сохраняются.
+func SanitizeSQL(sql string) string {
+	var b strings.Builder
+	b.Grow(len(sql))
+
+	runes := []rune(sql)
+	space := false
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			space = true
+			continue
+		case r == '\'':
+			// Строковый литерал, '' внутри - экранированная кавычка
+			for i++; i < len(runes); i++ {
+				if runes[i] == '\'' {
+					if i+1 < len(runes) && runes[i+1] == '\'' {
+						i++
+						continue
+					}
+					break
+				}
+			}
+			r = '?'
+		case unicode.IsDigit(r) && !isIdentPart(runes, i-1):
+			for i+1 < len(runes) && (unicode.IsDigit(runes[i+1]) || runes[i+1] == '.') {
+				i++
+			}
+			r = '?'
+		}
+
+		if space && b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		space = false
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// isIdentPart сообщает, продолжает ли цифра идентификатор или плейсхолдер ($1, table2)
+func isIdentPart(runes []rune, i int) bool {
+	if i < 0 {
+		return false
+	}
+	r := runes[i]
+	return r == '$' || r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
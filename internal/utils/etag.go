@@ -0,0 +1,44 @@
+package utils
+
+import "strings"
+
+// ETagMatches выполняет слабое сравнение ETag из списка If-None-Match
+func ETagMatches(header, etag string) bool {
+	target := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range ETagList(header) {
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == target {
+			return true
+		}
+	}
+	return false
+}
+
+// ETagList разбирает заголовок If-Match / If-None-Match на отдельные ETag.
+// Запятая внутри кавычек значения не разделяет, пустые элементы пропускаются.
+func ETagList(header string) []string {
+	var (
+		tags   []string
+		quoted bool
+		start  int
+	)
+	for i := 0; i <= len(header); i++ {
+		if i < len(header) {
+			switch header[i] {
+			case '"':
+				quoted = !quoted
+				continue
+			case ',':
+				if quoted {
+					continue
+				}
+			default:
+				continue
+			}
+		}
+		if tag := strings.TrimSpace(header[start:i]); tag != "" {
+			tags = append(tags, tag)
+		}
+		start = i + 1
+	}
+	return tags
+}
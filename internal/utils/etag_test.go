@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestETagList(t *testing.T) {
+	tests := []struct {
+		header string
+		want   []string
+	}{
+		{``, nil},
+		{`*`, []string{`*`}},
+		{`"a", W/"b" ,"c"`, []string{`"a"`, `W/"b"`, `"c"`}},
+		{`"a,b", "c"`, []string{`"a,b"`, `"c"`}},
+		{` , "a",,`, []string{`"a"`}},
+	}
+	for _, tt := range tests {
+		if got := ETagList(tt.header); !slices.Equal(got, tt.want) {
+			t.Errorf("ETagList(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestETagMatches(t *testing.T) {
+	if !ETagMatches(`W/"1", "2"`, `"1"`) {
+		t.Error("weak comparison must ignore W/")
+	}
+	if !ETagMatches(`*`, `"1"`) {
+		t.Error("* must match any ETag")
+	}
+	if ETagMatches(`"1,2"`, `"2"`) {
+		t.Error("comma inside quotes must not split the tag")
+	}
+}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"errors"
+	"fmt"
 	"regexp"
 	"strings"
 	"time"
@@ -9,7 +10,11 @@ import (
 
 var (
 	emailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}$`)
-	phoneRegex = regexp.MustCompile(`^\\+?[1-9]\\d{1,14}$`)
+	phoneRegex = regexp.MustCompile(`^\+?[1-9]\d{1,14}$`)
+	// Пробелы, дефисы, скобки и точки, которыми обычно разделяют цифры номера
+	phoneSeparators = strings.NewReplacer(" ", "", "-", "", "(", "", ")", "", ".", "")
+	snilsRegex      = regexp.MustCompile(`^\d{11}$`)
+	policyRegex     = regexp.MustCompile(`^\d{16}$`)
 )
 
 func ValidateEmail(email string) error {
@@ -26,6 +31,64 @@ func ValidatePhone(phone string) error {
 	return nil
 }
 
+// NormalizePhone убирает разделители: "+7 (912) 345-67-89" -> "+79123456789"
+func NormalizePhone(phone string) string {
+	return phoneSeparators.Replace(strings.TrimSpace(phone))
+}
+
+// NormalizeSNILS оставляет только цифры: "112-233-445 95" -> "11223344595"
+func NormalizeSNILS(snils string) string {
+	return strings.NewReplacer(" ", "", "-", "").Replace(strings.TrimSpace(snils))
+}
+
+// ValidateSNILS проверяет нормализованный СНИЛС: 11 цифр, последние две - контрольное число.
+// Контрольное число - сумма первых девяти цифр с весами 9..1; 100 и 101 дают 00, больше 101 -
+// остаток от деления на 101. Для номеров не больше 001-001-998 контрольное число не проверяется.
+func ValidateSNILS(snils string) error {
+	if !snilsRegex.MatchString(snils) {
+		return errors.New("invalid snils format (expected 11 digits)")
+	}
+
+	number := snils[:9]
+	if number <= "001001998" {
+		return nil
+	}
+
+	sum := 0
+	for i := range 9 {
+		sum += int(number[i]-'0') * (9 - i)
+	}
+	checksum := sum % 101
+	if checksum == 100 {
+		checksum = 0
+	}
+
+	if snils[9:] != fmt.Sprintf("%02d", checksum) {
+		return errors.New("invalid snils checksum")
+	}
+	return nil
+}
+
+// ValidatePolicyNumber проверяет единый номер полиса ОМС - 16 цифр
+func ValidatePolicyNumber(policy string) error {
+	if !policyRegex.MatchString(policy) {
+		return errors.New("invalid insurance policy number (expected 16 digits)")
+	}
+	return nil
+}
+
+// ValidateBirthDate проверяет дату рождения в формате YYYY-MM-DD: не в будущем и не раньше 1900 года
+func ValidateBirthDate(date string) error {
+	birthDate, err := time.Parse(time.DateOnly, date)
+	if err != nil {
+		return errors.New("invalid birth date format (expected YYYY-MM-DD)")
+	}
+	if birthDate.Year() < 1900 || birthDate.After(time.Now()) {
+		return errors.New("birth date is out of range")
+	}
+	return nil
+}
+
 func ValidateTimeSlot(from, to string) error {
 	fromTime, err := time.Parse("15:04", from)
 	if err != nil {
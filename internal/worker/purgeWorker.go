@@ -0,0 +1,64 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Purger - репозиторий, умеющий окончательно удалять мягко удалённые записи
+type Purger interface {
+	Purge(ctx context.Context, before time.Time) (int64, error)
+}
+
+type PurgeTarget struct {
+	Name   string
+	Purger Purger
+}
+
+// PurgeWorker периодически удаляет записи, срок хранения которых после мягкого удаления истёк.
+// Цели обрабатываются по порядку, поэтому зависимые таблицы нужно передавать раньше родительских.
+type PurgeWorker struct {
+	targets   []PurgeTarget
+	retention time.Duration
+	interval  time.Duration
+}
+
+func NewPurgeWorker(retention, interval time.Duration, targets ...PurgeTarget) *PurgeWorker {
+	return &PurgeWorker{
+		targets:   targets,
+		retention: retention,
+		interval:  interval,
+	}
+}
+
+// Run блокируется до отмены контекста
+func (w *PurgeWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.purge(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *PurgeWorker) purge(ctx context.Context) {
+	before := time.Now().Add(-w.retention)
+
+	for _, target := range w.targets {
+		count, err := target.Purger.Purge(ctx, before)
+		if err != nil {
+			slog.Error("Failed to purge soft-deleted records", "target", target.Name, "error", err.Error())
+			continue
+		}
+		if count > 0 {
+			slog.Info("Purged soft-deleted records", "target", target.Name, "count", count)
+		}
+	}
+}
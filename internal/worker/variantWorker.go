@@ -0,0 +1,59 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// VariantGenerator - сервис, создающий уменьшенные копии для очереди изображений
+type VariantGenerator interface {
+	GeneratePendingVariants(ctx context.Context, limit int) (int, error)
+}
+
+// VariantWorker создаёт уменьшенные копии загруженных изображений. Очередь - изображения
+// в статусе pending; пока она не пуста, порции обрабатываются подряд, затем воркер
+// ждёт следующего интервала.
+type VariantWorker struct {
+	generator VariantGenerator
+	interval  time.Duration
+	batchSize int
+}
+
+func NewVariantWorker(generator VariantGenerator, interval time.Duration, batchSize int) *VariantWorker {
+	return &VariantWorker{
+		generator: generator,
+		interval:  interval,
+		batchSize: batchSize,
+	}
+}
+
+// Run блокируется до отмены контекста
+func (w *VariantWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.drain(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *VariantWorker) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		count, err := w.generator.GeneratePendingVariants(ctx, w.batchSize)
+		if err != nil {
+			// Хранилище или БД недоступны - повторим на следующем интервале
+			slog.Error("Failed to generate media variants", "error", err.Error())
+			return
+		}
+		if count < w.batchSize {
+			return
+		}
+	}
+}